@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+// newClient returns an HTTP client which authenticates its requests with the access tokens
+// of the issuer's "tokenURL", renewed in the background before their expiration.
+// Close the returned Renewer to stop the renewals.
+func newClient(ctx context.Context, tokenURL, clientID, secret string) (*http.Client, *jwt.Renewer, error) {
+	skew := jwt.NewClockSkew()
+	tokenClient := &http.Client{Transport: skew.Transport(nil)} // observes the issuer's clock.
+
+	renewer := jwt.NewRenewer(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(clientID, secret)
+
+		resp, err := tokenClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("token: %s", resp.Status)
+		}
+
+		var tok tokenResponse
+		if err = json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+			return nil, err
+		}
+
+		return []byte(tok.AccessToken), nil
+	})
+	renewer.Expiry = func(token []byte) (time.Time, error) {
+		// Compare the expiration to the local clock, not the issuer's one.
+		t, err := jwt.Decode(token)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Unix(t.UnverifiedClaims.Expiry, 0).Add(-skew.Offset()), nil
+	}
+
+	if err := renewer.Start(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	client := &http.Client{Transport: bearerTransport{renewer: renewer, next: http.DefaultTransport}}
+	return client, renewer, nil
+}
+
+// bearerTransport is an http.RoundTripper which sets the renewer's token as the Authorization header.
+type bearerTransport struct {
+	renewer *jwt.Renewer
+	next    http.RoundTripper
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context()) // a RoundTripper should not modify the request.
+	r.Header.Set("Authorization", "Bearer "+string(t.renewer.Token()))
+	return t.next.RoundTrip(r)
+}
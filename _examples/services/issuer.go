@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+// issuerService is the identity provider: it issues access tokens to the registered clients
+// (client credentials through basic auth) and it publishes its public keys as a JWKS.
+type issuerService struct {
+	issuer  *jwt.Issuer
+	clients map[string]string // key = client id | value = client secret.
+
+	mu   sync.RWMutex
+	jwks jwt.JWKS
+}
+
+func newIssuerService(name, audience string, clients map[string]string) (*issuerService, error) {
+	s := &issuerService{
+		issuer:  jwt.NewIssuer(jwt.EdDSA, nil, 15*time.Minute),
+		clients: clients,
+	}
+	s.issuer.Issuer = name
+	s.issuer.Audience = []string{audience}
+
+	if err := s.rotate("key-1"); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// rotate generates a new signing key, the tokens of the previous keys are still verified
+// because their public keys remain published.
+func (s *issuerService) rotate(kid string) error {
+	privateKey, publicKey, err := jwt.GenerateKeyPair(jwt.EdDSA)
+	if err != nil {
+		return err
+	}
+
+	jwk, err := jwt.NewJWK(kid, jwt.EdDSA, publicKey)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.jwks.Keys = append(s.jwks.Keys, jwk)
+	s.mu.Unlock()
+
+	s.issuer.SetKey(kid, privateKey)
+	return nil
+}
+
+func (s *issuerService) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/jwks.json", s.jwksHandler)
+	mux.HandleFunc("/token", s.tokenHandler)
+	return mux
+}
+
+func (s *issuerService) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "max-age=300")
+	json.NewEncoder(w).Encode(s.jwks)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (s *issuerService) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientID, secret, ok := r.BasicAuth()
+	expected, registered := s.clients[clientID]
+	if !ok || !registered || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	token, err := s.issuer.TokenContext(r.Context(), clientID, jwt.Map{"scope": "orders:read"})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokenResponse{
+		AccessToken: string(token),
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.issuer.MaxAge / time.Second),
+	})
+}
@@ -0,0 +1,71 @@
+// Package main is an example of three services wired through the JWT package:
+// an issuer service which publishes its keys as a JWKS, a resource server
+// which verifies the tokens through its middleware and a client which
+// authenticates its requests through an http.RoundTripper.
+//
+// Run each one on a different terminal:
+//  go run . issuer
+//  go run . resource
+//  go run . client
+//
+// The main_test.go runs the same services through httptest.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+const (
+	issuerName = "http://localhost:8080"
+	audience   = "orders"
+)
+
+func main() {
+	var (
+		issuerURL   = flag.String("issuer", issuerName, "the URL of the issuer service")
+		resourceURL = flag.String("resource", "http://localhost:8081", "the URL of the resource server")
+	)
+	flag.Parse()
+
+	ctx := context.Background()
+	switch flag.Arg(0) {
+	case "issuer":
+		s, err := newIssuerService(issuerName, audience, map[string]string{"billing": "billing-secret"})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Issuer listening on: %s", issuerName)
+		log.Fatal(http.ListenAndServe(":8080", s.Handler()))
+	case "resource":
+		s := newResourceService(ctx, *issuerURL+"/.well-known/jwks.json", issuerName, audience)
+
+		log.Printf("Resource server listening on: %s", *resourceURL)
+		log.Fatal(http.ListenAndServe(":8081", s.Handler()))
+	case "client":
+		client, renewer, err := newClient(ctx, *issuerURL+"/token", "billing", "billing-secret")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer renewer.Close()
+
+		resp, err := client.Get(*resourceURL + "/orders")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		fmt.Printf("%s: ", resp.Status)
+		io.Copy(os.Stdout, resp.Body)
+		fmt.Println()
+	default:
+		fmt.Fprintln(os.Stderr, "usage: go run . [-issuer URL] [-resource URL] issuer|resource|client")
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServices(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	issuer, err := newIssuerService("issuer", audience, map[string]string{
+		"billing":  "billing-secret",
+		"shipping": "shipping-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuerSrv := httptest.NewServer(issuer.Handler())
+	defer issuerSrv.Close()
+
+	resource := newResourceService(ctx, issuerSrv.URL+"/.well-known/jwks.json", "issuer", audience)
+	resource.jwks.UnknownKidRefreshInterval = time.Nanosecond // don't wait for the rotation.
+	resourceSrv := httptest.NewServer(resource.Handler())
+	defer resourceSrv.Close()
+
+	expect := func(client *http.Client, method, path string, statusCode int, body string) {
+		t.Helper()
+
+		req, err := http.NewRequest(method, resourceSrv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != statusCode {
+			t.Fatalf("%s %s: expected status code: %d but got: %d (%s)", method, path, statusCode, resp.StatusCode, b)
+		}
+
+		if body != "" && string(b) != body {
+			t.Fatalf("%s %s: expected body: %q but got: %q", method, path, body, b)
+		}
+	}
+
+	if _, _, err = newClient(ctx, issuerSrv.URL+"/token", "billing", "wrong-secret"); err == nil {
+		t.Fatalf("expected a token error for the wrong client secret")
+	}
+
+	billing, renewer, err := newClient(ctx, issuerSrv.URL+"/token", "billing", "billing-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer renewer.Close()
+
+	expect(billing, http.MethodGet, "/orders", http.StatusOK, "orders of billing")
+	expect(http.DefaultClient, http.MethodGet, "/orders", http.StatusUnauthorized, "")
+	expect(http.DefaultClient, http.MethodGet, "/healthz", http.StatusOK, "")
+
+	// Tokens of a new key are verified through the refreshed JWKS,
+	// tokens of the previous key are still accepted.
+	if err = issuer.rotate("key-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	shipping, renewer, err := newClient(ctx, issuerSrv.URL+"/token", "shipping", "shipping-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer renewer.Close()
+
+	expect(shipping, http.MethodGet, "/orders", http.StatusOK, "orders of shipping")
+	expect(billing, http.MethodGet, "/orders", http.StatusOK, "orders of billing")
+
+	// Revoked tokens are rejected.
+	expect(billing, http.MethodPost, "/logout", http.StatusNoContent, "")
+	expect(billing, http.MethodGet, "/orders", http.StatusUnauthorized, "")
+	expect(shipping, http.MethodGet, "/orders", http.StatusOK, "orders of shipping")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+// resourceService is the API: it verifies the access tokens through the issuer's JWKS
+// and it serves the orders of the token's subject.
+type resourceService struct {
+	jwks      *jwt.JWKSClient
+	blocklist *jwt.Blocklist
+	verifier  *jwt.Verifier
+}
+
+func newResourceService(ctx context.Context, jwksURL, issuer, audience string) *resourceService {
+	jwks := jwt.NewJWKSClient(jwksURL)
+	jwks.StaleMaxAge = time.Hour
+
+	blocklist := jwt.NewBlocklistContext(ctx, time.Minute)
+
+	verifier := jwt.NewVerifier(jwt.EdDSA, nil, jwt.Expected{Issuer: issuer, Audience: []string{audience}})
+	verifier.KeyProvider = jwks
+	verifier.Blocklist = blocklist
+
+	return &resourceService{
+		jwks:      jwks,
+		blocklist: blocklist,
+		verifier:  verifier,
+	}
+}
+
+func (s *resourceService) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/orders", s.verifier.RouteHandler(http.HandlerFunc(s.ordersHandler), jwt.WithScopes("orders:read")))
+	mux.Handle("/logout", s.verifier.Handler(http.HandlerFunc(s.logoutHandler)))
+	mux.Handle("/healthz", jwt.HealthHandler(map[string]jwt.Healther{"jwks": s.jwks}))
+	return mux
+}
+
+func (s *resourceService) ordersHandler(w http.ResponseWriter, r *http.Request) {
+	verifiedToken, _ := jwt.GetVerifiedToken(r.Context())
+	fmt.Fprintf(w, "orders of %s", verifiedToken.StandardClaims.Subject)
+}
+
+// logoutHandler revokes the request's token until its expiration.
+func (s *resourceService) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	verifiedToken, _ := jwt.GetVerifiedToken(r.Context())
+	if err := s.blocklist.InvalidateToken(verifiedToken.Token, verifiedToken.StandardClaims); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
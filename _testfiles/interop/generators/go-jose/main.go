@@ -0,0 +1,147 @@
+// Command go-jose generates the go-jose.json fixtures with the github.com/go-jose/go-jose/v4 module
+// and verifies the kataras-jwt.json ones.
+// It's not part of the jwt module, run it from a temporary module:
+//
+//  mkdir /tmp/interop && cp _testfiles/interop/generators/go-jose/main.go /tmp/interop
+//  cd /tmp/interop && go mod init interop && go get github.com/go-jose/go-jose/v4
+//  go run . -testfiles $OLDPWD/_testfiles
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+type fixture struct {
+	Alg    string          `json:"alg"`
+	Token  string          `json:"token"`
+	Claims json.RawMessage `json:"claims"`
+	Key    string          `json:"key"`
+}
+
+type fixtures struct {
+	Generator string    `json:"generator"`
+	Tokens    []fixture `json:"tokens"`
+}
+
+var keys = []struct {
+	alg                   jose.SignatureAlgorithm
+	privateKey, publicKey string
+}{
+	{jose.HS256, "hmac.key", "hmac.key"},
+	{jose.RS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+	{jose.PS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+	{jose.ES256, "ecdsa_private_key.pem", "ecdsa_public_key.pem"},
+	{jose.EdDSA, "ed25519_private_key.pem", "ed25519_public_key.pem"},
+}
+
+func main() {
+	testfiles := flag.String("testfiles", "_testfiles", "the path of the _testfiles directory")
+	flag.Parse()
+
+	read := func(filename string) []byte {
+		b, err := os.ReadFile(filepath.Join(*testfiles, filename))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return b
+	}
+
+	claims := []byte(`{"iss":"interop","sub":"kataras","iat":1600000000}`)
+	result := fixtures{Generator: "github.com/go-jose/go-jose/v4"}
+	for _, k := range keys {
+		key := parseKey(k.alg, read(k.privateKey), true)
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: k.alg, Key: key}, (&jose.SignerOptions{}).WithType("JWT"))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		object, err := signer.Sign(claims)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		token, err := object.CompactSerialize()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		result.Tokens = append(result.Tokens, fixture{Alg: string(k.alg), Token: token, Claims: claims, Key: k.publicKey})
+	}
+
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(*testfiles, "interop", "go-jose.json"), append(b, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	// Verify the tokens generated by this library.
+	var ours fixtures
+	if err = json.Unmarshal(read("interop/kataras-jwt.json"), &ours); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, f := range ours.Tokens {
+		alg := jose.SignatureAlgorithm(f.Alg)
+		object, err := jose.ParseSigned(f.Token, []jose.SignatureAlgorithm{alg})
+		if err != nil {
+			log.Fatalf("%s: %v", f.Alg, err)
+		}
+
+		payload, err := object.Verify(parseKey(alg, read(f.Key), false))
+		if err != nil {
+			log.Fatalf("%s: %v", f.Alg, err)
+		}
+
+		var got, expected map[string]interface{}
+		json.Unmarshal(payload, &got)
+		json.Unmarshal(f.Claims, &expected)
+		if !reflect.DeepEqual(got, expected) {
+			log.Fatalf("%s: unexpected claims: %s", f.Alg, payload)
+		}
+	}
+
+	fmt.Println("go-jose.json generated, kataras-jwt.json verified")
+}
+
+func parseKey(alg jose.SignatureAlgorithm, b []byte, private bool) interface{} {
+	if alg == jose.HS256 {
+		return b
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		log.Fatalf("%s: invalid PEM", alg)
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch {
+	case !private:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case bytes.Contains(b, []byte("EC PRIVATE KEY")):
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case bytes.Contains(b, []byte("RSA PRIVATE KEY")):
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", alg, err)
+	}
+	return key
+}
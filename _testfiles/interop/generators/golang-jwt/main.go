@@ -0,0 +1,140 @@
+// Command golang-jwt generates the golang-jwt.json fixtures with the github.com/golang-jwt/jwt/v5 module
+// and verifies the kataras-jwt.json ones.
+// It's not part of the jwt module, run it from a temporary module:
+//
+//  mkdir /tmp/interop && cp _testfiles/interop/generators/golang-jwt/main.go /tmp/interop
+//  cd /tmp/interop && go mod init interop && go get github.com/golang-jwt/jwt/v5
+//  go run . -testfiles $OLDPWD/_testfiles
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type fixture struct {
+	Alg    string                 `json:"alg"`
+	Token  string                 `json:"token"`
+	Claims map[string]interface{} `json:"claims"`
+	Key    string                 `json:"key"`
+}
+
+type fixtures struct {
+	Generator string    `json:"generator"`
+	Tokens    []fixture `json:"tokens"`
+}
+
+var keys = []struct {
+	method                jwt.SigningMethod
+	privateKey, publicKey string
+}{
+	{jwt.SigningMethodHS256, "hmac.key", "hmac.key"},
+	{jwt.SigningMethodRS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+	{jwt.SigningMethodPS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+	{jwt.SigningMethodES256, "ecdsa_private_key.pem", "ecdsa_public_key.pem"},
+	{jwt.SigningMethodEdDSA, "ed25519_private_key.pem", "ed25519_public_key.pem"},
+}
+
+func main() {
+	testfiles := flag.String("testfiles", "_testfiles", "the path of the _testfiles directory")
+	flag.Parse()
+
+	read := func(filename string) []byte {
+		b, err := os.ReadFile(filepath.Join(*testfiles, filename))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return b
+	}
+
+	claims := jwt.MapClaims{"iss": "interop", "sub": "kataras", "iat": 1600000000}
+	result := fixtures{Generator: "github.com/golang-jwt/jwt/v5"}
+	for _, k := range keys {
+		token, err := jwt.NewWithClaims(k.method, claims).SignedString(privateKey(k.method, read(k.privateKey)))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		result.Tokens = append(result.Tokens, fixture{Alg: k.method.Alg(), Token: token, Claims: claims, Key: k.publicKey})
+	}
+
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(*testfiles, "interop", "golang-jwt.json"), append(b, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	// Verify the tokens generated by this library.
+	var ours fixtures
+	if err = json.Unmarshal(read("interop/kataras-jwt.json"), &ours); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, f := range ours.Tokens {
+		method := jwt.GetSigningMethod(f.Alg)
+		key := publicKey(method, read(f.Key))
+		token, err := jwt.Parse(f.Token, func(*jwt.Token) (interface{}, error) { return key, nil }, jwt.WithValidMethods([]string{f.Alg}))
+		if err != nil {
+			log.Fatalf("%s: %v", f.Alg, err)
+		}
+
+		got, err := json.Marshal(token.Claims)
+		if err != nil {
+			log.Fatal(err)
+		}
+		expected, _ := json.Marshal(f.Claims)
+		var gotMap, expectedMap map[string]interface{}
+		json.Unmarshal(got, &gotMap)
+		json.Unmarshal(expected, &expectedMap)
+		if !reflect.DeepEqual(gotMap, expectedMap) {
+			log.Fatalf("%s: unexpected claims: %s", f.Alg, got)
+		}
+	}
+
+	fmt.Println("golang-jwt.json generated, kataras-jwt.json verified")
+}
+
+func privateKey(method jwt.SigningMethod, b []byte) (key interface{}) {
+	var err error
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return b
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		key, err = jwt.ParseRSAPrivateKeyFromPEM(b)
+	case *jwt.SigningMethodECDSA:
+		key, err = jwt.ParseECPrivateKeyFromPEM(b)
+	default:
+		key, err = jwt.ParseEdPrivateKeyFromPEM(b)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return key
+}
+
+func publicKey(method jwt.SigningMethod, b []byte) (key interface{}) {
+	var err error
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return b
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		key, err = jwt.ParseRSAPublicKeyFromPEM(b)
+	case *jwt.SigningMethodECDSA:
+		key, err = jwt.ParseECPublicKeyFromPEM(b)
+	default:
+		key, err = jwt.ParseEdPublicKeyFromPEM(b)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return key
+}
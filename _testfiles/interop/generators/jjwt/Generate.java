@@ -0,0 +1,118 @@
+// Generates the jjwt.json fixtures with jjwt (0.12+) and verifies the kataras-jwt.json ones.
+// ES256 is not included: the JDK can not read the SEC1-encoded ecdsa_private_key.pem without BouncyCastle.
+//
+// Usage (JDK 17+, from the repository's root directory), with the jjwt-api, jjwt-impl
+// and jjwt-jackson jars (and their jackson-databind dependency) on the classpath:
+//  java -cp "$JJWT_CLASSPATH" _testfiles/interop/generators/jjwt/Generate.java
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.SerializationFeature;
+import io.jsonwebtoken.Claims;
+import io.jsonwebtoken.Jwts;
+import io.jsonwebtoken.security.Keys;
+import io.jsonwebtoken.security.SecureDigestAlgorithm;
+
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.security.Key;
+import java.security.KeyFactory;
+import java.security.PublicKey;
+import java.security.spec.PKCS8EncodedKeySpec;
+import java.security.spec.X509EncodedKeySpec;
+import java.util.ArrayList;
+import java.util.Base64;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+
+public class Generate {
+    static final Path TESTFILES = Path.of("_testfiles");
+
+    public static void main(String[] args) throws Exception {
+        Map<String, Object> claims = new LinkedHashMap<>();
+        claims.put("iss", "interop");
+        claims.put("sub", "kataras");
+        claims.put("iat", 1600000000);
+
+        // alg, private key, public key, key factory algorithm.
+        String[][] keys = {
+            {"HS256", "hmac.key", "hmac.key", ""},
+            {"RS256", "rsa_private_key.pem", "rsa_public_key.pem", "RSA"},
+            {"PS256", "rsa_private_key.pem", "rsa_public_key.pem", "RSA"},
+            {"EdDSA", "ed25519_private_key.pem", "ed25519_public_key.pem", "Ed25519"},
+        };
+
+        List<Map<String, Object>> tokens = new ArrayList<>();
+        for (String[] k : keys) {
+            String token = Jwts.builder()
+                .header().type("JWT").and()
+                .claims(claims)
+                .signWith(privateKey(k), algorithm(k[0]))
+                .compact();
+
+            Map<String, Object> fixture = new LinkedHashMap<>();
+            fixture.put("alg", k[0]);
+            fixture.put("token", token);
+            fixture.put("claims", claims);
+            fixture.put("key", k[2]);
+            tokens.add(fixture);
+        }
+
+        Map<String, Object> fixtures = new LinkedHashMap<>();
+        fixtures.put("generator", "jjwt");
+        fixtures.put("tokens", tokens);
+
+        ObjectMapper mapper = new ObjectMapper().enable(SerializationFeature.INDENT_OUTPUT);
+        Files.writeString(TESTFILES.resolve("interop/jjwt.json"), mapper.writeValueAsString(fixtures) + "\n");
+
+        // Verify the tokens generated by this library.
+        Map<?, ?> ours = mapper.readValue(TESTFILES.resolve("interop/kataras-jwt.json").toFile(), Map.class);
+        for (Object o : (List<?>) ours.get("tokens")) {
+            Map<?, ?> fixture = (Map<?, ?>) o;
+            String alg = (String) fixture.get("alg");
+            String[] k = null;
+            for (String[] candidate : keys) {
+                if (candidate[0].equals(alg)) {
+                    k = candidate;
+                }
+            }
+            if (k == null) {
+                continue; // ES256.
+            }
+
+            Claims got = parse(k, (String) fixture.get("token"));
+            if (!got.getSubject().equals("kataras") || !got.getIssuer().equals("interop")) {
+                throw new IllegalStateException(alg + ": unexpected claims: " + got);
+            }
+        }
+
+        System.out.println("jjwt.json generated, kataras-jwt.json verified");
+    }
+
+    static SecureDigestAlgorithm<Key, ?> algorithm(String alg) {
+        @SuppressWarnings("unchecked")
+        SecureDigestAlgorithm<Key, ?> algorithm = (SecureDigestAlgorithm<Key, ?>) Jwts.SIG.get().get(alg);
+        return algorithm;
+    }
+
+    static Key privateKey(String[] k) throws Exception {
+        if (k[3].isEmpty()) {
+            return Keys.hmacShaKeyFor(Files.readAllBytes(TESTFILES.resolve(k[1])));
+        }
+        return KeyFactory.getInstance(k[3]).generatePrivate(new PKCS8EncodedKeySpec(pem(k[1])));
+    }
+
+    static Claims parse(String[] k, String token) throws Exception {
+        if (k[3].isEmpty()) {
+            return Jwts.parser().verifyWith(Keys.hmacShaKeyFor(Files.readAllBytes(TESTFILES.resolve(k[2]))))
+                .build().parseSignedClaims(token).getPayload();
+        }
+
+        PublicKey key = KeyFactory.getInstance(k[3]).generatePublic(new X509EncodedKeySpec(pem(k[2])));
+        return Jwts.parser().verifyWith(key).build().parseSignedClaims(token).getPayload();
+    }
+
+    static byte[] pem(String filename) throws Exception {
+        String contents = Files.readString(TESTFILES.resolve(filename)).replaceAll("-----[A-Z ]+-----", "").replaceAll("\\s", "");
+        return Base64.getDecoder().decode(contents);
+    }
+}
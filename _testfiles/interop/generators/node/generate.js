@@ -0,0 +1,66 @@
+// Generates the node-crypto.json fixtures with the Node.js built-in crypto module
+// (an implementation of JWS independent of any JWT library)
+// and verifies the tokens of the kataras-jwt.json fixtures.
+//
+// Usage (Node.js 16+):
+//  node _testfiles/interop/generators/node/generate.js
+"use strict";
+
+const crypto = require("crypto");
+const fs = require("fs");
+const path = require("path");
+
+const testfiles = path.join(__dirname, "..", "..", "..");
+const interop = path.join(testfiles, "interop");
+const claims = { iss: "interop", sub: "kataras", iat: 1600000000 };
+
+const b64 = (b) => Buffer.from(b).toString("base64url");
+const privateKey = (name) => crypto.createPrivateKey(fs.readFileSync(path.join(testfiles, name)));
+const jwk = (key) => crypto.createPublicKey(key).export({ format: "jwk" });
+
+const algs = {
+  HS256: { key: fs.readFileSync(path.join(testfiles, "hmac.key")), sign: (key, data) => crypto.createHmac("sha256", key).update(data).digest() },
+  RS256: { key: privateKey("rsa_private_key.pem"), sign: (key, data) => crypto.sign("sha256", data, key) },
+  PS256: {
+    key: privateKey("rsa_private_key.pem"),
+    sign: (key, data) => crypto.sign("sha256", data, { key, padding: crypto.constants.RSA_PKCS1_PSS_PADDING, saltLength: 32 }),
+  },
+  ES256: { key: privateKey("ecdsa_private_key.pem"), sign: (key, data) => crypto.sign("sha256", data, { key, dsaEncoding: "ieee-p1363" }) },
+  EdDSA: { key: privateKey("ed25519_private_key.pem"), sign: (key, data) => crypto.sign(null, data, key) },
+};
+
+const verifiers = {
+  HS256: (key, data, sig) => crypto.timingSafeEqual(crypto.createHmac("sha256", key).update(data).digest(), sig),
+  RS256: (key, data, sig) => crypto.verify("sha256", data, key, sig),
+  PS256: (key, data, sig) => crypto.verify("sha256", data, { key, padding: crypto.constants.RSA_PKCS1_PSS_PADDING, saltLength: 32 }, sig),
+  ES256: (key, data, sig) => crypto.verify("sha256", data, { key, dsaEncoding: "ieee-p1363" }, sig),
+  EdDSA: (key, data, sig) => crypto.verify(null, data, key, sig),
+};
+
+const tokens = Object.entries(algs).map(([alg, { key, sign }]) => {
+  const unsigned = b64(JSON.stringify({ alg, typ: "JWT" })) + "." + b64(JSON.stringify(claims));
+  const token = unsigned + "." + b64(sign(key, unsigned));
+  const fixture = { alg, token, claims };
+  if (alg.startsWith("HS")) {
+    fixture.secret = b64(key);
+  } else {
+    fixture.jwk = jwk(key);
+  }
+  return fixture;
+});
+
+const generator = "node " + process.version + " crypto";
+fs.writeFileSync(path.join(interop, "node-crypto.json"), JSON.stringify({ generator, tokens }, null, 2) + "\n");
+
+// Verify the tokens generated by this library.
+const ours = JSON.parse(fs.readFileSync(path.join(interop, "kataras-jwt.json")));
+for (const { alg, token, secret, jwk: publicJWK } of ours.tokens) {
+  const [header, payload, signature] = token.split(".");
+  const key = secret ? Buffer.from(secret, "base64url") : crypto.createPublicKey({ key: publicJWK, format: "jwk" });
+  if (!verifiers[alg](key, Buffer.from(header + "." + payload), Buffer.from(signature, "base64url"))) {
+    throw new Error(alg + ": invalid signature of kataras-jwt token");
+  }
+  JSON.parse(Buffer.from(payload, "base64url"));
+}
+
+console.log("node-crypto.json generated, kataras-jwt.json verified");
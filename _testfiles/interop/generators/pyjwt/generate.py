@@ -0,0 +1,64 @@
+"""Generates the pyjwt.json fixtures with PyJWT and verifies the kataras-jwt.json ones.
+
+Usage (from the repository's root directory):
+    pip install "pyjwt[crypto]>=2.4"
+    python _testfiles/interop/generators/pyjwt/generate.py
+"""
+import json
+import os
+
+import jwt
+from jwt.algorithms import get_default_algorithms
+
+TESTFILES = os.path.join(os.path.dirname(__file__), "..", "..", "..")
+INTEROP = os.path.join(TESTFILES, "interop")
+CLAIMS = {"iss": "interop", "sub": "kataras", "iat": 1600000000}
+
+# alg: (private key, public key)
+KEYS = {
+    "HS256": ("hmac.key", "hmac.key"),
+    "RS256": ("rsa_private_key.pem", "rsa_public_key.pem"),
+    "PS256": ("rsa_private_key.pem", "rsa_public_key.pem"),
+    "ES256": ("ecdsa_private_key.pem", "ecdsa_public_key.pem"),
+    "EdDSA": ("ed25519_private_key.pem", "ed25519_public_key.pem"),
+}
+
+
+def read(filename):
+    with open(os.path.join(TESTFILES, filename), "rb") as f:
+        return f.read()
+
+
+def main():
+    algorithms = get_default_algorithms()
+    tokens = []
+    for alg, (private_key, public_key) in KEYS.items():
+        key = algorithms[alg].prepare_key(read(private_key))
+        token = jwt.encode(CLAIMS, key, algorithm=alg)
+        tokens.append({"alg": alg, "token": token, "claims": CLAIMS, "key": public_key})
+
+    fixtures = {"generator": "pyjwt " + jwt.__version__, "tokens": tokens}
+    with open(os.path.join(INTEROP, "pyjwt.json"), "w") as f:
+        json.dump(fixtures, f, indent=2)
+        f.write("\n")
+
+    # Verify the tokens generated by this library.
+    with open(os.path.join(INTEROP, "kataras-jwt.json")) as f:
+        ours = json.load(f)
+
+    for fixture in ours["tokens"]:
+        alg = fixture["alg"]
+        if "secret" in fixture:
+            key = jwt.utils.base64url_decode(fixture["secret"])
+        else:
+            key = jwt.PyJWK(fixture["jwk"], algorithm=alg).key
+
+        claims = jwt.decode(fixture["token"], key, algorithms=[alg])
+        if claims != fixture["claims"]:
+            raise ValueError("%s: unexpected claims: %s" % (alg, claims))
+
+    print("pyjwt.json generated, kataras-jwt.json verified")
+
+
+if __name__ == "__main__":
+    main()
@@ -16,19 +16,36 @@ var (
 )
 
 // Alg represents a signing and verifying algorithm.
+// It's the combination of the `AlgSigner` and `AlgVerifier` capabilities.
 type Alg interface {
+	AlgSigner
+	AlgVerifier
+	// Note:
+	// some signing algorithms may be asymmetric,
+	// so we accept the headerAndPayload as it's, instead of a Sign's result.
+}
+
+// AlgSigner represents the signing capability of an algorithm.
+// The `Sign` functions accept an AlgSigner,
+// so a remote signer (e.g. a KMS) does not have to implement the verification part.
+type AlgSigner interface {
 	// Name should return the "alg" JWT field.
 	Name() string
 	// Sign should accept the private key given on jwt.Sign and
 	// the base64-encoded header and payload data.
 	// Should return the signature.
 	Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
+}
+
+// AlgVerifier represents the verifying capability of an algorithm.
+// The `Verify` functions accept an AlgVerifier,
+// so verification-only deployments can use public-key-only implementations.
+type AlgVerifier interface {
+	// Name should return the "alg" JWT field.
+	Name() string
 	// Verify should verify the JWT "signature" (base64-decoded) against
 	// the header and payload (base64-encoded).
 	Verify(key PublicKey, headerAndPayload []byte, signature []byte) error
-	// Note:
-	// some signing algorithms may be asymmetric,
-	// so we accept the headerAndPayload as it's, instead of a Sign's result.
 }
 
 // The builtin signing available algorithms.
@@ -62,6 +79,16 @@ var (
 	//    "lastpage": "/views/settings"
 	//  }
 	NONE Alg = &algNONE{}
+	// TaggedNONE is a middle ground between the NONE and the HMAC algorithms
+	// for the same client-side use case: the token is tagged with a truncated (128 bits)
+	// HMAC-SHA256 of a per-session key, see `SessionTagKey`.
+	// The client, which receives the session key (e.g. on login), can check that
+	// the view data was not tampered with, without a full server verification.
+	// It's tamper-evidence against anyone without the session key, the server
+	// should not trust these tokens because the client holds the key too.
+	// The algorithm's name is: "NONE+HS256".
+	// Sign and Verify key: []byte (the session key).
+	TaggedNONE Alg = &algTaggedNONE{}
 	// HMAC-SHA signing algorithms.
 	// Keys should be type of []byte.
 	//
@@ -80,9 +107,9 @@ var (
 	// secret. Another good option is to switch to RS256 or other public-key algorithms, which are much
 	// more robust and flexible. This is NOT SIMPLY A HYPOTHETICAL ATTACK, it has been shown that brute
 	// force attacks for HS256 are simple enough to perform11 if the shared secret is too short.
-	HS256 Alg = &algHMAC{"HS256", crypto.SHA256}
-	HS384 Alg = &algHMAC{"HS384", crypto.SHA384}
-	HS512 Alg = &algHMAC{"HS512", crypto.SHA512}
+	HS256 Alg = &algHMAC{name: "HS256", hasher: crypto.SHA256}
+	HS384 Alg = &algHMAC{name: "HS384", hasher: crypto.SHA384}
+	HS512 Alg = &algHMAC{name: "HS512", hasher: crypto.SHA512}
 	// RSA signing algorithms.
 	// Sign   key: *rsa.PrivateKey
 	// Verify key: *rsa.PublicKey (or *rsa.PrivateKey with its PublicKey filled)
@@ -133,9 +160,9 @@ var (
 	// The generated files are in PEM format as well,
 	// so simply pasting them in your source will suffice.
 	// It generates a smaller token (almost 3 times less).
-	ES256 Alg = &algECDSA{"ES256", crypto.SHA256, 32, 256}
-	ES384 Alg = &algECDSA{"ES384", crypto.SHA384, 48, 384}
-	ES512 Alg = &algECDSA{"ES512", crypto.SHA512, 66, 521}
+	ES256 Alg = &algECDSA{name: "ES256", hasher: crypto.SHA256, keySize: 32, curveBits: 256}
+	ES384 Alg = &algECDSA{name: "ES384", hasher: crypto.SHA384, keySize: 48, curveBits: 384}
+	ES512 Alg = &algECDSA{name: "ES512", hasher: crypto.SHA512, keySize: 66, curveBits: 521}
 	// Ed25519 Edwards-curve Digital Signature Algorithm.
 	// The algorithm's name is: "EdDSA".
 	// Sign   key: ed25519.PrivateKey
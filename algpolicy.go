@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrAlgNotAllowed indicates that the token's "alg" header is not one of the `AlgPolicy` algorithms,
+	// e.g. a downgrade to a weaker algorithm.
+	ErrAlgNotAllowed = fmt.Errorf("%w: not allowed", ErrTokenAlg)
+	// ErrAlgNone indicates an unsecured ("none") token which the `AlgPolicy` does not opt into.
+	ErrAlgNone = fmt.Errorf("%w: none", ErrTokenAlg)
+	// ErrAlgConfusion indicates an HMAC token for an `AlgPolicy` of asymmetric algorithms only,
+	// e.g. the RS256 to HS256 attack, which signs a token with the public key as an HMAC secret.
+	ErrAlgConfusion = fmt.Errorf("%w: key confusion", ErrTokenAlg)
+
+	// ErrHeader indicates that a required header field is missing or invalid, see `AlgPolicy`.
+	ErrHeader = errors.New("invalid token header")
+	// ErrHeaderType indicates that the "typ" header is missing or it's not "JWT".
+	ErrHeaderType = fmt.Errorf("%w: typ", ErrHeader)
+	// ErrHeaderKeyID indicates that the "kid" header is missing.
+	ErrHeaderKeyID = fmt.Errorf("%w: kid", ErrHeader)
+	// ErrHeaderCritical indicates that the "crit" header lists an extension which is not
+	// one of the `CriticalHeaders` or an unencoded payload ("b64": false) of a non-detached token.
+	ErrHeaderCritical = fmt.Errorf("%w: crit", ErrHeader)
+)
+
+// AlgPolicy is a TokenValidator which guards against the algorithm confusion attacks.
+// The token's "alg" header must exactly match one of its algorithms, the builtin verification
+// already requires it to match the verification algorithm, so the policy protects the verifiers
+// of algorithms picked at runtime (e.g. through a `KeyResolver` or a `Keys` set) too.
+//
+// It returns distinct errors for each attack class, all of them are a type of ErrTokenAlg:
+//  - ErrAlgNone for unsecured tokens, unless the AllowNone field is true and NONE is listed;
+//  - ErrAlgConfusion for HMAC tokens when the policy lists asymmetric algorithms only;
+//  - ErrAlgNotAllowed for any other algorithm which is not listed (e.g. a downgrade).
+// The ErrHeaderType and ErrHeaderKeyID errors are returned on missing header fields.
+//
+// Usage:
+//  policy := jwt.AlgPolicy{Algs: []jwt.Alg{jwt.RS256}, RequireType: true, RequireKeyID: true}
+//  verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, token, policy)
+type AlgPolicy struct {
+	// Algs is the allowlist of algorithms, it's required.
+	Algs []Alg
+	// AllowNone opts into unsecured tokens of the NONE algorithm,
+	// if the Algs field lists it too.
+	AllowNone bool
+	// RequireType requires a "typ" header of "JWT".
+	RequireType bool
+	// RequireKeyID requires a "kid" header.
+	RequireKeyID bool
+}
+
+var _ TokenValidator = AlgPolicy{}
+
+// ValidateToken completes the TokenValidator interface.
+func (p AlgPolicy) ValidateToken(token []byte, c Claims, err error) error {
+	if err != nil {
+		return err
+	}
+
+	var h tokenHeader
+	if err = unverifiedHeader(token, &h); err != nil { // it's verified at this point.
+		return err
+	}
+
+	if strings.EqualFold(h.Alg, NONE.Name()) {
+		if p.AllowNone && p.allows(h.Alg) {
+			return p.validateFields(h)
+		}
+
+		return ErrAlgNone
+	}
+
+	if !p.allows(h.Alg) {
+		if isHMAC(h.Alg) && len(p.Algs) > 0 && !p.hasHMAC() {
+			return ErrAlgConfusion
+		}
+
+		return ErrAlgNotAllowed
+	}
+
+	return p.validateFields(h)
+}
+
+// allows reports whether the "name" algorithm is listed, case-sensitive.
+func (p AlgPolicy) allows(name string) bool {
+	for _, alg := range p.Algs {
+		if alg.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p AlgPolicy) hasHMAC() bool {
+	for _, alg := range p.Algs {
+		if isHMAC(alg.Name()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHMAC reports whether the "name" algorithm is a (case-insensitive) HMAC one.
+func isHMAC(name string) bool {
+	return len(name) > 2 && strings.EqualFold(name[:2], "HS")
+}
+
+func (p AlgPolicy) validateFields(h tokenHeader) error {
+	if p.RequireType && h.Typ != "JWT" {
+		return ErrHeaderType
+	}
+
+	if p.RequireKeyID && h.Kid == "" {
+		return ErrHeaderKeyID
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAlgPolicy(t *testing.T) {
+	claims := Map{"username": "kataras"}
+	sign := func(alg Alg, key PrivateKey, header Header) []byte {
+		token, err := SignWithHeader(alg, key, header, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	hs256 := sign(HS256, testSecret, Header{{"alg", "HS256"}, {"typ", "JWT"}, {"kid", "key-1"}})
+	none := sign(NONE, nil, Header{{"alg", "NONE"}, {"typ", "JWT"}})
+
+	tests := []struct {
+		alg    Alg
+		key    PublicKey
+		token  []byte
+		policy AlgPolicy
+		err    error
+	}{
+		{HS256, testSecret, hs256, AlgPolicy{Algs: []Alg{HS256}, RequireType: true, RequireKeyID: true}, nil},
+		{HS256, testSecret, hs256, AlgPolicy{Algs: []Alg{HS512, HS384}}, ErrAlgNotAllowed},
+		{HS256, testSecret, hs256, AlgPolicy{}, ErrAlgNotAllowed},
+		// E.g. a verifier of RS256 keys which picked HS256 with the public key through the header.
+		{HS256, testSecret, hs256, AlgPolicy{Algs: []Alg{RS256, ES256}}, ErrAlgConfusion},
+		{NONE, nil, none, AlgPolicy{Algs: []Alg{HS256}}, ErrAlgNone},
+		{NONE, nil, none, AlgPolicy{Algs: []Alg{NONE}}, ErrAlgNone},
+		{NONE, nil, none, AlgPolicy{Algs: []Alg{NONE}, AllowNone: true}, nil},
+		{HS256, testSecret, sign(HS256, testSecret, Header{{"alg", "HS256"}}), AlgPolicy{Algs: []Alg{HS256}, RequireType: true}, ErrHeaderType},
+		{HS256, testSecret, sign(HS256, testSecret, Header{{"alg", "HS256"}, {"typ", "JWT"}}), AlgPolicy{Algs: []Alg{HS256}, RequireKeyID: true}, ErrHeaderKeyID},
+	}
+
+	for i, tt := range tests {
+		_, err := Verify(tt.alg, tt.key, tt.token, tt.policy)
+		if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+
+		if tt.err != nil && !errors.Is(err, ErrTokenAlg) && !errors.Is(err, ErrHeader) {
+			t.Fatalf("[%d] expected a type of ErrTokenAlg or ErrHeader but got: %v", i, err)
+		}
+	}
+
+	if reason := FailureReason(ErrHeaderKeyID); reason != "header" {
+		t.Fatalf("expected failure reason: header but got: %s", reason)
+	}
+
+	if reason := FailureReason(ErrAlgConfusion); reason != "alg" {
+		t.Fatalf("expected failure reason: alg but got: %s", reason)
+	}
+}
@@ -0,0 +1,206 @@
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrAPIKey indicates an unknown (or revoked) API key, see `APIKeyLookup`.
+var ErrAPIKey = errors.New("invalid api key")
+
+// defaultAPIKeyCacheEntries is the default maximum number of cached tokens of an `APIKeyBridge`.
+const defaultAPIKeyCacheEntries = 10000
+
+// APIKey is the owner and the grants of a static API key, see `APIKeyLookup`.
+type APIKey struct {
+	// Subject is the "sub" claim of the key's tokens, e.g. the client's id.
+	Subject string
+	// Scopes are set as the "scope" claim of the key's tokens,
+	// a space-delimited string (RFC 8693), see `WithScopes`.
+	Scopes []string
+	// Claims are extra claims of the key's tokens, optional.
+	Claims Map
+}
+
+// APIKeyLookup validates a static API key and returns its owner and grants,
+// e.g. through a database lookup of the key's hash.
+// It should return a type of ErrAPIKey for an unknown (or revoked) key.
+type APIKeyLookup func(ctx context.Context, key string) (*APIKey, error)
+
+// APIKeyBridge exchanges the static API keys of the external clients for short-lived internal tokens,
+// so the internal services only ever see JWTs. The tokens are signed by the Issuer,
+// with the key's scopes as the "scope" claim, and they are cached (by the key's hash)
+// until they are about to expire, so the lookup is not called on every request.
+// Note that a revoked key is accepted until its cached token is renewed, like its token is.
+//
+// Usage:
+//  bridge := jwt.NewAPIKeyBridge(lookup, jwt.NewIssuer(jwt.EdDSA, privateKey, 5*time.Minute))
+//  http.Handle("/", bridge.Handler(reverseProxy)) // "X-API-Key: $key" to "Authorization: Bearer $token".
+type APIKeyBridge struct {
+	// Lookup validates the API keys, required.
+	Lookup APIKeyLookup
+	// Issuer signs the internal tokens, required.
+	Issuer *Issuer
+	// Header is the request header of the API key, see `Handler`. Defaults to "X-API-Key".
+	Header string
+	// MinTTL is the minimum remaining lifetime of a cached token,
+	// a token which expires sooner is signed again. Defaults to 30 seconds.
+	// Caching is disabled if it's negative.
+	MinTTL time.Duration
+	// CacheMaxEntries is the maximum number of cached tokens,
+	// the least recently used token is evicted on a full cache. Defaults to 10000.
+	CacheMaxEntries int
+	// ErrorHandler renders the errors of the HTTP middleware (see `Handler`).
+	// Defaults to the `DefaultErrorHandler`.
+	ErrorHandler ErrorHandler
+
+	mu    sync.Mutex
+	cache *lru[[sha256.Size]byte, exchangeEntry]
+}
+
+// NewAPIKeyBridge returns a new APIKeyBridge.
+// The rest of the APIKeyBridge fields can be modified before its first use.
+func NewAPIKeyBridge(lookup APIKeyLookup, issuer *Issuer) *APIKeyBridge {
+	return &APIKeyBridge{
+		Lookup: lookup,
+		Issuer: issuer,
+		Header: "X-API-Key",
+		MinTTL: 30 * time.Second,
+	}
+}
+
+// Exchange validates the "apiKey" and returns its internal token.
+// Returns ErrMissing if the "apiKey" is empty and the Lookup's error
+// (e.g. ErrAPIKey) if it's not valid.
+func (b *APIKeyBridge) Exchange(ctx context.Context, apiKey string) ([]byte, error) {
+	if apiKey == "" {
+		return nil, ErrMissing
+	}
+
+	cacheKey := sha256.Sum256([]byte(apiKey)) // the raw keys are never kept in memory.
+	if token, ok := b.cached(cacheKey); ok {
+		return token, nil
+	}
+
+	key, err := b.Lookup(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		return nil, ErrAPIKey
+	}
+
+	claims := make(Map, len(key.Claims)+1)
+	for k, v := range key.Claims {
+		claims[k] = v
+	}
+
+	if len(key.Scopes) > 0 {
+		claims["scope"] = strings.Join(key.Scopes, " ")
+	}
+
+	token, err := b.Issuer.TokenContext(ctx, key.Subject, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	b.store(cacheKey, token)
+	return token, nil
+}
+
+// Handler returns an HTTP middleware which exchanges the API key of the request's Header
+// for an internal token: the next handler sees an "Authorization: Bearer $token" request header
+// instead of the API key one. The errors are rendered by the ErrorHandler.
+func (b *APIKeyBridge) Handler(next http.Handler) http.Handler {
+	header := b.Header
+	if header == "" {
+		header = "X-API-Key"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := b.Exchange(r.Context(), strings.TrimSpace(r.Header.Get(header)))
+		if err != nil {
+			errorHandler := b.ErrorHandler
+			if errorHandler == nil {
+				errorHandler = DefaultErrorHandler
+			}
+
+			errorHandler(w, r, err)
+			return
+		}
+
+		r = r.Clone(r.Context())
+		r.Header.Del(header)
+		r.Header.Set("Authorization", "Bearer "+string(token))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (b *APIKeyBridge) cached(key [sha256.Size]byte) ([]byte, bool) {
+	if b.MinTTL < 0 {
+		return nil, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cache == nil {
+		return nil, false
+	}
+
+	entry, ok := b.cache.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	if !Clock().Before(entry.expiresAt) {
+		b.cache.remove(key)
+		return nil, false
+	}
+
+	return entry.token, true
+}
+
+func (b *APIKeyBridge) store(key [sha256.Size]byte, token []byte) {
+	if b.MinTTL < 0 {
+		return
+	}
+
+	var claims Claims
+	if err := unverifiedPayload(token, &claims); err != nil || claims.Expiry == 0 {
+		return // not cached, e.g. an encrypted payload.
+	}
+
+	expiresAt := time.Unix(claims.Expiry, 0).Add(-b.MinTTL)
+	if !Clock().Before(expiresAt) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cache == nil {
+		maxEntries := b.CacheMaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultAPIKeyCacheEntries
+		}
+		b.cache = newLRU[[sha256.Size]byte, exchangeEntry](maxEntries)
+	}
+
+	b.cache.set(key, exchangeEntry{token: token, expiresAt: expiresAt})
+}
+
+// Invalidate completes the CacheInvalidator interface, it drops the cached tokens
+// on any event, e.g. a cached token may be revoked or signed by a retired key.
+func (b *APIKeyBridge) Invalidate(event Invalidation) {
+	b.mu.Lock()
+	b.cache = nil
+	b.mu.Unlock()
+}
@@ -0,0 +1,62 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyBridge(t *testing.T) {
+	lookups := 0
+	lookup := func(_ context.Context, key string) (*APIKey, error) {
+		lookups++
+		if key != "s3cr3t" {
+			return nil, ErrAPIKey
+		}
+
+		return &APIKey{Subject: "partner-1", Scopes: []string{"orders:read", "orders:write"}}, nil
+	}
+
+	bridge := NewAPIKeyBridge(lookup, NewIssuer(testAlg, testSecret, 5*time.Minute))
+	verifier := NewVerifier(testAlg, testSecret)
+
+	handler := bridge.Handler(verifier.RouteHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "" {
+			t.Fatalf("expected the api key header to be removed")
+		}
+
+		verifiedToken, _ := GetVerifiedToken(r.Context())
+		w.Write([]byte(verifiedToken.StandardClaims.Subject))
+	}), WithScopes("orders:write")))
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-API-Key", "s3cr3t")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK || w.Body.String() != "partner-1" {
+			t.Fatalf("[%d] expected 200 partner-1 but got: %d %s", i, w.Code, w.Body.String())
+		}
+	}
+
+	if lookups != 1 {
+		t.Fatalf("expected a single lookup (cached token) but got: %d", lookups)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-API-Key", "invalid")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusUnauthorized, w.Code)
+	}
+
+	if _, err := bridge.Exchange(context.Background(), ""); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+}
@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned by `AsyncBlocklist.InvalidateToken`
+	// when its write-ahead queue is full.
+	ErrQueueFull = errors.New("blocklist write queue is full")
+	// ErrBlocklistClosed is returned by the `AsyncBlocklist` methods after its Close.
+	ErrBlocklistClosed = errors.New("blocklist is closed")
+)
+
+// AsyncBlocklist is a write-ahead queue in front of a persistent blocklist
+// (e.g. a database-backed TokenInvalidator) so token revocations,
+// e.g. on user logout, don't wait for the storage.
+// The revoked tokens are added to its (optional) in-memory Blocklist immediately
+// and they are written to the Store by a background flusher, in order.
+//
+// Call its Close method on shutdown to write the queued revocations.
+//
+// Usage:
+//  blocklist := jwt.NewAsyncBlocklist(redisBlocklist, 1024)
+//  blocklist.Blocklist = jwt.NewBlocklist(time.Hour)
+//  defer blocklist.Close(context.Background())
+//
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, blocklist)
+//  [...]
+//  err = blocklist.InvalidateToken(verifiedToken.Token, verifiedToken.StandardClaims)
+type AsyncBlocklist struct {
+	// Store is the persistent blocklist, it is called by a single goroutine.
+	Store TokenInvalidator
+	// Blocklist is an optional in-memory blocklist
+	// which blocks the revoked tokens before they are written to the Store.
+	// ValidateToken uses this one, if set, otherwise the Store,
+	// if it implements the TokenValidator interface.
+	Blocklist *Blocklist
+	// OnError is an optional hook which is called on Store failures,
+	// the failed revocations are not written again.
+	OnError func(err error)
+	// Invalidations is an optional publisher of the queued revocations to the caches of verification decisions,
+	// see `Invalidations`. The in-memory Blocklist publishes its own ones through its own field.
+	Invalidations *Invalidations
+
+	queue  chan asyncInvalidation
+	done   chan struct{}
+	mu     sync.RWMutex // protects the queue against send after close.
+	closed bool
+}
+
+type asyncInvalidation struct {
+	token  []byte
+	claims Claims
+	flush  chan struct{} // a Flush marker, the rest fields are empty.
+}
+
+var (
+	_ TokenValidator   = (*AsyncBlocklist)(nil)
+	_ PayloadValidator = (*AsyncBlocklist)(nil)
+	_ TokenInvalidator = (*AsyncBlocklist)(nil)
+)
+
+// NewAsyncBlocklist returns a new AsyncBlocklist which writes to the "store"
+// through a queue of, at most, "queueSize" pending revocations.
+// Its flusher goroutine runs until Close.
+func NewAsyncBlocklist(store TokenInvalidator, queueSize int) *AsyncBlocklist {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+
+	b := &AsyncBlocklist{
+		Store: store,
+		queue: make(chan asyncInvalidation, queueSize),
+		done:  make(chan struct{}),
+	}
+
+	go b.run()
+	return b
+}
+
+func (b *AsyncBlocklist) run() {
+	defer close(b.done)
+
+	for item := range b.queue {
+		if item.flush != nil {
+			close(item.flush)
+			continue
+		}
+
+		if err := b.Store.InvalidateToken(item.token, item.claims); err != nil && b.OnError != nil {
+			b.OnError(err)
+		}
+	}
+}
+
+// ValidateToken completes the `TokenValidator` interface.
+// It validates the token through the in-memory Blocklist, if set, otherwise through the Store.
+func (b *AsyncBlocklist) ValidateToken(token []byte, c Claims, err error) error {
+	if b.Blocklist != nil {
+		return b.Blocklist.ValidateToken(token, c, err)
+	}
+
+	if v, ok := b.Store.(TokenValidator); ok {
+		return v.ValidateToken(token, c, err)
+	}
+
+	return err
+}
+
+// ValidatePayload completes the `PayloadValidator` interface, so the tenant revocations
+// of the in-memory Blocklist are respected too (see `Blocklist.RevokeTenant`).
+func (b *AsyncBlocklist) ValidatePayload(ctx context.Context, token, payload []byte, c Claims, err error) error {
+	if b.Blocklist != nil {
+		return b.Blocklist.ValidatePayload(ctx, token, payload, c, err)
+	}
+
+	if v, ok := b.Store.(TokenValidator); ok {
+		return validateToken(ctx, v, token, payload, c, err)
+	}
+
+	return err
+}
+
+// InvalidateToken adds the token to the in-memory Blocklist, if set,
+// and queues its write to the Store without waiting for it.
+// It returns ErrQueueFull when the queue is full, in that case
+// the token is blocked by the in-memory Blocklist only.
+func (b *AsyncBlocklist) InvalidateToken(token []byte, c Claims) error {
+	if len(token) == 0 {
+		return ErrMissing
+	}
+
+	if b.Blocklist != nil {
+		if err := b.Blocklist.InvalidateToken(token, c); err != nil {
+			return err
+		}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBlocklistClosed
+	}
+
+	// Copy the token, the caller may reuse its buffer.
+	item := asyncInvalidation{token: append([]byte(nil), token...), claims: c}
+	select {
+	case b.queue <- item:
+	default:
+		return ErrQueueFull
+	}
+
+	if b.Invalidations != nil {
+		b.Invalidations.Publish(Invalidation{Kind: InvalidateRevocation})
+	}
+
+	return nil
+}
+
+// Flush waits until the revocations queued before its call are written to the Store.
+func (b *AsyncBlocklist) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBlocklistClosed
+	}
+
+	select {
+	case b.queue <- asyncInvalidation{flush: flushed}:
+		b.mu.RUnlock()
+	case <-ctx.Done():
+		b.mu.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting revocations and waits until the queued ones are written to the Store.
+// If the context is canceled before that, the flusher keeps running in the background
+// and the context's error is returned.
+func (b *AsyncBlocklist) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSlowBlocklist struct {
+	release chan struct{}
+
+	mu     sync.Mutex
+	tokens []string
+}
+
+func (s *testSlowBlocklist) InvalidateToken(token []byte, c Claims) error {
+	<-s.release
+
+	s.mu.Lock()
+	s.tokens = append(s.tokens, string(token))
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *testSlowBlocklist) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tokens)
+}
+
+type testInvalidatorFunc func(token []byte, c Claims) error
+
+func (fn testInvalidatorFunc) InvalidateToken(token []byte, c Claims) error {
+	return fn(token, c)
+}
+
+func TestAsyncBlocklist(t *testing.T) {
+	store := &testSlowBlocklist{release: make(chan struct{})}
+	b := NewAsyncBlocklist(store, 2)
+	b.Blocklist = NewBlocklist(0)
+
+	claims := Claims{Expiry: time.Now().Add(time.Minute).Unix()}
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The store blocks but the revocation returns immediately.
+	if err = b.InvalidateToken(token, claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, b); err != ErrBlocked {
+		t.Fatalf("expected error: %v but got: %v", ErrBlocked, err)
+	}
+
+	// The queue fills up while the store blocks.
+	accepted := 1
+	for i := 0; ; i++ {
+		if i > 3 {
+			t.Fatalf("expected error: %v", ErrQueueFull)
+		}
+
+		if err = b.InvalidateToken([]byte("next"), claims); err == ErrQueueFull {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		accepted++
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	if err = b.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error: %v but got: %v", context.DeadlineExceeded, err)
+	}
+	cancel()
+
+	close(store.release)
+	if err = b.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := store.count(); n != accepted {
+		t.Fatalf("expected %d written tokens on close but got: %d", accepted, n)
+	}
+
+	if err = b.InvalidateToken(token, claims); err != ErrBlocklistClosed {
+		t.Fatalf("expected error: %v but got: %v", ErrBlocklistClosed, err)
+	}
+}
+
+func TestAsyncBlocklistFlush(t *testing.T) {
+	store := &testSlowBlocklist{release: make(chan struct{})}
+	close(store.release)
+
+	failed := errors.New("store failure")
+	var reported error
+	b := NewAsyncBlocklist(testInvalidatorFunc(func(token []byte, c Claims) error {
+		if string(token) == "fail" {
+			return failed
+		}
+		return store.InvalidateToken(token, c)
+	}), 8)
+	b.OnError = func(err error) { reported = err }
+	defer b.Close(context.Background())
+
+	for _, token := range []string{"a", "fail", "b"} {
+		if err := b.InvalidateToken([]byte(token), Claims{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := store.count(); n != 2 {
+		t.Fatalf("expected 2 written tokens but got: %d", n)
+	}
+
+	if reported != failed {
+		t.Fatalf("expected reported error: %v but got: %v", failed, reported)
+	}
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrAttestation indicates that the token's signer attestation is missing
+// or it does not match the `AttestationPolicy`.
+var ErrAttestation = errors.New("token signer attestation rejected")
+
+// Attestation is the signer attestation metadata of a token, the "att" protected header,
+// for regulated environments which track the provenance of the signing keys.
+// Set it through the `Issuer.Attestation` field and validate it through an `AttestationPolicy`.
+type Attestation struct {
+	// KeyOrigin is the origin of the signing key, e.g. "hsm", "kms" or "software".
+	KeyOrigin string `json:"origin,omitempty"`
+	// HSMSerial is the serial number of the hardware security module which holds the signing key.
+	HSMSerial string `json:"hsm_serial,omitempty"`
+	// Extra holds any other attestation fields, e.g. the module's firmware version.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// Attestation returns the signer attestation of the token's header ("att"), if any.
+func (t *VerifiedToken) Attestation() (*Attestation, bool) {
+	var h tokenHeader
+	if err := json.Unmarshal(t.Header, &h); err != nil || h.Att == nil {
+		return nil, false
+	}
+
+	return h.Att, true
+}
+
+// AttestationPolicy is a TokenValidator which validates the signer attestation
+// of the tokens (see `Attestation`), tokens without an attestation always fail.
+// It returns a type of ErrAttestation on validation failures.
+//
+// Usage:
+//  policy := jwt.AttestationPolicy{KeyOrigins: []string{"hsm"}, HSMSerials: []string{"HSM-0042"}}
+//  verifiedToken, err := jwt.Verify(jwt.ES256, publicKey, token, policy)
+type AttestationPolicy struct {
+	// KeyOrigins is the list of the allowed key origins. Empty means any.
+	KeyOrigins []string
+	// HSMSerials is the list of the allowed HSM serial numbers. Empty means any.
+	HSMSerials []string
+	// Validate is an optional custom check, e.g. of the Extra fields.
+	Validate func(att *Attestation) error
+}
+
+var _ TokenValidator = AttestationPolicy{}
+
+// ValidateToken completes the TokenValidator interface.
+func (p AttestationPolicy) ValidateToken(token []byte, _ Claims, err error) error {
+	if err != nil {
+		return err
+	}
+
+	att, ok := tokenAttestation(token)
+	if !ok {
+		return fmt.Errorf("%w: missing attestation", ErrAttestation)
+	}
+
+	if len(p.KeyOrigins) > 0 && !containsString(p.KeyOrigins, att.KeyOrigin) {
+		return fmt.Errorf("%w: key origin is not allowed", ErrAttestation)
+	}
+
+	if len(p.HSMSerials) > 0 && !containsString(p.HSMSerials, att.HSMSerial) {
+		return fmt.Errorf("%w: hsm serial is not allowed", ErrAttestation)
+	}
+
+	if p.Validate != nil {
+		if err = p.Validate(att); err != nil {
+			return fmt.Errorf("%w: %v", ErrAttestation, err)
+		}
+	}
+
+	return nil
+}
+
+// tokenAttestation returns the "att" header of the compact "token".
+func tokenAttestation(token []byte) (*Attestation, bool) {
+	parts := bytes.Split(token, sep)
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	header, err := Base64Decode(parts[0])
+	if err != nil {
+		return nil, false
+	}
+
+	return (&VerifiedToken{Header: header}).Attestation()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
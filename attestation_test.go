@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAttestationPolicy(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.KeyID = "key-1"
+	issuer.Attestation = &Attestation{KeyOrigin: "hsm", HSMSerial: "HSM-0042", Extra: map[string]string{"firmware": "7.1"}}
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := AttestationPolicy{
+		KeyOrigins: []string{"hsm"},
+		HSMSerials: []string{"HSM-0001", "HSM-0042"},
+		Validate: func(att *Attestation) error {
+			if att.Extra["firmware"] < "7" {
+				return errors.New("outdated firmware")
+			}
+			return nil
+		},
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	att, ok := verifiedToken.Attestation()
+	if !ok || att.HSMSerial != "HSM-0042" || att.KeyOrigin != "hsm" {
+		t.Fatalf("unexpected attestation: %#+v", att)
+	}
+
+	tests := []AttestationPolicy{
+		{KeyOrigins: []string{"kms"}},
+		{HSMSerials: []string{"HSM-0001"}},
+		{Validate: func(att *Attestation) error { return errors.New("rejected") }},
+	}
+
+	for i, tt := range tests {
+		if _, err = Verify(testAlg, testSecret, token, tt); !errors.Is(err, ErrAttestation) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrAttestation, err)
+		}
+	}
+
+	// Tokens without attestation.
+	token, err = Sign(testAlg, testSecret, Map{"sub": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, AttestationPolicy{}); !errors.Is(err, ErrAttestation) {
+		t.Fatalf("expected error: %v but got: %v", ErrAttestation, err)
+	}
+}
@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry holds the information of an issued token,
+// it's passed to the `Audit` hook.
+// It never contains the token itself nor its signature
+// and the values of the claims listed in `RedactedClaims` are replaced with the RedactedValue.
+type AuditEntry struct {
+	Time     time.Time // The time the token was signed.
+	Alg      string    // The signing algorithm.
+	KeyID    string    // The "kid" header, if any.
+	ID       string    // The "jti" claim.
+	Parent   string    // The "prt" claim, see the `Issuer.Lineage` field.
+	Issuer   string    // The "iss" claim.
+	Subject  string    // The "sub" claim.
+	Audience []string  // The "aud" claim.
+	IssuedAt int64     // The "iat" claim.
+	Expiry   int64     // The "exp" claim.
+}
+
+// Audit is an optional hook which is called on every successful Sign
+// (`Sign`, `SignEncrypted` and the functions which use them) with
+// the standard claims of the issued token. It can be used to keep an issuance ledger.
+// It should be set once on initialization of the program and it must be safe for concurrent use.
+//
+// Usage:
+//  jwt.Audit = func(entry jwt.AuditEntry) {
+//    log.Printf("issued token: jti=%s sub=%s exp=%d", entry.ID, entry.Subject, entry.Expiry)
+//  }
+var Audit func(entry AuditEntry)
+
+// audit calls the Audit hook, if any, the "payload" is the plain (not encrypted) one.
+func audit(alg AlgSigner, kid string, payload []byte) {
+	if Audit == nil {
+		return
+	}
+
+	var claims struct {
+		Claims
+		Parent string `json:"prt"`
+	}
+	json.Unmarshal(payload, &claims) // the claims may be a custom non-JSON payload, ignore the error.
+
+	audience := claims.Audience
+	if len(audience) > 0 && isRedactedClaim("aud") {
+		audience = []string{RedactedValue}
+	}
+
+	Audit(AuditEntry{
+		Time:     Clock(),
+		Alg:      alg.Name(),
+		KeyID:    kid,
+		ID:       redactString("jti", claims.ID),
+		Parent:   redactString(ClaimParent, claims.Parent),
+		Issuer:   redactString("iss", claims.Issuer),
+		Subject:  redactString("sub", claims.Subject),
+		Audience: audience,
+		IssuedAt: claims.IssuedAt,
+		Expiry:   claims.Expiry,
+	})
+}
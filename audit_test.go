@@ -0,0 +1,42 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	var entries []AuditEntry
+	Audit = func(entry AuditEntry) {
+		entries = append(entries, entry)
+	}
+	t.Cleanup(func() {
+		Audit = nil
+	})
+
+	encrypt, _, err := GCM(MustGenerateRandom(32), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := Claims{ID: "jti:1", Subject: "kataras", Audience: []string{"app"}}
+	if _, err = SignEncrypted(testAlg, testSecret, encrypt, Map{"foo": "bar"}, claims, MaxAge(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test failures are not audited.
+	if _, err = Sign(testAlg, invalidKey, claims); err == nil {
+		t.Fatalf("expected sign error")
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected a single audit entry but got: %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Alg != testAlg.Name() || entry.ID != claims.ID || entry.Subject != claims.Subject ||
+		!reflect.DeepEqual(entry.Audience, []string(claims.Audience)) || entry.Expiry == 0 || entry.IssuedAt == 0 {
+		t.Fatalf("unexpected audit entry: %#+v", entry)
+	}
+}
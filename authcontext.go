@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientAuth indicates that the user authentication of a verified token
+// does not satisfy the required `AuthContext`, the client should step-up
+// (re-authenticate the user with a stronger method) and request a new token.
+// The DefaultErrorHandler responds with 401 "insufficient_user_authentication" (RFC 9470).
+var ErrInsufficientAuth = errors.New("insufficient user authentication")
+
+// AuthContext is a validator of the authentication context claims of the OpenID Connect
+// and the RFC 8176: the "acr" (authentication context class reference) string
+// and the "amr" (authentication methods references) array of strings.
+// It enforces step-up authentication policies, e.g.
+//  stepUp := jwt.AuthContext{
+//    Levels:  []string{"urn:acr:pwd", "urn:acr:mfa", "urn:acr:hwk"},
+//    MinACR:  "urn:acr:mfa",
+//    Methods: []string{"mfa"},
+//  }
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, stepUp)
+//
+// It returns a type of ErrInsufficientAuth on validation failures.
+type AuthContext struct {
+	// Levels is the list of the known "acr" values, from the weakest to the strongest.
+	// Tokens of an "acr" value which is not listed are rejected when MinACR is set.
+	Levels []string
+	// MinACR is the minimum required authentication context class, one of the Levels.
+	// The token's "acr" should be equal to or stronger than it. Empty means any (or no) "acr".
+	MinACR string
+	// Methods are the authentication methods which should all be listed
+	// by the token's "amr" claim, e.g. "mfa", "hwk", "otp".
+	Methods []string
+}
+
+var (
+	_ PayloadValidator = AuthContext{}
+	_ TokenValidator   = AuthContext{}
+)
+
+// MinimumACR returns an AuthContext which requires the token's "acr" to be
+// at least the "min" class of the ordered (weakest first) "levels".
+func MinimumACR(levels []string, min string) AuthContext {
+	return AuthContext{Levels: levels, MinACR: min}
+}
+
+// RequireAMR returns an AuthContext which requires the token's "amr"
+// to list all of the given authentication "methods", e.g. RequireAMR("mfa").
+func RequireAMR(methods ...string) AuthContext {
+	return AuthContext{Methods: methods}
+}
+
+// ValidatePayload completes the PayloadValidator interface.
+func (a AuthContext) ValidatePayload(_ context.Context, _, payload []byte, _ Claims, err error) error {
+	if err != nil {
+		return err
+	}
+
+	var claims struct {
+		ACR string   `json:"acr"`
+		AMR []string `json:"amr"`
+	}
+	if err = defaultUnmarshal(payload, &claims); err != nil {
+		return err
+	}
+
+	if a.MinACR != "" {
+		min := indexOfString(a.Levels, a.MinACR)
+		if min == -1 {
+			return fmt.Errorf("%w: unknown minimum acr %q", ErrInsufficientAuth, a.MinACR)
+		}
+
+		if claims.ACR == "" {
+			return fmt.Errorf("%w: missing acr", ErrInsufficientAuth)
+		}
+
+		if indexOfString(a.Levels, claims.ACR) < min {
+			return fmt.Errorf("%w: acr", ErrInsufficientAuth)
+		}
+	}
+
+	for _, method := range a.Methods {
+		if !containsString(claims.AMR, method) {
+			return fmt.Errorf("%w: amr %s", ErrInsufficientAuth, method)
+		}
+	}
+
+	return nil
+}
+
+// ValidateToken completes the TokenValidator interface.
+// See `PayloadValidatorFunc.ValidateToken`.
+func (a AuthContext) ValidateToken(token []byte, standardClaims Claims, err error) error {
+	return PayloadValidatorFunc(a.ValidatePayload).ValidateToken(token, standardClaims, err)
+}
+
+// indexOfString returns the index of "value" in "values" or -1.
+func indexOfString(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
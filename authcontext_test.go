@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthContext(t *testing.T) {
+	levels := []string{"pwd", "mfa", "hwk"}
+
+	tests := []struct {
+		claims   Map
+		validate AuthContext
+		err      bool
+	}{
+		{Map{"acr": "mfa"}, MinimumACR(levels, "mfa"), false},
+		{Map{"acr": "hwk"}, MinimumACR(levels, "mfa"), false},
+		{Map{"acr": "pwd"}, MinimumACR(levels, "mfa"), true},
+		{Map{"acr": "unknown"}, MinimumACR(levels, "pwd"), true},
+		{Map{"sub": "kataras"}, MinimumACR(levels, "pwd"), true},
+		{Map{"acr": "hwk"}, MinimumACR(levels, "invalid"), true},
+		{Map{"amr": []string{"pwd", "otp", "mfa"}}, RequireAMR("mfa"), false},
+		{Map{"amr": []string{"pwd"}}, RequireAMR("mfa"), true},
+		{Map{"sub": "kataras"}, RequireAMR("mfa"), true},
+		{Map{"acr": "mfa", "amr": []string{"hwk"}}, AuthContext{Levels: levels, MinACR: "mfa", Methods: []string{"hwk"}}, false},
+		{Map{"acr": "hwk", "amr": []string{"pwd"}}, AuthContext{Levels: levels, MinACR: "mfa", Methods: []string{"hwk"}}, true},
+		{Map{"sub": "kataras"}, AuthContext{}, false},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Verify(testAlg, testSecret, token, tt.validate)
+		if tt.err {
+			if !errors.Is(err, ErrInsufficientAuth) {
+				t.Fatalf("[%d] expected error: %v but got: %v", i, ErrInsufficientAuth, err)
+			}
+
+			if reason := FailureReason(err); reason != "insufficient_auth" {
+				t.Fatalf("[%d] expected failure reason: insufficient_auth but got: %s", i, reason)
+			}
+		} else if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+	}
+}
+
+func TestAuthContextErrorHandler(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"acr": "pwd"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret, MinimumACR([]string{"pwd", "mfa"}, "mfa"))
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("expected the handler not to be called")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+string(token))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusUnauthorized, w.Code)
+	}
+
+	expected := `Bearer error="insufficient_user_authentication", error_description="insufficient user authentication: acr"`
+	if got := w.Header().Get("WWW-Authenticate"); got != expected {
+		t.Fatalf("expected WWW-Authenticate: %q but got: %q", expected, got)
+	}
+}
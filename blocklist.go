@@ -1,7 +1,9 @@
 package jwt
 
 import (
+	"container/heap"
 	"context"
+	"encoding/json"
 	"errors"
 	"sync"
 	"time"
@@ -11,6 +13,10 @@ import (
 // but was blocked by the server's Blocklist.
 var ErrBlocked = errors.New("token is blocked")
 
+// ErrBlocklistFull indicates a token which can't be blocked
+// because the Blocklist holds the MaxEntries unexpired tokens already.
+var ErrBlocklistFull = errors.New("blocklist is full")
+
 // Blocklist is an in-memory storage of tokens that should be
 // immediately invalidated by the server-side.
 // The most common way to invalidate a token, e.g. on user logout,
@@ -24,14 +30,41 @@ type Blocklist struct {
 	// the unique identifier for a token, by default
 	// it checks if the "jti" is not empty, if it's then the key is the token itself.
 	GetKey func(token []byte, claims Claims) string
-
-	entries map[string]int64 // key = token or its ID | value = expiration unix seconds (to remove expired).
+	// MaxEntries is the maximum number of blocked tokens, defaults to zero (no limit).
+	// A full blocklist removes its expired tokens and, if it's still full,
+	// `InvalidateToken` fails with ErrBlocklistFull: a blocked token is never
+	// evicted, as it would be accepted again. This limit should be higher than
+	// the expected number of blocked tokens of a tokens lifetime.
+	MaxEntries int
+	// GCEvery is the interval of the expired tokens removal, see `Run`.
+	GCEvery time.Duration
+	// WatermarkMaxAge is the duration a revocation watermark (see `RevokeSubject` and `RevokeTenant`) is kept,
+	// it should be the tokens max age: all the tokens issued before the watermark
+	// have expired afterwards. Defaults to zero, the watermarks are never removed.
+	WatermarkMaxAge time.Duration
+	// TenantClaim is the name of the (string) claim which holds the tenant (or organization)
+	// of a token, see `RevokeTenant`. Defaults to "tenant".
+	TenantClaim string
+	// Invalidations is an optional publisher of the revocations to the caches of verification decisions,
+	// see `Invalidations`.
+	Invalidations *Invalidations
+
+	entries  map[string]*blockEntry // key = token or its ID.
+	expiries blockHeap              // the entries ordered by expiration, to remove the expired ones.
+	// key = subject or tenant | value = unix seconds, the tokens issued before that are blocked.
+	subjects map[string]int64
+	tenants  map[string]int64
 	// ^ we could make it a map[*VerifiedToken]struct{} too
 	// but let's have a more general usage here.
 	mu sync.RWMutex
+
+	runner runner
 }
 
-var _ TokenValidator = (*Blocklist)(nil)
+var (
+	_ TokenValidator   = (*Blocklist)(nil)
+	_ PayloadValidator = (*Blocklist)(nil)
+)
 
 // NewBlocklist returns a new up and running in-memory Token Blocklist.
 // It accepts the clear every "x" duration. Indeed, this duration
@@ -44,20 +77,47 @@ func NewBlocklist(gcEvery time.Duration) *Blocklist {
 
 // NewBlocklistContext same as `NewBlocklist`
 // but it also accepts a standard Go Context for GC cancelation.
+// The GC stops on "ctx" cancellation or on `Close`.
 func NewBlocklistContext(ctx context.Context, gcEvery time.Duration) *Blocklist {
 	b := &Blocklist{
-		entries: make(map[string]int64),
+		entries: make(map[string]*blockEntry),
 		Clock:   Clock,
 		GetKey:  defaultGetKey,
+		GCEvery: gcEvery,
 	}
 
 	if gcEvery > 0 {
-		go b.runGC(ctx, gcEvery)
+		b.runner.start(ctx, b.runGC)
 	}
 
 	return b
 }
 
+// Run calls the `GC` method every GCEvery duration until the "ctx" is cancelled
+// or `Close` is called. It blocks and returns the "ctx" error or nil on `Close`.
+// Returns ErrRunning if the GC is already running, e.g. by a non-zero "gcEvery"
+// of the `NewBlocklist`, and nil immediately if the GCEvery field is zero.
+//
+// Usage:
+//  blocklist := jwt.NewBlocklist(0)
+//  blocklist.GCEvery = time.Hour
+//  go blocklist.Run(ctx)
+//  defer blocklist.Close()
+func (b *Blocklist) Run(ctx context.Context) error {
+	if b.GCEvery <= 0 {
+		return nil
+	}
+
+	return b.runner.run(ctx, b.runGC)
+}
+
+// Close stops the GC goroutine, if running, and waits for it to return.
+// The blocklist can still be used afterwards.
+func (b *Blocklist) Close() error {
+	b.runner.stop()
+	return nil
+}
+
 func defaultGetKey(token []byte, c Claims) string {
 	if c.ID != "" {
 		return c.ID
@@ -82,9 +142,144 @@ func (b *Blocklist) ValidateToken(token []byte, c Claims, err error) error {
 		return ErrBlocked
 	}
 
+	b.mu.RLock()
+	revoked := revokedBefore(b.subjects, c.Subject, c.IssuedAt)
+	b.mu.RUnlock()
+
+	if revoked {
+		return ErrBlocked
+	}
+
+	return nil
+}
+
+// ValidatePayload completes the `PayloadValidator` interface.
+// Same as `ValidateToken` but it blocks the tokens of the revoked tenants too, see `RevokeTenant`.
+// The payload is decoded only when a tenant is revoked.
+func (b *Blocklist) ValidatePayload(_ context.Context, token, payload []byte, c Claims, err error) error {
+	if err = b.ValidateToken(token, c, err); err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	revokedTenants := len(b.tenants) > 0
+	b.mu.RUnlock()
+
+	if !revokedTenants {
+		return nil
+	}
+
+	tenant := b.tenant(payload)
+
+	b.mu.RLock()
+	revoked := revokedBefore(b.tenants, tenant, c.IssuedAt)
+	b.mu.RUnlock()
+
+	if revoked {
+		return ErrBlocked
+	}
+
+	return nil
+}
+
+// tenant returns the value of the TenantClaim of the "payload", or empty.
+func (b *Blocklist) tenant(payload []byte) string {
+	name := b.TenantClaim
+	if name == "" {
+		name = "tenant"
+	}
+
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+
+	var tenant string
+	json.Unmarshal(claims[name], &tenant) // a missing claim or a non-string value is not a tenant.
+	return tenant
+}
+
+// RevokeSubject blocks all the tokens of the "subject" (the "sub" claim) issued before the "issuedBefore" time,
+// e.g. on a password change or an account compromise, without enumerating them.
+// It's stored as a single per-subject watermark: a subject's tokens of an earlier "iat" claim
+// (or without one) are blocked. The watermark never moves back, a later call with an earlier time is a no-op.
+// See the WatermarkMaxAge field too.
+//
+// Usage:
+//  blocklist.RevokeSubject(userID, time.Now())
+func (b *Blocklist) RevokeSubject(subject string, issuedBefore time.Time) error {
+	if subject == "" {
+		return ErrMissing
+	}
+
+	b.mu.Lock()
+	if b.subjects == nil {
+		b.subjects = make(map[string]int64)
+	}
+	setWatermark(b.subjects, subject, issuedBefore.Unix())
+	b.mu.Unlock()
+
+	b.invalidate()
 	return nil
 }
 
+// RevokeTenant blocks all the tokens of the "tenant" (the TenantClaim claim) issued before the "issuedBefore" time,
+// e.g. on an organization offboarding, without enumerating its members' tokens.
+// Like `RevokeSubject`, it's stored as a single per-tenant watermark.
+//
+// Usage:
+//  blocklist.TenantClaim = "org_id"
+//  blocklist.RevokeTenant(orgID, time.Now())
+func (b *Blocklist) RevokeTenant(tenant string, issuedBefore time.Time) error {
+	if tenant == "" {
+		return ErrMissing
+	}
+
+	b.mu.Lock()
+	if b.tenants == nil {
+		b.tenants = make(map[string]int64)
+	}
+	setWatermark(b.tenants, tenant, issuedBefore.Unix())
+	b.mu.Unlock()
+
+	b.invalidate()
+	return nil
+}
+
+// setWatermark moves the watermark of the "key" forward to "unix" seconds.
+func setWatermark(watermarks map[string]int64, key string, unix int64) {
+	if watermark, ok := watermarks[key]; !ok || unix > watermark {
+		watermarks[key] = unix
+	}
+}
+
+// revokedBefore reports whether a token issued at "issuedAt" is revoked
+// by the watermark of the "key", if any.
+func revokedBefore(watermarks map[string]int64, key string, issuedAt int64) bool {
+	if len(watermarks) == 0 || key == "" {
+		return false
+	}
+
+	watermark, ok := watermarks[key]
+	return ok && issuedAt < watermark
+}
+
+// gcWatermarks removes the watermarks older than the WatermarkMaxAge. It should be called under lock.
+func (b *Blocklist) gcWatermarks(now int64) {
+	if b.WatermarkMaxAge <= 0 {
+		return
+	}
+
+	oldest := now - int64(b.WatermarkMaxAge/time.Second)
+	for _, watermarks := range []map[string]int64{b.subjects, b.tenants} {
+		for key, watermark := range watermarks {
+			if watermark < oldest {
+				delete(watermarks, key)
+			}
+		}
+	}
+}
+
 // InvalidateToken invalidates a verified JWT token.
 // It adds the request token, retrieved by Verify method, to this blocklist.
 // Next request will be blocked, even if the token was not yet expired.
@@ -98,16 +293,44 @@ func (b *Blocklist) InvalidateToken(token []byte, c Claims) error {
 	key := b.GetKey(token, c)
 
 	b.mu.Lock()
-	b.entries[key] = c.Expiry
+	if e, exists := b.entries[key]; exists {
+		e.expiry = c.Expiry
+		heap.Fix(&b.expiries, e.index)
+	} else {
+		if b.MaxEntries > 0 && len(b.entries) >= b.MaxEntries {
+			b.removeExpired(b.Clock().Round(time.Second).Unix())
+			if len(b.entries) >= b.MaxEntries {
+				b.mu.Unlock()
+				return ErrBlocklistFull
+			}
+		}
+
+		e = &blockEntry{key: key, expiry: c.Expiry}
+		heap.Push(&b.expiries, e)
+		b.entries[key] = e
+		recordBlocklistSize(1)
+	}
 	b.mu.Unlock()
 
+	b.invalidate()
 	return nil
 }
 
+// invalidate publishes a revocation to the Invalidations, if any.
+func (b *Blocklist) invalidate() {
+	if b.Invalidations != nil {
+		b.Invalidations.Publish(Invalidation{Kind: InvalidateRevocation})
+	}
+}
+
 // Del removes a token based on its "key" from the blocklist.
 func (b *Blocklist) Del(key string) error {
 	b.mu.Lock()
-	delete(b.entries, key)
+	if e, exists := b.entries[key]; exists {
+		heap.Remove(&b.expiries, e.index)
+		delete(b.entries, key)
+		recordBlocklistSize(-1)
+	}
 	b.mu.Unlock()
 
 	return nil
@@ -137,37 +360,72 @@ func (b *Blocklist) Has(key string) (bool, error) {
 	return ok, nil
 }
 
-// GC iterates over all entries and removes expired tokens.
+// GC removes the expired tokens.
 // This method is helpful to keep the list size small.
 // Depending on the application, the GC method can be scheduled
 // to called every half or a whole hour.
 // A good value for a GC cron task is the Token's max age.
 func (b *Blocklist) GC() int {
 	now := b.Clock().Round(time.Second).Unix()
-	var markedForDeletion []string
 
-	b.mu.RLock()
-	for token, expiry := range b.entries {
-		if now > expiry {
-			markedForDeletion = append(markedForDeletion, token)
-		}
-	}
-	b.mu.RUnlock()
+	b.mu.Lock()
+	n := b.removeExpired(now)
+	b.gcWatermarks(now)
+	b.mu.Unlock()
 
-	n := len(markedForDeletion)
-	if n > 0 {
-		for _, token := range markedForDeletion {
-			b.mu.Lock()
-			delete(b.entries, token)
-			b.mu.Unlock()
-		}
+	return n
+}
+
+// removeExpired removes the entries which expired before "now" unix seconds,
+// in expiration order. It should be called under lock.
+func (b *Blocklist) removeExpired(now int64) int {
+	n := 0
+	for len(b.expiries) > 0 && now > b.expiries[0].expiry {
+		e := heap.Pop(&b.expiries).(*blockEntry)
+		delete(b.entries, e.key)
+		n++
 	}
 
+	recordBlocklistSize(-n)
 	return n
 }
 
-func (b *Blocklist) runGC(ctx context.Context, every time.Duration) {
-	t := time.NewTicker(every)
+// blockEntry is a blocked token of a Blocklist.
+type blockEntry struct {
+	key    string
+	expiry int64 // expiration unix seconds.
+	index  int   // the index in the blockHeap.
+}
+
+// blockHeap is a container/heap of the blocked tokens, the one which expires sooner first.
+type blockHeap []*blockEntry
+
+func (h blockHeap) Len() int           { return len(h) }
+func (h blockHeap) Less(i, j int) bool { return h[i].expiry < h[j].expiry }
+
+func (h blockHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *blockHeap) Push(x interface{}) {
+	e := x.(*blockEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *blockHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+func (b *Blocklist) runGC(ctx context.Context) {
+	t := time.NewTicker(b.GCEvery)
 
 	for {
 		select {
@@ -85,3 +85,146 @@ func TestBlocklist(t *testing.T) {
 		t.Fatalf("expected all entries to be removed but: %d", got)
 	}
 }
+
+func TestBlocklistMaxEntries(t *testing.T) {
+	now := time.Now()
+	b := NewBlocklist(0)
+	b.MaxEntries = 2
+
+	b.InvalidateToken([]byte("expired"), Claims{Expiry: now.Add(-time.Minute).Unix()})
+	b.InvalidateToken([]byte("later"), Claims{Expiry: now.Add(time.Hour).Unix()})
+
+	// The expired token is removed first.
+	b.InvalidateToken([]byte("sooner"), Claims{Expiry: now.Add(time.Minute).Unix()})
+	if has, _ := b.Has("expired"); has {
+		t.Fatalf("expected the expired token to be evicted")
+	}
+
+	// Then a full blocklist of unexpired tokens rejects new ones, instead of accepting a blocked one again.
+	if err := b.InvalidateToken([]byte("another"), Claims{Expiry: now.Add(time.Hour).Unix()}); err != ErrBlocklistFull {
+		t.Fatalf("expected error: %v but got: %v", ErrBlocklistFull, err)
+	}
+
+	// An already blocked token can still be updated.
+	if err := b.InvalidateToken([]byte("sooner"), Claims{Expiry: now.Add(-time.Minute).Unix()}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"later", "sooner"} {
+		if has, _ := b.Has(key); !has {
+			t.Fatalf("expected %s to be blocked", key)
+		}
+	}
+
+	if got, _ := b.Count(); got != 2 {
+		t.Fatalf("expected 2 entries but got: %d", got)
+	}
+
+	// The updated token expired.
+	if err := b.InvalidateToken([]byte("another"), Claims{Expiry: now.Add(time.Hour).Unix()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if has, _ := b.Has("sooner"); has {
+		t.Fatalf("expected the expired token to be removed")
+	}
+
+	b.Del("later")
+	if n := b.GC(); n != 0 {
+		t.Fatalf("expected no expired tokens but got: %d", n)
+	}
+
+	if got, _ := b.Count(); got != 1 {
+		t.Fatalf("expected 1 entry but got: %d", got)
+	}
+}
+
+func TestBlocklistRevokeSubject(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	b := NewBlocklist(0)
+	b.WatermarkMaxAge = time.Hour
+
+	sign := func(subject string, issuedAt time.Time) []byte {
+		token, err := Sign(testAlg, testSecret, Claims{Subject: subject, IssuedAt: issuedAt.Unix(), Expiry: issuedAt.Add(time.Hour).Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	before, after, other := sign("kataras", now.Add(-time.Minute)), sign("kataras", now), sign("makis", now.Add(-time.Minute))
+
+	if err := b.RevokeSubject("kataras", now); err != nil {
+		t.Fatal(err)
+	}
+	b.RevokeSubject("kataras", now.Add(-time.Hour)) // the watermark never moves back.
+
+	var tests = []struct {
+		token []byte
+		err   error
+	}{
+		{before, ErrBlocked},
+		{after, nil},
+		{other, nil},
+	}
+
+	for i, tt := range tests {
+		if _, err := Verify(testAlg, testSecret, tt.token, b); err != tt.err {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+
+	// The watermark is removed when all the tokens issued before it have expired.
+	now = now.Add(time.Hour + time.Second)
+	b.GC()
+	if n := len(b.subjects); n != 0 {
+		t.Fatalf("expected the watermark to be removed but got: %d", n)
+	}
+}
+
+func TestBlocklistRevokeTenant(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	b := NewBlocklist(0)
+	b.TenantClaim = "org_id"
+
+	sign := func(orgID string, issuedAt time.Time) []byte {
+		token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "org_id": orgID, "iat": issuedAt.Unix(), "exp": issuedAt.Add(time.Hour).Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	before, after, other := sign("acme", now.Add(-time.Minute)), sign("acme", now), sign("umbrella", now.Add(-time.Minute))
+
+	if err := b.RevokeTenant("acme", now); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		token []byte
+		err   error
+	}{
+		{before, ErrBlocked},
+		{after, nil},
+		{other, nil},
+	}
+
+	for i, tt := range tests {
+		if _, err := Verify(testAlg, testSecret, tt.token, b); err != tt.err {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+
+		// Through an AsyncBlocklist of the same in-memory blocklist too.
+		async := &AsyncBlocklist{Blocklist: b}
+		if _, err := Verify(testAlg, testSecret, tt.token, async); err != tt.err {
+			t.Fatalf("[%d] async: expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+// BlocklistStore is a blocklist which can be looked up by key, e.g. a redis or a database one.
+// The in-memory `Blocklist` implements it too.
+type BlocklistStore interface {
+	TokenInvalidator
+	// Has reports whether the given "key" is blocked.
+	Has(key string) (bool, error)
+}
+
+var _ BlocklistStore = (*Blocklist)(nil)
+
+// BloomLoader loads all the keys of a blocklist store into a `BloomBlocklist` filter,
+// it calls "add" for each one of them.
+type BloomLoader func(ctx context.Context, add func(key string)) error
+
+// bloomFalsePositiveRate is the false positive rate of a `BloomBlocklist` filter at its capacity.
+const bloomFalsePositiveRate = 0.01
+
+// BloomBlocklist is a bloom filter in front of a large blocklist store,
+// so the common case of a not blocked token is answered without a store lookup.
+// Only the keys which may be blocked (all the blocked ones and
+// about 1% of the rest, at its capacity) are looked up in the store.
+// The filter is rebuilt from the store's keys periodically,
+// so it drops the expired entries too.
+//
+// Usage:
+//  blocklist := jwt.NewBloomBlocklist(redisBlocklist, redisBlocklist.Keys, 1_000_000, time.Hour)
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, blocklist)
+//  [...]
+//  err = blocklist.InvalidateToken(verifiedToken.Token, verifiedToken.StandardClaims)
+type BloomBlocklist struct {
+	// GetKey should return the same key as the store does
+	// for a token, by default the "jti" or the token itself.
+	GetKey func(token []byte, claims Claims) string
+	// OnError is an optional hook which is called on periodic rebuild failures.
+	OnError func(err error)
+	// RebuildEvery is the interval of the periodic rebuild, see `Run`.
+	RebuildEvery time.Duration
+	// FailurePolicy is the behavior of the `ValidateToken` when the store fails.
+	// The default FailClosed rejects the tokens with a *DependencyError,
+	// FailOpen accepts them as not blocked.
+	FailurePolicy FailurePolicy
+	// Invalidations is an optional publisher of the revocations to the caches of verification decisions,
+	// see `Invalidations`.
+	Invalidations *Invalidations
+
+	store    BlocklistStore
+	load     BloomLoader
+	capacity int
+
+	mu     sync.RWMutex
+	filter *bloomFilter // nil until the first rebuild, all keys are looked up in the store.
+	next   *bloomFilter // the filter in rebuild, if any.
+
+	runner runner
+}
+
+var (
+	_ TokenValidator = (*BloomBlocklist)(nil)
+	_ BlocklistStore = (*BloomBlocklist)(nil)
+)
+
+// NewBloomBlocklist returns a new BloomBlocklist in front of the "store".
+// The "load" function reads the store's keys, the filter is built by it
+// at start and every "rebuildEvery" duration. The "capacity" is the expected number of blocked tokens.
+// Call its Rebuild method manually if "rebuildEvery" is zero.
+// A nil "load" starts with an empty filter, for stores which are filled through the BloomBlocklist only.
+func NewBloomBlocklist(store BlocklistStore, load BloomLoader, capacity int, rebuildEvery time.Duration) *BloomBlocklist {
+	return NewBloomBlocklistContext(context.Background(), store, load, capacity, rebuildEvery)
+}
+
+// NewBloomBlocklistContext same as `NewBloomBlocklist`
+// but it also accepts a standard Go Context for rebuild cancelation.
+// The periodic rebuild stops on "ctx" cancellation or on `Close`.
+func NewBloomBlocklistContext(ctx context.Context, store BlocklistStore, load BloomLoader, capacity int, rebuildEvery time.Duration) *BloomBlocklist {
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	b := &BloomBlocklist{
+		GetKey:       defaultGetKey,
+		RebuildEvery: rebuildEvery,
+		store:        store,
+		load:         load,
+		capacity:     capacity,
+	}
+
+	if load == nil {
+		b.filter = newBloomFilter(capacity, bloomFalsePositiveRate)
+	} else if rebuildEvery > 0 {
+		b.runner.start(ctx, b.runRebuild)
+	}
+
+	return b
+}
+
+// Run rebuilds the filter at start and every RebuildEvery duration until the "ctx"
+// is cancelled or `Close` is called. It blocks and returns the "ctx" error or nil on `Close`.
+// Returns ErrRunning if the rebuild is already running, e.g. by a non-zero "rebuildEvery"
+// of the `NewBloomBlocklist`, and nil immediately if there is no loader or the RebuildEvery field is zero.
+func (b *BloomBlocklist) Run(ctx context.Context) error {
+	if b.load == nil || b.RebuildEvery <= 0 {
+		return nil
+	}
+
+	return b.runner.run(ctx, b.runRebuild)
+}
+
+// Close stops the periodic rebuild, if running, and waits for it to return.
+func (b *BloomBlocklist) Close() error {
+	b.runner.stop()
+	return nil
+}
+
+// ValidateToken completes the `TokenValidator` interface.
+// Returns ErrBlocked if the "token" was blocked by the store.
+func (b *BloomBlocklist) ValidateToken(token []byte, c Claims, err error) error {
+	if err != nil {
+		return err // respect the previous error.
+	}
+
+	blocked, err := b.Has(b.GetKey(token, c))
+	if err != nil {
+		if errors.Is(err, ErrMissing) {
+			return err
+		}
+
+		if b.FailurePolicy == FailOpen {
+			recordDegraded(DependencyBlocklist)
+			return nil
+		}
+
+		return dependencyError(DependencyBlocklist, err)
+	}
+
+	if blocked {
+		return ErrBlocked
+	}
+
+	return nil
+}
+
+// Has reports whether the given "key" is blocked,
+// the store is looked up only if the filter may contain the key.
+func (b *BloomBlocklist) Has(key string) (bool, error) {
+	if len(key) == 0 {
+		return false, ErrMissing
+	}
+
+	b.mu.RLock()
+	mayContain := b.filter == nil || b.filter.has(key)
+	b.mu.RUnlock()
+
+	if !mayContain {
+		return false, nil
+	}
+
+	return b.store.Has(key)
+}
+
+// InvalidateToken adds the token to the filter and then to the store.
+func (b *BloomBlocklist) InvalidateToken(token []byte, c Claims) error {
+	if len(token) == 0 {
+		return ErrMissing
+	}
+
+	key := b.GetKey(token, c)
+
+	b.mu.Lock()
+	if b.filter != nil {
+		b.filter.add(key)
+	}
+	if b.next != nil {
+		b.next.add(key)
+	}
+	b.mu.Unlock()
+
+	if err := b.store.InvalidateToken(token, c); err != nil {
+		return err
+	}
+
+	if b.Invalidations != nil {
+		b.Invalidations.Publish(Invalidation{Kind: InvalidateRevocation})
+	}
+
+	return nil
+}
+
+// Rebuild builds a new filter from the store's keys and replaces the current one on success.
+// Tokens invalidated during the rebuild are added to both filters.
+func (b *BloomBlocklist) Rebuild(ctx context.Context) error {
+	if b.load == nil {
+		return nil
+	}
+
+	next := newBloomFilter(b.capacity, bloomFalsePositiveRate)
+
+	b.mu.Lock()
+	b.next = next
+	b.mu.Unlock()
+
+	err := b.load(ctx, func(key string) {
+		b.mu.Lock()
+		next.add(key)
+		b.mu.Unlock()
+	})
+
+	b.mu.Lock()
+	if err == nil {
+		b.filter = next
+	}
+	b.next = nil
+	b.mu.Unlock()
+
+	return err
+}
+
+func (b *BloomBlocklist) runRebuild(ctx context.Context) {
+	rebuild := func() {
+		if err := b.Rebuild(ctx); err != nil && b.OnError != nil {
+			b.OnError(err)
+		}
+	}
+
+	rebuild()
+
+	t := time.NewTicker(b.RebuildEvery)
+	for {
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+			rebuild()
+		}
+	}
+}
+
+// bloomFilter is a bloom filter of "k" hash functions over "m" bits.
+type bloomFilter struct {
+	bits []uint64
+	m, k uint64
+}
+
+func newBloomFilter(n int, p float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// hashes returns the two hashes of the "key",
+// the k hash functions are derived by double hashing.
+func (f *bloomFilter) hashes(key string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+
+	return sum & 0xffffffff, sum>>32 | 1
+}
+
+func (f *bloomFilter) add(key string) {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (f *bloomFilter) has(key string) bool {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testCountingBlocklist struct {
+	*Blocklist
+	lookups int32
+}
+
+func (s *testCountingBlocklist) Has(key string) (bool, error) {
+	atomic.AddInt32(&s.lookups, 1)
+	return s.Blocklist.Has(key)
+}
+
+func (s *testCountingBlocklist) load(_ context.Context, add func(key string)) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key := range s.entries {
+		add(key)
+	}
+	return nil
+}
+
+func TestBloomBlocklist(t *testing.T) {
+	store := &testCountingBlocklist{Blocklist: NewBlocklist(0)}
+	expiry := time.Now().Add(time.Minute).Unix()
+	for i := 0; i < 100; i++ {
+		store.InvalidateToken([]byte("blocked"), Claims{ID: "blocked-" + strconv.Itoa(i), Expiry: expiry})
+	}
+
+	b := NewBloomBlocklist(store, store.load, 1000, 0)
+
+	// Every key is looked up in the store before the first build.
+	if has, err := b.Has("blocked-1"); err != nil || !has {
+		t.Fatalf("expected blocked key but got: %v (%v)", has, err)
+	}
+
+	if err := b.Rebuild(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.StoreInt32(&store.lookups, 0)
+	for i := 0; i < 1000; i++ {
+		if has, err := b.Has("allowed-" + strconv.Itoa(i)); err != nil || has {
+			t.Fatalf("expected not blocked key but got: %v (%v)", has, err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&store.lookups); n > 50 {
+		t.Fatalf("expected few store lookups of not blocked keys but got: %d", n)
+	}
+
+	for i := 0; i < 100; i++ {
+		if has, err := b.Has("blocked-" + strconv.Itoa(i)); err != nil || !has {
+			t.Fatalf("expected blocked key but got: %v (%v)", has, err)
+		}
+	}
+
+	claims := Claims{ID: "logout", Expiry: expiry}
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = b.InvalidateToken(token, claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, b); err != ErrBlocked {
+		t.Fatalf("expected error: %v but got: %v", ErrBlocked, err)
+	}
+}
+
+func TestBloomBlocklistRebuildFailure(t *testing.T) {
+	store := &testCountingBlocklist{Blocklist: NewBlocklist(0)}
+	store.InvalidateToken([]byte("blocked"), Claims{ID: "blocked"})
+
+	failed := errors.New("store failure")
+	fail := false
+	b := NewBloomBlocklist(store, func(ctx context.Context, add func(key string)) error {
+		if fail {
+			add("partial")
+			return failed
+		}
+		return store.load(ctx, add)
+	}, 100, 0)
+
+	if err := b.Rebuild(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed rebuild keeps the previous filter.
+	fail = true
+	if err := b.Rebuild(context.Background()); err != failed {
+		t.Fatalf("expected error: %v but got: %v", failed, err)
+	}
+
+	if has, err := b.Has("blocked"); err != nil || !has {
+		t.Fatalf("expected blocked key but got: %v (%v)", has, err)
+	}
+}
@@ -0,0 +1,154 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen indicates that a remote call was rejected
+// without being performed because the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker protects the verification path from remote dependencies
+// (e.g. the `JWKSClient`) which fail or respond slowly.
+// After "Threshold" consecutive failures the circuit opens and calls
+// fail fast with ErrCircuitOpen. When the "Cooldown" passes, a single trial call
+// is allowed (half-open state); on success the circuit closes again.
+//
+// A CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failures which opens the circuit.
+	Threshold int
+	// Cooldown is the duration the circuit stays open before a trial call.
+	Cooldown time.Duration
+	// Clock is used to calculate the cooldown.
+	Clock func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial call is in progress.
+}
+
+// NewCircuitBreaker returns a new circuit breaker which opens
+// after "threshold" consecutive failures for "cooldown" duration.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		Clock:     Clock,
+	}
+}
+
+// Do calls the "fn" if the circuit is closed (or half-open and no other trial is in progress)
+// and records its result. Returns ErrCircuitOpen if the call was rejected.
+func (b *CircuitBreaker) Do(fn func() error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// Open reports whether the circuit is currently open.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	open := b.isOpen()
+	b.mu.Unlock()
+
+	return open
+}
+
+func (b *CircuitBreaker) isOpen() bool {
+	return b.Threshold > 0 && b.failures >= b.Threshold
+}
+
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.isOpen() {
+		return true
+	}
+
+	if b.trial || b.Clock().Sub(b.openedAt) < b.Cooldown {
+		return false
+	}
+
+	b.trial = true
+	return true
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	b.trial = false
+	if err == nil {
+		b.failures = 0
+	} else {
+		b.failures++
+		if b.isOpen() {
+			b.openedAt = b.Clock()
+		}
+	}
+	b.mu.Unlock()
+}
+
+// remoteStatusError is returned by remote calls on unexpected HTTP status codes.
+type remoteStatusError struct {
+	name       string
+	statusCode int
+}
+
+func (e *remoteStatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status code: %d", e.name, e.statusCode)
+}
+
+// isRetryable reports whether a failed remote call can be retried.
+// Transport failures, server errors and rate-limit responses are retryable.
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *remoteStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= http.StatusInternalServerError || statusErr.statusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// retry calls "fn" through the (optional) "breaker" and retries up to "retries" times
+// on retryable failures, waiting for "backoff" between attempts (doubled on each retry).
+func retry(ctx context.Context, breaker *CircuitBreaker, retries int, backoff time.Duration, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		var err error
+		if breaker != nil {
+			err = breaker.Do(fn)
+		} else {
+			err = fn()
+		}
+
+		if err == nil || attempt >= retries || !isRetryable(err) {
+			return err
+		}
+
+		if backoff > 0 {
+			t := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return err
+			case <-t.C:
+			}
+
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(2, time.Minute)
+	b.Clock = func() time.Time { return now }
+
+	errRemote := errors.New("remote error")
+	fail := func() error { return errRemote }
+	var calls int
+	succeed := func() error {
+		calls++
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := b.Do(fail); err != errRemote {
+			t.Fatalf("expected remote error but got: %v", err)
+		}
+	}
+
+	if !b.Open() {
+		t.Fatalf("expected circuit to be open after two failures")
+	}
+
+	if err := b.Do(succeed); err != ErrCircuitOpen || calls != 0 {
+		t.Fatalf("expected error: %v without calling the function but got: %v", ErrCircuitOpen, err)
+	}
+
+	// Half-open: a failed trial re-opens the circuit.
+	now = now.Add(time.Minute)
+	if err := b.Do(fail); err != errRemote {
+		t.Fatalf("expected trial call to be performed but got: %v", err)
+	}
+	if err := b.Do(succeed); err != ErrCircuitOpen {
+		t.Fatalf("expected error: %v but got: %v", ErrCircuitOpen, err)
+	}
+
+	// Half-open: a successful trial closes the circuit.
+	now = now.Add(time.Minute)
+	if err := b.Do(succeed); err != nil || calls != 1 {
+		t.Fatalf("expected trial call to succeed but got: %v", err)
+	}
+
+	if b.Open() {
+		t.Fatalf("expected circuit to be closed")
+	}
+}
+
+func TestJWKSClientRetry(t *testing.T) {
+	var requests uint32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&requests, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+
+	c := NewJWKSClient(srv.URL)
+	c.Retries = 2
+	c.RetryBackoff = time.Millisecond
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 3 {
+		t.Fatalf("expected 3 requests but got: %d", got)
+	}
+
+	// Test breaker stops the retries.
+	atomic.StoreUint32(&requests, 0)
+	c.Breaker = NewCircuitBreaker(1, time.Minute)
+	if err := c.Refresh(context.Background()); err != ErrCircuitOpen {
+		t.Fatalf("expected error: %v but got: %v", ErrCircuitOpen, err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 1 {
+		t.Fatalf("expected a single request before the circuit opens but got: %d", got)
+	}
+}
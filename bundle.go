@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBundle indicates a verification bundle which can't be loaded,
+// e.g. one without keys or of an unsupported algorithm, see `NewBundleVerifier`.
+var ErrBundle = errors.New("invalid verification bundle")
+
+// bundleAlgs are the algorithms of the verification bundles, the asymmetric ones.
+var bundleAlgs = []Alg{RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512, EdDSA}
+
+// Bundle is the vetted verification configuration of an issuer: its public keys
+// and the policy of its tokens, which is exported as a single signed file (see `ExportBundle`)
+// so edge devices can verify the tokens fully offline, see `NewBundleVerifier`.
+type Bundle struct {
+	// Issuer is the expected "iss" claim of the tokens, optional.
+	Issuer string `json:"issuer,omitempty"`
+	// Audience is the list of the accepted audiences, the "aud" claim of a token
+	// must contain at least one of them. Optional.
+	Audience []string `json:"audience,omitempty"`
+	// Algs is the allowed algorithm of the tokens, e.g. ["EdDSA"].
+	// A Verifier verifies a single algorithm, so it must list one.
+	Algs []string `json:"algs"`
+	// Keys is the public key set of the issuer, each key must have a key id.
+	Keys *JWKS `json:"keys"`
+
+	// The bundle's "iat" and "exp" claims, set by the `ExportBundle`.
+	IssuedAt int64 `json:"iat,omitempty"`
+	Expiry   int64 `json:"exp,omitempty"`
+}
+
+// Bundle returns the verification bundle of the Issuer: its "iss" claim, its audience,
+// its algorithm and the public key of its current signing key (see `KeyProvider` and `SetKey`),
+// by its key id. More keys, e.g. the previous one during a rotation, can be appended to the bundle's Keys.
+// Returns ErrInvalidKey if the Issuer signs with a symmetric (HMAC) secret.
+//
+// Usage:
+//  bundle, err := issuer.Bundle(ctx)
+//  data, err := jwt.ExportBundle(jwt.EdDSA, rootKey, bundle, 30*24*time.Hour)
+//  os.WriteFile("verification.bundle", data, 0644)
+func (i *Issuer) Bundle(ctx context.Context) (*Bundle, error) {
+	kid, key := i.ActiveKey()
+	if i.KeyProvider != nil {
+		var err error
+		if kid, key, err = i.KeyProvider.PrivateKey(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	jwk, err := NewJWK(kid, nil, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	jwk.Alg = i.Alg.Name()
+
+	return &Bundle{
+		Issuer:   i.Issuer,
+		Audience: i.Audience,
+		Algs:     []string{i.Alg.Name()},
+		Keys:     &JWKS{Keys: []*JWK{jwk}},
+	}, nil
+}
+
+// ExportBundle signs the "bundle" with the "alg" and the "key" of the trust anchor
+// (e.g. an offline root key of the operators, not one of the issuer's keys)
+// and returns its file contents, a token which expires after "maxAge" (zero for never).
+// The bundle is loaded through the `NewBundleVerifier`.
+func ExportBundle(alg AlgSigner, key PrivateKey, bundle *Bundle, maxAge time.Duration) ([]byte, error) {
+	if bundle == nil || bundle.Keys == nil || len(bundle.Keys.Keys) == 0 {
+		return nil, fmt.Errorf("%w: no keys", ErrBundle)
+	}
+
+	exported := *bundle
+	exported.IssuedAt, exported.Expiry = 0, 0
+	if maxAge > 0 {
+		now := Clock()
+		exported.IssuedAt, exported.Expiry = now.Unix(), now.Add(maxAge).Unix()
+	}
+
+	return Sign(alg, key, exported)
+}
+
+// NewBundleVerifier verifies the "bundle" file contents (see `ExportBundle`) with the "alg"
+// and the public "key" of its trust anchor and returns a new Verifier of its keys and policy:
+// its algorithm (through an `AlgPolicy` which requires a "kid" header),
+// its Issuer (through the `Expected` validator) and its Audience, followed by the "validators".
+// Returns a type of ErrBundle if the bundle has no keys or it does not list a single supported
+// asymmetric algorithm, and the verification error (e.g. ErrExpired) of an invalid bundle.
+//
+// Usage:
+//  data, err := os.ReadFile("verification.bundle")
+//  verifier, err := jwt.NewBundleVerifier(jwt.EdDSA, rootPublicKey, data)
+func NewBundleVerifier(alg AlgVerifier, key PublicKey, bundle []byte, validators ...TokenValidator) (*Verifier, error) {
+	verifiedToken, err := Verify(alg, key, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Bundle
+	if err = json.Unmarshal(verifiedToken.Payload, &b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBundle, err)
+	}
+
+	if len(b.Algs) != 1 {
+		return nil, fmt.Errorf("%w: expected a single algorithm but got %d", ErrBundle, len(b.Algs))
+	}
+
+	var tokenAlg Alg
+	for _, a := range bundleAlgs {
+		if a.Name() == b.Algs[0] {
+			tokenAlg = a
+			break
+		}
+	}
+
+	if tokenAlg == nil {
+		return nil, fmt.Errorf("%w: unsupported algorithm: %s", ErrBundle, b.Algs[0])
+	}
+
+	if b.Keys == nil {
+		return nil, fmt.Errorf("%w: no keys", ErrBundle)
+	}
+
+	keys, err := b.Keys.publicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no keys", ErrBundle)
+	}
+
+	policy := []TokenValidator{AlgPolicy{Algs: []Alg{tokenAlg}, RequireKeyID: true}}
+	if b.Issuer != "" {
+		policy = append(policy, Expected{Issuer: b.Issuer})
+	}
+	if len(b.Audience) > 0 {
+		policy = append(policy, anyAudience(b.Audience))
+	}
+
+	verifier := NewVerifier(tokenAlg, nil, append(policy, validators...)...)
+	verifier.KeyProvider = &StaticJWKS{set: b.Keys, keys: keys}
+	return verifier, nil
+}
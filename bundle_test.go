@@ -0,0 +1,122 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBundle(t *testing.T) {
+	rootPublicKey, rootKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuerPublicKey, issuerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := NewIssuer(EdDSA, nil, time.Minute)
+	issuer.SetKey("2024", issuerKey)
+	issuer.Issuer = "https://idp.example.com"
+	issuer.Audience = []string{"devices"}
+
+	bundle, err := issuer.Bundle(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bundle.Keys.Keys) != 1 || bundle.Keys.Keys[0].Kid != "2024" || bundle.Algs[0] != "EdDSA" {
+		t.Fatalf("unexpected bundle: %#+v", bundle)
+	}
+
+	data, err := ExportBundle(EdDSA, rootKey, bundle, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewBundleVerifier(EdDSA, rootPublicKey, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := issuer.Token("device-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another issuer of the same key.
+	other, err := Sign(EdDSA, issuerKey, Claims{Issuer: "other", Audience: []string{"devices"}}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.VerifyToken(other); err == nil {
+		t.Fatalf("expected a token without a kid and of another issuer to fail")
+	}
+
+	// Tampered bundle.
+	tampered := append([]byte(nil), data...)
+	if tampered[len(tampered)-2] == 'A' {
+		tampered[len(tampered)-2] = 'B'
+	} else {
+		tampered[len(tampered)-2] = 'A'
+	}
+	if _, err = NewBundleVerifier(EdDSA, rootPublicKey, tampered); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// The issuer's own key is not the trust anchor.
+	if _, err = NewBundleVerifier(EdDSA, issuerPublicKey, data); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
+
+func TestBundleInvalid(t *testing.T) {
+	rootPublicKey, rootKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewIssuer(HS256, testSecret, time.Minute).Bundle(context.Background()); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	if _, err = ExportBundle(EdDSA, rootKey, &Bundle{Algs: []string{"EdDSA"}}, 0); !errors.Is(err, ErrBundle) {
+		t.Fatalf("expected error: %v but got: %v", ErrBundle, err)
+	}
+
+	jwk, err := NewJWK("1", nil, rootPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, algs := range [][]string{{"HS256"}, {"EdDSA", "RS256"}, nil} {
+		data, err := ExportBundle(EdDSA, rootKey, &Bundle{Algs: algs, Keys: &JWKS{Keys: []*JWK{jwk}}}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = NewBundleVerifier(EdDSA, rootPublicKey, data); !errors.Is(err, ErrBundle) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrBundle, err)
+		}
+	}
+
+	defer func() { Clock = time.Now }()
+	data, err := ExportBundle(EdDSA, rootKey, &Bundle{Algs: []string{"EdDSA"}, Keys: &JWKS{Keys: []*JWK{jwk}}}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Clock = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err = NewBundleVerifier(EdDSA, rootPublicKey, data); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+)
+
+// CasbinEnforcer is the method set of a Casbin enforcer this package depends on.
+// The *casbin.Enforcer (and *casbin.SyncedEnforcer) of the github.com/casbin/casbin/v2 module
+// completes it, so this package does not have to import Casbin itself.
+type CasbinEnforcer interface {
+	Enforce(rvals ...interface{}) (bool, error)
+}
+
+// CasbinAdapter feeds the claims of verified tokens into Casbin enforcement.
+// The request values passed to the enforcer are (subject, object, action), or
+// (subject, domain, object, action) when the TenantClaim is set.
+// The subject is the "sub" claim and, if RolesClaim is set, each one of the roles,
+// the request is authorized if any of those subjects is allowed.
+//
+// Usage:
+//  enforcer, _ := casbin.NewEnforcer("model.conf", "policy.csv")
+//  adapter := jwt.NewCasbinAdapter(enforcer)
+//  verifiedToken, err := jwt.Verify(alg, key, token, adapter.Validator("/invoices", "read"))
+type CasbinAdapter struct {
+	// Enforcer is the Casbin enforcer, required.
+	Enforcer CasbinEnforcer
+	// RolesClaim is the name of the claim which holds the roles (an array of strings), optional.
+	// Defaults to "roles".
+	RolesClaim string
+	// TenantClaim is the name of the claim which holds the tenant,
+	// it's passed as the Casbin domain. Optional.
+	TenantClaim string
+}
+
+// NewCasbinAdapter returns a new Casbin adapter of the "enforcer".
+func NewCasbinAdapter(enforcer CasbinEnforcer) *CasbinAdapter {
+	return &CasbinAdapter{
+		Enforcer:   enforcer,
+		RolesClaim: "roles",
+	}
+}
+
+// Enforce reports whether the verified token is allowed to perform the "act" action to the "obj" object.
+// Returns ErrPolicyDenied if it's not or a Casbin error.
+func (a *CasbinAdapter) Enforce(t *VerifiedToken, obj, act string) error {
+	return a.enforce(t.Payload, t.StandardClaims, obj, act)
+}
+
+// Validator returns a PayloadValidator which enforces the "act" action to the "obj" object,
+// see `Enforce`.
+func (a *CasbinAdapter) Validator(obj, act string) PayloadValidatorFunc {
+	return func(_ context.Context, _, payload []byte, standardClaims Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		return a.enforce(payload, standardClaims, obj, act)
+	}
+}
+
+func (a *CasbinAdapter) enforce(payload []byte, standardClaims Claims, obj, act string) error {
+	var claims Map
+	if err := defaultUnmarshal(payload, &claims); err != nil {
+		return err
+	}
+
+	var subjects []string
+	if standardClaims.Subject != "" {
+		subjects = append(subjects, standardClaims.Subject)
+	}
+
+	if a.RolesClaim != "" {
+		if roles, ok := claims[a.RolesClaim].([]interface{}); ok {
+			for _, role := range roles {
+				if s, ok := role.(string); ok && s != "" {
+					subjects = append(subjects, s)
+				}
+			}
+		}
+	}
+
+	var tenant string
+	if a.TenantClaim != "" {
+		tenant, _ = claims[a.TenantClaim].(string)
+		if tenant == "" {
+			return ErrPolicyDenied
+		}
+	}
+
+	for _, sub := range subjects {
+		var rvals []interface{}
+		if a.TenantClaim != "" {
+			rvals = []interface{}{sub, tenant, obj, act}
+		} else {
+			rvals = []interface{}{sub, obj, act}
+		}
+
+		ok, err := a.Enforcer.Enforce(rvals...)
+		if err != nil {
+			return fmt.Errorf("casbin: %w", err)
+		}
+
+		if ok {
+			return nil
+		}
+	}
+
+	return ErrPolicyDenied
+}
@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// casbinEnforcerTest allows the "sub,obj,act" (or "sub,dom,obj,act") policies.
+type casbinEnforcerTest map[string]bool
+
+func (e casbinEnforcerTest) Enforce(rvals ...interface{}) (bool, error) {
+	values := make([]string, 0, len(rvals))
+	for _, v := range rvals {
+		values = append(values, fmt.Sprint(v))
+	}
+
+	return e[strings.Join(values, ",")], nil
+}
+
+func TestCasbinAdapter(t *testing.T) {
+	enforcer := casbinEnforcerTest{
+		"kataras,/profile,read":             true,
+		"admin,/invoices,write":             true,
+		"billing,acme,/invoices,read":       true,
+		"billing,other-tenant,/export,read": true,
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "roles": []string{"user", "admin", "billing"}, "tenant": "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adapter := NewCasbinAdapter(enforcer)
+
+	tests := []struct {
+		obj, act string
+		allowed  bool
+	}{
+		{"/profile", "read", true},   // by subject.
+		{"/invoices", "write", true}, // by role.
+		{"/invoices", "delete", false},
+	}
+
+	for i, tt := range tests {
+		_, err = Verify(testAlg, testSecret, token, adapter.Validator(tt.obj, tt.act))
+		if tt.allowed && err != nil {
+			t.Fatalf("[%d] expected to be allowed but got: %v", i, err)
+		}
+
+		if !tt.allowed && !errors.Is(err, ErrPolicyDenied) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrPolicyDenied, err)
+		}
+	}
+
+	// Test domains.
+	adapter.TenantClaim = "tenant"
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = adapter.Enforce(verifiedToken, "/invoices", "read"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = adapter.Enforce(verifiedToken, "/export", "read"); err != ErrPolicyDenied {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+}
@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// claimCipherSep separates the key id prefix of an encrypted claim value from its ciphertext.
+const claimCipherSep = "."
+
+// ClaimCipher encrypts the values of selected claims (e.g. an email or a national id)
+// with AES-GCM, so the rest of the payload stays readable (e.g. by gateways and logs)
+// while the sensitive claims are readable by the key holders only.
+// An encrypted value is a string of the key id (the `KeyID` of the encryption)
+// and the base64url ciphertext, "kid.ciphertext", and it's bound to its claim's name.
+//
+// The key id prefix makes the key rotation seamless: new values are encrypted
+// with the active KeyID key and the values of the tokens issued before the rotation
+// are decrypted with the previous keys, as long as they are kept in the key ring.
+// A ClaimCipher is safe for concurrent use, a rotation replaces it with a new one.
+//
+// Usage:
+//  claimCipher, err := jwt.NewClaimCipher("2024", map[string][]byte{"2023": oldKey, "2024": newKey})
+//  issuer.Encrypt = claimCipher.EncryptFunc("email")
+//  verifier.Decrypt = claimCipher.DecryptFunc("email")
+type ClaimCipher struct {
+	// KeyID is the key id of the active key, which encrypts the claims.
+	KeyID string
+
+	keys map[string]cipher.AEAD
+}
+
+// NewClaimCipher returns a new ClaimCipher of the "keys" AES keys (16, 24 or 32 bytes) by their key id,
+// which encrypts with the "kid" key. The key ids must not contain a '.' character.
+func NewClaimCipher(kid string, keys map[string][]byte) (*ClaimCipher, error) {
+	c := &ClaimCipher{KeyID: kid, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, claimCipherSep) {
+			return nil, fmt.Errorf("%w: claim cipher key id: %q", ErrInvalidKey, id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		c.keys[id] = aead
+	}
+
+	if _, ok := c.keys[kid]; !ok {
+		return nil, fmt.Errorf("%w: claim cipher active key id: %q", ErrUnknownKid, kid)
+	}
+
+	return c, nil
+}
+
+// Encrypt returns a copy of the "claims" with the values of the "names" claims encrypted
+// by the KeyID key. Missing claims are skipped.
+func (c *ClaimCipher) Encrypt(claims Map, names ...string) (Map, error) {
+	aead, ok := c.keys[c.KeyID]
+	if !ok {
+		return nil, ErrUnknownKid
+	}
+
+	encrypted := make(Map, len(claims))
+	for name, value := range claims {
+		encrypted[name] = value
+	}
+
+	for _, name := range names {
+		value, ok := claims[name]
+		if !ok {
+			continue
+		}
+
+		plaintext, err := Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		nonce := make([]byte, aead.NonceSize())
+		if _, err = io.ReadFull(Random, nonce); err != nil {
+			return nil, err
+		}
+
+		ciphertext := aead.Seal(nonce, nonce, plaintext, []byte(name))
+		encrypted[name] = c.KeyID + claimCipherSep + string(Base64Encode(ciphertext))
+	}
+
+	return encrypted, nil
+}
+
+// Decrypt returns a copy of the "claims" with the values of the "names" claims decrypted
+// by the key of their key id prefix. Missing claims are skipped.
+// Returns a type of ErrDecrypt if a value is not an encrypted one, its key id is not in the key ring
+// or it fails to authenticate, e.g. a value which is moved from another claim.
+func (c *ClaimCipher) Decrypt(claims Map, names ...string) (Map, error) {
+	decrypted := make(Map, len(claims))
+	for name, value := range claims {
+		decrypted[name] = value
+	}
+
+	for _, name := range names {
+		value, ok := claims[name]
+		if !ok {
+			continue
+		}
+
+		s, ok := value.(string)
+		i := strings.LastIndex(s, claimCipherSep)
+		if !ok || i <= 0 {
+			return nil, fmt.Errorf("%w: %s: not encrypted", ErrDecrypt, name)
+		}
+
+		aead, ok := c.keys[s[:i]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: unknown key id", ErrDecrypt, name)
+		}
+
+		ciphertext, err := Base64Decode([]byte(s[i+1:]))
+		if err != nil || len(ciphertext) < aead.NonceSize() {
+			return nil, fmt.Errorf("%w: %s", ErrDecrypt, name)
+		}
+
+		nonce := ciphertext[:aead.NonceSize()]
+		plaintext, err := aead.Open(nil, nonce, ciphertext[aead.NonceSize():], []byte(name))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDecrypt, name)
+		}
+
+		var v interface{}
+		if err = defaultUnmarshal(plaintext, &v); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDecrypt, name)
+		}
+		decrypted[name] = v
+	}
+
+	return decrypted, nil
+}
+
+// EncryptFunc returns an InjectFunc which encrypts the "names" claims of a payload,
+// e.g. for the `Issuer.Encrypt` field and the `SignEncrypted` function.
+func (c *ClaimCipher) EncryptFunc(names ...string) InjectFunc {
+	return c.payloadFunc(c.Encrypt, names)
+}
+
+// DecryptFunc returns an InjectFunc which decrypts the "names" claims of a payload,
+// e.g. for the `Verifier.Decrypt` field and the `VerifyEncrypted` function.
+func (c *ClaimCipher) DecryptFunc(names ...string) InjectFunc {
+	return c.payloadFunc(c.Decrypt, names)
+}
+
+func (c *ClaimCipher) payloadFunc(fn func(Map, ...string) (Map, error), names []string) InjectFunc {
+	return func(payload []byte) ([]byte, error) {
+		var claims Map
+		if err := defaultUnmarshal(payload, &claims); err != nil {
+			return nil, err
+		}
+
+		claims, err := fn(claims, names...)
+		if err != nil {
+			return nil, err
+		}
+
+		return Marshal(claims)
+	}
+}
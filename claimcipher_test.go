@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaimCipher(t *testing.T) {
+	oldKey, newKey := MustGenerateRandom(32), MustGenerateRandom(32)
+
+	before, err := NewClaimCipher("2023", map[string][]byte{"2023": oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Encrypt = before.EncryptFunc("email", "address")
+
+	token, err := issuer.Token("kataras", Map{"email": "kataras@example.com", "address": Map{"city": "Athens"}, "role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The other claims are readable, the encrypted ones are prefixed by their key id.
+	unverifiedToken, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw Map
+	if err = Unmarshal(unverifiedToken.Payload, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if email, _ := raw["email"].(string); raw["role"] != "admin" || !strings.HasPrefix(email, "2023.") {
+		t.Fatalf("unexpected payload: %s", unverifiedToken.Payload)
+	}
+
+	// Rotation: new values are encrypted by the new key, the old ones are still decrypted.
+	after, err := NewClaimCipher("2024", map[string][]byte{"2023": oldKey, "2024": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.Decrypt = after.DecryptFunc("email", "address")
+
+	verifiedToken, err := verifier.VerifyToken(token, Expect(Map{"email": "kataras@example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims struct {
+		Address struct {
+			City string `json:"city"`
+		} `json:"address"`
+	}
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Address.City != "Athens" {
+		t.Fatalf("unexpected decrypted claims: %s", verifiedToken.Payload)
+	}
+
+	encrypted, err := after.Encrypt(Map{"email": "kataras@example.com"}, "email", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if email := encrypted["email"].(string); !strings.HasPrefix(email, "2024.") {
+		t.Fatalf("expected the active key id prefix but got: %s", email)
+	}
+
+	// The old key is retired.
+	retired, err := NewClaimCipher("2024", map[string][]byte{"2024": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = VerifyEncrypted(testAlg, testSecret, retired.DecryptFunc("email"), token); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected error: %v but got: %v", ErrDecrypt, err)
+	}
+}
+
+func TestClaimCipherInvalid(t *testing.T) {
+	key := MustGenerateRandom(32)
+	c, err := NewClaimCipher("1", map[string][]byte{"1": key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := c.Encrypt(Map{"email": "kataras@example.com"}, "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Moved to another claim.
+	if _, err = c.Decrypt(Map{"phone": encrypted["email"]}, "phone"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected error: %v but got: %v", ErrDecrypt, err)
+	}
+
+	if _, err = c.Decrypt(Map{"email": "kataras@example.com"}, "email"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected error: %v but got: %v", ErrDecrypt, err)
+	}
+
+	if _, err = NewClaimCipher("2", map[string][]byte{"1": key}); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if _, err = NewClaimCipher("1.0", map[string][]byte{"1.0": key}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -53,7 +54,40 @@ type Claims struct {
 	// claim is present, the party reading the data in this JWT must find itself in the aud claim or
 	// disregard the data contained in the JWT. As in the case of the iss and sub claims, this claim
 	// is application specific.
-	Audience []string `json:"aud,omitempty"`
+	// Both of the JSON forms, a single string and an array of strings, are accepted,
+	// it's always encoded as an array.
+	Audience Audience `json:"aud,omitempty"`
+}
+
+// Audience is the type of the "aud" claim, a list of recipients.
+// It accepts both a single string and an array of strings JSON value.
+type Audience []string
+
+// UnmarshalJSON completes the json.Unmarshaler interface.
+// It decodes a single string or an array of strings.
+func (aud *Audience) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		*aud = Audience{s}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*aud = values
+	return nil
+}
+
+// Contains reports whether the "recipient" is one of the audience.
+func (aud Audience) Contains(recipient string) bool {
+	return containsString(aud, recipient)
 }
 
 // Age returns the total age of the claims,
@@ -80,25 +114,38 @@ func (c Claims) Timeleft() time.Duration {
 func validateClaims(t time.Time, claims Claims) error {
 	now := t.Round(time.Second).Unix()
 
-	if claims.NotBefore > 0 {
-		if now < claims.NotBefore {
-			return ErrNotValidYet
+	for _, check := range timeClaimChecks {
+		if err := check.validate(now, claims); err != nil {
+			return err
 		}
 	}
 
-	if claims.IssuedAt > 0 {
-		if now < claims.IssuedAt {
+	return nil
+}
+
+// timeClaimChecks are the builtin validations of the time claims, in order.
+var timeClaimChecks = []struct {
+	name     string
+	validate func(now int64, claims Claims) error
+}{
+	{"nbf", func(now int64, claims Claims) error {
+		if claims.NotBefore > 0 && now < claims.NotBefore {
+			return ErrNotValidYet
+		}
+		return nil
+	}},
+	{"iat", func(now int64, claims Claims) error {
+		if claims.IssuedAt > 0 && now < claims.IssuedAt {
 			return ErrIssuedInTheFuture
 		}
-	}
-
-	if claims.Expiry > 0 {
-		if now > claims.Expiry {
+		return nil
+	}},
+	{"exp", func(now int64, claims Claims) error {
+		if claims.Expiry > 0 && now > claims.Expiry {
 			return ErrExpired
 		}
-	}
-
-	return nil
+		return nil
+	}},
 }
 
 // ApplyClaims implements the `SignOption` interface.
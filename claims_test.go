@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -147,3 +148,45 @@ func TestMaxAgeMap(t *testing.T) {
 	// test no panic if nil.
 	MaxAgeMap(maxAge, nil)
 }
+
+func TestAudience(t *testing.T) {
+	tests := []struct {
+		payload  string
+		expected Audience
+	}{
+		{`{"aud":"billing"}`, Audience{"billing"}},
+		{`{"aud":["billing","inventory"]}`, Audience{"billing", "inventory"}},
+		{`{"aud":[]}`, Audience{}},
+		{`{}`, nil},
+	}
+
+	for _, tt := range tests {
+		var claims Claims
+		if err := json.Unmarshal([]byte(tt.payload), &claims); err != nil {
+			t.Fatalf("%s: %v", tt.payload, err)
+		}
+
+		if !reflect.DeepEqual(claims.Audience, tt.expected) {
+			t.Fatalf("%s: expected audience: %#v but got: %#v", tt.payload, tt.expected, claims.Audience)
+		}
+	}
+
+	var claims Claims
+	if err := json.Unmarshal([]byte(`{"aud":1}`), &claims); err == nil {
+		t.Fatalf("expected an error on a number audience")
+	}
+
+	// Always encoded as an array.
+	b, err := json.Marshal(Claims{Audience: Audience{"billing"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"aud":["billing"]}`; string(b) != expected {
+		t.Fatalf("expected: %s but got: %s", expected, b)
+	}
+
+	if aud := (Audience{"billing", "inventory"}); !aud.Contains("inventory") || aud.Contains("admin") {
+		t.Fatalf("unexpected Contains results")
+	}
+}
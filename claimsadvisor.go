@@ -0,0 +1,255 @@
+package jwt
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// The kinds of a `ClaimSuggestion`.
+const (
+	// SuggestAbbreviate suggests a shorter name of a custom claim, see `ClaimAbbreviations`.
+	SuggestAbbreviate = "abbreviate"
+	// SuggestArray suggests an array of the keys of an object claim whose values are all true,
+	// e.g. {"read":true,"write":true} to ["read","write"].
+	SuggestArray = "array"
+	// SuggestCompress suggests a compressed payload.
+	SuggestCompress = "compress"
+)
+
+// minAbbreviatedName is the minimum length of a claim name which is suggested for abbreviation.
+const minAbbreviatedName = 4
+
+// ClaimSuggestion is a size reduction of a claims set, see `AdviseClaims`.
+type ClaimSuggestion struct {
+	Kind   string // The kind of the suggestion, e.g. SuggestAbbreviate.
+	Claim  string // The claim name, empty for the whole payload.
+	Saving int    // The estimated saving of the token's size in bytes.
+	Text   string // The human-readable suggestion.
+}
+
+// ClaimsAdvice is the size analysis of a claims set, see `AdviseClaims`.
+type ClaimsAdvice struct {
+	// Size is the size of the payload segment of the token in bytes.
+	Size int
+	// Claims are the sizes of the top-level claims, the largest first.
+	Claims []ClaimSize
+	// Suggestions are the size reductions, the largest saving first.
+	// The savings of different suggestions may overlap, e.g. the compression of abbreviated claims saves less.
+	Suggestions []ClaimSuggestion
+	// Abbreviations is the suggested abbreviation table of the long custom claim names,
+	// the table of the SuggestAbbreviate suggestions, nil if none.
+	Abbreviations ClaimAbbreviations
+}
+
+// AdviseClaims analyzes the size of the "claims" (a map or a struct value) of a token.
+// It reports the size each top-level claim contributes and suggestions to reduce it:
+// shorter names of the custom claims, arrays instead of set-like objects and a compressed payload.
+// Use it (e.g. in a test or a diagnostics endpoint) on the claims of a *TokenSizeError
+// to fit the token into its `MaxTokenSize` budget, the largest savings come first.
+//
+// Usage:
+//  advice, err := jwt.AdviseClaims(claims)
+//  for _, suggestion := range advice.Suggestions {
+//    log.Printf("%s: %d bytes", suggestion.Text, suggestion.Saving)
+//  }
+//  issuer.Abbreviations = advice.Abbreviations
+//  verifier.Abbreviations = advice.Abbreviations
+func AdviseClaims(claims interface{}) (*ClaimsAdvice, error) {
+	payload, err := Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	if err = json.Unmarshal(payload, &values); err != nil {
+		return nil, err
+	}
+
+	advice := &ClaimsAdvice{
+		Size:   base64.RawURLEncoding.EncodedLen(len(payload)),
+		Claims: claimSizes(payload),
+	}
+
+	// suggest adds a suggestion which saves "n" bytes of the (decoded) payload.
+	suggest := func(kind, claim string, n int, text string) {
+		saving := advice.Size - base64.RawURLEncoding.EncodedLen(len(payload)-n)
+		if saving > 0 {
+			advice.Suggestions = append(advice.Suggestions, ClaimSuggestion{Kind: kind, Claim: claim, Saving: saving, Text: text})
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Abbreviations, the short names must not collide with any claim's name.
+	taken := make(map[string]struct{}, len(values)+len(standardClaimNames))
+	for _, name := range standardClaimNames {
+		taken[name] = struct{}{}
+	}
+	for name := range values {
+		taken[name] = struct{}{}
+	}
+
+	for _, name := range names {
+		if len(name) < minAbbreviatedName || isStandardClaim(name) {
+			continue
+		}
+
+		short := abbreviate(name, taken)
+		if short == "" {
+			continue
+		}
+		taken[short] = struct{}{}
+
+		if advice.Abbreviations == nil {
+			advice.Abbreviations = make(ClaimAbbreviations)
+		}
+		advice.Abbreviations[name] = short
+
+		suggest(SuggestAbbreviate, name, len(name)-len(short), fmt.Sprintf("rename the %q claim to %q", name, short))
+	}
+
+	// Arrays instead of set-like objects.
+	for _, name := range names {
+		var set map[string]bool
+		if json.Unmarshal(values[name], &set) != nil || len(set) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(set))
+		for key, ok := range set {
+			if !ok {
+				keys = nil
+				break
+			}
+			keys = append(keys, key)
+		}
+
+		if keys == nil {
+			continue
+		}
+		sort.Strings(keys)
+
+		array, _ := json.Marshal(keys)
+		if n := len(values[name]) - len(array); n > 0 {
+			suggest(SuggestArray, name, n, fmt.Sprintf("encode the %q claim as an array of its keys", name))
+		}
+	}
+
+	// Compression.
+	var compressed bytes.Buffer
+	w, _ := flate.NewWriter(&compressed, flate.BestCompression) // a valid level never fails.
+	w.Write(payload)
+	w.Close()
+
+	if n := advice.Size - base64.RawURLEncoding.EncodedLen(compressed.Len()); n > advice.Size/10 {
+		advice.Suggestions = append(advice.Suggestions, ClaimSuggestion{
+			Kind:   SuggestCompress,
+			Saving: n,
+			Text:   "compress the payload with deflate, e.g. through the Issuer's Encrypt and the Verifier's Decrypt functions",
+		})
+	}
+
+	sort.SliceStable(advice.Suggestions, func(i, j int) bool {
+		return advice.Suggestions[i].Saving > advice.Suggestions[j].Saving
+	})
+
+	return advice, nil
+}
+
+// abbreviate returns the shortest prefix of the "name" (at least two characters)
+// or the first character followed by a digit, which is not "taken". Returns empty if none.
+func abbreviate(name string, taken map[string]struct{}) string {
+	for n := 2; n < len(name)-1; n++ {
+		if _, ok := taken[name[:n]]; !ok {
+			return name[:n]
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		short := fmt.Sprintf("%s%d", name[:1], i)
+		if _, ok := taken[short]; !ok {
+			return short
+		}
+	}
+
+	return ""
+}
+
+func isStandardClaim(name string) bool {
+	return containsString(standardClaimNames, name)
+}
+
+// ClaimAbbreviations is a table of custom claim names and their short names
+// (key = name | value = short name). The `Issuer.Abbreviations` field renames the claims
+// of the signed tokens to their short names and the `Verifier.Abbreviations` one
+// restores their names before any validation, so long descriptive claim names
+// do not count against the token's size. The standard claims are never renamed
+// and the short names must not be a standard claim's name, such entries are ignored.
+// See `AdviseClaims` for a suggested table.
+//
+// Usage:
+//  abbreviations := jwt.ClaimAbbreviations{"permissions": "pm", "organization": "org"}
+//  issuer.Abbreviations = abbreviations
+//  verifier.Abbreviations = abbreviations
+type ClaimAbbreviations map[string]string
+
+// Abbreviate returns a copy of the "claims" with the short names of the table.
+func (a ClaimAbbreviations) Abbreviate(claims Map) Map {
+	abbreviated := make(Map, len(claims))
+	for name, value := range claims {
+		if short, ok := a[name]; ok && !isStandardClaim(name) && !isStandardClaim(short) {
+			name = short
+		}
+		abbreviated[name] = value
+	}
+
+	return abbreviated
+}
+
+// Expand returns a copy of the "claims" with the names of the table's short names.
+func (a ClaimAbbreviations) Expand(claims Map) Map {
+	names := make(map[string]string, len(a))
+	for name, short := range a {
+		if !isStandardClaim(name) && !isStandardClaim(short) {
+			names[short] = name
+		}
+	}
+
+	expanded := make(Map, len(claims))
+	for short, value := range claims {
+		name, ok := names[short]
+		if !ok {
+			name = short
+		}
+		expanded[name] = value
+	}
+
+	return expanded
+}
+
+// expandPayload returns an InjectFunc which restores the claim names of the (decrypted) payload.
+func (a ClaimAbbreviations) expandPayload(decrypt InjectFunc) InjectFunc {
+	return func(payload []byte) ([]byte, error) {
+		if decrypt != nil {
+			var err error
+			if payload, err = decrypt(payload); err != nil {
+				return nil, err
+			}
+		}
+
+		var claims Map
+		if err := defaultUnmarshal(payload, &claims); err != nil {
+			return nil, err
+		}
+
+		return Marshal(a.Expand(claims))
+	}
+}
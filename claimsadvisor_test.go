@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdviseClaims(t *testing.T) {
+	claims := Map{
+		"sub":         "kataras",
+		"permissions": Map{"orders:read": true, "orders:write": true, "invoices:read": true},
+		"description": strings.Repeat("the quick brown fox ", 20),
+	}
+
+	advice, err := AdviseClaims(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if advice.Size == 0 || len(advice.Claims) != 3 || advice.Claims[0].Name != "description" {
+		t.Fatalf("unexpected claim sizes: %#+v", advice)
+	}
+
+	kinds := make(map[string]int)
+	for i, suggestion := range advice.Suggestions {
+		kinds[suggestion.Kind]++
+		if i > 0 && suggestion.Saving > advice.Suggestions[i-1].Saving {
+			t.Fatalf("expected the largest saving first but got: %#+v", advice.Suggestions)
+		}
+	}
+
+	if kinds[SuggestAbbreviate] != 2 || kinds[SuggestArray] != 1 || kinds[SuggestCompress] != 1 {
+		t.Fatalf("unexpected suggestions: %#+v", advice.Suggestions)
+	}
+
+	if advice.Suggestions[0].Kind != SuggestCompress {
+		t.Fatalf("expected the compression of a repetitive payload first but got: %#+v", advice.Suggestions[0])
+	}
+
+	if _, ok := advice.Abbreviations["sub"]; ok || len(advice.Abbreviations) != 2 {
+		t.Fatalf("unexpected abbreviations: %#+v", advice.Abbreviations)
+	}
+
+	abbreviated := advice.Abbreviations.Abbreviate(claims)
+	if _, ok := abbreviated["permissions"]; ok || abbreviated["sub"] != "kataras" {
+		t.Fatalf("unexpected abbreviated claims: %#+v", abbreviated)
+	}
+
+	if expanded := advice.Abbreviations.Expand(abbreviated); len(expanded) != 3 || expanded["description"] != claims["description"] {
+		t.Fatalf("unexpected expanded claims: %#+v", expanded)
+	}
+}
+
+func TestIssuerAbbreviations(t *testing.T) {
+	abbreviations := ClaimAbbreviations{"organization": "org", "permissions": "pm", "tenant": "sub"}
+
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Abbreviations = abbreviations
+
+	token, err := issuer.Token("kataras", Map{"organization": "acme", "permissions": []string{"read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if view := signed.View(); !view.Has("org") || !view.Has("pm") || view.Has("organization") {
+		t.Fatalf("expected the short claim names but got: %s", signed.Payload)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.Abbreviations = abbreviations
+
+	policy := ClaimContains("permissions", "read")
+	verifiedToken, err := verifier.VerifyToken(token, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims struct {
+		Organization string `json:"organization"`
+	}
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Organization != "acme" || verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("expected the restored claim names but got: %s", verifiedToken.Payload)
+	}
+}
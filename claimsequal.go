@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// VolatileClaims is a list of claim names which values differ on every issued token,
+// they are ignored by `ClaimsEqual`. Modify it once on initialization, e.g.
+//  jwt.VolatileClaims = append(jwt.VolatileClaims, "session_id")
+var VolatileClaims = []string{"iat", "nbf", "exp", "jti"}
+
+// ClaimsEqual reports whether the "a" and "b" claim sets are semantically equal,
+// e.g. ignoring the representation of numbers (1 and 1.0) and the forms of the "aud" claim
+// (a single string, an array of one string and the order of its elements).
+// The `VolatileClaims` and the "ignore" claim names are ignored,
+// a null claim is considered missing.
+//
+// The claim sets can be a map, a struct value (e.g. `Claims`) or a JSON payload ([]byte),
+// e.g. the `VerifiedToken.Payload`. Reports false if any of them can't be decoded.
+// It's useful in tests and idempotency checks.
+//
+// Usage:
+//  if !jwt.ClaimsEqual(verifiedToken.Payload, jwt.Map{"sub": "kataras", "aud": "admin"}) {
+//   [...]
+//  }
+func ClaimsEqual(a, b interface{}, ignore ...string) bool {
+	ca, err := canonicalClaims(a, ignore)
+	if err != nil {
+		return false
+	}
+
+	cb, err := canonicalClaims(b, ignore)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(ca, cb)
+}
+
+// canonicalClaims decodes the claims and it normalizes their values so they can be deep-compared.
+func canonicalClaims(claims interface{}, ignore []string) (Map, error) {
+	var payload []byte
+	switch v := claims.(type) {
+	case []byte:
+		payload = v
+	case json.RawMessage:
+		payload = v
+	default:
+		b, err := Marshal(claims)
+		if err != nil {
+			return nil, err
+		}
+		payload = b
+	}
+
+	var decoded Map
+	if err := defaultUnmarshal(payload, &decoded); err != nil {
+		return nil, err
+	}
+
+	canonical := make(Map, len(decoded))
+	for name, value := range decoded {
+		if value == nil || containsString(VolatileClaims, name) || containsString(ignore, name) {
+			continue
+		}
+
+		value = canonicalValue(value)
+		if name == "aud" {
+			value = canonicalAudience(value)
+		}
+
+		canonical[name] = value
+	}
+
+	return canonical, nil
+}
+
+// canonicalValue converts the numbers to int64 (if they are integers) or float64 values,
+// recursively, and it drops the null fields of the objects.
+func canonicalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return n
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+
+		if f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+			return int64(f) // e.g. 1.0 or 1e3.
+		}
+
+		return f
+	case Map:
+		m := make(Map, len(v))
+		for k, elem := range v {
+			if elem != nil {
+				m[k] = canonicalValue(elem)
+			}
+		}
+
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			list[i] = canonicalValue(elem)
+		}
+
+		return list
+	default:
+		return v
+	}
+}
+
+// canonicalAudience returns the "aud" claim as a sorted array.
+func canonicalAudience(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return []interface{}{v}
+	case []interface{}:
+		audience := make([]string, 0, len(v))
+		for _, elem := range v {
+			s, ok := elem.(string)
+			if !ok {
+				return v // not a valid audience, it's compared as it's.
+			}
+			audience = append(audience, s)
+		}
+		sort.Strings(audience)
+
+		list := make([]interface{}, len(audience))
+		for i, s := range audience {
+			list[i] = s
+		}
+
+		return list
+	default:
+		return v
+	}
+}
@@ -0,0 +1,40 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaimsEqual(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "aud": "admin", "level": 2}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		a, b   interface{}
+		ignore []string
+		equal  bool
+	}{
+		{verifiedToken.Payload, Map{"sub": "kataras", "aud": []string{"admin"}, "level": 2.0}, nil, true},
+		{Claims{Subject: "kataras", Audience: []string{"b", "a"}, Expiry: 1}, Map{"sub": "kataras", "aud": []string{"a", "b"}, "exp": 2}, nil, true},
+		{Map{"sub": "kataras", "n": int64(1e3)}, []byte(`{"sub":"kataras","n":1e3,"nbf":null}`), nil, true},
+		{Map{"nested": Map{"a": 1, "b": nil}}, Map{"nested": Map{"a": 1.0}}, nil, true},
+		{Map{"sub": "kataras", "sid": "1"}, Map{"sub": "kataras", "sid": "2"}, []string{"sid"}, true},
+		{Map{"sub": "kataras", "sid": "1"}, Map{"sub": "kataras", "sid": "2"}, nil, false},
+		{Map{"n": 1.5}, Map{"n": 1}, nil, false},
+		{Map{"roles": []string{"a", "b"}}, Map{"roles": []string{"b", "a"}}, nil, false},
+		{Map{"sub": "kataras"}, []byte("not json"), nil, false},
+	}
+
+	for i, tt := range tests {
+		if got := ClaimsEqual(tt.a, tt.b, tt.ignore...); got != tt.equal {
+			t.Fatalf("[%d] expected equal: %v but got: %v", i, tt.equal, got)
+		}
+	}
+}
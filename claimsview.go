@@ -0,0 +1,208 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ClaimsView is a read-only view of a token's claims for the html/template render contexts,
+// e.g. to show the "Pretty Name" of the client-side tokens of the NONE algorithm.
+// Its accessors never fail: a missing claim, a claim of another type or a zero ClaimsView
+// result in the zero value, so templates need no {{if}} guards.
+// The values are plain strings (never template.HTML), so html/template escapes them for their context.
+//
+// Usage:
+//  view := verifiedToken.View()
+//  tmpl.Execute(w, view)
+//
+//  <p>Hello, {{.String "name"}} ({{.Subject}})</p>
+//  {{range .Strings "roles"}}<span>{{.}}</span>{{end}}
+//  <a href="{{.StringOr "lastpage" "/"}}">Continue</a>
+type ClaimsView struct {
+	claims Map
+}
+
+// NewClaimsView returns a ClaimsView of the "claims", which may be nil.
+func NewClaimsView(claims Map) ClaimsView {
+	return ClaimsView{claims: claims}
+}
+
+// View returns a ClaimsView of the token's payload,
+// an empty one if the payload is not a JSON object (e.g. an encrypted payload).
+func (t *VerifiedToken) View() ClaimsView {
+	if t == nil {
+		return ClaimsView{}
+	}
+
+	return viewPayload(t.Payload)
+}
+
+// View returns a ClaimsView of the token's unverified payload,
+// an empty one if the payload is not a JSON object (e.g. an encrypted payload).
+// The claims can't be trusted, see `Decode`.
+func (t *UnverifiedToken) View() ClaimsView {
+	if t == nil {
+		return ClaimsView{}
+	}
+
+	return viewPayload(t.Payload)
+}
+
+func viewPayload(payload []byte) ClaimsView {
+	var claims Map
+	if err := Unmarshal(payload, &claims); err != nil {
+		return ClaimsView{}
+	}
+
+	return ClaimsView{claims: claims}
+}
+
+// Has reports whether the "name" claim exists and it's not null.
+func (v ClaimsView) Has(name string) bool {
+	return v.claims[name] != nil
+}
+
+// Names returns the names of the claims, sorted.
+func (v ClaimsView) Names() []string {
+	names := make([]string, 0, len(v.claims))
+	for name := range v.claims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the raw value of the "name" claim, nil if it's missing.
+func (v ClaimsView) Get(name string) interface{} {
+	return v.claims[name]
+}
+
+// String returns the "name" claim as a string. Numbers and booleans are formatted,
+// any other type (e.g. an object) results in an empty string.
+func (v ClaimsView) String(name string) string {
+	switch value := v.claims[name].(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case int:
+		return strconv.Itoa(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return ""
+	}
+}
+
+// StringOr same as `String` but it returns the "fallback" if the claim is empty.
+func (v ClaimsView) StringOr(name, fallback string) string {
+	if s := v.String(name); s != "" {
+		return s
+	}
+
+	return fallback
+}
+
+// Int returns the "name" claim as an integer, zero if it's not a number.
+func (v ClaimsView) Int(name string) int64 {
+	switch value := v.claims[name].(type) {
+	case float64:
+		return int64(value)
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return int64(f)
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	default:
+		return 0
+	}
+}
+
+// Bool returns the "name" claim as a boolean, false if it's not a boolean.
+func (v ClaimsView) Bool(name string) bool {
+	b, _ := v.claims[name].(bool)
+	return b
+}
+
+// Strings returns the "name" claim as a list of strings,
+// a single string results in a list of one element and the non-string elements are skipped.
+func (v ClaimsView) Strings(name string) []string {
+	switch value := v.claims[name].(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, elem := range value {
+			if s, ok := elem.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+// Time returns the "name" claim, a NumericDate (e.g. "exp"), as a time,
+// the zero time if it's not a number. Use its Format method in templates.
+func (v ClaimsView) Time(name string) time.Time {
+	if n := v.Int(name); n > 0 {
+		return time.Unix(n, 0)
+	}
+
+	return time.Time{}
+}
+
+// View returns a ClaimsView of the "name" claim, an object (e.g. "act" or "address"),
+// an empty one if it's not an object.
+func (v ClaimsView) View(name string) ClaimsView {
+	claims, _ := v.claims[name].(map[string]interface{})
+	return ClaimsView{claims: claims}
+}
+
+// Subject returns the "sub" claim.
+func (v ClaimsView) Subject() string {
+	return v.String("sub")
+}
+
+// Issuer returns the "iss" claim.
+func (v ClaimsView) Issuer() string {
+	return v.String("iss")
+}
+
+// Audience returns the "aud" claim, a single string or a list of strings.
+func (v ClaimsView) Audience() []string {
+	return v.Strings("aud")
+}
+
+// ID returns the "jti" claim.
+func (v ClaimsView) ID() string {
+	return v.String("jti")
+}
+
+// ExpiresAt returns the "exp" claim as a time, the zero time if it's missing.
+func (v ClaimsView) ExpiresAt() time.Time {
+	return v.Time("exp")
+}
+
+// IssuedAt returns the "iat" claim as a time, the zero time if it's missing.
+func (v ClaimsView) IssuedAt() time.Time {
+	return v.Time("iat")
+}
@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"html/template"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaimsView(t *testing.T) {
+	token, err := Sign(NONE, nil, Map{
+		"sub":      "user123",
+		"name":     "<script>alert(1)</script>",
+		"lastpage": "/views/settings",
+		"roles":    []string{"admin", "editor"},
+		"aud":      "app",
+		"age":      42,
+		"pro":      true,
+		"profile":  Map{"city": "Athens"},
+	}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unverifiedToken, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view := unverifiedToken.View()
+	if view.Subject() != "user123" || view.Int("age") != 42 || !view.Bool("pro") || view.String("age") != "42" {
+		t.Fatalf("unexpected view of: %s", unverifiedToken.Payload)
+	}
+
+	if expected := []string{"app"}; !reflect.DeepEqual(view.Audience(), expected) {
+		t.Fatalf("expected audience: %v but got: %v", expected, view.Audience())
+	}
+
+	if view.ExpiresAt().IsZero() || view.View("profile").String("city") != "Athens" {
+		t.Fatalf("unexpected view of: %s", unverifiedToken.Payload)
+	}
+
+	// Missing claims, other types and zero views.
+	var zero ClaimsView
+	if view.String("profile") != "" || view.Int("name") != 0 || view.Strings("missing") != nil ||
+		view.StringOr("missing", "/") != "/" || zero.Has("sub") || zero.View("profile").String("city") != "" ||
+		!zero.Time("exp").IsZero() || len(zero.Names()) != 0 {
+		t.Fatalf("expected zero values")
+	}
+
+	var nilToken *VerifiedToken
+	if nilToken.View().Has("sub") {
+		t.Fatalf("expected an empty view of a nil token")
+	}
+
+	tmpl := template.Must(template.New("").Parse(
+		`<p>{{.String "name"}} ({{.Subject}})</p>{{range .Strings "roles"}}<i>{{.}}</i>{{end}}` +
+			`<a href="{{.StringOr "lastpage" "/"}}">{{.String "missing"}}{{(.View "profile").String "city"}}</a>`))
+
+	var b strings.Builder
+	if err = tmpl.Execute(&b, view); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<p>&lt;script&gt;alert(1)&lt;/script&gt; (user123)</p><i>admin</i><i>editor</i><a href="/views/settings">Athens</a>`
+	if got := b.String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
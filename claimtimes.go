@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// timeClaimNames are the standard numeric date claims of `ClaimTimes`.
+var timeClaimNames = []string{"exp", "iat", "nbf"}
+
+// ClaimTimes returns the "exp", "iat", "nbf" and the "custom" numeric date claims
+// (e.g. "auth_time") of the JSON "payload" in the "loc" time zone,
+// so admin panels and debug output don't convert the Unix timestamps manually.
+// Claims which are missing or null are omitted. A nil "loc" means the time.Local.
+// Returns an error if the payload is not a JSON object or a claim is not a number.
+//
+// Usage:
+//  loc, _ := time.LoadLocation("Europe/Athens")
+//  times, err := jwt.ClaimTimes(verifiedToken.Payload, loc, "auth_time")
+//  expiresAt := times["exp"]
+func ClaimTimes(payload []byte, loc *time.Location, custom ...string) (map[string]time.Time, error) {
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	times := make(map[string]time.Time, len(timeClaimNames)+len(custom))
+	for _, names := range [][]string{timeClaimNames, custom} {
+		for _, name := range names {
+			value, ok := claims[name]
+			if !ok || bytes.Equal(value, []byte("null")) {
+				continue
+			}
+
+			t, err := numericDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("claim %q: %w", name, err)
+			}
+
+			times[name] = t.In(loc)
+		}
+	}
+
+	return times, nil
+}
+
+// FormatClaimTimes same as `ClaimTimes` but it formats the times by the "layout",
+// e.g. time.RFC1123. An empty "layout" means the time.RFC3339.
+//
+// Usage:
+//  times, err := jwt.FormatClaimTimes(verifiedToken.Payload, loc, "02 Jan 2006 15:04 MST")
+//  // map[exp:14 Sep 2020 15:26 EEST iat:13 Sep 2020 15:26 EEST]
+func FormatClaimTimes(payload []byte, loc *time.Location, layout string, custom ...string) (map[string]string, error) {
+	times, err := ClaimTimes(payload, loc, custom...)
+	if err != nil {
+		return nil, err
+	}
+
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	formatted := make(map[string]string, len(times))
+	for name, t := range times {
+		formatted[name] = t.Format(layout)
+	}
+
+	return formatted, nil
+}
+
+// numericDate decodes a JSON NumericDate value, seconds since epoch
+// which may contain a fractional part (RFC 7519 section 2).
+func numericDate(value json.RawMessage) (time.Time, error) {
+	var n json.Number
+	if err := json.Unmarshal(value, &n); err != nil {
+		return time.Time{}, fmt.Errorf("not a numeric date: %s", value)
+	}
+
+	if seconds, err := n.Int64(); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not a numeric date: %s", value)
+	}
+
+	seconds, fraction := math.Modf(f)
+	return time.Unix(int64(seconds), int64(fraction*1e9)), nil
+}
@@ -0,0 +1,40 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClaimTimes(t *testing.T) {
+	loc := time.FixedZone("EEST", 3*60*60)
+	payload := []byte(`{"sub":"kataras","iat":1600000000,"exp":1600003600,"nbf":null,"auth_time":1599999999.5}`)
+
+	times, err := ClaimTimes(payload, loc, "auth_time", "updated_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]time.Time{
+		"iat":       time.Unix(1600000000, 0).In(loc),
+		"exp":       time.Unix(1600003600, 0).In(loc),
+		"auth_time": time.Unix(1599999999, 5e8).In(loc),
+	}
+	if !reflect.DeepEqual(times, expected) {
+		t.Fatalf("expected times: %v but got: %v", expected, times)
+	}
+
+	formatted, err := FormatClaimTimes(payload, loc, "2006-01-02 15:04 MST")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFormatted := map[string]string{"iat": "2020-09-13 15:26 EEST", "exp": "2020-09-13 16:26 EEST"}
+	if !reflect.DeepEqual(formatted, expectedFormatted) {
+		t.Fatalf("expected formatted times: %v but got: %v", expectedFormatted, formatted)
+	}
+
+	if _, err = ClaimTimes([]byte(`{"exp":"tomorrow"}`), loc); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrClientNotAllowed indicates that the token was presented by
+// a client which is not allowed by a context-aware validator, e.g. `IPRange`.
+var ErrClientNotAllowed = errors.New("token presented by a not allowed client")
+
+// ClientInfo holds information about the client which presented the token.
+// Store it to a Context through `WithClientInfo` and pass that context to
+// the `VerifyContext` function so any `ContextValidator` can read it.
+type ClientInfo struct {
+	IP        net.IP               // The client's IP address.
+	UserAgent string               // The client's User-Agent.
+	TLS       *tls.ConnectionState // The connection TLS state, if any (e.g. mTLS client certificates).
+	Method    string               // The HTTP request's method, if any (see `ExpiryGrace`).
+}
+
+type clientInfoContextKey struct{}
+
+// WithClientInfo returns a copy of "ctx" which carries the given client information.
+// Use `GetClientInfo` to retrieve it.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// GetClientInfo returns the client information stored by `WithClientInfo`.
+// Reports false if the "ctx" does not carry any.
+func GetClientInfo(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value(clientInfoContextKey{}).(ClientInfo)
+	return info, ok
+}
+
+// ClientInfoFromRequest returns the client information of an HTTP request.
+// The IP is extracted from the request's RemoteAddr,
+// proxy headers (e.g. X-Forwarded-For) are not trusted here.
+//
+// Usage:
+//  ctx := WithClientInfo(r.Context(), ClientInfoFromRequest(r))
+//  verifiedToken, err := VerifyContext(ctx, alg, key, token, IPRange(network))
+func ClientInfoFromRequest(r *http.Request) ClientInfo {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return ClientInfo{
+		IP:        net.ParseIP(host),
+		UserAgent: r.UserAgent(),
+		TLS:       r.TLS,
+		Method:    r.Method,
+	}
+}
+
+// IPRange is a ContextValidator which binds the token to one or more
+// IP networks. The client's IP is retrieved from the Context (see `WithClientInfo`).
+// If the client information is missing or its IP does not belong
+// to any of the "networks" then it returns ErrClientNotAllowed.
+//
+// Usage:
+//  _, network, _ := net.ParseCIDR("10.0.0.0/8")
+//  verifiedToken, err := VerifyContext(ctx, alg, key, token, IPRange(network))
+func IPRange(networks ...*net.IPNet) ContextValidatorFunc {
+	return func(ctx context.Context, _ []byte, _ Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, ok := GetClientInfo(ctx)
+		if !ok || info.IP == nil {
+			return ErrClientNotAllowed
+		}
+
+		for _, network := range networks {
+			if network.Contains(info.IP) {
+				return nil
+			}
+		}
+
+		return ErrClientNotAllowed
+	}
+}
@@ -0,0 +1,45 @@
+package jwt
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIPRange(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, network, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.1.2.3:1234"
+	ctx := WithClientInfo(context.Background(), ClientInfoFromRequest(r))
+	if _, err = VerifyContext(ctx, testAlg, testSecret, token, IPRange(network)); err != nil {
+		t.Fatalf("expected client to be allowed but got: %v", err)
+	}
+
+	r.RemoteAddr = "192.168.1.2:1234"
+	ctx = WithClientInfo(context.Background(), ClientInfoFromRequest(r))
+	if _, err = VerifyContext(ctx, testAlg, testSecret, token, IPRange(network)); err != ErrClientNotAllowed {
+		t.Fatalf("expected error: %v but got: %v", ErrClientNotAllowed, err)
+	}
+
+	// Test missing client information.
+	if _, err = Verify(testAlg, testSecret, token, IPRange(network)); err != ErrClientNotAllowed {
+		t.Fatalf("expected error: %v but got: %v", ErrClientNotAllowed, err)
+	}
+
+	// Test respect previous error.
+	err = IPRange(network).ValidateTokenContext(ctx, token, Claims{}, ErrExpired)
+	if err != ErrExpired {
+		t.Fatalf("expected to respect previous error 'ErrExpired' but got: %v", err)
+	}
+}
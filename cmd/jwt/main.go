@@ -0,0 +1,50 @@
+// Command jwt is a command line companion of the github.com/kataras/jwt package.
+//
+// Usage:
+//
+//  go install github.com/kataras/jwt/cmd/jwt@latest
+//  jwt testdata -key ./private_key.pem -aud orders > testdata/tokens.json
+//
+// Run "jwt [command] -h" for the flags of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: jwt <command> [flags]
+
+Commands:
+  testdata  generates fixture tokens (valid, expired, wrong audience, tampered signature, alg-confused)
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "jwt:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return flag.ErrHelp
+	}
+
+	switch args[0] {
+	case "testdata":
+		return runTestdata(args[1:], stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stderr, usage)
+		return flag.ErrHelp
+	default:
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+// algs are the signing algorithms of the -alg flag, by name.
+var algs = map[string]jwt.Alg{
+	"HS256": jwt.HS256, "HS384": jwt.HS384, "HS512": jwt.HS512,
+	"RS256": jwt.RS256, "RS384": jwt.RS384, "RS512": jwt.RS512,
+	"PS256": jwt.PS256, "PS384": jwt.PS384, "PS512": jwt.PS512,
+	"ES256": jwt.ES256, "ES384": jwt.ES384, "ES512": jwt.ES512,
+	"EdDSA": jwt.EdDSA,
+}
+
+// fixture is a generated token and its expected verification result.
+type fixture struct {
+	Name   string          `json:"name"`
+	Token  string          `json:"token"`
+	Claims json.RawMessage `json:"claims"`
+	Valid  bool            `json:"valid"`
+	Error  string          `json:"error,omitempty"` // the expected jwt error, e.g. "token expired".
+}
+
+// fixtures is the output of the testdata command.
+type fixtures struct {
+	Generator string    `json:"generator"`
+	Alg       string    `json:"alg"`
+	Time      int64     `json:"time"` // the generation time (seconds since epoch).
+	Tokens    []fixture `json:"tokens"`
+}
+
+type testdataOptions struct {
+	alg      jwt.Alg
+	key      jwt.PrivateKey
+	public   crypto.PublicKey // nil for HMAC keys.
+	issuer   string
+	subject  string
+	audience string
+	maxAge   time.Duration
+	now      time.Time
+}
+
+func runTestdata(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("testdata", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, "Usage: jwt testdata -key <file> [flags]\n\n"+
+			"Generates fixture tokens signed by the key file (a PEM private key or an HMAC secret) for negative tests:\n"+
+			"valid, expired, wrong-aud, tampered-signature and alg-confused.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	keyFile := flags.String("key", "", "the private key (PEM) or the HMAC secret file, required")
+	algName := flags.String("alg", "", "the signing algorithm, defaults to RS256, ES256/384/512 (by curve), EdDSA or HS256 (by key)")
+	issuer := flags.String("iss", "testdata", "the \"iss\" claim")
+	subject := flags.String("sub", "kataras", "the \"sub\" claim")
+	audience := flags.String("aud", "testdata", "the \"aud\" claim")
+	maxAge := flags.Duration("max-age", 100*365*24*time.Hour, "the lifetime of the valid tokens")
+	unix := flags.Int64("time", 0, "the generation time (seconds since epoch), defaults to now")
+	out := flags.String("out", "", "the output file, defaults to the standard output")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *keyFile == "" {
+		flags.Usage()
+		return fmt.Errorf("testdata: -key is required")
+	}
+
+	data, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return fmt.Errorf("testdata: %w", err)
+	}
+
+	opts, err := parseKey(data, *algName)
+	if err != nil {
+		return fmt.Errorf("testdata: %w", err)
+	}
+
+	opts.issuer, opts.subject, opts.audience, opts.maxAge = *issuer, *subject, *audience, *maxAge
+	opts.now = time.Now()
+	if *unix > 0 {
+		opts.now = time.Unix(*unix, 0)
+	}
+
+	result, err := generateFixtures(opts)
+	if err != nil {
+		return fmt.Errorf("testdata: %w", err)
+	}
+
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if *out == "" {
+		_, err = stdout.Write(b)
+		return err
+	}
+
+	return os.WriteFile(*out, b, 0644)
+}
+
+// parseKey parses a PEM private key (RSA, ECDSA or Ed25519) or an HMAC secret
+// and it resolves the signing algorithm, "algName" overrides the default one of the key.
+func parseKey(data []byte, algName string) (testdataOptions, error) {
+	var (
+		opts        testdataOptions
+		defaultName string
+	)
+
+	if privateKey, err := jwt.ParsePrivateKeyRSA(data); err == nil {
+		opts.key, opts.public, defaultName = privateKey, &privateKey.PublicKey, "RS256"
+	} else if privateKey, err := jwt.ParsePrivateKeyECDSA(data); err == nil {
+		opts.key, opts.public = privateKey, &privateKey.PublicKey
+		switch privateKey.Curve {
+		case elliptic.P384():
+			defaultName = "ES384"
+		case elliptic.P521():
+			defaultName = "ES512"
+		default:
+			defaultName = "ES256"
+		}
+	} else if privateKey, err := jwt.ParsePrivateKeyEdDSA(data); err == nil {
+		opts.key, opts.public, defaultName = privateKey, privateKey.Public(), "EdDSA"
+	} else if block, _ := pem.Decode(data); block != nil {
+		return opts, fmt.Errorf("unsupported %s key", block.Type)
+	} else {
+		opts.key, defaultName = data, "HS256" // a shared secret.
+	}
+
+	if algName == "" {
+		algName = defaultName
+	}
+
+	alg, ok := algs[algName]
+	if !ok {
+		return opts, fmt.Errorf("unknown algorithm %q", algName)
+	}
+	opts.alg = alg
+
+	return opts, nil
+}
+
+// generateFixtures signs the fixture tokens of the "opts".
+func generateFixtures(opts testdataOptions) (*fixtures, error) {
+	result := &fixtures{
+		Generator: "github.com/kataras/jwt/cmd/jwt",
+		Alg:       opts.alg.Name(),
+		Time:      opts.now.Unix(),
+	}
+
+	add := func(name string, alg jwt.Alg, key jwt.PrivateKey, claims jwt.Claims, expected error) error {
+		payload, err := json.Marshal(claims)
+		if err != nil {
+			return err
+		}
+
+		token, err := jwt.Sign(alg, key, claims)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		f := fixture{Name: name, Token: string(token), Claims: payload, Valid: expected == nil}
+		if expected != nil {
+			f.Error = expected.Error()
+		}
+
+		result.Tokens = append(result.Tokens, f)
+		return nil
+	}
+
+	valid := opts.claims(opts.now, opts.maxAge)
+	if err := add("valid", opts.alg, opts.key, valid, nil); err != nil {
+		return nil, err
+	}
+
+	expired := opts.claims(opts.now.Add(-2*time.Hour), time.Hour)
+	if err := add("expired", opts.alg, opts.key, expired, jwt.ErrExpired); err != nil {
+		return nil, err
+	}
+
+	wrongAudience := valid
+	wrongAudience.Audience = []string{opts.audience + ".invalid"}
+	if err := add("wrong-aud", opts.alg, opts.key, wrongAudience, jwt.ErrAudienceMismatch); err != nil {
+		return nil, err
+	}
+
+	if err := add("tampered-signature", opts.alg, opts.key, valid, jwt.ErrTokenSignature); err != nil {
+		return nil, err
+	}
+	tampered := &result.Tokens[len(result.Tokens)-1]
+	tampered.Token = tamperSignature(tampered.Token)
+
+	// An asymmetric key's token re-signed by HS256 with the PEM public key as the secret
+	// (the classic algorithm confusion attack) or an unsecured token of a shared secret.
+	var err error
+	if opts.public != nil {
+		var publicKey []byte
+		if publicKey, err = encodePublicKey(opts.public); err == nil {
+			err = add("alg-confused", jwt.HS256, publicKey, valid, jwt.ErrTokenAlg)
+		}
+	} else {
+		err = add("alg-confused", jwt.NONE, nil, valid, jwt.ErrTokenAlg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (opts testdataOptions) claims(issuedAt time.Time, maxAge time.Duration) jwt.Claims {
+	return jwt.Claims{
+		Issuer:   opts.issuer,
+		Subject:  opts.subject,
+		Audience: []string{opts.audience},
+		IssuedAt: issuedAt.Unix(),
+		Expiry:   issuedAt.Add(maxAge).Unix(),
+	}
+}
+
+// tamperSignature flips the first character of the token's signature part.
+func tamperSignature(token string) string {
+	i := strings.LastIndexByte(token, '.') + 1
+	if i >= len(token) {
+		return token
+	}
+
+	c := byte('A')
+	if token[i] == 'A' {
+		c = 'B'
+	}
+
+	return token[:i] + string(c) + token[i+1:]
+}
+
+// encodePublicKey returns the PEM-encoded (PKIX) form of the public key,
+// the bytes which a vulnerable verifier may use as an HMAC secret.
+func encodePublicKey(public crypto.PublicKey) ([]byte, error) {
+	switch public.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported public key of type %T", public)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
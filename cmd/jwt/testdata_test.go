@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kataras/jwt"
+)
+
+func TestTestdata(t *testing.T) {
+	tests := []struct {
+		alg                   jwt.Alg
+		privateKey, publicKey string
+	}{
+		{jwt.HS256, "hmac.key", "hmac.key"},
+		{jwt.RS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+		{jwt.ES256, "ecdsa_private_key.pem", "ecdsa_public_key.pem"},
+		{jwt.EdDSA, "ed25519_private_key.pem", "ed25519_public_key.pem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg.Name(), func(t *testing.T) {
+			var stdout bytes.Buffer
+			if err := run([]string{"testdata", "-key", "../../_testfiles/" + tt.privateKey, "-aud", "orders"}, &stdout, io.Discard); err != nil {
+				t.Fatal(err)
+			}
+
+			var result fixtures
+			if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Alg != tt.alg.Name() || len(result.Tokens) != 5 {
+				t.Fatalf("unexpected fixtures: %s", stdout.String())
+			}
+
+			publicKey := loadPublicKey(t, tt.alg, "../../_testfiles/"+tt.publicKey)
+			for _, f := range result.Tokens {
+				_, err := jwt.Verify(tt.alg, publicKey, []byte(f.Token), jwt.Expected{Audience: []string{"orders"}})
+				if f.Valid {
+					if err != nil {
+						t.Fatalf("%s: %v", f.Name, err)
+					}
+					continue
+				}
+
+				if err == nil || !strings.HasPrefix(err.Error(), f.Error) {
+					t.Fatalf("%s: expected error: %s but got: %v", f.Name, f.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestTestdataFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := run([]string{"testdata"}, &stdout, io.Discard); err == nil {
+		t.Fatalf("expected an error of a missing -key flag")
+	}
+
+	if err := run([]string{"testdata", "-key", "../../_testfiles/rsa_private_key.pem", "-alg", "HS1"}, &stdout, io.Discard); err == nil {
+		t.Fatalf("expected an error of an unknown algorithm")
+	}
+
+	if err := run([]string{"unknown"}, &stdout, io.Discard); err == nil {
+		t.Fatalf("expected an error of an unknown command")
+	}
+
+	args := []string{"testdata", "-key", "../../_testfiles/ed25519_private_key.pem", "-time", "1600000000"}
+	var a, b bytes.Buffer
+	if err := run(args, &a, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(args, &b, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Fatalf("expected the same fixtures of the same generation time")
+	}
+}
+
+func loadPublicKey(t *testing.T, alg jwt.Alg, filename string) jwt.PublicKey {
+	t.Helper()
+
+	var (
+		key interface{}
+		err error
+	)
+
+	switch alg {
+	case jwt.HS256:
+		key, err = os.ReadFile(filename)
+	case jwt.RS256:
+		key, err = jwt.LoadPublicKeyRSA(filename)
+	case jwt.ES256:
+		key, err = jwt.LoadPublicKeyECDSA(filename)
+	default:
+		key, err = jwt.LoadPublicKeyEdDSA(filename)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
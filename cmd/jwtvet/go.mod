@@ -0,0 +1,5 @@
+module github.com/kataras/jwt/cmd/jwtvet
+
+go 1.18
+
+require golang.org/x/tools v0.12.0
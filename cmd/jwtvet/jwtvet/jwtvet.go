@@ -0,0 +1,180 @@
+// Package jwtvet defines an Analyzer which reports the risky uses
+// of the github.com/kataras/jwt package in the non-test Go files:
+// short string literals passed as HMAC keys and any use of the unsecured NONE algorithm.
+package jwtvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const jwtImportPath = "github.com/kataras/jwt"
+
+// hmacKeySizes are the minimum key sizes (the hash sizes) of the HMAC algorithms, see RFC 7518 section 3.2.
+var hmacKeySizes = map[string]int{
+	"HS256": 32,
+	"HS384": 48,
+	"HS512": 64,
+}
+
+// Analyzer reports the calls of the jwt package which pass a short literal key
+// (e.g. jwt.Sign(jwt.HS256, []byte("secret"), claims)) to an HMAC algorithm
+// and the uses of the jwt.NONE algorithm. Test files are skipped.
+var Analyzer = &analysis.Analyzer{
+	Name: "jwtvet",
+	Doc:  "report short literal HMAC keys and the NONE algorithm of the github.com/kataras/jwt package",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var files []*ast.File
+	for _, file := range pass.Files {
+		if !strings.HasSuffix(pass.Fset.Position(file.Package).Filename, "_test.go") {
+			files = append(files, file)
+		}
+	}
+
+	literals := byteLiterals(pass, files)
+
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.Ident:
+				if isJWTObject(pass.TypesInfo.Uses[n], "NONE") {
+					pass.Reportf(n.Pos(), "jwt.NONE produces unsecured tokens, use it in tests only")
+				}
+			case *ast.CallExpr:
+				checkKey(pass, n, literals)
+			}
+
+			return true
+		})
+	}
+
+	return nil, nil
+}
+
+// checkKey reports the "call" of a jwt package function
+// which passes a short literal key to an HMAC algorithm.
+func checkKey(pass *analysis.Pass, call *ast.CallExpr, literals map[types.Object]string) {
+	if len(call.Args) < 2 {
+		return
+	}
+
+	if fn, ok := calleeObject(pass, call.Fun).(*types.Func); !ok || !isJWTObject(fn, "") {
+		return
+	}
+
+	alg := calleeObject(pass, call.Args[0])
+	if !isJWTObject(alg, "") {
+		return
+	}
+
+	minSize, ok := hmacKeySizes[alg.Name()]
+	if !ok {
+		return
+	}
+
+	key, ok := byteLiteral(pass, call.Args[1])
+	if !ok {
+		if ident, isIdent := call.Args[1].(*ast.Ident); isIdent {
+			key, ok = literals[pass.TypesInfo.Uses[ident]]
+		}
+	}
+
+	if ok && len(key) < minSize {
+		pass.Reportf(call.Args[1].Pos(), "%s key of %d bytes is shorter than %d bytes, load a random secret instead of a string literal", alg.Name(), len(key), minSize)
+	}
+}
+
+// calleeObject returns the object of an identifier or a selector expression, if any.
+func calleeObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return pass.TypesInfo.Uses[expr]
+	case *ast.SelectorExpr:
+		return pass.TypesInfo.Uses[expr.Sel]
+	default:
+		return nil
+	}
+}
+
+// isJWTObject reports whether the "obj" is declared at the package level of the jwt package,
+// if "name" is not empty it must be its name too.
+func isJWTObject(obj types.Object, name string) bool {
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != jwtImportPath || obj.Parent() != obj.Pkg().Scope() {
+		return false
+	}
+
+	return name == "" || obj.Name() == name
+}
+
+// byteLiterals returns the variables of the "files" which are assigned to a []byte("...") literal.
+func byteLiterals(pass *analysis.Pass, files []*ast.File) map[types.Object]string {
+	literals := make(map[types.Object]string)
+	add := func(names []*ast.Ident, values []ast.Expr) {
+		if len(names) != len(values) {
+			return
+		}
+
+		for i, name := range names {
+			if value, ok := byteLiteral(pass, values[i]); ok {
+				if obj := pass.TypesInfo.ObjectOf(name); obj != nil {
+					literals[obj] = value
+				}
+			}
+		}
+	}
+
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				add(n.Names, n.Values)
+			case *ast.AssignStmt:
+				names := make([]*ast.Ident, 0, len(n.Lhs))
+				for _, lhs := range n.Lhs {
+					ident, ok := lhs.(*ast.Ident)
+					if !ok {
+						return true
+					}
+					names = append(names, ident)
+				}
+				add(names, n.Rhs)
+			}
+
+			return true
+		})
+	}
+
+	return literals
+}
+
+// byteLiteral reports the value of a []byte conversion of a constant string, e.g. []byte("...").
+func byteLiteral(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+
+	typ, ok := pass.TypesInfo.Types[call.Fun]
+	if !ok || !typ.IsType() {
+		return "", false
+	}
+
+	slice, ok := typ.Type.Underlying().(*types.Slice)
+	if !ok || !types.Identical(slice.Elem(), types.Typ[types.Byte]) {
+		return "", false
+	}
+
+	value := pass.TypesInfo.Types[call.Args[0]].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(value), true
+}
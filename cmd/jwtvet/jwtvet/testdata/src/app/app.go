@@ -0,0 +1,28 @@
+package app
+
+import (
+	"os"
+
+	j "github.com/kataras/jwt"
+)
+
+var sharedKey = []byte("sercrethatmaycontainch@r$32chars")
+
+const constKey = "short"
+
+func sign(claims interface{}) {
+	weak := []byte("secret")
+	j.Sign(j.HS256, []byte("short"), claims) // want `HS256 key of 5 bytes is shorter than 32 bytes`
+	j.Sign(j.HS512, sharedKey, claims)       // want `HS512 key of 32 bytes is shorter than 64 bytes`
+	j.NewVerifier(j.HS256, weak)             // want `HS256 key of 6 bytes is shorter than 32 bytes`
+	j.NewVerifier(j.HS256, []byte(constKey)) // want `HS256 key of 5 bytes is shorter than 32 bytes`
+	j.NewVerifier(j.HS256, sharedKey)
+	j.NewVerifier(j.HS256, []byte(os.Getenv("KEY")))
+	j.Sign(j.EdDSA, []byte("short"), claims)
+	j.Verify(j.NONE, nil, nil) // want `jwt.NONE produces unsecured tokens`
+}
+
+// NONE is not the jwt one.
+var NONE = "none"
+
+func local() string { return NONE }
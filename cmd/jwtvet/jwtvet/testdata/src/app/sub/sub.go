@@ -0,0 +1,5 @@
+package sub
+
+import . "github.com/kataras/jwt"
+
+var alg = NONE // want `jwt.NONE produces unsecured tokens`
@@ -0,0 +1,21 @@
+// Package jwt is a stub of the github.com/kataras/jwt package for the jwtvet tests.
+package jwt
+
+type Alg interface{ Name() string }
+
+type alg string
+
+func (a alg) Name() string { return string(a) }
+
+var (
+	NONE  Alg = alg("none")
+	HS256 Alg = alg("HS256")
+	HS512 Alg = alg("HS512")
+	EdDSA Alg = alg("EdDSA")
+)
+
+func Sign(alg Alg, key interface{}, claims interface{}) ([]byte, error) { return nil, nil }
+
+func Verify(alg Alg, key interface{}, token []byte) (interface{}, error) { return nil, nil }
+
+func NewVerifier(alg Alg, key interface{}) interface{} { return nil }
@@ -0,0 +1,27 @@
+// Command jwtvet reports the risky uses of the github.com/kataras/jwt package
+// in the non-test Go files, for the CI pipelines of its users, see the `jwtvet.Analyzer`:
+//
+//  - short string literals passed as HMAC keys, e.g. jwt.Sign(jwt.HS256, []byte("secret"), claims);
+//  - any use of the unsecured jwt.NONE algorithm.
+//
+// It exits with a non-zero status if any issue is found.
+//
+// Usage:
+//
+//  go install github.com/kataras/jwt/cmd/jwtvet@latest
+//  jwtvet ./...
+//
+// Or through the go vet command:
+//
+//  go vet -vettool=$(which jwtvet) ./...
+package main
+
+import (
+	"github.com/kataras/jwt/cmd/jwtvet/jwtvet"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(jwtvet.Analyzer)
+}
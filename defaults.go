@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoDefault indicates a `SignDefault` or a `VerifyDefault` call
+// before a default Issuer or Verifier was registered through `SetDefault`.
+var ErrNoDefault = errors.New("no default issuer or verifier")
+
+var defaults struct {
+	mu       sync.RWMutex
+	issuer   *Issuer
+	verifier *Verifier
+}
+
+// SetDefault registers the package-level default "issuer" and "verifier"
+// of the `SignDefault` and `VerifyDefault` one-liners. Either of them can be nil.
+// It's safe to call it concurrently with the signing and verifying of tokens,
+// e.g. to reconfigure the keys of a running application, the calls in flight
+// keep using the previous Issuer and Verifier.
+// Large applications should pass their Issuers and Verifiers explicitly instead.
+//
+// Usage:
+//  jwt.SetDefault(jwt.NewIssuer(jwt.HS256, sharedKey, 15*time.Minute), jwt.NewVerifier(jwt.HS256, sharedKey))
+//  token, err := jwt.SignDefault("kataras", nil)
+//  verifiedToken, err := jwt.VerifyDefault(token)
+func SetDefault(issuer *Issuer, verifier *Verifier) {
+	defaults.mu.Lock()
+	defaults.issuer = issuer
+	defaults.verifier = verifier
+	defaults.mu.Unlock()
+}
+
+// Default returns the default Issuer and Verifier registered through `SetDefault`, if any.
+func Default() (*Issuer, *Verifier) {
+	defaults.mu.RLock()
+	defer defaults.mu.RUnlock()
+
+	return defaults.issuer, defaults.verifier
+}
+
+// SignDefault generates a new token for the given "subject" through the default Issuer,
+// see `SetDefault` and `Issuer.Token`. Returns ErrNoDefault if there is no default Issuer.
+func SignDefault(subject string, customClaims interface{}) ([]byte, error) {
+	issuer, _ := Default()
+	if issuer == nil {
+		return nil, ErrNoDefault
+	}
+
+	return issuer.Token(subject, customClaims)
+}
+
+// VerifyDefault verifies the given "token" through the default Verifier,
+// see `SetDefault` and `Verifier.VerifyToken`. Returns ErrNoDefault if there is no default Verifier.
+func VerifyDefault(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	_, verifier := Default()
+	if verifier == nil {
+		return nil, ErrNoDefault
+	}
+
+	return verifier.VerifyToken(token, validators...)
+}
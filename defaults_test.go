@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDefault(t *testing.T) {
+	defer SetDefault(nil, nil)
+
+	if _, err := SignDefault("kataras", nil); !errors.Is(err, ErrNoDefault) {
+		t.Fatalf("expected error: %v but got: %v", ErrNoDefault, err)
+	}
+
+	if _, err := VerifyDefault([]byte("token")); !errors.Is(err, ErrNoDefault) {
+		t.Fatalf("expected error: %v but got: %v", ErrNoDefault, err)
+	}
+
+	SetDefault(NewIssuer(testAlg, testSecret, time.Minute), NewVerifier(testAlg, testSecret))
+
+	token, err := SignDefault("kataras", Map{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyDefault(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("expected subject: kataras but got: %s", verifiedToken.StandardClaims.Subject)
+	}
+
+	// Reconfigure concurrently with signing and verifying.
+	otherSecret := []byte("another secret key of 32 bytes..")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetDefault(NewIssuer(testAlg, otherSecret, time.Minute), NewVerifier(testAlg, otherSecret))
+		}()
+		go func() {
+			defer wg.Done()
+			if token, err := SignDefault("kataras", nil); err == nil {
+				VerifyDefault(token)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err = VerifyDefault(token); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
@@ -0,0 +1,199 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CriticalHeaders is a list of the "crit" header extensions (RFC 7515 section 4.1.11)
+// the application understands, besides the builtin "b64" one (RFC 7797).
+// Tokens which list any other extension are rejected with ErrHeaderCritical.
+// The application should validate the values of its extensions, e.g. through `VerifiedToken.Header`.
+//
+// Usage:
+//  jwt.CriticalHeaders = []string{"http://openbanking.org.uk/iat"}
+var CriticalHeaders []string
+
+var detachedSep = []byte("..")
+
+// SignDetached signs the "payload" of "alg" algorithm and "key" private key
+// and returns a token with a detached payload (RFC 7515 Appendix F): "header..signature".
+// The payload travels separately, e.g. as the body of an HTTP request, see `VerifyDetached`.
+//
+// If the "header" is nil, the payload is signed unencoded (RFC 7797), the header is:
+//  {"alg":"[alg]","b64":false,"crit":["b64"]}
+// Otherwise the "header" is written exactly as `SignWithHeader` writes it,
+// the payload is signed unencoded if its "b64" field is false, which must be listed in its "crit" field.
+//
+// Usage:
+//  signature, err := jwt.SignDetached(jwt.PS256, privateKey, body, jwt.Header{
+//   {Name: "alg", Value: "PS256"},
+//   {Name: "kid", Value: kid},
+//   {Name: "b64", Value: false},
+//   {Name: "crit", Value: []string{"b64"}},
+//  })
+//  // r.Header.Set("X-JWS-Signature", string(signature))
+func SignDetached(alg AlgSigner, key PrivateKey, payload []byte, header Header) ([]byte, error) {
+	if header == nil {
+		header = Header{{Name: "alg", Value: alg.Name()}, {Name: "b64", Value: false}, {Name: "crit", Value: []string{"b64"}}}
+	}
+
+	encoded, err := header.encode(alg.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var h tokenHeader
+	if err = json.Unmarshal(encoded, &h); err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	if h.B64 != nil && !containsString(h.Crit, "b64") {
+		return nil, fmt.Errorf("%w: b64 is not listed", ErrHeaderCritical)
+	}
+
+	encodedHeader := Base64Encode(encoded)
+	signature, err := alg.Sign(key, detachedSigningInput(encodedHeader, payload, h))
+	if err != nil {
+		return nil, fmt.Errorf("signDetached: signature: %w", err)
+	}
+
+	// header..signature
+	token := make([]byte, 0, len(encodedHeader)+len(detachedSep)+base64.RawURLEncoding.EncodedLen(len(signature)))
+	token = append(token, encodedHeader...)
+	token = append(token, detachedSep...)
+	token = appendBase64(token, signature)
+
+	kid, _ := header.value("kid").(string)
+	audit(alg, kid, payload)
+	return token, nil
+}
+
+// detachedSigningInput returns the header.payload bytes which are signed,
+// the payload is base64-encoded unless the "b64" header is false.
+func detachedSigningInput(encodedHeader, payload []byte, h tokenHeader) []byte {
+	unencoded := h.B64 != nil && !*h.B64
+
+	payloadSize := len(payload)
+	if !unencoded {
+		payloadSize = base64.RawURLEncoding.EncodedLen(payloadSize)
+	}
+
+	input := make([]byte, 0, len(encodedHeader)+len(sep)+payloadSize)
+	input = append(input, encodedHeader...)
+	input = append(input, sep...)
+
+	if unencoded {
+		return append(input, payload...)
+	}
+
+	return appendBase64(input, payload)
+}
+
+// VerifyDetached verifies a token of a detached payload (see `SignDetached`)
+// against the "payload" which was received separately.
+// The header's "alg" must match the "alg" algorithm and its "crit" field may only list
+// the "b64" and the `CriticalHeaders` extensions, otherwise it returns ErrHeaderCritical.
+//
+// If the payload is a JSON object (or "validators" are given) it's validated as claims, like `Verify` does,
+// e.g. an expired "exp" claim returns ErrExpired. Any other payload is not validated.
+//
+// Usage:
+//  verifiedToken, err := jwt.VerifyDetached(jwt.PS256, publicKey, []byte(r.Header.Get("X-JWS-Signature")), body)
+func VerifyDetached(alg AlgVerifier, key PublicKey, token, payload []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyDetachedContext(context.Background(), alg, key, token, payload, validators...)
+}
+
+// VerifyDetachedContext same as `VerifyDetached` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+func VerifyDetachedContext(ctx context.Context, alg AlgVerifier, key PublicKey, token, payload []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	report := verifyReport(ctx)
+	start := report.now()
+
+	verifiedToken, err := verifyDetached(ctx, alg, key, token, payload, validators)
+	recordVerified(token, verifiedToken, err)
+
+	if report != nil {
+		report.Token, report.Err, report.Duration = verifiedToken, err, time.Since(start)
+	}
+
+	return verifiedToken, err
+}
+
+func verifyDetached(ctx context.Context, alg AlgVerifier, key PublicKey, token, payload []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	report := verifyReport(ctx)
+	if len(token) == 0 {
+		report.add("form", report.now(), ErrMissing)
+		return nil, ErrMissing
+	}
+
+	start := report.now()
+	encodedHeader, attached, encodedSignature, ok := splitToken(token)
+	if !ok || len(attached) > 0 {
+		report.add("form", start, ErrTokenForm)
+		return nil, ErrTokenForm
+	}
+	report.add("form", start, nil)
+
+	start = report.now()
+	header, h, err := decodeDetachedHeader(alg.Name(), encodedHeader)
+	report.add("header", start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := Base64Decode(encodedSignature)
+	if err != nil {
+		return nil, ErrTokenForm
+	}
+
+	start = report.now()
+	err = alg.Verify(key, detachedSigningInput(encodedHeader, payload, h), signature)
+	report.add("signature", start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if len(validators) > 0 || isJSONObject(payload) {
+		if claims, err = validatePayload(ctx, token, payload, validators); err != nil {
+			return nil, err
+		}
+	}
+
+	return &VerifiedToken{
+		Token:          token,
+		Header:         header,
+		Payload:        payload,
+		Signature:      signature,
+		StandardClaims: claims,
+	}, nil
+}
+
+func decodeDetachedHeader(alg string, encodedHeader []byte) ([]byte, tokenHeader, error) {
+	var h tokenHeader
+
+	header, err := Base64Decode(encodedHeader)
+	if err != nil {
+		return nil, h, ErrTokenForm
+	}
+
+	if err = json.Unmarshal(header, &h); err != nil || h.Alg != alg {
+		return nil, h, ErrTokenAlg
+	}
+
+	if err = h.checkCritical(true); err != nil {
+		return nil, h, err
+	}
+
+	return header, h, nil
+}
+
+func isJSONObject(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '{'
+}
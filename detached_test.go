@@ -0,0 +1,148 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignDetached(t *testing.T) {
+	body := []byte(`{"amount":"10.00","currency":"EUR"}`)
+
+	token, err := SignDetached(testAlg, testSecret, body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// {"alg":"HS256","b64":false,"crit":["b64"]}
+	expectedHeader := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19.."
+	if !bytes.HasPrefix(token, []byte(expectedHeader)) {
+		t.Fatalf("expected a detached token of header: %s but got: %s", expectedHeader, token)
+	}
+
+	verifiedToken, err := VerifyDetached(testAlg, testSecret, token, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(verifiedToken.Payload, body) {
+		t.Fatalf("expected payload: %s but got: %s", body, verifiedToken.Payload)
+	}
+
+	if _, err = VerifyDetached(testAlg, testSecret, token, []byte(`{"amount":"1000.00","currency":"EUR"}`)); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// The detached token is not a compact token of an attached payload.
+	if _, err = Verify(testAlg, testSecret, token); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestSignDetachedEncoded(t *testing.T) {
+	// RFC 7515 Appendix F: a detached, base64-encoded, payload.
+	header := Header{{Name: "alg", Value: "HS256"}, {Name: "typ", Value: "JWT"}}
+	body := []byte("not a json object")
+
+	token, err := SignDetached(testAlg, testSecret, body, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attached, err := SignWithHeader(testAlg, testSecret, header, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same signature as the attached payload's one.
+	if got, expected := token[bytes.LastIndexByte(token, '.'):], attached[bytes.LastIndexByte(attached, '.'):]; !bytes.Equal(got, expected) {
+		t.Fatalf("expected signature: %s but got: %s", expected, got)
+	}
+
+	if _, err = VerifyDetached(testAlg, testSecret, token, body); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyDetached(testAlg, testSecret, attached, body); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+}
+
+func TestVerifyDetachedClaims(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	body := []byte(`{"sub":"kataras","exp":1600000060}`)
+	token, err := SignDetached(testAlg, testSecret, body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyDetached(testAlg, testSecret, token, body, Expected{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("expected the subject claim but got: %#+v", verifiedToken.StandardClaims)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err = VerifyDetached(testAlg, testSecret, token, body); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+}
+
+func TestCriticalHeaders(t *testing.T) {
+	const extension = "http://openbanking.org.uk/iat"
+	defer func() { CriticalHeaders = nil }()
+
+	body := []byte("body")
+	header := Header{
+		{Name: "alg", Value: "HS256"},
+		{Name: "b64", Value: false},
+		{Name: extension, Value: 1600000000},
+		{Name: "crit", Value: []string{"b64", extension}},
+	}
+
+	token, err := SignDetached(testAlg, testSecret, body, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyDetached(testAlg, testSecret, token, body); !errors.Is(err, ErrHeaderCritical) {
+		t.Fatalf("expected error: %v but got: %v", ErrHeaderCritical, err)
+	}
+
+	CriticalHeaders = []string{extension}
+	if _, err = VerifyDetached(testAlg, testSecret, token, body); err != nil {
+		t.Fatal(err)
+	}
+
+	// The "b64" header must be listed as critical.
+	if _, err = SignDetached(testAlg, testSecret, body, Header{{Name: "alg", Value: "HS256"}, {Name: "b64", Value: false}}); !errors.Is(err, ErrHeaderCritical) {
+		t.Fatalf("expected error: %v but got: %v", ErrHeaderCritical, err)
+	}
+
+	// Attached payloads are rejected on unsupported critical extensions too.
+	attached, err := SignWithHeader(testAlg, testSecret, Header{{Name: "alg", Value: "HS256"}, {Name: "crit", Value: []string{"exp"}}}, Map{"sub": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, attached); !errors.Is(err, ErrHeaderCritical) {
+		t.Fatalf("expected error: %v but got: %v", ErrHeaderCritical, err)
+	}
+
+	// Unencoded payloads of attached tokens are not supported.
+	attached, err = SignWithHeader(testAlg, testSecret, Header{{Name: "alg", Value: "HS256"}, {Name: "b64", Value: false}, {Name: "crit", Value: []string{"b64"}}}, Map{"sub": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, attached); !errors.Is(err, ErrHeaderCritical) {
+		t.Fatalf("expected error: %v but got: %v", ErrHeaderCritical, err)
+	}
+}
@@ -0,0 +1,108 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrDownscope indicates an `Issuer.Downscope` request which keeps none of the token's scopes
+// or none of its audiences.
+var ErrDownscope = errors.New("downscope: no granted scope or audience")
+
+// downscopeReserved are the claims which are never carried over by `Issuer.Downscope`,
+// even if they are listed in the DownscopeClaims: the narrower token's own scopes, version and lineage.
+var downscopeReserved = append([]string{"scope", "scp", ClaimVersion, ClaimParent}, standardClaimNames...)
+
+// Downscope verifies the "token" through the "verifier" and generates a narrower token
+// of the Issuer (its key and "iss" claim), for the same subject, to pass to a less trusted subsystem.
+// The new token grants the scopes of the token ("scope" or "scp" claim) which are listed in the "allowedScopes",
+// as a "scope" claim, and it is for the audiences of the token which are listed in the "audiences",
+// all of its audiences if "audiences" is empty (any of the "audiences" if the token has none).
+// Its lifetime is the Issuer's one, capped to the token's remaining lifetime,
+// so it never outlives the token. Only the custom claims listed in the Issuer's DownscopeClaims
+// are carried over, so privilege claims such as "roles", "groups" or "act" never reach the subsystem
+// unless they are explicitly allowed.
+//
+// Returns ErrDownscope if no scope (or no audience) is kept
+// and ErrExpired if the token expires in less than a second.
+//
+// Usage:
+//  issuer := jwt.NewIssuer(jwt.EdDSA, localKey, time.Minute)
+//  issuer.DownscopeClaims = []string{"tenant"}
+//  narrow, err := issuer.Downscope(ctx, verifier, token, []string{"orders:read"}, "reports-worker")
+func (i *Issuer) Downscope(ctx context.Context, verifier *Verifier, token []byte, allowedScopes []string, audiences ...string) ([]byte, error) {
+	verifiedToken, err := verifier.VerifyTokenContext(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Map
+	if err = defaultUnmarshal(verifiedToken.Payload, &claims); err != nil {
+		return nil, err
+	}
+
+	var scopes []string
+	for _, scope := range grantedScopes(claims) {
+		if containsString(allowedScopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrDownscope
+	}
+
+	standardClaims := verifiedToken.StandardClaims
+	audience := standardClaims.Audience
+	if len(audiences) > 0 {
+		audience = audiences
+		if len(standardClaims.Audience) > 0 {
+			audience = nil
+			for _, aud := range audiences {
+				if containsString(standardClaims.Audience, aud) {
+					audience = append(audience, aud)
+				}
+			}
+
+			if len(audience) == 0 {
+				return nil, ErrDownscope
+			}
+		}
+	}
+
+	carried := make(Map, len(i.DownscopeClaims)+1)
+	for _, name := range i.DownscopeClaims {
+		if value, ok := claims[name]; ok && !containsString(downscopeReserved, name) {
+			carried[name] = value
+		}
+	}
+	carried["scope"] = strings.Join(scopes, " ")
+
+	var customClaims interface{} = carried
+	if i.Lineage {
+		if customClaims, err = withLineage(carried, standardClaims.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	maxAge, err := i.lifetime(standardClaims.Subject, customClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	if standardClaims.Expiry > 0 {
+		remaining := time.Unix(standardClaims.Expiry, 0).Sub(Clock()).Truncate(time.Second)
+		if remaining <= time.Second {
+			return nil, ErrExpired
+		}
+
+		if maxAge <= 0 || remaining < maxAge {
+			maxAge = remaining
+		}
+	}
+
+	profile := &SigningProfile{Audience: audience, MaxAge: maxAge}
+	return i.token(ctx, "", profile, standardClaims.Subject, customClaims)
+}
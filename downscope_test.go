@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIssuerDownscope(t *testing.T) {
+	ctx := context.Background()
+	verifier := NewVerifier(testAlg, testSecret)
+
+	token, err := Sign(testAlg, testSecret, Map{
+		"sub":    "kataras",
+		"iss":    "idp",
+		"aud":    []string{"orders", "billing"},
+		"scope":  "orders:read orders:write",
+		"scp":    []string{"billing:read"},
+		"tenant": "acme",
+		"roles":  []string{"admin"},
+		"act":    Map{"sub": "support-agent"},
+	}, MaxAge(10*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := NewIssuer(testAlg, []byte("local secret"), time.Hour)
+	local.Issuer = "gateway"
+	local.DownscopeClaims = []string{"tenant", "scp", "sub"}
+
+	narrow, err := local.Downscope(ctx, verifier, token, []string{"orders:read", "billing:read", "admin"}, "orders", "unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, []byte("local secret"), narrow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims["scope"] != "orders:read billing:read" || claims["scp"] != nil || claims["tenant"] != "acme" || claims["roles"] != nil || claims["act"] != nil {
+		t.Fatalf("unexpected claims: %s", verifiedToken.Payload)
+	}
+
+	standardClaims := verifiedToken.StandardClaims
+	if standardClaims.Subject != "kataras" || standardClaims.Issuer != "gateway" || !reflect.DeepEqual(standardClaims.Audience, Audience{"orders"}) {
+		t.Fatalf("unexpected standard claims: %#+v", standardClaims)
+	}
+
+	// Capped to the token's remaining lifetime, not the Issuer's hour.
+	if timeLeft := standardClaims.Timeleft(); timeLeft > 10*time.Minute || timeLeft < 9*time.Minute {
+		t.Fatalf("expected a lifetime of at most 10 minutes but got: %s", timeLeft)
+	}
+
+	if _, err = local.Downscope(ctx, verifier, token, []string{"admin"}); !errors.Is(err, ErrDownscope) {
+		t.Fatalf("expected error: %v but got: %v", ErrDownscope, err)
+	}
+
+	if _, err = local.Downscope(ctx, verifier, token, []string{"orders:read"}, "unknown"); !errors.Is(err, ErrDownscope) {
+		t.Fatalf("expected error: %v but got: %v", ErrDownscope, err)
+	}
+
+	if _, err = local.Downscope(ctx, verifier, narrow, []string{"orders:read"}); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
+
+func TestIssuerDownscopeShorterLifetime(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "scope": "read write", "roles": []string{"admin"}, "tenant": "acme"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := NewIssuer(testAlg, testSecret, time.Minute)
+	narrow, err := local.Downscope(context.Background(), NewVerifier(testAlg, testSecret), token, []string{"read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, narrow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if timeLeft := verifiedToken.StandardClaims.Timeleft(); timeLeft > time.Minute {
+		t.Fatalf("expected the Issuer's lifetime but got: %s", timeLeft)
+	}
+
+	if aud := verifiedToken.StandardClaims.Audience; len(aud) != 0 {
+		t.Fatalf("expected no audience but got: %v", aud)
+	}
+
+	// No DownscopeClaims, no custom claims are carried over.
+	if view := verifiedToken.View(); view.Has("roles") || view.Has("tenant") || view.String("scope") != "read" {
+		t.Fatalf("unexpected claims: %s", verifiedToken.Payload)
+	}
+}
@@ -3,9 +3,8 @@ package jwt
 import (
 	"crypto"
 	"crypto/ecdsa"
-	"crypto/rand"
 	"crypto/x509"
-	"encoding/pem"
+	"encoding/asn1"
 	"fmt"
 	"math/big"
 )
@@ -15,6 +14,44 @@ type algECDSA struct {
 	hasher    crypto.Hash
 	keySize   int
 	curveBits int
+
+	acceptDER     bool
+	deterministic bool
+	lowS          bool
+}
+
+// ECDSAOption configures an ECDSA algorithm, see the `ECDSA` function.
+type ECDSAOption func(*algECDSA)
+
+// ECDSA returns a copy of the "alg" ECDSA algorithm (ES256, ES384 or ES512)
+// configured by the given options. It panics if the "alg" is not an ECDSA algorithm.
+//
+// Usage:
+//  alg := jwt.ECDSA(jwt.ES256, jwt.AcceptDER())
+//  verifiedToken, err := jwt.Verify(alg, publicKey, token)
+func ECDSA(alg Alg, opts ...ECDSAOption) Alg {
+	a, ok := alg.(*algECDSA)
+	if !ok {
+		panicHandler(fmt.Errorf("ECDSA: %s is not an ECDSA algorithm", alg.Name()))
+		return alg
+	}
+
+	configured := *a
+	for _, opt := range opts {
+		opt(&configured)
+	}
+
+	return &configured
+}
+
+// AcceptDER is an ECDSAOption which makes the verification to accept
+// ASN.1 DER-encoded signatures too, which some non-compliant issuers emit,
+// instead of the RFC 7518 (R || S) ones only.
+// The generated signatures are always the RFC 7518 ones.
+func AcceptDER() ECDSAOption {
+	return func(a *algECDSA) {
+		a.acceptDER = true
+	}
 }
 
 func (a *algECDSA) Name() string {
@@ -37,11 +74,21 @@ func (a *algECDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 	}
 
 	hashed := h.Sum(nil)
-	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hashed)
-	if err != nil {
+	var r, s *big.Int
+	if a.deterministic {
+		if r, s, err = signDeterministic(privateKey, a.hasher, hashed); err != nil {
+			return nil, err
+		}
+	} else if r, s, err = ecdsa.Sign(Random, privateKey, hashed); err != nil {
 		return nil, err
 	}
 
+	if a.lowS {
+		if n := privateKey.Curve.Params().N; s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+			s = new(big.Int).Sub(n, s)
+		}
+	}
+
 	curveBits := privateKey.Curve.Params().BitSize
 	if a.curveBits != curveBits {
 		return nil, ErrInvalidKey
@@ -74,13 +121,6 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 		}
 	}
 
-	if len(signature) != 2*a.keySize {
-		return ErrTokenSignature
-	}
-
-	r := big.NewInt(0).SetBytes(signature[:a.keySize])
-	s := big.NewInt(0).SetBytes(signature[a.keySize:])
-
 	h := a.hasher.New()
 	// header.payload
 	_, err := h.Write(headerAndPayload)
@@ -89,11 +129,52 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 	}
 
 	hashed := h.Sum(nil)
-	if !ecdsa.Verify(publicKey, hashed, r, s) {
-		return ErrTokenSignature
+
+	if len(signature) == 2*a.keySize {
+		r := big.NewInt(0).SetBytes(signature[:a.keySize])
+		s := big.NewInt(0).SetBytes(signature[a.keySize:])
+		if ecdsa.Verify(publicKey, hashed, r, s) {
+			return nil
+		}
+	}
+
+	// A DER-encoded signature may have the same length as the RFC 7518 one.
+	if a.acceptDER {
+		var sig struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(signature, &sig); err == nil && len(rest) == 0 &&
+			ecdsa.Verify(publicKey, hashed, sig.R, sig.S) {
+			return nil
+		}
 	}
 
-	return nil
+	return ErrTokenSignature
+}
+
+// Deterministic is an ECDSAOption which generates the signature nonces
+// deterministically, as described in RFC 6979, instead of randomly.
+// The same private key and input always produce the same signature,
+// useful for reproducible signatures and environments without a reliable random source.
+//
+// On Go 1.24+ it's built on the constant-time crypto/ecdsa implementation
+// (P-256, P-384 and P-521 keys only). WARNING: on older Go versions it falls back to
+// a math/big implementation which is not constant-time, the timing of a signature
+// leaks information about its nonce and a leaked nonce recovers the private key,
+// use it for tests and golden files only there.
+func Deterministic() ECDSAOption {
+	return func(a *algECDSA) {
+		a.deterministic = true
+	}
+}
+
+// LowS is an ECDSAOption which normalizes the generated signatures to
+// their low-S form (S <= N/2), which some strict verifiers require.
+// Both forms are valid and accepted on verification.
+func LowS() ECDSAOption {
+	return func(a *algECDSA) {
+		a.lowS = true
+	}
 }
 
 // Key Helpers.
@@ -153,29 +234,51 @@ func LoadPublicKeyECDSA(filename string) (*ecdsa.PublicKey, error) {
 }
 
 // ParsePrivateKeyECDSA decodes and parses the
-// PEM-encoded ECDSA private key's raw contents.
+// PEM-encoded (or DER) ECDSA private key's raw contents, of SEC 1 or PKCS#8 form.
 // Pass the result to the `Token` (signing) function.
 func ParsePrivateKeyECDSA(key []byte) (*ecdsa.PrivateKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
+	der, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if der == nil {
 		return nil, fmt.Errorf("private key: malformed or missing PEM format (ECDSA)")
 	}
 
-	return x509.ParseECPrivateKey(block.Bytes)
+	privateKey, err := x509.ParseECPrivateKey(der) // SEC 1.
+	if err != nil {
+		if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+			pKey, ok := key.(*ecdsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("private key: expected a type of *ecdsa.PrivateKey")
+			}
+
+			privateKey = pKey
+		} else {
+			return nil, err
+		}
+	}
+
+	return privateKey, nil
 }
 
 // ParsePublicKeyECDSA decodes and parses the
-// PEM-encoded ECDSA public key's raw contents.
+// PEM-encoded (or DER) ECDSA public key's raw contents.
 // Pass the result to the `Verify` function.
 func ParsePublicKeyECDSA(key []byte) (*ecdsa.PublicKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
+	der, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if der == nil {
 		return nil, fmt.Errorf("public key: malformed or missing PEM format (ECDSA)")
 	}
 
-	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	parsedKey, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		if cert, err := x509.ParseCertificate(der); err == nil {
 			parsedKey = cert.PublicKey
 		} else {
 			return nil, err
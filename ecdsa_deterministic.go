@@ -0,0 +1,84 @@
+//go:build !go1.24
+// +build !go1.24
+
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"math/big"
+)
+
+// signDeterministic generates the ECDSA signature of the "hashed" input
+// with the RFC 6979 (section 3.2) nonce of the "hasher" hash function.
+//
+// It's not constant-time: the math/big arithmetic and the scalar multiplication
+// of the secret nonce are variable-time, see the `Deterministic` option.
+func signDeterministic(privateKey *ecdsa.PrivateKey, hasher crypto.Hash, hashed []byte) (*big.Int, *big.Int, error) {
+	curve := privateKey.Curve
+	n := curve.Params().N
+	qlen := n.BitLen()
+	rolen := (qlen + 7) / 8
+
+	bits2int := func(b []byte) *big.Int {
+		v := new(big.Int).SetBytes(b)
+		if excess := len(b)*8 - qlen; excess > 0 {
+			v.Rsh(v, uint(excess))
+		}
+		return v
+	}
+	int2octets := func(v *big.Int) []byte {
+		b := v.Bytes()
+		if len(b) < rolen {
+			b = append(make([]byte, rolen-len(b)), b...)
+		}
+		return b
+	}
+
+	e := bits2int(hashed)
+	x := int2octets(privateKey.D)
+	h1 := int2octets(new(big.Int).Mod(e, n)) // bits2octets.
+
+	mac := func(key []byte, data ...[]byte) []byte {
+		m := hmac.New(hasher.New, key)
+		for _, d := range data {
+			m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+
+	v := bytes.Repeat([]byte{0x01}, hasher.Size())
+	k := make([]byte, hasher.Size())
+	k = mac(k, v, []byte{0x00}, x, h1)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h1)
+	v = mac(k, v)
+
+	for {
+		var t []byte
+		for len(t) < rolen {
+			v = mac(k, v)
+			t = append(t, v...)
+		}
+
+		nonce := bits2int(t[:rolen])
+		if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+			x1, _ := curve.ScalarBaseMult(int2octets(nonce))
+			r := x1.Mod(x1, n)
+			if r.Sign() != 0 {
+				s := new(big.Int).Mul(r, privateKey.D)
+				s.Add(s, e)
+				s.Mul(s, nonce.ModInverse(nonce, n))
+				s.Mod(s, n)
+				if s.Sign() != 0 {
+					return r, s, nil
+				}
+			}
+		}
+
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.24
+// +build go1.24
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+)
+
+// signDeterministic generates the ECDSA signature of the "hashed" input
+// with the RFC 6979 nonce of the "hasher" hash function,
+// through the constant-time crypto/ecdsa implementation (a nil random source).
+func signDeterministic(privateKey *ecdsa.PrivateKey, hasher crypto.Hash, hashed []byte) (*big.Int, *big.Int, error) {
+	signature, err := privateKey.Sign(nil, hashed, hasher)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err = asn1.Unmarshal(signature, &sig); err != nil {
+		return nil, nil, err
+	}
+
+	return sig.R, sig.S, nil
+}
@@ -1,6 +1,12 @@
 package jwt
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
 	"testing"
 )
 
@@ -35,3 +41,103 @@ func TestMustLoadECDSA(t *testing.T) {
 		MustLoadECDSA("./invalid.pem", "./invalid.pem")
 	})
 }
+
+func TestECDSAAcceptDER(t *testing.T) {
+	privateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatalf("ecdsa: private key: %v", err)
+	}
+
+	headerAndPayload := []byte("header.payload")
+	hashed := sha256.Sum256(headerAndPayload)
+	der, err := ecdsa.SignASN1(rand.Reader, privateKey, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ES256.Verify(&privateKey.PublicKey, headerAndPayload, der); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	alg := ECDSA(ES256, AcceptDER())
+	if alg.Name() != ES256.Name() {
+		t.Fatalf("expected name: %s but got: %s", ES256.Name(), alg.Name())
+	}
+
+	if err = alg.Verify(&privateKey.PublicKey, headerAndPayload, der); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = alg.Verify(&privateKey.PublicKey, headerAndPayload, append(der, 0)); err != ErrTokenSignature {
+		t.Fatalf("expected error on trailing data: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// The generated signatures are always the RFC 7518 ones.
+	signature, err := alg.Sign(privateKey, headerAndPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ES256.Verify(&privateKey.PublicKey, headerAndPayload, signature); err != nil {
+		t.Fatal(err)
+	}
+
+	catchPanic(t, true, func() {
+		ECDSA(HS256, AcceptDER())
+	})
+}
+
+func TestECDSADeterministic(t *testing.T) {
+	// RFC 6979 Appendix A.2.5, ECDSA with P-256 and SHA-256.
+	hexInt := func(s string) *big.Int {
+		v, _ := new(big.Int).SetString(s, 16)
+		return v
+	}
+
+	privateKey := &ecdsa.PrivateKey{D: hexInt("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")}
+	privateKey.Curve = elliptic.P256()
+	privateKey.X = hexInt("60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6")
+	privateKey.Y = hexInt("7903FE1008B8BC99A41AE9E95628BC64F2F1B20C2D7E9F5177A3C294D4462299")
+
+	tests := []struct {
+		message string
+		r, s    string
+	}{
+		{"sample", "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716", "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+		{"test", "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367", "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	}
+
+	alg := ECDSA(ES256, Deterministic())
+	lowS := ECDSA(ES256, Deterministic(), LowS())
+	halfOrder := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+
+	for _, tt := range tests {
+		signature, err := alg.Sign(privateKey, []byte(tt.message))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := padBytes(hexInt(tt.r).Bytes(), 32); !bytes.Equal(signature[:32], expected) {
+			t.Fatalf("%s: expected r: %x but got: %x", tt.message, expected, signature[:32])
+		}
+
+		if expected := padBytes(hexInt(tt.s).Bytes(), 32); !bytes.Equal(signature[32:], expected) {
+			t.Fatalf("%s: expected s: %x but got: %x", tt.message, expected, signature[32:])
+		}
+
+		normalized, err := lowS.Sign(privateKey, []byte(tt.message))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s := new(big.Int).SetBytes(normalized[32:]); s.Cmp(halfOrder) > 0 {
+			t.Fatalf("%s: expected a low-S signature but got: %x", tt.message, s)
+		}
+
+		for _, sig := range [][]byte{signature, normalized} {
+			if err = ES256.Verify(&privateKey.PublicKey, []byte(tt.message), sig); err != nil {
+				t.Fatalf("%s: %v", tt.message, err)
+			}
+		}
+	}
+}
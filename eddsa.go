@@ -3,7 +3,6 @@ package jwt
 import (
 	"crypto/ed25519"
 	"encoding/asn1"
-	"encoding/pem"
 	"fmt"
 )
 
@@ -106,7 +105,7 @@ func LoadPublicKeyEdDSA(filename string) (ed25519.PublicKey, error) {
 }
 
 // ParsePrivateKeyEdDSA decodes and parses the
-// PEM-encoded ed25519 private key's raw contents.
+// PEM-encoded (or DER) PKCS#8 ed25519 private key's raw contents.
 // Pass the result to the `Token` (signing) function.
 func ParsePrivateKeyEdDSA(key []byte) (ed25519.PrivateKey, error) {
 	asn1PrivKey := struct {
@@ -117,26 +116,31 @@ func ParsePrivateKeyEdDSA(key []byte) (ed25519.PrivateKey, error) {
 		PrivateKey []byte
 	}{}
 
-	block, _ := pem.Decode(key)
-	if block == nil {
+	der, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if der == nil {
 		return nil, fmt.Errorf("private key: malformed or missing PEM format (EdDSA)")
 	}
 
-	if _, err := asn1.Unmarshal(block.Bytes, &asn1PrivKey); err != nil {
+	if _, err := asn1.Unmarshal(der, &asn1PrivKey); err != nil {
 		return nil, err
 	}
 
-	seed := asn1PrivKey.PrivateKey[2:]
-	if l := len(seed); l != ed25519.SeedSize {
+	if l := len(asn1PrivKey.PrivateKey) - 2; l != ed25519.SeedSize { // an OCTET STRING of the seed.
 		return nil, fmt.Errorf("private key: bad seed length: %d", l)
 	}
 
+	seed := asn1PrivKey.PrivateKey[2:]
+
 	privateKey := ed25519.NewKeyFromSeed(seed)
 	return privateKey, nil
 }
 
 // ParsePublicKeyEdDSA decodes and parses the
-// PEM-encoded ed25519 public key's raw contents.
+// PEM-encoded (or DER) ed25519 public key's raw contents.
 // Pass the result to the `Verify` function.
 func ParsePublicKeyEdDSA(key []byte) (ed25519.PublicKey, error) {
 	asn1PubKey := struct {
@@ -146,12 +150,16 @@ func ParsePublicKeyEdDSA(key []byte) (ed25519.PublicKey, error) {
 		PublicKey asn1.BitString
 	}{}
 
-	block, _ := pem.Decode(key)
-	if block == nil {
+	der, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if der == nil {
 		return nil, fmt.Errorf("public key: malformed or missing PEM format (EdDSA)")
 	}
 
-	if _, err := asn1.Unmarshal(block.Bytes, &asn1PubKey); err != nil {
+	if _, err := asn1.Unmarshal(der, &asn1PubKey); err != nil {
 		return nil, err
 	}
 
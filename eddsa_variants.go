@@ -0,0 +1,88 @@
+//go:build go1.20
+// +build go1.20
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+)
+
+// Ed25519ph returns the pre-hashed Ed25519 (RFC 8032 section 5.1) signing algorithm
+// of the (optional, up to 255 bytes) "context" string.
+// The algorithm's name is: "Ed25519ph".
+// Sign   key: ed25519.PrivateKey
+// Verify key: ed25519.PublicKey or ed25519.PrivateKey
+//
+// It's not registered for JWS, use it to integrate with systems that mandate it.
+// Requires Go 1.20+.
+func Ed25519ph(context string) Alg {
+	return &algEd25519Options{"Ed25519ph", &ed25519.Options{Hash: crypto.SHA512, Context: context}}
+}
+
+// Ed25519ctx returns the Ed25519 (RFC 8032 section 5.1) signing algorithm
+// of the "context" string (up to 255 bytes).
+// The algorithm's name is: "Ed25519ctx".
+// Sign   key: ed25519.PrivateKey
+// Verify key: ed25519.PublicKey or ed25519.PrivateKey
+//
+// It's not registered for JWS, use it to integrate with systems that mandate it.
+// Requires Go 1.20+.
+func Ed25519ctx(context string) Alg {
+	return &algEd25519Options{"Ed25519ctx", &ed25519.Options{Context: context}}
+}
+
+type algEd25519Options struct {
+	name string
+	opts *ed25519.Options
+}
+
+func (a *algEd25519Options) Name() string {
+	return a.name
+}
+
+// message returns the signed message, the SHA-512 digest of the input for Ed25519ph.
+func (a *algEd25519Options) message(headerAndPayload []byte) []byte {
+	if a.opts.Hash == crypto.SHA512 {
+		digest := sha512.Sum512(headerAndPayload)
+		return digest[:]
+	}
+
+	return headerAndPayload
+}
+
+func (a *algEd25519Options) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return privateKey.Sign(nil, a.message(headerAndPayload), a.opts)
+}
+
+func (a *algEd25519Options) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		if privateKey, ok := key.(ed25519.PrivateKey); ok {
+			publicKey = privateKey.Public().(ed25519.PublicKey)
+		} else {
+			return ErrInvalidKey
+		}
+	}
+
+	if len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvalidKey
+	}
+
+	if err := ed25519.VerifyWithOptions(publicKey, a.message(headerAndPayload), signature, a.opts); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenSignature, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build go1.20
+// +build go1.20
+
+package jwt
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestEd25519Variants(t *testing.T) {
+	// RFC 8032 section 7.2 (Ed25519ctx, "foo" context) and 7.3 (Ed25519ph).
+	tests := []struct {
+		alg                      Alg
+		seed, message, signature string
+	}{
+		{
+			Ed25519ctx("foo"),
+			"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+			"f726936d19c800494e3fdaff20b276a8",
+			"55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d",
+		},
+		{
+			Ed25519ph(""),
+			"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+			"616263",
+			"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406",
+		},
+	}
+
+	for _, tt := range tests {
+		seed, _ := hex.DecodeString(tt.seed)
+		message, _ := hex.DecodeString(tt.message)
+		privateKey := ed25519.NewKeyFromSeed(seed)
+
+		signature, err := tt.alg.Sign(privateKey, message)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.alg.Name(), err)
+		}
+
+		if got := hex.EncodeToString(signature); got != tt.signature {
+			t.Fatalf("%s: expected signature: %s but got: %s", tt.alg.Name(), tt.signature, got)
+		}
+
+		if err = tt.alg.Verify(privateKey.Public(), message, signature); err != nil {
+			t.Fatalf("%s: %v", tt.alg.Name(), err)
+		}
+
+		// The pure Ed25519 does not verify the variants' signatures.
+		if err = EdDSA.Verify(privateKey.Public(), message, signature); !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("%s: expected error: %v but got: %v", tt.alg.Name(), ErrTokenSignature, err)
+		}
+	}
+
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+	token, err := Sign(Ed25519ctx("myapp"), privateKey, Map{"sub": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(Ed25519ctx("myapp"), publicKey, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(Ed25519ctx("another"), publicKey, token); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error on a different context: %v but got: %v", ErrTokenSignature, err)
+	}
+}
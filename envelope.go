@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// EnvelopeVersion is the current schema version of an `Envelope`.
+const EnvelopeVersion = 1
+
+// envelopeType is the "typ" header of an encoded envelope,
+// so an envelope is never accepted as a token by `Verify`.
+const envelopeType = "envelope+jwt"
+
+var (
+	// ErrEnvelope indicates that an encoded envelope is malformed or its signature is invalid.
+	ErrEnvelope = errors.New("invalid token envelope")
+	// ErrEnvelopeVersion indicates that an envelope's schema version is newer than the `EnvelopeVersion`.
+	ErrEnvelopeVersion = fmt.Errorf("%w: unsupported version", ErrEnvelope)
+)
+
+// Envelope carries a token along with its metadata through a queue or an event bus,
+// so asynchronous consumers can verify the token and trace the message.
+// The envelope is signed as a whole (see `EncodeEnvelope`),
+// its metadata can't be modified without invalidating its signature.
+type Envelope struct {
+	// Version is the envelope's schema version, set to the `EnvelopeVersion` on encode.
+	Version int `json:"v"`
+	// Token is the carried token, verified separately, see `VerifyToken`.
+	Token string `json:"token"`
+	// Node is the name of the node (e.g. the service instance) which issued the envelope.
+	Node string `json:"node,omitempty"`
+	// TraceID is the trace identifier of the message, for distributed tracing.
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// EncodeEnvelope signs the envelope "e" of "alg" algorithm and "key" private key.
+// The result is a compact JWS of a "typ": "envelope+jwt" header,
+// which is rejected by `Verify`, so an envelope can't be used as a token.
+// Use a different key than the tokens one though.
+//
+// Usage:
+//  message, err := jwt.EncodeEnvelope(jwt.HS256, envelopeKey, jwt.Envelope{Token: string(token), Node: hostname, TraceID: traceID})
+//  [publish the message...]
+func EncodeEnvelope(alg AlgSigner, key PrivateKey, e Envelope) ([]byte, error) {
+	e.Version = EnvelopeVersion
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	header := Header{{Name: "alg", Value: alg.Name()}, {Name: "typ", Value: envelopeType}}
+	return SignWithHeader(alg, key, header, payload)
+}
+
+// DecodeEnvelope verifies the signature of the encoded envelope "data" (see `EncodeEnvelope`)
+// of "alg" algorithm and "key" public key and returns the envelope.
+// Returns ErrEnvelope if it's malformed or its signature is invalid
+// and ErrEnvelopeVersion if its schema version is not supported.
+// The carried token is NOT verified, call the `VerifyToken` method of the envelope.
+//
+// Usage:
+//  envelope, err := jwt.DecodeEnvelope(jwt.HS256, envelopeKey, message)
+//  [handle error...]
+//  verifiedToken, err := envelope.VerifyToken(ctx, verifier)
+func DecodeEnvelope(alg AlgVerifier, key PublicKey, data []byte) (*Envelope, error) {
+	t, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnvelope, err)
+	}
+
+	if t.header.Alg != alg.Name() || t.header.Typ != envelopeType {
+		return nil, fmt.Errorf("%w: header", ErrEnvelope)
+	}
+
+	n := bytes.LastIndexByte(data, sep[0]) // the end of the header.payload part.
+	headerPayload := data[:n:n]
+	if err = alg.Verify(key, headerPayload, t.Signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnvelope, err)
+	}
+
+	var e Envelope
+	if err = json.Unmarshal(t.Payload, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnvelope, err)
+	}
+
+	if e.Version > EnvelopeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrEnvelopeVersion, e.Version)
+	}
+
+	return &e, nil
+}
+
+// VerifyToken verifies the carried token through the "verifier",
+// exactly like the `Verifier.VerifyTokenContext` method does.
+func (e *Envelope) VerifyToken(ctx context.Context, verifier *Verifier, validators ...TokenValidator) (*VerifiedToken, error) {
+	return verifier.VerifyTokenContext(ctx, []byte(e.Token), validators...)
+}
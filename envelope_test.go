@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEnvelope(t *testing.T) {
+	envelopeKey := []byte("envelopesercrethatmaycontainch@r")
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := EncodeEnvelope(HS256, envelopeKey, Envelope{Token: string(token), Node: "node-1", TraceID: "4bf92f3577b34da6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An envelope is not a token.
+	if _, err = Verify(HS256, envelopeKey, message); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+
+	envelope, err := DecodeEnvelope(HS256, envelopeKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Envelope{Version: EnvelopeVersion, Token: string(token), Node: "node-1", TraceID: "4bf92f3577b34da6"}
+	if *envelope != expected {
+		t.Fatalf("expected envelope: %#+v but got: %#+v", expected, *envelope)
+	}
+
+	verifiedToken, err := envelope.VerifyToken(context.Background(), NewVerifier(testAlg, testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("expected the carried token's subject but got: %q", verifiedToken.StandardClaims.Subject)
+	}
+
+	tampered := bytes.Replace(message, message[bytes.IndexByte(message, '.')+1:][:4], []byte("AAAA"), 1)
+	if _, err = DecodeEnvelope(HS256, envelopeKey, tampered); !errors.Is(err, ErrEnvelope) {
+		t.Fatalf("expected error: %v but got: %v", ErrEnvelope, err)
+	}
+
+	// A token is not an envelope.
+	if _, err = DecodeEnvelope(testAlg, testSecret, token); !errors.Is(err, ErrEnvelope) {
+		t.Fatalf("expected error: %v but got: %v", ErrEnvelope, err)
+	}
+}
+
+func TestEnvelopeVersion(t *testing.T) {
+	envelopeKey := []byte("envelopesercrethatmaycontainch@r")
+
+	header := Header{{Name: "alg", Value: "HS256"}, {Name: "typ", Value: envelopeType}}
+	message, err := SignWithHeader(HS256, envelopeKey, header, []byte(`{"v":2,"token":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = DecodeEnvelope(HS256, envelopeKey, message); !errors.Is(err, ErrEnvelopeVersion) {
+		t.Fatalf("expected error: %v but got: %v", ErrEnvelopeVersion, err)
+	}
+}
@@ -0,0 +1,189 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ClaimsTransform transforms the claims of a verified inbound token into the subject
+// and the custom claims of the outbound token of a `TokenExchange`.
+type ClaimsTransform func(ctx context.Context, inbound *VerifiedToken) (subject string, claims interface{}, err error)
+
+// ForwardClaims returns a ClaimsTransform which keeps the inbound subject
+// and the "allow" claims only, see `Minimize`.
+func ForwardClaims(allow ...string) ClaimsTransform {
+	return func(_ context.Context, inbound *VerifiedToken) (string, interface{}, error) {
+		claims, err := Minimize(inbound, allow...)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return inbound.StandardClaims.Subject, claims, nil
+	}
+}
+
+// defaultExchangeCacheEntries is the default maximum number of cached tokens of a `TokenExchange`.
+const defaultExchangeCacheEntries = 10000
+
+// TokenExchange is a gateway's token translation pipeline: it verifies an inbound token
+// (e.g. of the identity provider's keys), transforms its claims and signs an outbound
+// token for the internal services (e.g. by the gateway's key).
+// The outbound tokens are cached by the inbound token's "iss" and "jti" claims,
+// so the repeated requests of the same token are not signed again;
+// the inbound token is always verified, a cached token does not skip any validation.
+// Inbound tokens without a "jti" are not cached.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.RS256, nil)
+//  verifier.KeyProvider = jwks // the identity provider's keys.
+//  issuer := jwt.NewIssuer(jwt.EdDSA, gatewayKey, time.Minute)
+//
+//  exchange := jwt.NewTokenExchange(verifier, jwt.ForwardClaims("roles"), issuer)
+//  internalToken, err := exchange.Exchange(r.Context(), token)
+type TokenExchange struct {
+	// Verifier verifies the inbound tokens, required.
+	Verifier *Verifier
+	// Transform computes the outbound claims, optional.
+	// Defaults to the inbound subject without any other claims.
+	Transform ClaimsTransform
+	// Issuer signs the outbound tokens, required.
+	// Its MaxAge should not be longer than the inbound tokens lifetime.
+	Issuer *Issuer
+	// MinTTL is the minimum remaining lifetime of a cached outbound token,
+	// a token which expires sooner is signed again. Defaults to 30 seconds.
+	// Caching is disabled if it's negative.
+	MinTTL time.Duration
+	// CacheMaxEntries is the maximum number of cached outbound tokens,
+	// the least recently used token is evicted on a full cache. Defaults to 10000.
+	CacheMaxEntries int
+
+	mu    sync.Mutex
+	cache *lru[string, exchangeEntry]
+}
+
+type exchangeEntry struct {
+	token []byte
+	// expiresAt is the reuse deadline, the outbound token's "exp" minus MinTTL
+	// or the inbound token's "exp", whichever comes first.
+	expiresAt time.Time
+}
+
+// NewTokenExchange returns a new TokenExchange.
+// The rest of the TokenExchange fields can be modified before its first use.
+func NewTokenExchange(verifier *Verifier, transform ClaimsTransform, issuer *Issuer) *TokenExchange {
+	return &TokenExchange{
+		Verifier:  verifier,
+		Transform: transform,
+		Issuer:    issuer,
+		MinTTL:    30 * time.Second,
+	}
+}
+
+// Exchange verifies the "inbound" token and returns its outbound token.
+// The "validators" run after the Verifier's ones.
+func (e *TokenExchange) Exchange(ctx context.Context, inbound []byte, validators ...TokenValidator) ([]byte, error) {
+	verifiedToken, err := e.Verifier.VerifyTokenContext(ctx, inbound, validators...)
+	if err != nil {
+		return nil, err
+	}
+
+	key := e.cacheKey(verifiedToken.StandardClaims)
+	if key != "" {
+		if token, ok := e.cached(key); ok {
+			return token, nil
+		}
+	}
+
+	subject, claims := verifiedToken.StandardClaims.Subject, interface{}(nil)
+	if e.Transform != nil {
+		if subject, claims, err = e.Transform(ctx, verifiedToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.Issuer.Lineage {
+		if claims, err = withLineage(claims, verifiedToken.StandardClaims.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := e.Issuer.TokenContext(ctx, subject, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		e.store(key, token, verifiedToken.StandardClaims)
+	}
+
+	return token, nil
+}
+
+func (e *TokenExchange) cacheKey(inbound Claims) string {
+	if inbound.ID == "" || e.MinTTL < 0 {
+		return ""
+	}
+
+	return inbound.Issuer + "\x00" + inbound.ID
+}
+
+func (e *TokenExchange) cached(key string) ([]byte, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cache == nil {
+		return nil, false
+	}
+
+	entry, ok := e.cache.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	if !Clock().Before(entry.expiresAt) {
+		e.cache.remove(key)
+		return nil, false
+	}
+
+	return entry.token, true
+}
+
+func (e *TokenExchange) store(key string, token []byte, inbound Claims) {
+	var outbound Claims
+	if err := unverifiedPayload(token, &outbound); err != nil || outbound.Expiry == 0 {
+		return // not cached, e.g. an encrypted payload.
+	}
+
+	expiresAt := time.Unix(outbound.Expiry, 0).Add(-e.MinTTL)
+	if inbound.Expiry > 0 {
+		if inboundExpiry := time.Unix(inbound.Expiry, 0); inboundExpiry.Before(expiresAt) {
+			expiresAt = inboundExpiry
+		}
+	}
+
+	if !Clock().Before(expiresAt) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cache == nil {
+		maxEntries := e.CacheMaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultExchangeCacheEntries
+		}
+		e.cache = newLRU[string, exchangeEntry](maxEntries)
+	}
+
+	e.cache.set(key, exchangeEntry{token: token, expiresAt: expiresAt})
+}
+
+// Invalidate completes the CacheInvalidator interface, it drops the cached outbound tokens
+// on any event, e.g. a cached token may be revoked or signed by a retired key.
+func (e *TokenExchange) Invalidate(event Invalidation) {
+	e.mu.Lock()
+	e.cache = nil
+	e.mu.Unlock()
+}
@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenExchange(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	idpKey, idpPublicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+	gatewayKey := []byte("gatewaysercrethatmaycontainch@r$")
+
+	issuer := NewIssuer(HS256, gatewayKey, 5*time.Minute)
+	issuer.Issuer = "gateway"
+	exchange := NewTokenExchange(NewVerifier(EdDSA, idpPublicKey), ForwardClaims("roles"), issuer)
+
+	inbound, err := Sign(EdDSA, idpKey, Map{"sub": "kataras", "jti": "1", "iss": "idp", "roles": []string{"admin"}, "email": "kataras2006@hotmail.com"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outbound, err := exchange.Exchange(context.Background(), inbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(HS256, gatewayKey, outbound, Expected{Issuer: "gateway", Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := claims["roles"]; !ok {
+		t.Fatalf("expected the roles claim")
+	}
+
+	if _, ok := claims["email"]; ok {
+		t.Fatalf("expected the email claim to be removed")
+	}
+
+	// Cached by the inbound jti.
+	now = now.Add(time.Minute)
+	cached, err := exchange.Exchange(context.Background(), inbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(cached, outbound) {
+		t.Fatalf("expected the cached outbound token")
+	}
+
+	// Signed again near its expiration (MinTTL).
+	now = now.Add(4*time.Minute - 10*time.Second)
+	renewed, err := exchange.Exchange(context.Background(), inbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(renewed, outbound) {
+		t.Fatalf("expected a new outbound token")
+	}
+
+	// The inbound token is always verified.
+	if _, err = exchange.Exchange(context.Background(), append(inbound[:len(inbound)-2:len(inbound)-2], "xx"...)); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestTokenExchangeDefaultTransform(t *testing.T) {
+	exchange := NewTokenExchange(NewVerifier(testAlg, testSecret), nil, NewIssuer(HS512, testSecret, time.Minute))
+
+	inbound, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "roles": []string{"admin"}}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outbound, err := exchange.Exchange(context.Background(), inbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(HS512, testSecret, outbound, Expected{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(verifiedToken.Payload, []byte("roles")) {
+		t.Fatalf("expected the subject only but got: %s", verifiedToken.Payload)
+	}
+}
+
+func TestTokenExchangeCacheMaxEntries(t *testing.T) {
+	exchange := NewTokenExchange(NewVerifier(testAlg, testSecret), nil, NewIssuer(HS512, testSecret, time.Hour))
+	exchange.CacheMaxEntries = 1
+
+	sign := func(id string) []byte {
+		token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "jti": id}, MaxAge(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	ctx := context.Background()
+	first, second := sign("1"), sign("2")
+	for _, inbound := range [][]byte{first, second} {
+		if _, err := exchange.Exchange(ctx, inbound); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := exchange.cache.len(); n != 1 {
+		t.Fatalf("expected a single cached token but got: %d", n)
+	}
+
+	if _, ok := exchange.cached(exchange.cacheKey(Claims{ID: "1"})); ok {
+		t.Fatalf("expected the least recently used token to be evicted")
+	}
+}
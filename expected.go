@@ -1,8 +1,11 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 )
 
 // Expected is a TokenValidator which performs simple checks
@@ -25,11 +28,25 @@ var _ TokenValidator = Expected{}
 //  }
 var ErrExpected = errors.New("field not match")
 
+// The typed errors of the `Expected` validator, they are all a type of ErrExpected.
+var (
+	// ErrIssuerMismatch indicates that the "iss" claim does not match the expected one.
+	ErrIssuerMismatch = fmt.Errorf("%w: iss", ErrExpected)
+	// ErrSubjectMismatch indicates that the "sub" claim does not match the expected one.
+	ErrSubjectMismatch = fmt.Errorf("%w: sub", ErrExpected)
+	// ErrAudienceMismatch indicates that the "aud" claim does not match the expected one.
+	ErrAudienceMismatch = fmt.Errorf("%w: aud", ErrExpected)
+	// ErrIDMismatch indicates that the "jti" claim does not match the expected one.
+	ErrIDMismatch = fmt.Errorf("%w: jti", ErrExpected)
+)
+
 // ValidateToken completes the TokenValidator interface.
 // It performs simple checks against the expected "e" and the verified "c" claims.
 // Can be passed at the Verify's last input argument.
 //
-// It returns a type of ErrExpected on validation failures.
+// It returns a type of ErrExpected on validation failures,
+// e.g. ErrIssuerMismatch and ErrAudienceMismatch.
+// The issuers are compared by their canonical forms, see `NormalizeIssuer`.
 func (e Expected) ValidateToken(token []byte, c Claims, err error) error {
 	if err != nil {
 		return err
@@ -55,33 +72,92 @@ func (e Expected) ValidateToken(token []byte, c Claims, err error) error {
 
 	if v := e.ID; v != "" {
 		if v != c.ID {
-			return fmt.Errorf("%w: jti", ErrExpected)
+			return ErrIDMismatch
 		}
 	}
 
 	if v := e.Issuer; v != "" {
-		if v != c.Issuer {
-			return fmt.Errorf("%w: iss", ErrExpected)
+		if !sameIssuer(v, c.Issuer) {
+			return ErrIssuerMismatch
 		}
 	}
 
 	if v := e.Subject; v != "" {
 		if v != c.Subject {
-			return fmt.Errorf("%w: sub", ErrExpected)
+			return ErrSubjectMismatch
 		}
 	}
 
 	if n := len(e.Audience); n > 0 {
 		if n != len(c.Audience) {
-			return fmt.Errorf("%w: aud (length)", ErrExpected)
+			return fmt.Errorf("%w (length)", ErrAudienceMismatch)
 		}
 
 		for i := range c.Audience {
 			if v := e.Audience[i]; v != c.Audience[i] {
-				return fmt.Errorf("%w: aud (%q)", ErrExpected, v)
+				return fmt.Errorf("%w (%q)", ErrAudienceMismatch, v)
 			}
 		}
 	}
 
 	return nil
 }
+
+// Expect returns a PayloadValidator which deep-compares the named claims
+// of the token's payload against the "expected" values.
+// The values can be of any type which can be encoded to JSON,
+// e.g. a string, a number, a slice or a nested map.
+//
+// It returns a type of ErrExpected on validation failures,
+// the error contains the claim name but never its value.
+//
+// Usage:
+//  verifiedToken, err := Verify(..., Expect(Map{"role": "admin", "tenant": "acme"}))
+func Expect(expected Map) PayloadValidatorFunc {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names) // fail on the same claim on each call.
+
+	normalized, err := normalizeClaimValue(expected)
+	values, _ := normalized.(Map)
+
+	return func(_ context.Context, _, payload []byte, _ Claims, prevErr error) error {
+		if prevErr != nil {
+			return prevErr
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrExpected, err)
+		}
+
+		var claims Map
+		if err := defaultUnmarshal(payload, &claims); err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			got, ok := claims[name]
+			if !ok || !reflect.DeepEqual(values[name], got) {
+				return fmt.Errorf("%w: %s", ErrExpected, name)
+			}
+		}
+
+		return nil
+	}
+}
+
+// normalizeClaimValue converts a Go value to the decoded form of the payload values,
+// e.g. an int value becomes a json.Number and a struct value becomes a Map,
+// so it can be deep-compared against claims of a decoded payload.
+func normalizeClaimValue(value interface{}) (interface{}, error) {
+	b, err := Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	err = defaultUnmarshal(b, &normalized)
+	return normalized, err
+}
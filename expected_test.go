@@ -147,3 +147,93 @@ func TestExpected(t *testing.T) {
 		t.Fatalf("expected error: %v but got: %v", expectedErr, gotErr)
 	}
 }
+
+func TestExpect(t *testing.T) {
+	encrypt, decrypt, err := GCM(MustGenerateRandom(32), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := Map{
+		"role":   "admin",
+		"tenant": "acme",
+		"level":  3,
+		"groups": []string{"a", "b"},
+		"meta":   Map{"plan": "pro"},
+	}
+
+	token, err := SignEncrypted(testAlg, testSecret, encrypt, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Test all OK, including the encrypted payload and values of different Go types.
+	valid := Expect(Map{"role": "admin", "level": int64(3), "groups": []interface{}{"a", "b"}, "meta": Map{"plan": "pro"}})
+	if _, err = VerifyEncrypted(testAlg, testSecret, decrypt, token, valid); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expected Map
+		field    string
+	}{
+		{Map{"role": "admin", "tenant": "other"}, "tenant"},
+		{Map{"level": 4}, "level"},
+		{Map{"groups": []string{"b", "a"}}, "groups"},
+		{Map{"missing": "value"}, "missing"},
+	}
+
+	for i, tt := range tests {
+		_, err = VerifyEncrypted(testAlg, testSecret, decrypt, token, Expect(tt.expected))
+		if !errors.Is(err, ErrExpected) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrExpected, err)
+		}
+
+		if expected := fmt.Sprintf("%v: %s", ErrExpected, tt.field); err.Error() != expected {
+			t.Fatalf("[%d] expected error: %q but got: %q", i, expected, err.Error())
+		}
+	}
+
+	// Test the TokenValidator fallback which decodes the payload of a non-encrypted token.
+	token, err = Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = valid.ValidateToken(token, Claims{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpectedTypedErrors(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, []byte(`{"iss":"myapp","sub":"kataras","aud":"billing","jti":"1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expected Expected
+		err      error
+	}{
+		{Expected{Issuer: "myapp", Subject: "kataras", Audience: []string{"billing"}, ID: "1"}, nil},
+		{Expected{Issuer: "other"}, ErrIssuerMismatch},
+		{Expected{Subject: "other"}, ErrSubjectMismatch},
+		{Expected{Audience: []string{"inventory"}}, ErrAudienceMismatch},
+		{Expected{Audience: []string{"billing", "inventory"}}, ErrAudienceMismatch},
+		{Expected{ID: "2"}, ErrIDMismatch},
+	}
+
+	for i, tt := range tests {
+		_, err := Verify(testAlg, testSecret, token, tt.expected)
+		if tt.err == nil {
+			if err != nil {
+				t.Fatalf("[%d] %v", i, err)
+			}
+			continue
+		}
+
+		if !errors.Is(err, tt.err) || !errors.Is(err, ErrExpected) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+}
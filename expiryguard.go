@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type tokenExpiredContextKey struct{}
+
+// ExpiryContext returns a copy of the "parent" context which is cancelled
+// when the verified token expires (see `VerifiedToken.TimeLeft`), so long-lived operations
+// (e.g. Server-Sent Events or WebSocket connections) do not honor a stale authentication forever.
+// Use `TokenExpired` to tell a token expiration from any other cancellation (e.g. the client went away).
+// A token without an "exp" claim never cancels the context.
+// The caller should call the returned cancel function to release the resources.
+//
+// Example Code:
+//
+//  ctx, cancel := jwt.ExpiryContext(ctx, verifiedToken)
+//  defer cancel()
+//  <-ctx.Done()
+//  if jwt.TokenExpired(ctx) { [ask the client to re-authenticate...] }
+func ExpiryContext(parent context.Context, verifiedToken *VerifiedToken) (context.Context, context.CancelFunc) {
+	expired := new(uint32)
+	ctx, cancel := context.WithCancel(context.WithValue(parent, tokenExpiredContextKey{}, expired))
+
+	if verifiedToken == nil || verifiedToken.StandardClaims.Expiry <= 0 {
+		return ctx, cancel
+	}
+
+	timer := time.AfterFunc(verifiedToken.TimeLeft(), func() {
+		atomic.StoreUint32(expired, 1)
+		cancel()
+	})
+
+	return ctx, func() {
+		timer.Stop()
+		cancel()
+	}
+}
+
+// TokenExpired reports whether the "ctx" (or its parent) which was returned
+// by `ExpiryContext` (or the `ExpiryGuard`) was cancelled because the token has expired.
+func TokenExpired(ctx context.Context) bool {
+	expired, ok := ctx.Value(tokenExpiredContextKey{}).(*uint32)
+	return ok && atomic.LoadUint32(expired) == 1
+}
+
+// ExpiryGuard is an HTTP middleware for Server-Sent Events and other long-lived connections.
+// It cancels the request's Context when the verified token (see `GetVerifiedToken`) expires,
+// the "next" handler should return (or push a re-authentication event, see `TokenExpired`)
+// once the request's Context is done. Requests without a verified token pass through.
+// Register it after the `Verifier.Handler` middleware.
+//
+// Usage:
+//  http.Handle("/events", verifier.Handler(jwt.ExpiryGuard(eventsHandler)))
+//
+//  func eventsHandler(w http.ResponseWriter, r *http.Request) {
+//    for {
+//      select {
+//      case <-r.Context().Done():
+//        if jwt.TokenExpired(r.Context()) {
+//          fmt.Fprint(w, "event: reauth\ndata: token expired\n\n")
+//        }
+//        return
+//      case event := <-events:
+//        [write the event...]
+//      }
+//    }
+//  }
+func ExpiryGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifiedToken, ok := GetVerifiedToken(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := ExpiryContext(r.Context(), verifiedToken)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
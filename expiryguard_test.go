@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExpiryContext(t *testing.T) {
+	now := time.Now()
+	verifiedToken := &VerifiedToken{StandardClaims: Claims{Expiry: now.Add(time.Second).Unix()}}
+
+	ctx, cancel := ExpiryContext(context.Background(), verifiedToken)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the context to be cancelled on token expiration")
+	}
+
+	if !TokenExpired(ctx) {
+		t.Fatalf("expected token expired")
+	}
+
+	// Cancelled by the parent, not by the token expiration.
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = ExpiryContext(parent, verifiedToken)
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+
+	if TokenExpired(ctx) {
+		t.Fatalf("expected token not expired")
+	}
+
+	// No expiration.
+	ctx, cancel = ExpiryContext(context.Background(), &VerifiedToken{})
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("expected the context to not be cancelled")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestExpiryGuard(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	handler := verifier.Handler(ExpiryGuard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			if TokenExpired(r.Context()) {
+				w.Write([]byte("event: reauth\n\n"))
+			}
+		case <-time.After(5 * time.Second):
+			w.Write([]byte("event: timeout\n\n"))
+		}
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Authorization", "Bearer "+string(token))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if expected, got := "event: reauth\n\n", rec.Body.String(); got != expected {
+		t.Fatalf("expected body: %q but got: %q", expected, got)
+	}
+
+	// Requests without a verified token pass through.
+	called := false
+	ExpiryGuard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !called {
+		t.Fatalf("expected the next handler to be called")
+	}
+}
@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VerifyCheck is a single check of a `VerifyReport`.
+type VerifyCheck struct {
+	// Name is the check's name: "form", "header", "signature", "payload", "decrypt",
+	// "claims" (the JSON decoding), "nbf", "iat", "exp"
+	// or the Go type of a validator, e.g. "jwt.Expected".
+	Name string
+	// Err is the check's error, nil if it passed.
+	// The error of a validator may be its previous error, if it respects it.
+	Err error
+	// Duration is the time spent on the check.
+	Duration time.Duration
+}
+
+// Passed reports whether the check passed.
+func (c VerifyCheck) Passed() bool {
+	return c.Err == nil
+}
+
+// VerifyReport is the report of a token verification, see `VerifyExplain`.
+// The checks are listed in order, the checks after a failure
+// (e.g. the claims of a token of an invalid signature) are not performed.
+// The "nbf", "iat" and "exp" checks are reported separately, even after a failure of one of them.
+type VerifyReport struct {
+	Checks   []VerifyCheck
+	Duration time.Duration // The total verification time.
+
+	// The verification result.
+	Token *VerifiedToken
+	Err   error
+}
+
+// String returns a human-readable list of the report's checks, for debugging.
+func (r *VerifyReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Checks {
+		status := "PASS"
+		if !c.Passed() {
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(&b, "%s %-12s %10s", status, c.Name, c.Duration)
+		if c.Err != nil {
+			fmt.Fprintf(&b, " %v", c.Err)
+		}
+		b.WriteByte('\n')
+	}
+
+	fmt.Fprintf(&b, "total %s", r.Duration)
+	if r.Err != nil {
+		fmt.Fprintf(&b, ": %v", r.Err)
+	}
+
+	return b.String()
+}
+
+// now returns the current time, or zero on a nil report.
+func (r *VerifyReport) now() time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// add appends a check of the time spent since "start".
+// It's a no-op on a nil report.
+func (r *VerifyReport) add(name string, start time.Time, err error) {
+	if r == nil {
+		return
+	}
+
+	r.Checks = append(r.Checks, VerifyCheck{Name: name, Err: err, Duration: time.Since(start)})
+}
+
+type verifyReportContextKey struct{}
+
+// WithVerifyReport returns a copy of the "ctx" which records the checks
+// and the result of a verification, through the `VerifyContext` function or a `Verifier`,
+// to the returned report. A context (and its report) should be used for a single verification.
+//
+// Usage:
+//  ctx, report := jwt.WithVerifyReport(r.Context())
+//  verifiedToken, err := verifier.VerifyTokenContext(ctx, token)
+//  log.Println(report)
+func WithVerifyReport(ctx context.Context) (context.Context, *VerifyReport) {
+	report := new(VerifyReport)
+	return context.WithValue(ctx, verifyReportContextKey{}, report), report
+}
+
+// verifyReport returns the report of the "ctx" or nil.
+func verifyReport(ctx context.Context) *VerifyReport {
+	report, _ := ctx.Value(verifyReportContextKey{}).(*VerifyReport)
+	return report
+}
+
+// VerifyExplain same as `Verify` but it returns the report of every check performed,
+// with its result and its duration, for debugging authentication failures.
+// It runs the same code as `Verify`.
+//
+// Usage:
+//  report := jwt.VerifyExplain(jwt.HS256, sharedKey, token, jwt.Expected{Issuer: "myapp"})
+//  fmt.Println(report)
+//  // PASS form                 1µs
+//  // PASS header               9µs
+//  // PASS signature            4µs
+//  // ...
+//  // FAIL jwt.Expected         2µs token expectation failed: iss
+func VerifyExplain(alg AlgVerifier, key PublicKey, token []byte, validators ...TokenValidator) *VerifyReport {
+	return VerifyExplainContext(context.Background(), alg, key, token, validators...)
+}
+
+// VerifyExplainContext same as `VerifyExplain` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+func VerifyExplainContext(ctx context.Context, alg AlgVerifier, key PublicKey, token []byte, validators ...TokenValidator) *VerifyReport {
+	ctx, report := WithVerifyReport(ctx)
+	VerifyContext(ctx, alg, key, token, validators...)
+	return report
+}
@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func checkNames(report *VerifyReport) []string {
+	names := make([]string, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestVerifyExplain(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{Issuer: "myapp"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := VerifyExplain(testAlg, testSecret, token, Expected{Issuer: "myapp"})
+	if report.Err != nil || report.Token == nil {
+		t.Fatalf("expected a verified token but got: %v", report.Err)
+	}
+
+	expected := "form header signature payload claims nbf iat exp jwt.Expected"
+	if got := strings.Join(checkNames(report), " "); got != expected {
+		t.Fatalf("expected checks: %q but got: %q", expected, got)
+	}
+
+	for _, c := range report.Checks {
+		if !c.Passed() {
+			t.Fatalf("expected check %q to pass but got: %v", c.Name, c.Err)
+		}
+	}
+
+	// The checks stop on the first failure.
+	report = VerifyExplain(testAlg, []byte("invalid"), token, Expected{Issuer: "myapp"})
+	if !errors.Is(report.Err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, report.Err)
+	}
+
+	if got := strings.Join(checkNames(report), " "); got != "form header signature" {
+		t.Fatalf("expected checks of the signature failure but got: %q", got)
+	}
+
+	if last := report.Checks[len(report.Checks)-1]; last.Err != report.Err {
+		t.Fatalf("expected the failed check error: %v but got: %v", report.Err, last.Err)
+	}
+
+	if s := report.String(); !strings.Contains(s, "FAIL signature") {
+		t.Fatalf("expected a failed signature line but got:\n%s", s)
+	}
+}
+
+func TestWithVerifyReport(t *testing.T) {
+	now := time.Now()
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: now.Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	ctx, report := WithVerifyReport(context.Background())
+	if _, err = verifier.VerifyTokenContext(ctx, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if report.Err != ErrExpired {
+		t.Fatalf("expected report error: %v but got: %v", ErrExpired, report.Err)
+	}
+
+	var exp *VerifyCheck
+	for i := range report.Checks {
+		if report.Checks[i].Name == "exp" {
+			exp = &report.Checks[i]
+		}
+	}
+
+	if exp == nil || exp.Err != ErrExpired {
+		t.Fatalf("expected a failed exp check but got: %#+v", report.Checks)
+	}
+}
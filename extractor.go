@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TokenExtractor is a function which extracts a token from an HTTP request.
+// It returns an empty string when the request does not carry a token.
+// See `FromHeader`, `FromQuery` and `FromCookie`.
+type TokenExtractor func(r *http.Request) string
+
+// TokenSource is a named TokenExtractor, see the `Verifier.Sources` field.
+// The name of the source which matched a request's token is reported
+// through the `GetTokenSource` function, useful on migrations between
+// token storage strategies (e.g. from a cookie to the Authorization header).
+type TokenSource struct {
+	Name    string // e.g. "bearer", "cookie".
+	Extract TokenExtractor
+}
+
+// FromHeader is a TokenExtractor which reads the token
+// from the "Authorization: Bearer $token" request header.
+func FromHeader(r *http.Request) string {
+	return trimScheme(r.Header.Get("Authorization"), "Bearer")
+}
+
+// FromAuthorization returns a TokenExtractor which reads the token
+// from the "Authorization: $scheme $token" request header, e.g. FromAuthorization("JWT").
+// The scheme is case-insensitive.
+func FromAuthorization(scheme string) TokenExtractor {
+	return func(r *http.Request) string {
+		return trimScheme(r.Header.Get("Authorization"), scheme)
+	}
+}
+
+// FromCustomHeader returns a TokenExtractor which reads the token
+// from the "name" request header, e.g. FromCustomHeader("X-Access-Token").
+// An optional "Bearer" scheme prefix of the header value is removed.
+func FromCustomHeader(name string) TokenExtractor {
+	return func(r *http.Request) string {
+		return customHeaderToken(r.Header.Get(name))
+	}
+}
+
+// customHeaderToken returns the token of a custom header "value",
+// without its optional "Bearer" scheme prefix.
+func customHeaderToken(value string) string {
+	value = strings.TrimSpace(value)
+	if token := trimScheme(value, "Bearer"); token != "" {
+		return token
+	}
+
+	if strings.IndexByte(value, ' ') != -1 {
+		return "" // a value of a different scheme.
+	}
+
+	return value
+}
+
+// trimScheme returns the credentials of "value" if it starts with the given (case-insensitive) "scheme",
+// otherwise it returns an empty string.
+func trimScheme(value, scheme string) string {
+	prefix := len(scheme) + 1
+	if len(value) <= prefix || value[len(scheme)] != ' ' || !strings.EqualFold(value[:len(scheme)], scheme) {
+		return ""
+	}
+
+	return strings.TrimSpace(value[prefix:])
+}
+
+// FromQuery returns a TokenExtractor which reads the token
+// from the "key" URL query parameter, e.g. FromQuery("token").
+func FromQuery(key string) TokenExtractor {
+	return func(r *http.Request) string {
+		return r.URL.Query().Get(key)
+	}
+}
+
+// FromCookie returns a TokenExtractor which reads the token
+// from the value of the "name" cookie.
+func FromCookie(name string) TokenExtractor {
+	return func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+
+		return cookie.Value
+	}
+}
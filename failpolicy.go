@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDependencyUnavailable indicates a verification which failed because of a remote dependency
+// (a JWKS endpoint, a blocklist store or an introspection service) under the FailClosed policy,
+// the returned error is a type of *DependencyError.
+var ErrDependencyUnavailable = errors.New("dependency unavailable")
+
+// The names of the remote dependencies of a verification,
+// see `DependencyError` and the `Statistics.Degraded` field.
+const (
+	DependencyJWKS          = "jwks"
+	DependencyBlocklist     = "blocklist"
+	DependencyIntrospection = "introspection"
+)
+
+// FailurePolicy is the behavior of a verification when a remote dependency fails,
+// see the `JWKSClient.FailurePolicy`, `BloomBlocklist.FailurePolicy`
+// and `RemoteVerifier.FailurePolicy` fields. The zero value is FailClosed.
+type FailurePolicy uint8
+
+const (
+	// FailClosed rejects the tokens with a *DependencyError (a type of ErrDependencyUnavailable,
+	// 503 Service Unavailable by the `DefaultErrorHandler`) while the dependency fails.
+	// It's the default policy.
+	FailClosed FailurePolicy = iota
+	// FailOpen is the opt-in degraded mode: the tokens are verified without the failed dependency
+	// and each such verification is counted by the `Statistics.Degraded` field.
+	// It trades security for availability, e.g. a revoked token is accepted while the blocklist is down.
+	FailOpen
+)
+
+// String returns the name of the policy, "fail-closed" or "fail-open".
+func (p FailurePolicy) String() string {
+	if p == FailOpen {
+		return "fail-open"
+	}
+
+	return "fail-closed"
+}
+
+// DependencyError is the error of a failed remote dependency under the FailClosed policy.
+// It's a type of ErrDependencyUnavailable and it unwraps to the dependency's error (e.g. ErrCircuitOpen).
+type DependencyError struct {
+	Dependency string // The dependency name, e.g. "jwks".
+	Err        error  // The dependency's error.
+}
+
+// Error completes the error interface.
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("%v: %s: %v", ErrDependencyUnavailable, e.Dependency, e.Err)
+}
+
+// Is reports whether the "target" is the ErrDependencyUnavailable, see errors.Is.
+func (e *DependencyError) Is(target error) bool {
+	return target == ErrDependencyUnavailable
+}
+
+// Unwrap returns the dependency's error.
+func (e *DependencyError) Unwrap() error {
+	return e.Err
+}
+
+// dependencyError wraps the "err" of the "dependency" with a *DependencyError,
+// unless it's already one.
+func dependencyError(dependency string, err error) error {
+	if errors.Is(err, ErrDependencyUnavailable) {
+		return err
+	}
+
+	return &DependencyError{Dependency: dependency, Err: err}
+}
@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testFailingBlocklist struct {
+	*Blocklist
+	err error
+}
+
+func (s *testFailingBlocklist) Has(key string) (bool, error) {
+	return false, s.err
+}
+
+func TestFailurePolicyBlocklist(t *testing.T) {
+	failed := errors.New("store is down")
+	store := &testFailingBlocklist{Blocklist: NewBlocklist(0), err: failed}
+	b := NewBloomBlocklist(store, func(ctx context.Context, add func(key string)) error {
+		return failed // no filter, all keys are looked up in the store.
+	}, 100, 0)
+
+	token, err := Sign(testAlg, testSecret, Map{"jti": "id"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Verify(testAlg, testSecret, token, b)
+	var depErr *DependencyError
+	if !errors.As(err, &depErr) || depErr.Dependency != DependencyBlocklist || !errors.Is(err, failed) {
+		t.Fatalf("expected a blocklist dependency error but got: %v", err)
+	}
+
+	if got := HTTPStatus(err); got != http.StatusServiceUnavailable {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusServiceUnavailable, got)
+	}
+
+	if got := FailureReason(err); got != "dependency_unavailable" {
+		t.Fatalf("expected failure reason: dependency_unavailable but got: %s", got)
+	}
+
+	prev := Stats().Degraded[DependencyBlocklist]
+	b.FailurePolicy = FailOpen
+	if _, err = Verify(testAlg, testSecret, token, b); err != nil {
+		t.Fatalf("expected the token to be accepted but got: %v", err)
+	}
+
+	if got := Stats().Degraded[DependencyBlocklist]; got != prev+1 {
+		t.Fatalf("expected %d degraded verifications but got: %d", prev+1, got)
+	}
+}
+
+func TestFailurePolicyRemote(t *testing.T) {
+	failed := errors.New("service unavailable")
+	remote := NewRemoteVerifier(RemoteVerificationFunc(func(ctx context.Context, token []byte) (*RemoteResult, error) {
+		return nil, failed
+	}))
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.FailurePolicy = FailOpen // without a Local Verifier it can't fail open.
+	if _, err = remote.Verify(context.Background(), token); !errors.Is(err, ErrDependencyUnavailable) || !errors.Is(err, failed) {
+		t.Fatalf("expected error: %v but got: %v", ErrDependencyUnavailable, err)
+	}
+
+	prev := Stats().Degraded[DependencyIntrospection]
+	remote.Local = NewVerifier(testAlg, testSecret)
+	verifiedToken, err := remote.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("expected the locally verified token but got: %v", err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("expected the local claims but got: %#+v", verifiedToken.StandardClaims)
+	}
+
+	if got := Stats().Degraded[DependencyIntrospection]; got != prev+1 {
+		t.Fatalf("expected %d degraded verifications but got: %d", prev+1, got)
+	}
+}
+
+func TestFailurePolicyJWKS(t *testing.T) {
+	set := testJWKS(t)
+	var down uint32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadUint32(&down) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "max-age=60")
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	newClient := func(policy FailurePolicy) *JWKSClient {
+		c := NewJWKSClient(srv.URL)
+		c.Clock = func() time.Time { return now }
+		c.StaleMaxAge = time.Minute
+		c.FailurePolicy = policy
+		if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	closed, open := newClient(FailClosed), newClient(FailOpen)
+
+	atomic.StoreUint32(&down, 1)
+	now = now.Add(10 * time.Minute) // beyond the StaleMaxAge.
+
+	_, err := closed.PublicKey(context.Background(), "rsa")
+	var depErr *DependencyError
+	if !errors.As(err, &depErr) || depErr.Dependency != DependencyJWKS {
+		t.Fatalf("expected a jwks dependency error but got: %v", err)
+	}
+
+	// The first call serves the cached key while it refreshes in the background.
+	if _, err = open.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+	waitJWKSRefresh(open)
+
+	prev := Stats().Degraded[DependencyJWKS]
+	for i := 0; i < 2; i++ {
+		if _, err = open.PublicKey(context.Background(), "rsa"); err != nil {
+			t.Fatalf("[%d] expected the last-known-good key but got: %v", i, err)
+		}
+	}
+
+	if got := Stats().Degraded[DependencyJWKS]; got != prev+2 {
+		t.Fatalf("expected %d degraded verifications but got: %d", prev+2, got)
+	}
+
+	// Recovery.
+	atomic.StoreUint32(&down, 0)
+	now = now.Add(time.Minute)
+	if _, err = open.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitJWKSRefresh(open)
+	if open.Stale() {
+		t.Fatalf("expected client to recover from stale state")
+	}
+
+	// The call which triggered the recovering refresh was still served degraded.
+	if got := Stats().Degraded[DependencyJWKS]; got != prev+3 {
+		t.Fatalf("expected no degraded verifications after recovery but got: %d", got-prev-3)
+	}
+
+	if _, err = open.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Stats().Degraded[DependencyJWKS]; got != prev+3 {
+		t.Fatalf("expected no degraded verifications after recovery but got: %d", got-prev-3)
+	}
+}
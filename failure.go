@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FailureEntry holds the information of a failed verification,
+// it's passed to the `OnFailure` hook.
+// It never contains the raw token, see `RedactToken`.
+type FailureEntry struct {
+	Time   time.Time // The time of the failure.
+	Reason string    // The machine-readable reason, see `FailureReason`.
+	Err    error     // The verification error.
+	Token  string    // The redacted token, see `RedactToken`.
+	// Suppressed is the number of failures which were not passed to the hook
+	// since the previous entry, see `RateLimitFailures`.
+	Suppressed uint64
+}
+
+// OnFailure is an optional hook which is called on every failed verification
+// (`Verify`, `VerifyEncrypted`, a `Verifier` and the functions which use them).
+// It can be used to log the authentication failures.
+// It should be set once on initialization of the program and it must be safe for concurrent use.
+//
+// A burst of failures (e.g. a credential-stuffing attack) can flood the logs,
+// wrap the hook with `RateLimitFailures` to limit the calls.
+// The aggregate failure counts are always available through the `Stats` function.
+//
+// Usage:
+//  jwt.OnFailure = jwt.RateLimitFailures(10, time.Second, func(entry jwt.FailureEntry) {
+//    log.Printf("verify: %s: %v (suppressed: %d)", entry.Reason, entry.Err, entry.Suppressed)
+//  })
+var OnFailure func(entry FailureEntry)
+
+// reportFailure calls the OnFailure hook, if any.
+func reportFailure(token []byte, err error) {
+	if OnFailure == nil {
+		return
+	}
+
+	OnFailure(FailureEntry{
+		Time:   Clock(),
+		Reason: FailureReason(err),
+		Err:    err,
+		Token:  RedactToken(token),
+	})
+}
+
+// RateLimitFailures wraps the "hook" so it's called at most "n" times per "every" duration.
+// The failures over the limit are suppressed, their number is reported by the next
+// passed entry's Suppressed field and by the `Statistics.SuppressedFailures` counter.
+func RateLimitFailures(n int, every time.Duration, hook func(entry FailureEntry)) func(entry FailureEntry) {
+	var (
+		mu          sync.Mutex
+		windowStart time.Time
+		calls       int
+		suppressed  uint64
+	)
+
+	return func(entry FailureEntry) {
+		mu.Lock()
+		if entry.Time.Sub(windowStart) >= every || entry.Time.Before(windowStart) {
+			windowStart = entry.Time
+			calls = 0
+		}
+
+		if calls >= n {
+			suppressed++
+			mu.Unlock()
+			atomic.AddUint64(&stats.suppressedFailures, 1)
+			return
+		}
+
+		calls++
+		entry.Suppressed = suppressed
+		suppressed = 0
+		mu.Unlock()
+
+		hook(entry)
+	}
+}
@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnFailure(t *testing.T) {
+	now := time.Now()
+	Clock = func() time.Time { return now }
+
+	var entries []FailureEntry
+	OnFailure = RateLimitFailures(2, time.Second, func(entry FailureEntry) {
+		entries = append(entries, entry)
+	})
+	t.Cleanup(func() {
+		OnFailure = nil
+		Clock = time.Now
+	})
+
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: now.Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suppressed := Stats().SuppressedFailures
+	for i := 0; i < 5; i++ {
+		if _, err = Verify(testAlg, testSecret, token); err != ErrExpired {
+			t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+		}
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 reported failures but got: %d", len(entries))
+	}
+
+	if got := Stats().SuppressedFailures - suppressed; got != 3 {
+		t.Fatalf("expected 3 suppressed failures but got: %d", got)
+	}
+
+	entry := entries[0]
+	if entry.Reason != "expired" || entry.Err != ErrExpired || strings.Contains(entry.Token, string(token[len(token)-10:])) {
+		t.Fatalf("unexpected failure entry: %#+v", entry)
+	}
+
+	// Test the next window reports the suppressed failures.
+	now = now.Add(time.Second)
+	if _, err = Verify(testAlg, testSecret, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if len(entries) != 3 || entries[2].Suppressed != 3 {
+		t.Fatalf("expected a third entry with 3 suppressed failures but got: %#+v", entries)
+	}
+
+	// Test successful verifications are not reported.
+	token, err = Sign(testAlg, testSecret, Claims{Expiry: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("expected successful verification to not be reported")
+	}
+}
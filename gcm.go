@@ -3,7 +3,6 @@ package jwt
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"errors"
 	"io"
 )
@@ -44,7 +43,7 @@ func GCM(key, additionalData []byte) (encrypt, decrypt InjectFunc, err error) {
 
 	encrypt = func(payload []byte) ([]byte, error) {
 		nonce := make([]byte, gcm.NonceSize())
-		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		if _, err := io.ReadFull(Random, nonce); err != nil {
 			return nil, err
 		}
 
@@ -0,0 +1,125 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// The claims of the anonymous (guest) tokens, see `Issuer.GuestToken`.
+const (
+	// ClaimAnonymous is the name of the (boolean) claim which marks a guest token.
+	ClaimAnonymous = "anon"
+	// ClaimDevice is the name of the claim which holds the device identifier of a guest token.
+	ClaimDevice = "device"
+)
+
+var (
+	// ErrAnonymous indicates a guest token (or a token without a subject)
+	// which was presented to an `Authenticated` verifier.
+	ErrAnonymous = errors.New("token is anonymous")
+	// ErrGuestDevice indicates that a guest token was requested without a device identifier.
+	ErrGuestDevice = errors.New("issuer: guest device is required")
+)
+
+// defaultGuestProfile is the signing profile of the guest tokens
+// of an Issuer without a GuestProfile.
+var defaultGuestProfile = SigningProfile{
+	MaxAge:   5 * time.Minute,
+	Audience: []string{"guest"},
+}
+
+// GuestToken generates an anonymous token for the free tier of an API:
+// it has no "sub" claim, it's marked by the "anon" claim and it carries the "device" one,
+// so the rate limiters can key the requests of a guest by its device.
+// Its lifetime and audience are the Issuer's GuestProfile ones,
+// which default to 5 minutes and the "guest" audience.
+// The Issuer's Quota is consulted with the "guest:" + device as the subject,
+// see `MintBySubject` and `MintByClient`.
+// Returns ErrGuestDevice if the "device" is empty.
+//
+// Usage:
+//  token, err := issuer.GuestToken(r.Context(), deviceID)
+//  [...]
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.Expected{Audience: []string{"guest"}})
+//  device, isGuest := jwt.GuestDevice(verifiedToken)
+func (i *Issuer) GuestToken(ctx context.Context, device string) ([]byte, error) {
+	if device == "" {
+		return nil, ErrGuestDevice
+	}
+
+	if i.Quota != nil {
+		if err := i.Quota(ctx, "guest:"+device); err != nil {
+			return nil, err
+		}
+	}
+
+	profile := defaultGuestProfile
+	if i.GuestProfile != nil {
+		profile = *i.GuestProfile
+	}
+
+	if profile.MaxAge <= 0 {
+		profile.MaxAge = defaultGuestProfile.MaxAge
+	}
+
+	if len(profile.Audience) == 0 {
+		profile.Audience = defaultGuestProfile.Audience
+	}
+
+	claims := make(Map, len(profile.Claims)+2)
+	for k, v := range profile.Claims {
+		claims[k] = v
+	}
+	claims[ClaimAnonymous] = true
+	claims[ClaimDevice] = device
+	profile.Claims = claims
+
+	return i.sign(ctx, "", &profile, "", nil)
+}
+
+// guestClaims are the claims of a guest token.
+type guestClaims struct {
+	Anonymous bool   `json:"anon"`
+	Device    string `json:"device"`
+}
+
+// GuestDevice reports whether the verified token is a guest token (see `Issuer.GuestToken`)
+// and returns its device identifier, e.g. the key of a rate limiter.
+func GuestDevice(t *VerifiedToken) (string, bool) {
+	return guestDevice(t.Payload)
+}
+
+func guestDevice(payload []byte) (string, bool) {
+	var claims guestClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || !claims.Anonymous {
+		return "", false
+	}
+
+	return claims.Device, true
+}
+
+// Authenticated returns a TokenValidator which rejects the guest tokens (see `Issuer.GuestToken`)
+// and the tokens without a "sub" claim with ErrAnonymous,
+// for the endpoints of the authenticated users only.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.HS256, sharedKey, jwt.Authenticated())
+func Authenticated() TokenValidator {
+	return PayloadValidatorFunc(func(_ context.Context, _, payload []byte, claims Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if claims.Subject == "" {
+			return ErrAnonymous
+		}
+
+		if _, isGuest := guestDevice(payload); isGuest {
+			return ErrAnonymous
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGuestToken(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Hour)
+	ctx := context.Background()
+
+	var quotaSubject string
+	issuer.Quota = func(_ context.Context, subject string) error {
+		quotaSubject = subject
+		return nil
+	}
+
+	token, err := issuer.GuestToken(ctx, "device-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if quotaSubject != "guest:device-1" {
+		t.Fatalf("expected the quota of the device but got: %q", quotaSubject)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, Expected{Audience: []string{"guest"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims := verifiedToken.StandardClaims; claims.Subject != "" || claims.Age() != 5*time.Minute {
+		t.Fatalf("expected a short-lived token without a subject but got: %#+v", claims)
+	}
+
+	if device, isGuest := GuestDevice(verifiedToken); !isGuest || device != "device-1" {
+		t.Fatalf("expected a guest token of device-1 but got: %q (%v)", device, isGuest)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, Authenticated()); !errors.Is(err, ErrAnonymous) {
+		t.Fatalf("expected error: %v but got: %v", ErrAnonymous, err)
+	}
+
+	if _, err = issuer.GuestToken(ctx, ""); !errors.Is(err, ErrGuestDevice) {
+		t.Fatalf("expected error: %v but got: %v", ErrGuestDevice, err)
+	}
+}
+
+func TestAuthenticated(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Hour)
+	issuer.GuestProfile = &SigningProfile{MaxAge: time.Minute, Audience: []string{"free"}}
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, Authenticated())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, isGuest := GuestDevice(verifiedToken); isGuest {
+		t.Fatalf("expected an authenticated token")
+	}
+
+	// A token of a subject which is marked as anonymous is rejected too.
+	token, err = issuer.Token("kataras", Map{ClaimAnonymous: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, Authenticated()); !errors.Is(err, ErrAnonymous) {
+		t.Fatalf("expected error: %v but got: %v", ErrAnonymous, err)
+	}
+
+	guest, err := issuer.GuestToken(context.Background(), "device-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, guest, Expected{Audience: []string{"free"}}); err != nil {
+		t.Fatal(err)
+	}
+}
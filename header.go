@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// HeaderField is a name and value pair of a `Header`.
+type HeaderField struct {
+	Name  string
+	Value interface{}
+}
+
+// Header is the ordered list of the JSON header fields of a token signed by `SignWithHeader`.
+// The fields are written exactly as listed: in the same order and without whitespace,
+// the builtin "typ" field is not added, so the signed output can match byte-to-byte
+// what a legacy verifier (e.g. one with a naive string comparison) expects.
+// The "alg" field is required and its value must be the signing algorithm's name.
+//
+// Usage:
+//  header := jwt.Header{{Name: "typ", Value: "JWT"}, {Name: "alg", Value: "HS256"}}
+//  // eyJ0eXAiOiJKV1QiLCJhbGciOiJIUzI1NiJ9 ({"typ":"JWT","alg":"HS256"})
+type Header []HeaderField
+
+// SignWithHeader same as `Sign` but it writes the given, exact, "header" instead of the builtin one.
+// Note that the payload is written as `Marshal` encodes it, pass the "claims" as []byte
+// to control its bytes too. The tokens are verified by `Verify` as usual.
+//
+// Returns a type of ErrTokenAlg if the "header" misses the "alg" field or it does not match "alg".
+func SignWithHeader(alg AlgSigner, key PrivateKey, header Header, claims interface{}, opts ...SignOption) ([]byte, error) {
+	encoded, err := header.encode(alg.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := header.value("kid").(string)
+	return signWithHeader(alg, key, kid, Base64Encode(encoded), nil, claims, opts...)
+}
+
+// value returns the value of the field "name", or nil.
+func (h Header) value(name string) interface{} {
+	for _, field := range h {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+
+	return nil
+}
+
+// encode returns the JSON (not base64-encoded) header of the fields.
+func (h Header) encode(alg string) ([]byte, error) {
+	if value, ok := h.value("alg").(string); !ok || value != alg {
+		return nil, fmt.Errorf("%w: header alg does not match %s", ErrTokenAlg, alg)
+	}
+
+	seen := make(map[string]struct{}, len(h))
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range h {
+		if _, ok := seen[field.Name]; ok {
+			return nil, fmt.Errorf("header: duplicate field %q", field.Name)
+		}
+		seen[field.Name] = struct{}{}
+
+		name, _ := json.Marshal(field.Name) // a string never fails to marshal.
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("header: field %q: %w", field.Name, err)
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
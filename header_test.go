@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSignWithHeader(t *testing.T) {
+	payload := []byte(`{"sub":"kataras"}`)
+
+	tests := []struct {
+		header   Header
+		expected string
+	}{
+		{Header{{"typ", "JWT"}, {"alg", "HS256"}}, `{"typ":"JWT","alg":"HS256"}`},
+		{Header{{"alg", "HS256"}}, `{"alg":"HS256"}`},
+		{Header{{"kid", "key-1"}, {"alg", "HS256"}, {"typ", "JWT"}}, `{"kid":"key-1","alg":"HS256","typ":"JWT"}`},
+	}
+
+	for i, tt := range tests {
+		token, err := SignWithHeader(testAlg, testSecret, tt.header, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts := bytes.Split(token, sep)
+		if got := string(parts[0]); got != string(Base64Encode([]byte(tt.expected))) {
+			t.Fatalf("[%d] expected header: %s but got: %s", i, tt.expected, got)
+		}
+
+		if got := string(parts[1]); got != string(Base64Encode(payload)) {
+			t.Fatalf("[%d] expected payload: %s but got: %s", i, payload, got)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if got := string(verifiedToken.Header); got != tt.expected {
+			t.Fatalf("[%d] expected decoded header: %s but got: %s", i, tt.expected, got)
+		}
+	}
+
+	for i, header := range []Header{
+		{{"typ", "JWT"}},
+		{{"alg", "RS256"}},
+		{{"alg", "HS256"}, {"alg", "HS256"}},
+		{{"alg", "HS256"}, {"x", func() {}}},
+	} {
+		if _, err := SignWithHeader(testAlg, testSecret, header, payload); err == nil {
+			t.Fatalf("[%d] expected an error", i)
+		} else if i < 2 && !errors.Is(err, ErrTokenAlg) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrTokenAlg, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrNotReady is returned by `Health.Ready` when a key provider
+// cannot serve keys, e.g. it never refreshed successfully.
+var ErrNotReady = errors.New("key provider is not ready")
+
+// Health holds the health information of a key provider.
+// See the `Healther` interface.
+type Health struct {
+	// LastRefresh is the time of the last successful refresh.
+	LastRefresh time.Time
+	// Keys is the number of the currently available keys.
+	Keys int
+	// LastError is the error of the last refresh, if any.
+	LastError error
+	// Stale reports whether the provider serves last-known-good keys
+	// after a failed refresh.
+	Stale bool
+}
+
+// Ready returns a non-nil error if the key provider cannot serve keys.
+// A provider which serves stale keys is still considered ready.
+func (h Health) Ready() error {
+	if h.LastRefresh.IsZero() || h.Keys == 0 {
+		if h.LastError != nil {
+			return h.LastError
+		}
+
+		return ErrNotReady
+	}
+
+	if h.LastError != nil && !h.Stale {
+		return h.LastError
+	}
+
+	return nil
+}
+
+// MarshalJSON completes the json.Marshaler interface.
+func (h Health) MarshalJSON() ([]byte, error) {
+	v := struct {
+		LastRefresh *time.Time `json:"last_refresh,omitempty"`
+		Keys        int        `json:"keys"`
+		LastError   string     `json:"last_error,omitempty"`
+		Stale       bool       `json:"stale,omitempty"`
+		Ready       bool       `json:"ready"`
+	}{
+		Keys:  h.Keys,
+		Stale: h.Stale,
+		Ready: h.Ready() == nil,
+	}
+
+	if !h.LastRefresh.IsZero() {
+		v.LastRefresh = &h.LastRefresh
+	}
+
+	if h.LastError != nil {
+		v.LastError = h.LastError.Error()
+	}
+
+	return json.Marshal(v)
+}
+
+// Healther is implemented by the key providers which can report
+// their health, e.g. the `JWKSClient`.
+type Healther interface {
+	Health() Health
+}
+
+// HealthHandler returns an HTTP handler which reports the health of the given key providers
+// as JSON, it can be registered as (or called by) a readiness endpoint.
+// It responds with 200 OK if all providers are ready, otherwise with 503 Service Unavailable.
+//
+// Usage:
+//  http.Handle("/readyz", HealthHandler(map[string]Healther{"idp": jwksClient}))
+func HealthHandler(providers map[string]Healther) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusCode := http.StatusOK
+		health := make(map[string]Health, len(providers))
+		for name, provider := range providers {
+			h := provider.Health()
+			if h.Ready() != nil {
+				statusCode = http.StatusServiceUnavailable
+			}
+
+			health[name] = h
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(health)
+	})
+}
@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJWKSClientHealth(t *testing.T) {
+	set := testJWKS(t)
+	var down uint32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadUint32(&down) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	c := NewJWKSClient(srv.URL)
+	if err := c.Health().Ready(); err != ErrNotReady {
+		t.Fatalf("expected error: %v but got: %v", ErrNotReady, err)
+	}
+
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	h := c.Health()
+	if h.Keys != len(set.Keys) || h.LastRefresh.IsZero() || h.LastError != nil {
+		t.Fatalf("unexpected health: %#+v", h)
+	}
+
+	if err := h.Ready(); err != nil {
+		t.Fatalf("expected ready but got: %v", err)
+	}
+
+	handler := HealthHandler(map[string]Healther{"idp": c})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, rec.Code)
+	}
+
+	atomic.StoreUint32(&down, 1)
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+
+	// The cached keys are still valid.
+	if h = c.Health(); !h.Stale || h.Ready() != nil {
+		t.Fatalf("expected ready with stale keys after a failed refresh but got: %#+v", h)
+	}
+
+	// Expire the cache.
+	c.Clock = func() time.Time { return time.Now().Add(2 * c.MaxAge) }
+	if _, err := c.PublicKey(context.Background(), "rsa"); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+
+	if err := c.Health().Ready(); err == nil {
+		t.Fatalf("expected not ready after a failed refresh")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	var got map[string]struct {
+		LastError string `json:"last_error"`
+		Ready     bool   `json:"ready"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["idp"].Ready || got["idp"].LastError == "" {
+		t.Fatalf("unexpected health response: %#+v", got)
+	}
+}
@@ -3,15 +3,24 @@ package jwt
 import (
 	"crypto"
 	"crypto/hmac"
-	"crypto/rand"
 	_ "crypto/sha256" // ignore:lint
 	_ "crypto/sha512"
+	"hash"
+	"io"
 	"os"
+	"sync"
 )
 
 type algHMAC struct {
 	name   string
 	hasher crypto.Hash
+
+	pool sync.Pool // *hmacState, the keyed hashes are reused by their Reset method.
+}
+
+type hmacState struct {
+	secret []byte
+	h      hash.Hash
 }
 
 func (a *algHMAC) Name() string {
@@ -24,16 +33,26 @@ func (a *algHMAC) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 		return nil, ErrInvalidKey
 	}
 
-	// We can improve its performance (if we store the secret on the same structure)
-	// by using a pool and its Reset method.
-	h := hmac.New(a.hasher.New, secret)
+	state, _ := a.pool.Get().(*hmacState)
+	if state == nil || !hmac.Equal(state.secret, secret) {
+		state = &hmacState{
+			secret: append([]byte(nil), secret...),
+			h:      hmac.New(a.hasher.New, secret),
+		}
+	} else {
+		state.h.Reset()
+	}
+
 	// header.payload
-	_, err := h.Write(headerAndPayload)
+	_, err := state.h.Write(headerAndPayload)
 	if err != nil {
 		return nil, err // this should never happen according to the internal docs.
 	}
 
-	return h.Sum(nil), nil
+	signature := state.h.Sum(nil)
+	a.pool.Put(state)
+
+	return signature, nil
 }
 
 func (a *algHMAC) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
@@ -60,7 +79,7 @@ var panicHandler = func(v interface{}) {
 //  MustGenerateRandom(64)
 func MustGenerateRandom(n int) []byte {
 	key := make([]byte, n)
-	_, err := rand.Read(key)
+	_, err := io.ReadFull(Random, key)
 	if err != nil {
 		panicHandler(err)
 	}
@@ -0,0 +1,184 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The claims of the impersonation tokens, see `Issuer.IssueImpersonation`.
+const (
+	// ClaimActor is the name of the actor claim (RFC 8693 section 4.1),
+	// an object of the acting party's "sub" and of its own (optional) "act" claim.
+	ClaimActor = "act"
+	// ClaimReason is the name of the claim which holds the justification of an impersonation.
+	ClaimReason = "reason"
+)
+
+// defaultImpersonationMaxTTL is the maximum lifetime of the impersonation tokens
+// of an Issuer without an ImpersonationMaxTTL.
+const defaultImpersonationMaxTTL = 15 * time.Minute
+
+var (
+	// ErrImpersonation indicates that an impersonation token can't be issued, see `Issuer.IssueImpersonation`.
+	ErrImpersonation = errors.New("issuer: impersonation")
+	// ErrImpersonationPolicy indicates that an impersonation token was requested
+	// from an Issuer without an ImpersonationPolicy.
+	ErrImpersonationPolicy = fmt.Errorf("%w: policy is required", ErrImpersonation)
+	// ErrImpersonationReason indicates that an impersonation token was requested without a reason.
+	ErrImpersonationReason = fmt.Errorf("%w: reason is required", ErrImpersonation)
+	// ErrImpersonationTTL indicates that an impersonation token was requested
+	// without a lifetime or with one longer than the Issuer's ImpersonationMaxTTL.
+	ErrImpersonationTTL = fmt.Errorf("%w: invalid lifetime", ErrImpersonation)
+)
+
+// ImpersonationEntry holds the information of an issued impersonation token,
+// it's passed to the `Issuer.ImpersonationAudit` hook.
+type ImpersonationEntry struct {
+	Time    time.Time // The time the token was signed.
+	Actor   string    // The acting party's subject, the "act.sub" claim.
+	Subject string    // The impersonated subject, the "sub" claim.
+	Reason  string    // The "reason" claim.
+	ID      string    // The "jti" claim, e.g. to revoke the token.
+	Expiry  int64     // The "exp" claim.
+}
+
+// IssueImpersonation generates a token of the "targetSubject" for the acting party of the "adminClaims"
+// (e.g. the verified claims of a support agent), so it can act on behalf of the target user.
+// The token carries the actor claim (RFC 8693 section 4.1) of the admin's "sub",
+// chained to the admin's own "act" claim (if any), the "reason" claim and a unique "jti" claim.
+//
+// The guardrails:
+//  - the Issuer's ImpersonationPolicy is required, otherwise ErrImpersonationPolicy is returned,
+//    and it must authorize the admin claims, otherwise ErrPolicyDenied is returned;
+//  - the "reason" is mandatory, otherwise ErrImpersonationReason is returned;
+//  - the "ttl" is mandatory and up to the ImpersonationMaxTTL (defaults to 15 minutes), otherwise ErrImpersonationTTL is returned;
+//  - the issued token is reported to the ImpersonationAudit hook (if any).
+//
+// The Issuer's Quota is consulted with the actor's subject.
+// The verifiers can restrict the accepted actors through the `ImpersonatedBy` policy.
+//
+// Usage:
+//  issuer.ImpersonationPolicy = jwt.ClaimContains("roles", "support")
+//  issuer.ImpersonationAudit = func(entry jwt.ImpersonationEntry) { ledger.Record(entry) }
+//
+//  var adminClaims jwt.Map
+//  verifiedToken.Claims(&adminClaims)
+//  token, err := issuer.IssueImpersonation(r.Context(), adminClaims, "user-42", "ticket #1234", 10*time.Minute)
+func (i *Issuer) IssueImpersonation(ctx context.Context, adminClaims Map, targetSubject, reason string, ttl time.Duration) ([]byte, error) {
+	if i.ImpersonationPolicy == nil {
+		return nil, ErrImpersonationPolicy // fail closed, who may impersonate must be explicit.
+	}
+
+	actor, _ := adminClaims["sub"].(string)
+	if actor == "" {
+		return nil, fmt.Errorf("%w: actor subject is required", ErrImpersonation)
+	}
+
+	if targetSubject == "" || targetSubject == actor {
+		return nil, fmt.Errorf("%w: invalid target subject", ErrImpersonation)
+	}
+
+	if reason == "" {
+		return nil, ErrImpersonationReason
+	}
+
+	maxTTL := i.ImpersonationMaxTTL
+	if maxTTL <= 0 {
+		maxTTL = defaultImpersonationMaxTTL
+	}
+
+	if ttl <= 0 || ttl > maxTTL {
+		return nil, ErrImpersonationTTL
+	}
+
+	if !i.ImpersonationPolicy(adminClaims) {
+		return nil, ErrPolicyDenied
+	}
+
+	if i.Quota != nil {
+		if err := i.Quota(ctx, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	act := Map{"sub": actor}
+	if prev, ok := adminClaims[ClaimActor].(Map); ok {
+		act[ClaimActor] = prev // the admin acts on behalf of another party too.
+	}
+
+	id := string(Base64Encode(MustGenerateRandom(16)))
+	claims := Map{
+		ClaimActor:  act,
+		ClaimReason: reason,
+		"jti":       id,
+	}
+
+	profile := &SigningProfile{MaxAge: ttl, Audience: i.Audience}
+	token, err := i.sign(ctx, "", profile, targetSubject, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.ImpersonationAudit != nil {
+		entry := ImpersonationEntry{
+			Time:    Clock(),
+			Actor:   actor,
+			Subject: targetSubject,
+			Reason:  reason,
+			ID:      id,
+		}
+
+		var standardClaims Claims
+		if unverifiedPayload(token, &standardClaims) == nil { // not decoded if encrypted.
+			entry.Expiry = standardClaims.Expiry
+		}
+
+		i.ImpersonationAudit(entry)
+	}
+
+	return token, nil
+}
+
+// Actors returns the subjects of the actor claim chain of the claims (see `Issuer.IssueImpersonation`),
+// the current actor first. Returns nil if the token is not an impersonation (or a delegation) one.
+func Actors(claims Map) []string {
+	var actors []string
+
+	act, _ := claims[ClaimActor].(Map)
+	for act != nil {
+		sub, _ := act["sub"].(string)
+		actors = append(actors, sub)
+		act, _ = act[ClaimActor].(Map)
+	}
+
+	return actors
+}
+
+// ImpersonatedBy returns a Policy which authorizes the tokens without an actor claim
+// and the impersonation tokens whose every actor of the chain is one of the "actors" subjects,
+// e.g. to accept impersonation tokens of the support team only.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.HS256, sharedKey, jwt.ImpersonatedBy("support-1", "support-2"))
+func ImpersonatedBy(actors ...string) Policy {
+	return func(claims Map) bool {
+		if _, ok := claims[ClaimActor]; !ok {
+			return true
+		}
+
+		chain := Actors(claims)
+		if len(chain) == 0 {
+			return false // a malformed actor claim.
+		}
+
+		for _, actor := range chain {
+			if !containsString(actors, actor) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
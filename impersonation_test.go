@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIssueImpersonation(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Hour)
+	ctx := context.Background()
+
+	// No policy, no impersonation.
+	if _, err := issuer.IssueImpersonation(ctx, Map{"sub": "agent-1"}, "user-42", "ticket #1234", time.Minute); !errors.Is(err, ErrImpersonationPolicy) {
+		t.Fatalf("expected error: %v but got: %v", ErrImpersonationPolicy, err)
+	}
+
+	issuer.ImpersonationPolicy = ClaimContains("roles", "support")
+
+	var entries []ImpersonationEntry
+	issuer.ImpersonationAudit = func(entry ImpersonationEntry) {
+		entries = append(entries, entry)
+	}
+
+	admin := Map{"sub": "agent-1", "roles": []interface{}{"support"}, "act": Map{"sub": "bot"}}
+	token, err := issuer.IssueImpersonation(ctx, admin, "user-42", "ticket #1234", 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims := verifiedToken.StandardClaims; claims.Subject != "user-42" || claims.Age() != 10*time.Minute || claims.ID == "" {
+		t.Fatalf("unexpected claims: %#+v", claims)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := Actors(claims), []string{"agent-1", "bot"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected actors: %v but got: %v", expected, got)
+	}
+
+	if claims[ClaimReason] != "ticket #1234" {
+		t.Fatalf("expected the reason claim but got: %v", claims[ClaimReason])
+	}
+
+	if len(entries) != 1 || entries[0].Actor != "agent-1" || entries[0].Subject != "user-42" ||
+		entries[0].ID != verifiedToken.StandardClaims.ID || entries[0].Expiry != verifiedToken.StandardClaims.Expiry {
+		t.Fatalf("unexpected audit entries: %#+v", entries)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ImpersonatedBy("agent-1", "bot")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ImpersonatedBy("agent-1")); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+
+	tests := []struct {
+		admin   Map
+		target  string
+		reason  string
+		ttl     time.Duration
+		wantErr error
+	}{
+		{admin, "user-42", "", time.Minute, ErrImpersonationReason},
+		{admin, "user-42", "ticket", 0, ErrImpersonationTTL},
+		{admin, "user-42", "ticket", time.Hour, ErrImpersonationTTL},
+		{admin, "agent-1", "ticket", time.Minute, ErrImpersonation},
+		{Map{"roles": []interface{}{"support"}}, "user-42", "ticket", time.Minute, ErrImpersonation},
+		{Map{"sub": "user-1"}, "user-42", "ticket", time.Minute, ErrPolicyDenied},
+	}
+
+	for i, tt := range tests {
+		if _, err = issuer.IssueImpersonation(ctx, tt.admin, tt.target, tt.reason, tt.ttl); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected no audit entries of the denied impersonations but got: %d", len(entries))
+	}
+}
+
+func TestImpersonatedBy(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "user-42"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ImpersonatedBy("agent-1")); err != nil {
+		t.Fatalf("expected a token without an actor claim to be accepted but got: %v", err)
+	}
+
+	token, err = Sign(testAlg, testSecret, Map{"sub": "user-42", "act": "agent-1"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ImpersonatedBy("agent-1")); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+}
@@ -0,0 +1,230 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Run `go test -run TestInteropOutput -interop.update` to regenerate the
+// _testfiles/interop/kataras-jwt.json fixtures, see _testfiles/interop/README.md.
+var updateInterop = flag.Bool("interop.update", false, "regenerate the kataras-jwt.json interop fixtures")
+
+const interopDir = "./_testfiles/interop"
+
+type (
+	interopFixtures struct {
+		Generator string         `json:"generator"`
+		Tokens    []interopToken `json:"tokens"`
+	}
+
+	interopToken struct {
+		Name   string          `json:"name,omitempty"`
+		Alg    string          `json:"alg"`
+		Token  string          `json:"token"`
+		Claims json.RawMessage `json:"claims"`
+		Secret string          `json:"secret,omitempty"` // base64url, HMAC only.
+		JWK    *JWK            `json:"jwk,omitempty"`
+		Key    string          `json:"key,omitempty"`  // a filename of the _testfiles directory.
+		Time   int64           `json:"time,omitempty"` // the verification time, defaults to now.
+	}
+)
+
+var interopAlgs = map[string]Alg{
+	HS256.Name(): HS256, HS384.Name(): HS384, HS512.Name(): HS512,
+	RS256.Name(): RS256, RS384.Name(): RS384, RS512.Name(): RS512,
+	PS256.Name(): PS256, PS384.Name(): PS384, PS512.Name(): PS512,
+	ES256.Name(): ES256, ES384.Name(): ES384, ES512.Name(): ES512,
+	EdDSA.Name(): EdDSA,
+}
+
+var interopClaims = Map{"iss": "interop", "sub": "kataras", "iat": 1600000000}
+
+// TestInteropFixtures verifies the tokens generated by other JWT implementations.
+func TestInteropFixtures(t *testing.T) {
+	filenames, err := filepath.Glob(filepath.Join(interopDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(filenames) == 0 {
+		t.Fatalf("no interop fixtures found in: %s", interopDir)
+	}
+
+	t.Cleanup(func() {
+		Clock = time.Now
+	})
+
+	for _, filename := range filenames {
+		fixtures := readInteropFixtures(t, filename)
+		for i, tt := range fixtures.Tokens {
+			alg, ok := interopAlgs[tt.Alg]
+			if !ok {
+				t.Fatalf("%s: [%d] unsupported algorithm: %s", fixtures.Generator, i, tt.Alg)
+			}
+
+			var key PublicKey
+			switch {
+			case tt.Secret != "":
+				key, err = Base64Decode([]byte(tt.Secret))
+			case tt.JWK != nil:
+				key, err = tt.JWK.PublicKey()
+			default:
+				key, err = readInteropKey(alg, tt.Key, false)
+			}
+			if err != nil {
+				t.Fatalf("%s: [%d] %v", fixtures.Generator, i, err)
+			}
+
+			Clock = time.Now
+			if tt.Time > 0 {
+				Clock = func() time.Time { return time.Unix(tt.Time, 0) }
+			}
+
+			verifiedToken, err := Verify(alg, key, []byte(tt.Token))
+			if err != nil {
+				t.Fatalf("%s: [%d] %s: %v", fixtures.Generator, i, tt.Alg, err)
+			}
+
+			var expected, got Map
+			if err = defaultUnmarshal(tt.Claims, &expected); err != nil {
+				t.Fatalf("%s: [%d] %v", fixtures.Generator, i, err)
+			}
+
+			if err = defaultUnmarshal(verifiedToken.Payload, &got); err != nil {
+				t.Fatalf("%s: [%d] %v", fixtures.Generator, i, err)
+			}
+
+			if !reflect.DeepEqual(expected, got) {
+				t.Fatalf("%s: [%d] %s: expected claims: %v but got: %v", fixtures.Generator, i, tt.Alg, expected, got)
+			}
+		}
+	}
+}
+
+// TestInteropOutput makes sure that the tokens of the deterministic algorithms
+// are encoded byte-to-byte as the ones which the other implementations verify.
+func TestInteropOutput(t *testing.T) {
+	tests := []struct {
+		alg        Alg
+		privateKey string
+		publicKey  string
+	}{
+		{HS256, "hmac.key", "hmac.key"},
+		{RS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+		{PS256, "rsa_private_key.pem", "rsa_public_key.pem"},
+		{ES256, "ecdsa_private_key.pem", "ecdsa_public_key.pem"},
+		{EdDSA, "ed25519_private_key.pem", "ed25519_public_key.pem"},
+	}
+
+	filename := filepath.Join(interopDir, "kataras-jwt.json")
+	fixtures := interopFixtures{Generator: "kataras/jwt"}
+	if !*updateInterop {
+		fixtures = readInteropFixtures(t, filename)
+	}
+
+	for i, tt := range tests {
+		privateKey, err := readInteropKey(tt.alg, tt.privateKey, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := Sign(tt.alg, privateKey, interopClaims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *updateInterop {
+			fixture := interopToken{Alg: tt.alg.Name(), Token: string(token), Key: tt.publicKey}
+			if fixture.Claims, err = json.Marshal(interopClaims); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.alg == HS256 {
+				fixture.Secret = string(Base64Encode(privateKey.([]byte)))
+			} else if fixture.JWK, err = NewJWK("", nil, privateKey.(crypto.Signer).Public()); err != nil {
+				t.Fatal(err)
+			}
+
+			fixtures.Tokens = append(fixtures.Tokens, fixture)
+			continue
+		}
+
+		if i >= len(fixtures.Tokens) || fixtures.Tokens[i].Alg != tt.alg.Name() {
+			t.Fatalf("[%d] missing %s fixture, regenerate them with -interop.update", i, tt.alg.Name())
+		}
+
+		expected := []byte(fixtures.Tokens[i].Token)
+		switch tt.alg {
+		case PS256, ES256: // randomized signatures, compare the signing input only.
+			expected = expected[:bytes.LastIndexByte(expected, '.')]
+			token = token[:bytes.LastIndexByte(token, '.')]
+		}
+
+		if !bytes.Equal(token, expected) {
+			t.Fatalf("[%d] %s: encoding drift:\nexpected: %s\ngot:      %s", i, tt.alg.Name(), expected, token)
+		}
+	}
+
+	if *updateInterop {
+		b, err := json.MarshalIndent(fixtures, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = ioutil.WriteFile(filename, append(b, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readInteropKey reads the "filename" key of the _testfiles directory,
+// the raw contents for HMAC, the private key when "private" is true, the public one otherwise.
+func readInteropKey(alg Alg, filename string, private bool) (interface{}, error) {
+	filename = filepath.Join("./_testfiles", filename)
+
+	var parse KeyParser
+	switch alg {
+	case HS256, HS384, HS512:
+		return ReadFile(filename)
+	case ES256, ES384, ES512:
+		parse = ParseKeyECDSA
+	case EdDSA:
+		parse = ParseKeyEdDSA
+	default:
+		parse = ParseKeyRSA
+	}
+
+	key, err := KeyFromFile(filename, "", parse)
+	if err != nil {
+		return nil, err
+	}
+
+	if private {
+		return key.Private, nil
+	}
+
+	return key.Public, nil
+}
+
+func readInteropFixtures(t *testing.T, filename string) interopFixtures {
+	t.Helper()
+
+	b, err := ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fixtures interopFixtures
+	if err = json.Unmarshal(b, &fixtures); err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+
+	return fixtures
+}
@@ -0,0 +1,84 @@
+package jwt
+
+import "sync"
+
+// The kinds of an `Invalidation`.
+const (
+	// InvalidateRevocation is published when tokens are revoked,
+	// e.g. by the `Blocklist.InvalidateToken` and `Blocklist.RevokeSubject` methods.
+	InvalidateRevocation = "revocation"
+	// InvalidateKeys is published when the verification keys change,
+	// e.g. by a `JWKSClient` refresh which adds, removes or rotates keys.
+	InvalidateKeys = "keys"
+)
+
+// Invalidation is an event of a change which invalidates the cached verification decisions,
+// see `Invalidations`.
+type Invalidation struct {
+	// Kind is the kind of the change, e.g. InvalidateRevocation or InvalidateKeys.
+	Kind string
+	// KeyIDs are the changed key ids of an InvalidateKeys event, if known.
+	KeyIDs []string
+}
+
+// CacheInvalidator is implemented by the caches of verification decisions,
+// which drop their (affected) entries on an Invalidation, e.g. the `MemoVerifier`,
+// the `NegativeCache`, the `RemoteVerifier` and the `TokenExchange`.
+type CacheInvalidator interface {
+	Invalidate(event Invalidation)
+}
+
+var (
+	_ CacheInvalidator = (*MemoVerifier)(nil)
+	_ CacheInvalidator = (*NegativeCache)(nil)
+	_ CacheInvalidator = (*RemoteVerifier)(nil)
+	_ CacheInvalidator = (*TokenExchange)(nil)
+	_ CacheInvalidator = (*APIKeyBridge)(nil)
+)
+
+// Invalidations publishes the revocations and the key changes to the caches of verification decisions,
+// so they take effect immediately instead of after the caches' max age.
+// Set it to the `Invalidations` field of the blocklists and of the `JWKSClient`,
+// or call its Publish method on other changes, e.g. a local key rotation.
+// An Invalidations is safe for concurrent use.
+//
+// Usage:
+//  invalidations := jwt.NewInvalidations(memoVerifier, verifier.NegativeCache, remoteVerifier)
+//  blocklist.Invalidations = invalidations
+//  jwksClient.Invalidations = invalidations
+type Invalidations struct {
+	// OnInvalidate is an optional hook which is called on each published event,
+	// after the caches are invalidated, e.g. to log it.
+	OnInvalidate func(event Invalidation)
+
+	mu     sync.RWMutex
+	caches []CacheInvalidator
+}
+
+// NewInvalidations returns a new Invalidations of the "caches".
+// The rest of the Invalidations fields can be modified before its first use.
+func NewInvalidations(caches ...CacheInvalidator) *Invalidations {
+	return &Invalidations{caches: caches}
+}
+
+// Add registers more caches.
+func (i *Invalidations) Add(caches ...CacheInvalidator) {
+	i.mu.Lock()
+	i.caches = append(i.caches, caches...)
+	i.mu.Unlock()
+}
+
+// Publish invalidates the caches of the "event", it returns after all of them are invalidated.
+func (i *Invalidations) Publish(event Invalidation) {
+	i.mu.RLock()
+	caches := i.caches
+	i.mu.RUnlock()
+
+	for _, cache := range caches {
+		cache.Invalidate(event)
+	}
+
+	if i.OnInvalidate != nil {
+		i.OnInvalidate(event)
+	}
+}
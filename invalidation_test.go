@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInvalidationsRevocation(t *testing.T) {
+	blocklist := NewBlocklist(0)
+	defer blocklist.Close()
+
+	var calls uint32
+	remote := NewRemoteVerifier(RemoteVerificationFunc(func(ctx context.Context, token []byte) (*RemoteResult, error) {
+		atomic.AddUint32(&calls, 1)
+		revoked, _ := blocklist.Has("token-1")
+		return &RemoteResult{Active: !revoked}, nil
+	}))
+	remote.CacheMaxAge = time.Hour
+
+	negativeCache := NewNegativeCache(time.Minute)
+	negativeCache.Add([]byte("forged"))
+
+	var events []Invalidation
+	blocklist.Invalidations = NewInvalidations(remote, negativeCache)
+	blocklist.Invalidations.OnInvalidate = func(event Invalidation) {
+		events = append(events, event)
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "jti": "token-1"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err = remote.Verify(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadUint32(&calls); got != 1 {
+		t.Fatalf("expected a cached result but got %d calls", got)
+	}
+
+	if err = blocklist.InvalidateToken(token, Claims{ID: "token-1", Expiry: Clock().Add(time.Minute).Unix()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = remote.Verify(ctx, token); !errors.Is(err, ErrTokenInactive) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenInactive, err)
+	}
+
+	if expected := []Invalidation{{Kind: InvalidateRevocation}}; !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events: %#+v but got: %#+v", expected, events)
+	}
+
+	// A revocation can't verify a forged token.
+	if !negativeCache.Rejected([]byte("forged")) {
+		t.Fatalf("expected the forged token to be still cached")
+	}
+}
+
+func TestInvalidationsJWKS(t *testing.T) {
+	initial := testJWKS(t) // rsa, ecdsa and eddsa.
+
+	rotatedKey, err := LoadPublicKeyRSA("./_testfiles/rsapss_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := new(JWKS)
+	for _, jwk := range initial.Keys {
+		if jwk.Kid == "rsa" {
+			if jwk, err = NewJWK("rsa", nil, rotatedKey); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rotated.Keys = append(rotated.Keys, jwk)
+	}
+
+	var current atomic.Value
+	current.Store(initial)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(current.Load())
+	}))
+	defer srv.Close()
+
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	memo := NewMemoVerifier(RS256)
+	negativeCache := NewNegativeCache(time.Minute)
+
+	var events []Invalidation
+	c := NewJWKSClient(srv.URL)
+	c.Invalidations = NewInvalidations(memo)
+	c.Invalidations.Add(negativeCache)
+	c.Invalidations.OnInvalidate = func(event Invalidation) {
+		events = append(events, event)
+	}
+
+	ctx := context.Background()
+	if err = c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Sign(RS256, privateKey, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewVerifier(memo, publicKey).VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+	negativeCache.Add([]byte("forged"))
+
+	current.Store(rotated)
+	if err = c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if memo.entries != nil || negativeCache.Len() != 0 {
+		t.Fatalf("expected the caches to be invalidated on a key rotation")
+	}
+
+	expected := []Invalidation{
+		{Kind: InvalidateKeys, KeyIDs: []string{"ecdsa", "eddsa", "rsa"}},
+		{Kind: InvalidateKeys, KeyIDs: []string{"rsa"}},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events: %#+v but got: %#+v", expected, events)
+	}
+}
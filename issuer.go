@@ -0,0 +1,347 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrUnknownProfile indicates that an `Issuer` has no signing profile for the target audience.
+var ErrUnknownProfile = errors.New("unknown signing profile")
+
+// Issuer holds the configuration to generate tokens for a specific service.
+// It's configured once, on the initialization of the program,
+// so call sites do not have to pass the algorithm, the key and the standard claims
+// on every `Sign` call. An Issuer is safe for concurrent use,
+// its fields should not be modified after the first Token call,
+// use the `SetKey` method to rotate the signing key at runtime.
+//
+// Usage:
+//  issuer := jwt.NewIssuer(jwt.HS256, sharedKey, 15*time.Minute)
+//  issuer.Issuer = "myapp"
+//  issuer.Audience = []string{"admin"}
+//
+//  token, err := issuer.Token("kataras", jwt.Map{"role": "admin"})
+type Issuer struct {
+	// Alg is the signing algorithm, required.
+	Alg AlgSigner
+	// Key is the private key (or the shared secret) of the algorithm, required.
+	Key PrivateKey
+	// Encrypt is an optional function to encrypt the payload, see `SignEncrypted`.
+	Encrypt InjectFunc
+
+	// Issuer is the default "iss" claim, optional.
+	Issuer string
+	// Audience is the default "aud" claim, optional.
+	Audience []string
+	// MaxAge is the lifetime of the generated tokens, it sets the "exp" and "iat" claims.
+	// See `MaxAge` package-level function too.
+	MaxAge time.Duration
+	// KeyID is the "kid" header of the generated tokens, optional.
+	// It's useful when the verifiers select the public key by id, e.g. through a `JWKS`.
+	KeyID string
+	// KeyProvider is an optional source of the signing key and its key id,
+	// when it's set the Key and KeyID fields are ignored, see `StaticKey`.
+	KeyProvider SigningKeyProvider
+	// Attestation is optional signer attestation metadata (e.g. the key origin and the HSM serial number),
+	// set as the "att" protected header of the generated tokens, see `AttestationPolicy`.
+	Attestation *Attestation
+	// Version is the claims schema version, set as the "ver" claim if it's greater than zero.
+	// See the `Migrations` type.
+	Version int
+	// TTL is an optional policy which computes the lifetime of each token
+	// instead of the fixed MaxAge, e.g. admins get 15 minutes and service accounts one hour.
+	// A zero (or negative) result falls back to the MaxAge.
+	TTL TTLPolicy
+	// MaxTTL is the hard cap of the lifetimes computed by the TTL policy,
+	// a longer lifetime is reduced to it. Defaults to the MaxAge (if any).
+	MaxTTL time.Duration
+	// Profiles are optional named signing profiles, selected by the target audience
+	// through the `TokenFor` method, e.g. "browser" sessions and "services" tokens
+	// of different algorithms, keys and lifetimes.
+	Profiles map[string]*SigningProfile
+	// Quota is an optional hook which is consulted before each token is signed,
+	// e.g. a rate limiter of the subject, see `MintRateLimit`.
+	// Its error (e.g. ErrQuotaExceeded) is returned as it's.
+	Quota MintQuota
+	// RefreshMaxAge is the lifetime of the refresh tokens of the `TokenPair`
+	// and `RefreshPair` methods, required by them.
+	RefreshMaxAge time.Duration
+	// RefreshReplays records the ids of the refresh tokens exchanged by `RefreshPair`,
+	// so each refresh token is exchanged once, even by concurrent requests. Required by `RefreshPair`.
+	RefreshReplays *ReplayStore
+	// Lineage stamps a random "jti" claim and the "prt" (parent) claim of the parent token's "jti"
+	// on the tokens derived from another token: the access tokens of `Refresh` (and of a `TokenPair`)
+	// point to their refresh token, the refresh tokens of `RefreshPair` to the previous one
+	// and the outbound tokens of a `TokenExchange` to the inbound one.
+	// So a leaked token can be traced back to its origin session, see `LineageLog`.
+	Lineage bool
+	// GuestProfile is the optional signing profile of the anonymous tokens
+	// of the `GuestToken` method. Defaults to 5 minutes and the "guest" audience.
+	GuestProfile *SigningProfile
+	// ImpersonationPolicy is the policy which authorizes the admin claims
+	// of the `IssueImpersonation` method, e.g. jwt.ClaimContains("roles", "support"), required by it.
+	ImpersonationPolicy Policy
+	// ImpersonationMaxTTL is the maximum lifetime of the impersonation tokens. Defaults to 15 minutes.
+	ImpersonationMaxTTL time.Duration
+	// ImpersonationAudit is an optional hook which is called on every issued impersonation token.
+	ImpersonationAudit func(entry ImpersonationEntry)
+	// DownscopeClaims is the allowlist of the custom claims which the `Downscope` method carries over
+	// to the narrower tokens, e.g. "tenant". The rest of them (e.g. "roles", "groups" and "act") are dropped.
+	DownscopeClaims []string
+	// MaxTokenSize is an optional size budget of the generated tokens in bytes,
+	// e.g. the `CookieSizeBudget`, see the `MaxTokenSize` sign option.
+	MaxTokenSize int
+	// Abbreviations is an optional table of the short names of the custom claims of the generated tokens,
+	// see `ClaimAbbreviations` and the `Verifier.Abbreviations` field.
+	Abbreviations ClaimAbbreviations
+
+	active atomic.Value // *issuerKey, see SetKey.
+}
+
+// TTLPolicy computes the lifetime of a token from its "subject" and custom "claims",
+// see the `Issuer.TTL` field. The custom claims (nil if none) are decoded as a Map,
+// their values should not be modified.
+//
+// Usage:
+//  issuer.TTL = func(subject string, claims jwt.Map) time.Duration {
+//    if claims["role"] == "admin" { return 15 * time.Minute }
+//    return time.Hour
+//  }
+type TTLPolicy func(subject string, claims Map) time.Duration
+
+// SigningProfile is a set of signing settings of an `Issuer` for a target audience,
+// see the `Issuer.Profiles` field. Its zero fields fall back to the Issuer's ones.
+type SigningProfile struct {
+	// Alg is the signing algorithm of the profile.
+	// If nil, the Issuer's algorithm and key are used instead of the Key and KeyID fields.
+	Alg AlgSigner
+	// Key is the private key (or the shared secret) of the Alg.
+	Key PrivateKey
+	// KeyID is the "kid" header of the profile's tokens, optional.
+	KeyID string
+	// MaxAge is the lifetime of the profile's tokens.
+	// Defaults to the Issuer's lifetime (see the `Issuer.TTL` field).
+	MaxAge time.Duration
+	// Audience is the "aud" claim of the profile's tokens.
+	// Defaults to the profile's name.
+	Audience []string
+	// Claims are extra claims of the profile's tokens, e.g. "scope".
+	// The custom claims of a `TokenFor` call take precedence.
+	Claims Map
+}
+
+type issuerKey struct {
+	kid string
+	key PrivateKey
+}
+
+// NewIssuer returns a new token Issuer of "alg" algorithm and "key" private key,
+// which generates tokens with "maxAge" lifetime.
+// The rest of the Issuer fields can be modified before its first use.
+func NewIssuer(alg AlgSigner, key PrivateKey, maxAge time.Duration) *Issuer {
+	return &Issuer{
+		Alg:    alg,
+		Key:    key,
+		MaxAge: maxAge,
+	}
+}
+
+// SetKey atomically replaces the signing key and its "kid" header,
+// the tokens generated after the call are signed with the new key,
+// in-flight Token calls complete with the previous one.
+// It's safe for concurrent use, e.g. from a key rotation controller,
+// and it takes precedence over the Key and KeyID fields (but not over a KeyProvider).
+func (i *Issuer) SetKey(kid string, key PrivateKey) {
+	i.active.Store(&issuerKey{kid: kid, key: key})
+}
+
+// ActiveKey returns the current signing key and its key id.
+func (i *Issuer) ActiveKey() (string, PrivateKey) {
+	if active, ok := i.active.Load().(*issuerKey); ok {
+		return active.kid, active.key
+	}
+
+	return i.KeyID, i.Key
+}
+
+// Token generates a new token for the given "subject" ("sub" claim).
+// The "customClaims" are optional (can be nil), a map or a struct value
+// which is merged with the Issuer's standard claims.
+func (i *Issuer) Token(subject string, customClaims interface{}) ([]byte, error) {
+	return i.TokenContext(context.Background(), subject, customClaims)
+}
+
+// TokenContext same as `Token` but it accepts a standard Go Context
+// which is passed to the Issuer's KeyProvider and Quota.
+func (i *Issuer) TokenContext(ctx context.Context, subject string, customClaims interface{}) ([]byte, error) {
+	return i.token(ctx, "", nil, subject, customClaims)
+}
+
+// TokenFor generates a new token for the given "subject" through the signing profile
+// of the target "audience", see the `Issuer.Profiles` field.
+// Returns ErrUnknownProfile if the Issuer has no profile for the "audience".
+//
+// Usage:
+//  issuer.Profiles = map[string]*jwt.SigningProfile{
+//    "browser":  {MaxAge: 15 * time.Minute},
+//    "services": {Alg: jwt.EdDSA, Key: privateKey, KeyID: "s2s", MaxAge: time.Hour},
+//  }
+//
+//  token, err := issuer.TokenFor("services", "billing-worker", nil)
+func (i *Issuer) TokenFor(audience, subject string, customClaims interface{}) ([]byte, error) {
+	return i.TokenForContext(context.Background(), audience, subject, customClaims)
+}
+
+// TokenForContext same as `TokenFor` but it accepts a standard Go Context
+// which is passed to the Issuer's KeyProvider and Quota.
+func (i *Issuer) TokenForContext(ctx context.Context, audience, subject string, customClaims interface{}) ([]byte, error) {
+	profile, ok := i.Profiles[audience]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, audience)
+	}
+
+	return i.token(ctx, audience, profile, subject, customClaims)
+}
+
+// token generates a new token of the (optional) "profile" of the "audience" name.
+func (i *Issuer) token(ctx context.Context, audience string, profile *SigningProfile, subject string, customClaims interface{}) ([]byte, error) {
+	if i.Quota != nil {
+		if err := i.Quota(ctx, subject); err != nil {
+			return nil, err
+		}
+	}
+
+	return i.sign(ctx, audience, profile, subject, customClaims)
+}
+
+// sign same as token but it does not consult the Quota.
+func (i *Issuer) sign(ctx context.Context, audience string, profile *SigningProfile, subject string, customClaims interface{}) ([]byte, error) {
+	alg := i.Alg
+	kid, key := i.ActiveKey()
+	if profile != nil && profile.Alg != nil {
+		alg, kid, key = profile.Alg, profile.KeyID, profile.Key
+	} else if i.KeyProvider != nil {
+		var err error
+		if kid, key, err = i.KeyProvider.PrivateKey(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	claims := Claims{
+		Issuer:   i.Issuer,
+		Subject:  subject,
+		Audience: i.Audience,
+	}
+
+	var maxAge time.Duration
+	if profile != nil {
+		claims.Audience = profile.Audience
+		if len(claims.Audience) == 0 && audience != "" {
+			claims.Audience = []string{audience}
+		}
+
+		if len(profile.Claims) > 0 {
+			custom, err := claimsMap(customClaims)
+			if err != nil {
+				return nil, err
+			}
+
+			merged := make(Map, len(profile.Claims)+len(custom))
+			for k, v := range profile.Claims {
+				merged[k] = v
+			}
+			for k, v := range custom {
+				merged[k] = v
+			}
+			customClaims = merged
+		}
+
+		maxAge = profile.MaxAge
+	}
+
+	if maxAge <= 0 {
+		var err error
+		if maxAge, err = i.lifetime(subject, customClaims); err != nil {
+			return nil, err
+		}
+	}
+	MaxAge(maxAge).ApplyClaims(&claims)
+
+	if len(i.Abbreviations) > 0 && customClaims != nil {
+		custom, err := claimsMap(customClaims)
+		if err != nil {
+			return nil, err
+		}
+		customClaims = i.Abbreviations.Abbreviate(custom)
+	}
+
+	if i.Version > 0 {
+		version := Map{ClaimVersion: i.Version}
+		if customClaims == nil {
+			customClaims = version
+		} else {
+			customClaims = Merge(customClaims, version)
+		}
+	}
+
+	var opts []SignOption
+	if i.MaxTokenSize > 0 {
+		opts = append(opts, MaxTokenSize(i.MaxTokenSize))
+	}
+
+	if customClaims == nil {
+		return signEncrypted(alg, key, kid, i.Attestation, i.Encrypt, claims, opts...)
+	}
+
+	return signEncrypted(alg, key, kid, i.Attestation, i.Encrypt, customClaims, append(opts, claims)...)
+}
+
+// lifetime returns the lifetime of a token, the MaxAge or the result of the TTL policy (capped to MaxTTL).
+func (i *Issuer) lifetime(subject string, customClaims interface{}) (time.Duration, error) {
+	if i.TTL == nil {
+		return i.MaxAge, nil
+	}
+
+	claims, err := claimsMap(customClaims)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl := i.TTL(subject, claims)
+	if ttl <= 0 {
+		return i.MaxAge, nil
+	}
+
+	maxTTL := i.MaxTTL
+	if maxTTL <= 0 {
+		maxTTL = i.MaxAge
+	}
+
+	if maxTTL > 0 && ttl > maxTTL {
+		return maxTTL, nil
+	}
+
+	return ttl, nil
+}
+
+// claimsMap returns the custom claims (a map or a struct value) as a Map, nil if "customClaims" is nil.
+func claimsMap(customClaims interface{}) (Map, error) {
+	claims, ok := customClaims.(Map)
+	if ok || customClaims == nil {
+		return claims, nil
+	}
+
+	b, err := Marshal(customClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = Unmarshal(b, &claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,276 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIssuer(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Issuer = "myapp"
+	issuer.Audience = []string{"admin"}
+	issuer.KeyID = "key-1"
+
+	var kid string
+	Audit = func(entry AuditEntry) {
+		kid = entry.KeyID
+	}
+	t.Cleanup(func() {
+		Audit = nil
+	})
+
+	token, err := issuer.Token("kataras", Map{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kid != issuer.KeyID {
+		t.Fatalf("expected audit entry with kid: %q but got: %q", issuer.KeyID, kid)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []byte(`{"alg":"HS256","kid":"key-1","typ":"JWT"}`); !bytes.Equal(verifiedToken.Header, expected) {
+		t.Fatalf("expected header: %s but got: %s", expected, verifiedToken.Header)
+	}
+
+	claims := verifiedToken.StandardClaims
+	if claims.Issuer != issuer.Issuer || claims.Subject != "kataras" || !reflect.DeepEqual([]string(claims.Audience), issuer.Audience) {
+		t.Fatalf("unexpected standard claims: %#+v", claims)
+	}
+
+	if expected, got := claims.IssuedAt+60, claims.Expiry; expected != got {
+		t.Fatalf("expected expiration: %d but got: %d", expected, got)
+	}
+
+	var custom struct {
+		Role string `json:"role"`
+	}
+	if err = verifiedToken.Claims(&custom); err != nil {
+		t.Fatal(err)
+	}
+
+	if custom.Role != "admin" {
+		t.Fatalf("expected custom claims to be merged but got: %#+v", custom)
+	}
+
+	// Test standard claims only.
+	token, err = issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompareHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		ok     bool
+	}{
+		{`{"alg":"HS256","typ":"JWT"}`, true},
+		{`{"typ":"JWT","alg":"HS256"}`, true},
+		{`{"alg":"HS256","kid":"key-1","typ":"JWT"}`, true},
+		{`{"alg":"HS256"}`, true},
+		{`{"alg":"HS512","typ":"JWT"}`, false},
+		{`{"alg":"HS256","typ":"JWE"}`, false},
+		{`{"alg":"none","typ":"JWT"}`, false},
+		{`invalid`, false},
+	}
+
+	for i, tt := range tests {
+		if err := compareHeader(HS256.Name(), []byte(tt.header)); (err == nil) != tt.ok {
+			t.Fatalf("[%d] %s: expected ok: %v but got error: %v", i, tt.header, tt.ok, err)
+		}
+	}
+}
+
+func TestIssuerSetKey(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.KeyID = "key-1"
+
+	newSecret := []byte("anothersercrethatmaycontainch@r$")
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				token, err := issuer.Token("kataras", nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				kid, err := tokenKeyID(token)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				key := testSecret
+				if kid == "key-2" {
+					key = newSecret
+				}
+
+				if _, err = Verify(testAlg, key, token); err != nil {
+					t.Errorf("%s: %v", kid, err)
+					return
+				}
+			}
+		}()
+	}
+
+	issuer.SetKey("key-2", newSecret)
+	wg.Wait()
+
+	if kid, key := issuer.ActiveKey(); kid != "key-2" || !bytes.Equal(key.([]byte), newSecret) {
+		t.Fatalf("expected the new active key but got: %s", kid)
+	}
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
+
+func TestIssuerTTL(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, 15*time.Minute)
+	issuer.MaxTTL = 2 * time.Hour
+	issuer.TTL = func(subject string, claims Map) time.Duration {
+		switch claims["role"] {
+		case "admin":
+			return 5 * time.Minute
+		case "service":
+			return time.Hour
+		case "forever":
+			return 24 * time.Hour
+		default:
+			return 0
+		}
+	}
+
+	type serviceClaims struct {
+		Role string `json:"role"`
+	}
+
+	tests := []struct {
+		claims   interface{}
+		expected time.Duration
+	}{
+		{Map{"role": "admin"}, 5 * time.Minute},
+		{serviceClaims{Role: "service"}, time.Hour},
+		{Map{"role": "forever"}, issuer.MaxTTL}, // capped.
+		{Map{"role": "user"}, issuer.MaxAge},
+		{nil, issuer.MaxAge},
+	}
+
+	for _, tt := range tests {
+		token, err := issuer.Token("kataras", tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := verifiedToken.StandardClaims.Age(); got != tt.expected {
+			t.Fatalf("%v: expected lifetime: %s but got: %s", tt.claims, tt.expected, got)
+		}
+	}
+
+	// The hard cap defaults to the MaxAge.
+	issuer.MaxTTL = 0
+	token, err := issuer.Token("kataras", Map{"role": "service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := verifiedToken.StandardClaims.Age(); got != issuer.MaxAge {
+		t.Fatalf("expected lifetime: %s but got: %s", issuer.MaxAge, got)
+	}
+}
+
+func TestIssuerProfiles(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	issuer := NewIssuer(testAlg, testSecret, 15*time.Minute)
+	issuer.Issuer = "myapp"
+	issuer.Profiles = map[string]*SigningProfile{
+		"browser": {MaxAge: 5 * time.Minute},
+		"services": {
+			Alg:      EdDSA,
+			Key:      privateKey,
+			KeyID:    "s2s",
+			MaxAge:   time.Hour,
+			Audience: []string{"billing", "inventory"},
+			Claims:   Map{"scope": "internal", "tier": "gold"},
+		},
+	}
+
+	token, err := issuer.TokenFor("browser", "kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, Expected{Issuer: "myapp", Audience: []string{"browser"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := verifiedToken.StandardClaims.Age(); got != 5*time.Minute {
+		t.Fatalf("expected lifetime: %s but got: %s", 5*time.Minute, got)
+	}
+
+	token, err = issuer.TokenFor("services", "billing-worker", Map{"tier": "silver"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(EdDSA, publicKey, token, Expected{Audience: []string{"billing", "inventory"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kid, _ := tokenKeyID(token); kid != "s2s" {
+		t.Fatalf("expected kid: s2s but got: %s", kid)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims["scope"] != "internal" || claims["tier"] != "silver" {
+		t.Fatalf("expected the profile and custom claims but got: %v", claims)
+	}
+
+	if got := verifiedToken.StandardClaims.Age(); got != time.Hour {
+		t.Fatalf("expected lifetime: %s but got: %s", time.Hour, got)
+	}
+
+	if _, err = issuer.TokenFor("unknown", "kataras", nil); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownProfile, err)
+	}
+}
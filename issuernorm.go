@@ -0,0 +1,85 @@
+package jwt
+
+import "strings"
+
+// NormalizeIssuer is an optional function which returns the canonical form of an issuer identifier.
+// When it's set, the "iss" claim and the configured issuers are compared by their canonical forms,
+// e.g. on the `Expected` validator, the `AllowIssuers` pre-verification check
+// and the routes of a `VerifierRouter`. Defaults to nil, the issuers are compared as they are.
+//
+// Usage:
+//  jwt.NormalizeIssuer = jwt.DefaultIssuerNormalization.Normalize
+var NormalizeIssuer func(issuer string) string
+
+// IssuerNormalization is a configurable canonical form of the URL issuer identifiers,
+// e.g. "HTTPS://IdP.example.com:443/" and "https://idp.example.com" are the same issuer.
+// Issuers which are not URLs (e.g. "myapp") are never modified.
+// See the `NormalizeIssuer` package-level variable.
+type IssuerNormalization struct {
+	// TrimTrailingSlash removes the trailing slashes of the path.
+	TrimTrailingSlash bool
+	// LowerCase lowercases the scheme and the host, which are case-insensitive.
+	// The path is always case-sensitive.
+	LowerCase bool
+	// TrimDefaultPort removes the default port of the scheme, e.g. the ":443" of a "https" issuer.
+	TrimDefaultPort bool
+}
+
+// DefaultIssuerNormalization is an IssuerNormalization of all its rules enabled.
+var DefaultIssuerNormalization = IssuerNormalization{
+	TrimTrailingSlash: true,
+	LowerCase:         true,
+	TrimDefaultPort:   true,
+}
+
+// defaultPorts are the default ports of the issuer URL schemes.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Normalize returns the canonical form of the "issuer", it can be set as the `NormalizeIssuer`.
+func (n IssuerNormalization) Normalize(issuer string) string {
+	i := strings.Index(issuer, "://")
+	if i <= 0 {
+		return issuer
+	}
+
+	scheme, host, path := issuer[:i], issuer[i+3:], ""
+	if j := strings.IndexAny(host, "/?#"); j >= 0 {
+		host, path = host[:j], host[j:]
+	}
+
+	if n.LowerCase {
+		scheme = strings.ToLower(scheme)
+		host = strings.ToLower(host)
+	}
+
+	if n.TrimDefaultPort {
+		if port, ok := defaultPorts[strings.ToLower(scheme)]; ok {
+			host = strings.TrimSuffix(host, ":"+port)
+		}
+	}
+
+	// The issuers have no query or fragment (RFC 8414),
+	// a path of them is kept as it's.
+	if n.TrimTrailingSlash && !strings.ContainsAny(path, "?#") {
+		path = strings.TrimRight(path, "/")
+	}
+
+	return scheme + "://" + host + path
+}
+
+// sameIssuer reports whether the "expected" and the "got" issuers are the same one,
+// see `NormalizeIssuer`.
+func sameIssuer(expected, got string) bool {
+	if expected == got {
+		return true
+	}
+
+	if normalize := NormalizeIssuer; normalize != nil {
+		return normalize(expected) == normalize(got)
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIssuerNormalization(t *testing.T) {
+	tests := []struct {
+		normalization IssuerNormalization
+		issuer        string
+		expected      string
+	}{
+		{DefaultIssuerNormalization, "https://idp.example.com/", "https://idp.example.com"},
+		{DefaultIssuerNormalization, "HTTPS://IdP.Example.com:443/realms/Acme/", "https://idp.example.com/realms/Acme"},
+		{DefaultIssuerNormalization, "http://idp.example.com:80", "http://idp.example.com"},
+		{DefaultIssuerNormalization, "https://idp.example.com:8443/", "https://idp.example.com:8443"},
+		{DefaultIssuerNormalization, "myapp/", "myapp/"},
+		{IssuerNormalization{TrimTrailingSlash: true}, "HTTPS://IdP.example.com:443/", "HTTPS://IdP.example.com:443"},
+		{IssuerNormalization{LowerCase: true}, "HTTPS://IdP.example.com/", "https://idp.example.com/"},
+		{IssuerNormalization{TrimDefaultPort: true}, "HTTPS://idp.example.com:443", "HTTPS://idp.example.com"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.normalization.Normalize(tt.issuer); got != tt.expected {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestNormalizeIssuer(t *testing.T) {
+	claims := Claims{Issuer: "https://idp.example.com/"}
+	expected := Expected{Issuer: "HTTPS://idp.example.com:443"}
+	route := NewVerifierRouter(VerifierRoute{Issuer: expected.Issuer, Verifier: NewVerifier(testAlg, testSecret)})
+
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unverifiedToken, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = expected.ValidateToken(token, claims, nil); !errors.Is(err, ErrIssuerMismatch) {
+		t.Fatalf("expected error: %v but got: %v", ErrIssuerMismatch, err)
+	}
+	if _, err = route.Route(token); !errors.Is(err, ErrNoVerifier) {
+		t.Fatalf("expected error: %v but got: %v", ErrNoVerifier, err)
+	}
+
+	NormalizeIssuer = DefaultIssuerNormalization.Normalize
+	defer func() { NormalizeIssuer = nil }()
+
+	if err = expected.ValidateToken(token, claims, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = route.Route(token); err != nil {
+		t.Fatal(err)
+	}
+	if err = AllowIssuers(expected.Issuer)(context.Background(), unverifiedToken); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = AllowIssuers("https://other.example.com")(context.Background(), unverifiedToken); !errors.Is(err, ErrPreVerify) {
+		t.Fatalf("expected error: %v but got: %v", ErrPreVerify, err)
+	}
+}
@@ -0,0 +1,740 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownKid indicates that the token's "kid" header
+	// does not match any of the known keys.
+	ErrUnknownKid = errors.New("unknown kid")
+	// ErrUnsupportedJWK indicates that a JSON Web Key is
+	// of an unsupported key type (kty) or curve (crv).
+	ErrUnsupportedJWK = errors.New("unsupported json web key")
+	// ErrStaleJWKS is reported to the `JWKSClient.OnError` hook when a refresh failed
+	// and the client serves the last-known-good keys (see `JWKSClient.StaleMaxAge`).
+	ErrStaleJWKS = errors.New("jwks: serving stale keys")
+	// ErrTooManyKeys indicates that a fetched key set contains
+	// more keys than the `JWKSClient.MaxKeys` limit.
+	ErrTooManyKeys = errors.New("jwks: too many keys")
+)
+
+// JWK represents a public JSON Web Key (RFC 7517).
+// Supported key types are: "RSA", "EC" (P-256, P-384, P-521) and "OKP" (Ed25519).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA public key fields.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC and OKP public key fields.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS represents a JSON Web Key Set, the JSON document
+// which identity providers publish their public keys through.
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// publicKeys returns the signature verification keys of the set by their key id.
+// Encryption keys and keys of unsupported types are skipped.
+func (set *JWKS) publicKeys() (map[string]PublicKey, error) {
+	keys := make(map[string]PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue // skip encryption keys.
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			if errors.Is(err, ErrUnsupportedJWK) {
+				continue // skip any unknown key type, e.g. a future one.
+			}
+
+			return nil, fmt.Errorf("jwks: kid: %q: %w", jwk.Kid, err)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// NewJWK returns a JSON Web Key of the given public key.
+// The "alg" is optional and can be nil.
+// The "publicKey" should be a type of *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func NewJWK(kid string, alg Alg, publicKey PublicKey) (*JWK, error) {
+	jwk := &JWK{Kid: kid}
+	if alg != nil {
+		jwk.Alg = alg.Name()
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = string(Base64Encode(key.N.Bytes()))
+		jwk.E = string(Base64Encode(big.NewInt(int64(key.E)).Bytes()))
+	case *ecdsa.PublicKey:
+		params := key.Curve.Params()
+		size := (params.BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = params.Name
+		jwk.X = string(Base64Encode(padBytes(key.X.Bytes(), size)))
+		jwk.Y = string(Base64Encode(padBytes(key.Y.Bytes(), size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = string(Base64Encode(key))
+	default:
+		return nil, ErrUnsupportedJWK
+	}
+
+	return jwk, nil
+}
+
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
+
+// PublicKey parses and returns the Go public key value of this JSON Web Key.
+// The result is a type of *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func (k *JWK) PublicKey() (PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+			return nil, fmt.Errorf("%w: rsa: invalid exponent", ErrUnsupportedJWK)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: ec: curve: %q", ErrUnsupportedJWK, k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("%w: ec: point is not on curve", ErrUnsupportedJWK)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: okp: curve: %q", ErrUnsupportedJWK, k.Crv)
+		}
+
+		x, err := Base64Decode([]byte(k.X))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: okp: bad key length: %d", ErrUnsupportedJWK, len(x))
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: kty: %q", ErrUnsupportedJWK, k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := Base64Decode([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+// maxJWKSResponseSize limits the size of a JWKS document read from a remote server.
+const maxJWKSResponseSize = 1 << 20 // 1MB.
+
+// defaultJWKSMaxKeys is the default maximum number of keys of a `JWKSClient`.
+const defaultJWKSMaxKeys = 100
+
+// defaultJWKSMinMaxAge is the default JWKSClient.MinMaxAge.
+const defaultJWKSMinMaxAge = 30 * time.Second
+
+// maxCacheMaxAge is the maximum caching duration a "Cache-Control: max-age" directive can describe,
+// larger values (e.g. overflowing ones) are capped to it.
+const maxCacheMaxAge = 365 * 24 * time.Hour
+
+// JWKSClient fetches and caches the public keys of a remote JSON Web Key Set.
+//
+// The keys are cached for the duration the server describes through
+// its "Cache-Control: max-age" response header or for the `MaxAge` field when missing.
+// On refresh, the client performs a conditional request through the "If-None-Match"
+// header, so servers can respond with "304 Not Modified" when the key set did not change.
+//
+// When a refresh fails (e.g. identity provider outage) the client can keep serving
+// the last-known-good keys for a grace period, see the `StaleMaxAge` field.
+//
+// A JWKSClient is safe for concurrent use.
+type JWKSClient struct {
+	// URL is the remote JWKS location, e.g. https://idp.example.com/.well-known/jwks.json.
+	URL string
+	// Client is the HTTP Client which is used to fetch the key set.
+	// Defaults to the package-level HTTPClient.
+	Client *http.Client
+	// Clock is used to calculate the cache expiration.
+	Clock func() time.Time
+	// MaxAge is the default duration the fetched keys are cached for
+	// when the server's response does not contain any caching directives.
+	MaxAge time.Duration
+	// MinMaxAge is the minimum duration the fetched keys are cached for,
+	// even when the server's response disables caching (e.g. "no-cache" or "max-age=0"),
+	// so a misconfigured identity provider can't make every verification refetch the key set.
+	// Defaults to 30 seconds by `NewJWKSClient`.
+	MinMaxAge time.Duration
+	// StaleMaxAge is the grace period, after the cache expiration,
+	// which the last-known-good keys are still served for when refreshing fails.
+	// Defaults to zero, all verifications fail as soon as a refresh fails.
+	StaleMaxAge time.Duration
+	// FailurePolicy is the behavior when the keys can't be refreshed after the StaleMaxAge.
+	// The default FailClosed fails the verifications with a *DependencyError,
+	// FailOpen keeps serving the last-known-good keys until a refresh succeeds
+	// (the keys removed by the identity provider meanwhile are still accepted).
+	FailurePolicy FailurePolicy
+	// Breaker is an optional circuit breaker which protects the verification path
+	// from a failing or slow identity provider. While it's open, refreshes fail fast
+	// with ErrCircuitOpen (and stale keys are served, if allowed by the `StaleMaxAge`).
+	Breaker *CircuitBreaker
+	// Retries is the maximum number of times a failed refresh is retried.
+	// Only transport failures and 5xx/429 responses are retried.
+	// Defaults to zero.
+	Retries int
+	// RetryBackoff is the wait duration before the first retry,
+	// it's doubled on each next retry.
+	RetryBackoff time.Duration
+	// OnError is an optional hook which is called on refresh failures.
+	// When the client serves stale keys the error is wrapped with the ErrStaleJWKS,
+	// so it can be logged or counted as a degradation instead of an outage.
+	OnError func(err error)
+	// UnknownKidRefreshInterval, if greater than zero, makes a token of an unknown "kid"
+	// trigger a refresh before its cache expiration, e.g. right after the identity provider
+	// rotated its signing key. It's the minimum duration since the last refresh (of any reason),
+	// so tokens of random key ids can't flood the identity provider.
+	// Defaults to 5 minutes by `NewJWKSClient`.
+	UnknownKidRefreshInterval time.Duration
+	// OnChange is an optional hook which is called when a refresh adds, removes
+	// or rotates keys. See the `Subscribe` method too.
+	OnChange func(change JWKSChange)
+	// MaxKeys is the maximum number of keys a fetched key set may contain,
+	// a larger set fails the refresh with ErrTooManyKeys and the current keys are kept.
+	// Defaults to 100 by `NewJWKSClient`, zero means no limit.
+	MaxKeys int
+	// Invalidations is an optional publisher of the key changes (see `OnChange`)
+	// to the caches of verification decisions, see `Invalidations`.
+	Invalidations *Invalidations
+
+	mu         sync.RWMutex
+	keys       map[string]PublicKey // key = kid.
+	set        *JWKS
+	etag       string
+	expiresAt  time.Time
+	staleUntil time.Time
+	stale      bool
+
+	lastRefresh time.Time // last successful refresh.
+	lastErr     error     // last refresh error, if any.
+
+	unknownKidRefresh time.Time // last refresh because of an unknown kid.
+
+	refreshMu  sync.Mutex   // allows a single refresh at a time.
+	refreshing *refreshCall // the in-flight refresh of an expired cache, if any.
+
+	subscribers []chan JWKSChange
+
+	runner runner
+}
+
+// NewJWKSClient returns a new JWKS client for the given "url".
+// The keys are lazily fetched on the first `PublicKey` call
+// or when the `Refresh` method is called manually.
+func NewJWKSClient(url string) *JWKSClient {
+	return &JWKSClient{
+		URL:                       url,
+		Clock:                     Clock,
+		MaxAge:                    time.Hour,
+		MinMaxAge:                 defaultJWKSMinMaxAge,
+		UnknownKidRefreshInterval: 5 * time.Minute,
+		MaxKeys:                   defaultJWKSMaxKeys,
+	}
+}
+
+// PublicKey returns the public key of the given key id.
+// It refreshes the key set when the cache is expired.
+// Returns ErrUnknownKid when the key set does not contain the "kid".
+func (c *JWKSClient) PublicKey(ctx context.Context, kid string) (PublicKey, error) {
+	hit := !c.expired()
+	if !hit {
+		if err := c.refreshIfExpired(ctx); err != nil {
+			recordKeyCache(false)
+			return nil, err
+		}
+	}
+
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	degraded := c.stale && !c.Clock().Before(c.staleUntil) // served by the FailOpen policy.
+	c.mu.RUnlock()
+
+	if ok && degraded {
+		recordDegraded(DependencyJWKS)
+	}
+
+	if !ok && hit && c.refreshUnknownKid(ctx) {
+		hit = false
+		c.mu.RLock()
+		key, ok = c.keys[kid]
+		c.mu.RUnlock()
+	}
+
+	recordKeyCache(hit && ok)
+	if !ok {
+		return nil, ErrUnknownKid
+	}
+
+	return key, nil
+}
+
+// refreshUnknownKid refreshes the key set because of an unknown kid,
+// at most once per UnknownKidRefreshInterval. It reports whether it refreshed the keys.
+// Failed attempts count too, so an outage is not retried on every token.
+func (c *JWKSClient) refreshUnknownKid(ctx context.Context) bool {
+	if c.UnknownKidRefreshInterval <= 0 {
+		return false
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	now := c.Clock()
+	c.mu.Lock()
+	last := c.unknownKidRefresh
+	if c.lastRefresh.After(last) {
+		last = c.lastRefresh
+	}
+	allowed := now.Sub(last) >= c.UnknownKidRefreshInterval
+	if allowed {
+		c.unknownKidRefresh = now
+	}
+	c.mu.Unlock()
+
+	if !allowed {
+		return false
+	}
+
+	if err := c.fetchRetry(ctx); err != nil {
+		if c.OnError != nil {
+			c.OnError(err)
+		}
+		return false
+	}
+
+	return true
+}
+
+// Set returns the last fetched key set (may be nil).
+func (c *JWKSClient) Set() *JWKS {
+	c.mu.RLock()
+	set := c.set
+	c.mu.RUnlock()
+
+	return set
+}
+
+func (c *JWKSClient) expired() bool {
+	c.mu.RLock()
+	expiresAt := c.expiresAt
+	c.mu.RUnlock()
+
+	return !c.Clock().Before(expiresAt)
+}
+
+// Stale reports whether the client currently serves stale keys
+// because the last refresh failed.
+func (c *JWKSClient) Stale() bool {
+	c.mu.RLock()
+	stale := c.stale
+	c.mu.RUnlock()
+
+	return stale
+}
+
+// staleRetryInterval is the minimum duration between
+// two refresh attempts while the client serves stale keys.
+const staleRetryInterval = 10 * time.Second
+
+// refreshCall is an in-flight refresh of an expired cache,
+// shared by all the callers which need it.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// refreshIfExpired refreshes the key set if the cache is expired.
+// While the cached keys can still be served (see `StaleMaxAge` and `FailurePolicy`)
+// the refresh runs in the background and the call returns immediately,
+// otherwise it waits for the refresh. A single refresh runs at a time,
+// it's not cancelled by the "ctx" of the caller which started it.
+func (c *JWKSClient) refreshIfExpired(ctx context.Context) error {
+	if !c.expired() {
+		return nil
+	}
+
+	c.mu.Lock()
+	call := c.refreshing
+	if call == nil {
+		call = &refreshCall{done: make(chan struct{})}
+		c.refreshing = call
+		go c.refreshExpired(withoutCancel{ctx}, call)
+	}
+	servable := c.keys != nil && (c.FailurePolicy == FailOpen || c.Clock().Before(c.staleUntil))
+	c.mu.Unlock()
+
+	if servable {
+		return nil
+	}
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return dependencyError(DependencyJWKS, ctx.Err())
+	}
+}
+
+// refreshExpired runs the "call" refresh and reports its failure to the OnError hook.
+func (c *JWKSClient) refreshExpired(ctx context.Context, call *refreshCall) {
+	defer func() {
+		c.mu.Lock()
+		c.refreshing = nil
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if !c.expired() { // refreshed while waiting.
+		return
+	}
+
+	err := c.fetchRetry(ctx)
+	if err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	stale := c.stale
+	if stale {
+		// Do not hit the server on every call, retry later.
+		c.expiresAt = c.Clock().Add(staleRetryInterval)
+		if c.FailurePolicy != FailOpen && c.expiresAt.After(c.staleUntil) {
+			c.expiresAt = c.staleUntil
+		}
+	}
+	c.mu.Unlock()
+
+	if stale {
+		err = fmt.Errorf("%w: %v", ErrStaleJWKS, err)
+	}
+
+	if c.OnError != nil {
+		c.OnError(err)
+	}
+
+	if !stale {
+		call.err = dependencyError(DependencyJWKS, err)
+	}
+}
+
+// withoutCancel is a context which keeps the values of its parent
+// but it's never cancelled, like the context.WithoutCancel of Go 1.21.
+// The requests through it are still bounded by the timeout of the HTTP Client.
+type withoutCancel struct {
+	context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (withoutCancel) Done() <-chan struct{}       { return nil }
+func (withoutCancel) Err() error                  { return nil }
+
+// Refresh fetches the remote key set.
+// If a previous response contained an ETag then the request is conditional
+// and a "304 Not Modified" response keeps the current keys,
+// only the cache expiration is updated.
+//
+// On failure the current keys are kept (see `Stale`) and the error is returned as it's.
+func (c *JWKSClient) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	err := c.fetchRetry(ctx)
+	c.refreshMu.Unlock()
+
+	return err
+}
+
+// Run refreshes the key set in the background before its cache expiration,
+// so the verifications never wait for a refresh, until the "ctx" is cancelled
+// or `Close` is called. It blocks and returns the "ctx" error or nil on `Close`.
+// Refresh failures are reported to the OnError hook and retried, the keys
+// are still lazily refreshed by the `PublicKey` method as well.
+// Returns ErrRunning if it's already running.
+//
+// Usage:
+//  jwks := jwt.NewJWKSClient("https://idp.example.com/.well-known/jwks.json")
+//  go jwks.Run(ctx)
+//  defer jwks.Close()
+func (c *JWKSClient) Run(ctx context.Context) error {
+	return c.runner.run(ctx, func(ctx context.Context) {
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			err := c.Refresh(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				if c.Stale() {
+					err = fmt.Errorf("%w: %v", ErrStaleJWKS, err)
+				}
+
+				if c.OnError != nil {
+					c.OnError(err)
+				}
+			}
+
+			timer.Reset(c.nextRefresh(err))
+		}
+	})
+}
+
+// Close stops the background refresher of `Run`, if running, and waits for it to return.
+func (c *JWKSClient) Close() error {
+	c.runner.stop()
+	return nil
+}
+
+// nextRefresh returns the time to wait before the next background refresh,
+// the 9/10 of the cache lifetime left, at least the staleRetryInterval.
+func (c *JWKSClient) nextRefresh(err error) time.Duration {
+	if err != nil {
+		return staleRetryInterval
+	}
+
+	c.mu.RLock()
+	remaining := c.expiresAt.Sub(c.Clock())
+	c.mu.RUnlock()
+
+	if wait := remaining - remaining/10; wait > staleRetryInterval {
+		return wait
+	}
+
+	return staleRetryInterval
+}
+
+func (c *JWKSClient) fetchRetry(ctx context.Context) error {
+	err := retry(ctx, c.Breaker, c.Retries, c.RetryBackoff, func() error {
+		return c.fetch(ctx)
+	})
+
+	c.mu.Lock()
+	if err == nil {
+		c.lastRefresh = c.Clock()
+	} else {
+		// The last-known-good keys can still be served.
+		c.stale = c.keys != nil && (c.FailurePolicy == FailOpen || c.Clock().Before(c.staleUntil))
+	}
+	c.lastErr = err
+	c.mu.Unlock()
+
+	return err
+}
+
+var _ Healther = (*JWKSClient)(nil)
+
+// Health completes the `Healther` interface.
+// It reports the last successful refresh time, the number of cached keys
+// and the last refresh error.
+func (c *JWKSClient) Health() Health {
+	c.mu.RLock()
+	h := Health{
+		LastRefresh: c.lastRefresh,
+		Keys:        len(c.keys),
+		LastError:   c.lastErr,
+		Stale:       c.stale,
+	}
+	c.mu.RUnlock()
+
+	return h
+}
+
+func (c *JWKSClient) fetch(ctx context.Context) error {
+	c.mu.RLock()
+	etag := c.etag
+	c.mu.RUnlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := httpClient(c.Client).Do(req)
+	if err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	maxAge := cacheMaxAge(resp.Header, c.MaxAge)
+	if maxAge < c.MinMaxAge {
+		maxAge = c.MinMaxAge
+	}
+	expiresAt := c.Clock().Add(maxAge)
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		c.mu.Lock()
+		c.expiresAt = expiresAt
+		c.staleUntil = expiresAt.Add(c.StaleMaxAge)
+		c.stale = false
+		c.mu.Unlock()
+		return nil
+	case http.StatusOK:
+	default:
+		return &remoteStatusError{name: "jwks", statusCode: resp.StatusCode}
+	}
+
+	var set JWKS
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxJWKSResponseSize)).Decode(&set); err != nil {
+		return fmt.Errorf("jwks: decode: %w", err)
+	}
+
+	if c.MaxKeys > 0 && len(set.Keys) > c.MaxKeys {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyKeys, len(set.Keys), c.MaxKeys)
+	}
+
+	keys, err := set.publicKeys()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	change := diffKeys(c.keys, keys)
+	c.keys = keys
+	c.set = &set
+	c.etag = resp.Header.Get("ETag")
+	c.expiresAt = expiresAt
+	c.staleUntil = expiresAt.Add(c.StaleMaxAge)
+	c.stale = false
+	c.mu.Unlock()
+
+	c.notifyChange(change)
+	return nil
+}
+
+// cacheMaxAge returns the caching duration described by the response headers.
+// It respects the "no-cache", "no-store" and "max-age" directives of "Cache-Control"
+// and subtracts the "Age" header (when a shared cache served the response).
+// The "max-age" is capped to one year.
+// Returns the "defaultMaxAge" when no directive exists.
+func cacheMaxAge(header http.Header, defaultMaxAge time.Duration) time.Duration {
+	cacheControl := header.Get("Cache-Control")
+	if cacheControl == "" {
+		return defaultMaxAge
+	}
+
+	maxAge := defaultMaxAge
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache", directive == "no-store":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseInt(strings.Trim(directive[len("max-age="):], `"`), 10, 64)
+			if err != nil || seconds < 0 {
+				continue
+			}
+
+			maxAge = maxCacheMaxAge
+			if seconds < int64(maxCacheMaxAge/time.Second) {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+
+			if age, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && age > 0 {
+				if age < int64(maxAge/time.Second) {
+					maxAge -= time.Duration(age) * time.Second
+				} else {
+					maxAge = 0
+				}
+			}
+		}
+	}
+
+	return maxAge
+}
@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// StaticJWKS is a `KeyProvider` of a fixed JSON Web Key Set, e.g. one which is embedded
+// at compile time (go:embed), for air-gapped environments and command line tools
+// which must not fetch keys at runtime. Its keys are selected by the token's "kid" header.
+// A StaticJWKS is safe for concurrent use.
+type StaticJWKS struct {
+	set  *JWKS
+	keys map[string]PublicKey // key = kid.
+}
+
+var _ KeyProvider = (*StaticJWKS)(nil)
+
+// ParseJWKS decodes the JSON Web Key Set document "data" to a `StaticJWKS`.
+// Encryption keys and keys of unsupported types are skipped.
+//
+// Usage:
+//  //go:embed jwks.json
+//  var jwksJSON []byte
+//
+//  keys, err := jwt.ParseJWKS(jwksJSON)
+//  verifier := jwt.NewVerifier(jwt.RS256, nil)
+//  verifier.KeyProvider = keys
+func ParseJWKS(data []byte) (*StaticJWKS, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwks: decode: %w", err)
+	}
+
+	keys, err := set.publicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticJWKS{set: &set, keys: keys}, nil
+}
+
+// NewStaticVerifier returns a new token Verifier of the "alg" algorithm
+// and the keys of the embedded (or read from a file) JSON Web Key Set "jwks", see `ParseJWKS`.
+//
+// Usage:
+//  //go:embed jwks.json
+//  var jwksJSON []byte
+//
+//  verifier, err := jwt.NewStaticVerifier(jwt.RS256, jwksJSON, jwt.Expected{Issuer: "myapp"})
+func NewStaticVerifier(alg AlgVerifier, jwks []byte, validators ...TokenValidator) (*Verifier, error) {
+	keys, err := ParseJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := NewVerifier(alg, nil, validators...)
+	verifier.KeyProvider = keys
+	return verifier, nil
+}
+
+// PublicKey returns the public key of the given key id
+// or ErrUnknownKid when the key set does not contain it.
+func (s *StaticJWKS) PublicKey(ctx context.Context, kid string) (PublicKey, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKid
+	}
+
+	return key, nil
+}
+
+// Set returns the decoded key set.
+func (s *StaticJWKS) Set() *JWKS {
+	return s.set
+}
@@ -0,0 +1,24 @@
+//go:build go1.16
+// +build go1.16
+
+package jwt
+
+import "io/fs"
+
+// LoadJWKS reads the "name" JSON Web Key Set file of the "fsys" file system,
+// e.g. an embed.FS, and decodes it to a `StaticJWKS`, see `ParseJWKS`.
+// Requires Go 1.16+.
+//
+// Usage:
+//  //go:embed keys
+//  var keysFS embed.FS
+//
+//  keys, err := jwt.LoadJWKS(keysFS, "keys/jwks.json")
+func LoadJWKS(fsys fs.FS, name string) (*StaticJWKS, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJWKS(data)
+}
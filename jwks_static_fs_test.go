@@ -0,0 +1,30 @@
+//go:build go1.16
+// +build go1.16
+
+package jwt
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestLoadJWKS(t *testing.T) {
+	// An embed.FS in applications, go:embed requires the go1.16 (or later) directive of the go.mod file.
+	fsys := os.DirFS("./_testfiles")
+
+	keys, err := LoadJWKS(fsys, "jwks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kid := range []string{"rsa-1", "ed-1"} {
+		if _, err = keys.PublicKey(context.Background(), kid); err != nil {
+			t.Fatalf("%s: %v", kid, err)
+		}
+	}
+
+	if _, err = LoadJWKS(fsys, "missing.json"); err == nil {
+		t.Fatalf("expected an error on missing file")
+	}
+}
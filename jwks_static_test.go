@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestStaticVerifier(t *testing.T) {
+	jwks, err := ioutil.ReadFile("./_testfiles/jwks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ParseJWKS(jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 2, len(keys.Set().Keys); got != expected {
+		t.Fatalf("expected %d keys but got: %d", expected, got)
+	}
+
+	privateKey, _ := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+	issuer := NewIssuer(EdDSA, privateKey, time.Minute)
+	issuer.KeyID = "ed-1"
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewStaticVerifier(EdDSA, jwks, Expected{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	issuer.KeyID = "ed-2"
+	token, err = issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != ErrUnknownKid {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if _, err = ParseJWKS([]byte(`{"keys":`)); err == nil {
+		t.Fatalf("expected a decode error")
+	}
+}
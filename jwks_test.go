@@ -0,0 +1,440 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testJWKS(t *testing.T) *JWKS {
+	t.Helper()
+
+	rsaPublicKey, err := LoadPublicKeyRSA("./_testfiles/rsa_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecdsaPublicKey, err := LoadPublicKeyECDSA("./_testfiles/ecdsa_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edPublicKey, err := LoadPublicKeyEdDSA("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := new(JWKS)
+	for kid, key := range map[string]PublicKey{"rsa": rsaPublicKey, "ecdsa": ecdsaPublicKey, "eddsa": edPublicKey} {
+		jwk, err := NewJWK(kid, nil, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := jwk.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, key) {
+			t.Fatalf("[%s] expected parsed key to match the original one", kid)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func TestJWKSClientCacheControl(t *testing.T) {
+	set := testJWKS(t)
+	var requests, notModified uint32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&requests, 1)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddUint32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	c := NewJWKSClient(srv.URL)
+	c.Clock = func() time.Time { return now }
+
+	key, err := c.PublicKey(context.Background(), "rsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := key.(*rsa.PublicKey); !ok {
+		t.Fatalf("expected an RSA public key but got: %T", key)
+	}
+
+	// Served by cache.
+	key, err = c.PublicKey(context.Background(), "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := key.(*ecdsa.PublicKey); !ok {
+		t.Fatalf("expected an ECDSA public key but got: %T", key)
+	}
+
+	if _, err = c.PublicKey(context.Background(), "unknown"); err != ErrUnknownKid {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 1 {
+		t.Fatalf("expected a single request but got: %d", got)
+	}
+
+	// Expire the cache, a conditional request should be made.
+	now = now.Add(61 * time.Second)
+	if _, err = c.PublicKey(context.Background(), "eddsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 2 {
+		t.Fatalf("expected two requests but got: %d", got)
+	}
+
+	if got := atomic.LoadUint32(&notModified); got != 1 {
+		t.Fatalf("expected a not modified response but got: %d", got)
+	}
+
+	if got := len(c.Set().Keys); got != len(set.Keys) {
+		t.Fatalf("expected %d keys but got: %d", len(set.Keys), got)
+	}
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	tests := []struct {
+		cacheControl string
+		age          string
+		expected     time.Duration
+	}{
+		{"", "", time.Hour},
+		{"max-age=120", "", 2 * time.Minute},
+		{"public, max-age=120", "20", 100 * time.Second},
+		{"max-age=10", "20", 0},
+		{"no-cache", "", 0},
+		{"no-store, max-age=120", "", 0},
+		{"max-age=invalid", "", time.Hour},
+		{"max-age=0", "", 0},
+		{"max-age=99999999999999999", "", 365 * 24 * time.Hour},
+		{"max-age=120", "99999999999999999", 0},
+	}
+
+	for i, tt := range tests {
+		header := make(http.Header)
+		header.Set("Cache-Control", tt.cacheControl)
+		header.Set("Age", tt.age)
+
+		if got := cacheMaxAge(header, time.Hour); got != tt.expected {
+			t.Fatalf("[%d] expected max age: %s but got: %s", i, tt.expected, got)
+		}
+	}
+}
+
+func TestJWKSClientMinMaxAge(t *testing.T) {
+	set := testJWKS(t)
+	var requests uint32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&requests, 1)
+		w.Header().Set("Cache-Control", "no-cache")
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	c := NewJWKSClient(srv.URL)
+	c.Clock = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 1 {
+		t.Fatalf("expected a single request but got: %d", got)
+	}
+
+	now = now.Add(defaultJWKSMinMaxAge + time.Second)
+	if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 2 {
+		t.Fatalf("expected two requests but got: %d", got)
+	}
+}
+
+// waitJWKSRefresh waits for the background refresh of an expired cache, if any.
+func waitJWKSRefresh(c *JWKSClient) {
+	c.mu.RLock()
+	call := c.refreshing
+	c.mu.RUnlock()
+
+	if call != nil {
+		<-call.done
+	}
+}
+
+func TestJWKSClientStale(t *testing.T) {
+	set := testJWKS(t)
+	var down uint32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadUint32(&down) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "max-age=60")
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	var reported []error
+	c := NewJWKSClient(srv.URL)
+	c.Clock = func() time.Time { return now }
+	c.StaleMaxAge = 10 * time.Minute
+	c.OnError = func(err error) {
+		reported = append(reported, err)
+	}
+
+	if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.StoreUint32(&down, 1)
+	now = now.Add(2 * time.Minute)
+
+	if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatalf("expected stale key to be served but got: %v", err)
+	}
+
+	waitJWKSRefresh(c)
+	if !c.Stale() {
+		t.Fatalf("expected client to report stale keys")
+	}
+
+	if len(reported) != 1 || !errors.Is(reported[0], ErrStaleJWKS) {
+		t.Fatalf("expected a single ErrStaleJWKS to be reported but got: %v", reported)
+	}
+
+	// Test explicit refresh returns the error but keeps the keys.
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+
+	// Test the grace period is over.
+	now = now.Add(10 * time.Minute)
+	if _, err := c.PublicKey(context.Background(), "rsa"); err == nil || errors.Is(err, ErrStaleJWKS) {
+		t.Fatalf("expected refresh error after the grace period but got: %v", err)
+	}
+
+	// Test recovery.
+	atomic.StoreUint32(&down, 0)
+	if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitJWKSRefresh(c)
+	if c.Stale() {
+		t.Fatalf("expected client to recover from stale state")
+	}
+}
+
+func TestJWKSClientBackgroundRefresh(t *testing.T) {
+	set := testJWKS(t)
+	var requests uint32
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&requests, 1) > 1 {
+			<-release // slow identity provider.
+		}
+
+		w.Header().Set("Cache-Control", "max-age=60")
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	now := time.Now()
+	c := NewJWKSClient(srv.URL)
+	c.Clock = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	c.StaleMaxAge = 10 * time.Minute
+
+	if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+
+	// The cached keys are served while a single refresh runs in the background,
+	// even if the context of the call which started it is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 10; i++ {
+		if _, err := c.PublicKey(ctx, "rsa"); err != nil {
+			t.Fatalf("[%d] expected the cached key but got: %v", i, err)
+		}
+	}
+	cancel()
+
+	close(release)
+	waitJWKSRefresh(c)
+
+	if got := atomic.LoadUint32(&requests); got != 2 {
+		t.Fatalf("expected two requests but got: %d", got)
+	}
+
+	if h := c.Health(); h.LastError != nil || h.Stale {
+		t.Fatalf("expected a successful background refresh but got: %#+v", h)
+	}
+
+	// Without cached keys the callers wait for the refresh.
+	c = NewJWKSClient(srv.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadUint32(&requests); got != 3 {
+		t.Fatalf("expected a single request for the waiting callers but got: %d", got-2)
+	}
+}
+
+func TestJWKSClientUnknownKidRefresh(t *testing.T) {
+	set := testJWKS(t)
+	rotated := &JWKS{Keys: append([]*JWK{}, set.Keys...)}
+	edPublicKey, err := LoadPublicKeyEdDSA("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk, err := NewJWK("eddsa-2", nil, edPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated.Keys = append(rotated.Keys, jwk)
+
+	var (
+		requests uint32
+		current  atomic.Value
+	)
+	current.Store(set)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&requests, 1)
+		json.NewEncoder(w).Encode(current.Load())
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	c := NewJWKSClient(srv.URL)
+	c.Clock = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err = c.PublicKey(ctx, "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	current.Store(rotated)
+
+	// Too soon after the last refresh.
+	now = now.Add(time.Minute)
+	if _, err = c.PublicKey(ctx, "eddsa-2"); err != ErrUnknownKid {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 1 {
+		t.Fatalf("expected a single request but got: %d", got)
+	}
+
+	now = now.Add(c.UnknownKidRefreshInterval)
+	if _, err = c.PublicKey(ctx, "eddsa-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 2 {
+		t.Fatalf("expected two requests but got: %d", got)
+	}
+
+	// Random key ids do not hit the server.
+	for i := 0; i < 3; i++ {
+		if _, err = c.PublicKey(ctx, "random"); err != ErrUnknownKid {
+			t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+		}
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 2 {
+		t.Fatalf("expected two requests but got: %d", got)
+	}
+
+	// Disabled.
+	c.UnknownKidRefreshInterval = 0
+	now = now.Add(10 * time.Minute)
+	if _, err = c.PublicKey(ctx, "random"); err != ErrUnknownKid {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 2 {
+		t.Fatalf("expected two requests but got: %d", got)
+	}
+}
+
+func TestJWKSClientMaxKeys(t *testing.T) {
+	set := testJWKS(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	c := NewJWKSClient(srv.URL)
+	c.MaxKeys = len(set.Keys)
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	c.MaxKeys = len(set.Keys) - 1
+	if err := c.Refresh(context.Background()); !errors.Is(err, ErrTooManyKeys) {
+		t.Fatalf("expected error: %v but got: %v", ErrTooManyKeys, err)
+	}
+
+	// The current keys are kept.
+	if _, err := c.PublicKey(context.Background(), "rsa"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto"
+	"reflect"
+	"sort"
+)
+
+// JWKSChange describes the key ids which a `JWKSClient` refresh changed.
+// See the `JWKSClient.OnChange` field and the `JWKSClient.Subscribe` method.
+type JWKSChange struct {
+	// Added are the key ids of the new keys.
+	Added []string
+	// Removed are the key ids of the keys which are not part of the key set anymore.
+	Removed []string
+	// Rotated are the key ids which exist before and after the refresh
+	// but their key material changed.
+	Rotated []string
+}
+
+// Empty reports whether the change contains no key ids.
+func (ch JWKSChange) Empty() bool {
+	return len(ch.Added) == 0 && len(ch.Removed) == 0 && len(ch.Rotated) == 0
+}
+
+// merge returns the union of the "ch" and "other" changes.
+func (ch JWKSChange) merge(other JWKSChange) JWKSChange {
+	return JWKSChange{
+		Added:   mergeKeyIDs(ch.Added, other.Added),
+		Removed: mergeKeyIDs(ch.Removed, other.Removed),
+		Rotated: mergeKeyIDs(ch.Rotated, other.Rotated),
+	}
+}
+
+func mergeKeyIDs(a, b []string) []string {
+	for _, kid := range b {
+		if !containsString(a, kid) {
+			a = append(a, kid)
+		}
+	}
+
+	sort.Strings(a)
+	return a
+}
+
+// diffKeys returns the change from the "prev" keys to the "next" ones.
+func diffKeys(prev, next map[string]PublicKey) JWKSChange {
+	var ch JWKSChange
+	for kid, key := range next {
+		prevKey, ok := prev[kid]
+		switch {
+		case !ok:
+			ch.Added = append(ch.Added, kid)
+		case !equalPublicKeys(prevKey, key):
+			ch.Rotated = append(ch.Rotated, kid)
+		}
+	}
+
+	for kid := range prev {
+		if _, ok := next[kid]; !ok {
+			ch.Removed = append(ch.Removed, kid)
+		}
+	}
+
+	sort.Strings(ch.Added)
+	sort.Strings(ch.Removed)
+	sort.Strings(ch.Rotated)
+	return ch
+}
+
+func equalPublicKeys(a, b PublicKey) bool {
+	if k, ok := a.(interface{ Equal(crypto.PublicKey) bool }); ok {
+		return k.Equal(b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// Subscribe returns a channel which receives the changes of the key set,
+// e.g. to invalidate caches of verified tokens or pinned thumbprints promptly.
+// A slow subscriber never blocks the client: the changes it did not receive yet
+// are merged into a single pending one.
+func (c *JWKSClient) Subscribe() <-chan JWKSChange {
+	ch := make(chan JWKSChange, 1)
+	c.mu.Lock()
+	c.subscribers = append(c.subscribers, ch)
+	c.mu.Unlock()
+	return ch
+}
+
+// notifyChange sends the "change" to the subscribers, the Invalidations and the OnChange hook.
+// It should be called without holding the lock.
+func (c *JWKSClient) notifyChange(change JWKSChange) {
+	if change.Empty() {
+		return
+	}
+
+	c.mu.Lock()
+	for _, ch := range c.subscribers {
+		pending := change
+		select { // merge the previous (not received) change, if any.
+		case prev := <-ch:
+			pending = prev.merge(change)
+		default:
+		}
+		ch <- pending
+	}
+	c.mu.Unlock()
+
+	if c.Invalidations != nil {
+		kids := mergeKeyIDs(mergeKeyIDs(append([]string(nil), change.Added...), change.Removed), change.Rotated)
+		c.Invalidations.Publish(Invalidation{Kind: InvalidateKeys, KeyIDs: kids})
+	}
+
+	if c.OnChange != nil {
+		c.OnChange(change)
+	}
+}
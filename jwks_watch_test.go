@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestJWKSClientSubscribe(t *testing.T) {
+	initial := testJWKS(t) // rsa, ecdsa and eddsa.
+
+	rotatedKey, err := LoadPublicKeyRSA("./_testfiles/rsapss_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := new(JWKS)
+	for _, jwk := range initial.Keys {
+		switch jwk.Kid {
+		case "rsa":
+			jwk, err = NewJWK("rsa", nil, rotatedKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+		case "eddsa":
+			continue // removed.
+		}
+
+		rotated.Keys = append(rotated.Keys, jwk)
+	}
+
+	var current atomic.Value
+	current.Store(initial)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(current.Load())
+	}))
+	defer srv.Close()
+
+	var changes []JWKSChange
+	c := NewJWKSClient(srv.URL)
+	c.OnChange = func(change JWKSChange) {
+		changes = append(changes, change)
+	}
+	sub := c.Subscribe()
+
+	ctx := context.Background()
+	if err = c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	current.Store(rotated)
+	if err = c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same keys, no change.
+	if err = c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []JWKSChange{
+		{Added: []string{"ecdsa", "eddsa", "rsa"}},
+		{Removed: []string{"eddsa"}, Rotated: []string{"rsa"}},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected changes: %#+v but got: %#+v", expected, changes)
+	}
+
+	// The subscriber did not receive the first change, they are merged.
+	select {
+	case got := <-sub:
+		merged := JWKSChange{Added: []string{"ecdsa", "eddsa", "rsa"}, Removed: []string{"eddsa"}, Rotated: []string{"rsa"}}
+		if !reflect.DeepEqual(got, merged) {
+			t.Fatalf("expected change: %#+v but got: %#+v", merged, got)
+		}
+	default:
+		t.Fatalf("expected a pending change")
+	}
+
+	select {
+	case got := <-sub:
+		t.Fatalf("expected no more changes but got: %#+v", got)
+	default:
+	}
+}
@@ -2,8 +2,11 @@ package jwt
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/json"
+	"io"
 	"io/ioutil"
+	"net/http"
 	"reflect"
 	"time"
 )
@@ -17,12 +20,37 @@ type Map = map[string]interface{}
 // Usage: now := Clock()
 var Clock = time.Now
 
+// Random is the randomness source of the package, e.g. of the random HMAC keys and "jti" claims,
+// the RSA-PSS salts, the ECDSA nonces, the encryption nonces and the generated key pairs.
+// Defaults to the crypto/rand.Reader. It can be overridden by a deterministic source
+// for golden-file tests of the signed output, never in production.
+// Note that the crypto/ecdsa package mixes extra randomness of its own, ECDSA tests
+// should load fixture keys and sign through the `Deterministic` option.
+var Random io.Reader = rand.Reader
+
 // ReadFile can be used to customize the way the
 // Must/Load Key function helpers are loading the filenames from.
 // Example of usage: embedded key pairs.
 // Defaults to the `ioutil.ReadFile` which reads the file from the physical disk.
 var ReadFile = ioutil.ReadFile
 
+// HTTPClient is the default HTTP Client which all the network-touching
+// components of this package (e.g. the `JWKSClient`) use to perform
+// their requests, unless a component's own Client field is set.
+// It can be modified to control proxies, mTLS (through a custom http.RoundTripper)
+// and timeouts centrally.
+// Defaults to a client with a 10 seconds timeout.
+var HTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// httpClient returns the "client" or the package-level HTTPClient if it's nil.
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+
+	return HTTPClient
+}
+
 // Marshal same as json.Marshal.
 // This variable can be modified to enable custom encoder behavior
 // for a signed payload.
@@ -60,6 +88,34 @@ func UnmarshalWithRequired(payload []byte, dest interface{}) error {
 	return meetRequirements(reflect.ValueOf(dest))
 }
 
+// unmarshalClaims decodes the standard claims of a verified token, see `UseJSON`.
+// The standard one is used instead of the custom Unmarshal by default,
+// no need to support the "required" feature there.
+var unmarshalClaims = json.Unmarshal
+
+// UseJSON replaces the JSON encoder and decoder of the package with the given functions,
+// e.g. of a faster drop-in replacement of the encoding/json package (jsoniter, go-json).
+// Unlike a plain replacement of the Marshal and Unmarshal variables,
+// raw []byte claims are still signed as they are and the standard claims
+// of the verified tokens are decoded through "unmarshal" too.
+// Configure the decoder to unmarshal numbers as json.Number to keep
+// the default behavior of map claims. It should be called before any Sign or Verify call.
+//
+// Usage:
+//  json := jsoniter.Config{UseNumber: true}.Froze()
+//  jwt.UseJSON(json.Marshal, json.Unmarshal)
+func UseJSON(marshal func(v interface{}) ([]byte, error), unmarshal func(data []byte, v interface{}) error) {
+	Marshal = func(v interface{}) ([]byte, error) {
+		if b, ok := v.([]byte); ok {
+			return b, nil
+		}
+
+		return marshal(v)
+	}
+	Unmarshal = unmarshal
+	unmarshalClaims = unmarshal
+}
+
 func defaultUnmarshal(payload []byte, dest interface{}) error {
 	dec := json.NewDecoder(bytes.NewReader(payload))
 	dec.UseNumber() // fixes the issue of setting float64 instead of int64 on maps.
@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+
+	var calls int
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	prevClient := HTTPClient
+	t.Cleanup(func() {
+		HTTPClient = prevClient
+	})
+
+	HTTPClient = &http.Client{Transport: transport}
+	if err := NewJWKSClient(srv.URL).Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the package-level HTTPClient to be used")
+	}
+
+	// Test a component's own client has priority.
+	c := NewJWKSClient(srv.URL)
+	c.Client = srv.Client()
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the component's client to be used instead of the package-level one")
+	}
+}
+
+func TestUseJSON(t *testing.T) {
+	prevMarshal, prevUnmarshal, prevUnmarshalClaims := Marshal, Unmarshal, unmarshalClaims
+	t.Cleanup(func() {
+		Marshal, Unmarshal, unmarshalClaims = prevMarshal, prevUnmarshal, prevUnmarshalClaims
+	})
+
+	var marshals, unmarshals int
+	UseJSON(func(v interface{}) ([]byte, error) {
+		marshals++
+		return json.Marshal(v)
+	}, func(data []byte, v interface{}) error {
+		unmarshals++
+		return json.Unmarshal(data, v)
+	})
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Raw claims are signed as they are.
+	rawToken, err := Sign(testAlg, testSecret, []byte(`{"username":"kataras"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(rawToken) != string(token) {
+		t.Fatalf("expected the raw claims to be signed as they are")
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if marshals != 1 {
+		t.Fatalf("expected a single marshal call but got: %d", marshals)
+	}
+
+	// The standard and the custom claims.
+	if unmarshals != 2 {
+		t.Fatalf("expected two unmarshal calls but got: %d", unmarshals)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	prevRandom := Random
+	t.Cleanup(func() {
+		Random = prevRandom
+		Clock = time.Now
+	})
+
+	now := time.Unix(1600000000, 0)
+	Clock = func() time.Time { return now }
+
+	rsaKey, _ := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	sign := func(alg Alg) []byte {
+		Random = rand.New(rand.NewSource(1))
+
+		var privateKey PrivateKey = rsaKey
+		if alg != PS256 {
+			var err error
+			if privateKey, _, err = GenerateKeyPair(alg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		token, err := Sign(alg, privateKey, Map{"sub": "kataras"}, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return token
+	}
+
+	// The generated keys and the RSA-PSS salts. The crypto/ecdsa package
+	// mixes extra randomness of its own, see the Deterministic ECDSA option instead.
+	for _, alg := range []Alg{HS256, EdDSA, PS256} {
+		if first, second := sign(alg), sign(alg); !bytes.Equal(first, second) {
+			t.Fatalf("[%s] expected the same signed output of the same random source", alg.Name())
+		}
+	}
+}
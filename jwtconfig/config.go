@@ -0,0 +1,393 @@
+// Package jwtconfig builds the token Issuers and Verifiers of the github.com/kataras/jwt package
+// from a declarative configuration file, so the services of a platform share the same auth configuration schema.
+//
+// Usage:
+//  cfg, err := jwtconfig.Load("./auth.json")
+//  [handle error...]
+//  issuer, err := cfg.Issuer("default")
+//  verifier, err := cfg.Verifier("default")
+//  router, err := cfg.VerifierRouter()
+//
+// An example configuration:
+//  {
+//    "issuers": {
+//      "default": {"alg": "EdDSA", "key": {"file": "./ed25519_private_key.pem", "kid": "2024-01"}, "issuer": "myapp", "max_age": "15m"}
+//    },
+//    "verifiers": {
+//      "default": {"alg": "EdDSA", "key": {"file": "./ed25519_public_key.pem"}, "issuer": "myapp", "audience": ["orders"], "tolerance": "30s",
+//                  "blocklist": {"backend": "memory", "gc_every": "1h"}},
+//      "idp":     {"alg": "RS256", "jwks_url": "https://idp.example.com/.well-known/jwks.json"}
+//    },
+//    "router": {
+//      "routes": [{"audience": "internal-api", "verifier": "default"}, {"issuer": "https://idp.example.com", "verifier": "idp"}]
+//    }
+//  }
+//
+// JSON files are decoded by default, YAML files are decoded by a registered decoder, see `Decoders`.
+package jwtconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+var (
+	// ErrFormat indicates a configuration file of an extension without a registered decoder, see `Decoders`.
+	ErrFormat = errors.New("jwtconfig: unsupported format")
+	// ErrNotFound indicates an issuer or a verifier name which is not configured.
+	ErrNotFound = errors.New("jwtconfig: not found")
+	// ErrInvalid indicates an invalid configuration value, e.g. an unknown algorithm.
+	ErrInvalid = errors.New("jwtconfig: invalid configuration")
+)
+
+// Decoder decodes the "data" configuration file contents into "v".
+type Decoder func(data []byte, v interface{}) error
+
+// Decoders are the configuration file decoders, by (lowercase) file extension.
+// The ".json" one rejects unknown fields. Register a YAML decoder once on initialization,
+// the configuration types have both "json" and "yaml" field tags, e.g.
+//  jwtconfig.Decoders[".yaml"] = yaml.Unmarshal // gopkg.in/yaml.v3
+//  jwtconfig.Decoders[".yml"] = yaml.Unmarshal
+var Decoders = map[string]Decoder{
+	".json": decodeJSON,
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// BlocklistFactory builds the blocklist of a verifier from its configuration.
+type BlocklistFactory func(c BlocklistConfig) (jwt.TokenValidator, error)
+
+// Blocklists are the blocklist backends, by name. The builtin "memory" one is the in-memory `jwt.Blocklist`.
+// Register the shared backends (e.g. redis) once on initialization, e.g.
+//  jwtconfig.Blocklists["redis"] = func(c jwtconfig.BlocklistConfig) (jwt.TokenValidator, error) {
+//    return redisblocklist.New(c.Options["addr"]), nil
+//  }
+var Blocklists = map[string]BlocklistFactory{
+	"memory": func(c BlocklistConfig) (jwt.TokenValidator, error) {
+		return jwt.NewBlocklist(c.GCEvery.Duration()), nil
+	},
+}
+
+// algs are the signing algorithms of the configuration, by name, and their key parsers.
+var algs = map[string]struct {
+	alg   jwt.Alg
+	parse jwt.KeyParser
+}{
+	"HS256": {jwt.HS256, jwt.ParseKeyHMAC}, "HS384": {jwt.HS384, jwt.ParseKeyHMAC}, "HS512": {jwt.HS512, jwt.ParseKeyHMAC},
+	"RS256": {jwt.RS256, jwt.ParseKeyRSA}, "RS384": {jwt.RS384, jwt.ParseKeyRSA}, "RS512": {jwt.RS512, jwt.ParseKeyRSA},
+	"PS256": {jwt.PS256, jwt.ParseKeyRSA}, "PS384": {jwt.PS384, jwt.ParseKeyRSA}, "PS512": {jwt.PS512, jwt.ParseKeyRSA},
+	"ES256": {jwt.ES256, jwt.ParseKeyECDSA}, "ES384": {jwt.ES384, jwt.ParseKeyECDSA}, "ES512": {jwt.ES512, jwt.ParseKeyECDSA},
+	"EdDSA": {jwt.EdDSA, jwt.ParseKeyEdDSA},
+}
+
+// Config is the declarative configuration of the token issuers and verifiers of a service, by name.
+type Config struct {
+	Issuers   map[string]IssuerConfig   `json:"issuers,omitempty" yaml:"issuers,omitempty"`
+	Verifiers map[string]VerifierConfig `json:"verifiers,omitempty" yaml:"verifiers,omitempty"`
+	// Router selects the verifier of a token by its issuer and audience, see `Config.VerifierRouter`.
+	Router *RouterConfig `json:"router,omitempty" yaml:"router,omitempty"`
+
+	dir string // the directory of the relative key files.
+}
+
+// KeyConfig is the source of a key: a file or an environment variable.
+// The key is parsed according to the algorithm, a PEM-encoded key or an HMAC shared secret.
+type KeyConfig struct {
+	// ID is the key id, the "kid" header of the issued tokens,
+	// a verifier rejects tokens of a different "kid" header.
+	ID string `json:"kid,omitempty" yaml:"kid,omitempty"`
+	// File is the key's file, relative to the configuration file's directory.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Env is the environment variable of the key's contents, used if the File is empty.
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// RouterConfig is the configuration of a `jwt.VerifierRouter`.
+type RouterConfig struct {
+	// Routes are the routes of the router, in order.
+	Routes []RouteConfig `json:"routes" yaml:"routes"`
+	// Default is the optional verifier name of the tokens which no route matches.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// RouteConfig is the configuration of a `jwt.VerifierRoute`.
+type RouteConfig struct {
+	// Issuer matches the tokens of that "iss" claim, empty matches any issuer.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Audience matches the tokens of an "aud" claim which contains it, empty matches any audience.
+	Audience string `json:"audience,omitempty" yaml:"audience,omitempty"`
+	// Verifier is the name of the configured verifier of the matched tokens, required.
+	Verifier string `json:"verifier" yaml:"verifier"`
+}
+
+// IssuerConfig is the configuration of a `jwt.Issuer`.
+type IssuerConfig struct {
+	// Alg is the signing algorithm name, e.g. "EdDSA", required.
+	Alg string `json:"alg" yaml:"alg"`
+	// Key is the private key (or the shared secret), required.
+	Key KeyConfig `json:"key" yaml:"key"`
+	// Issuer is the "iss" claim of the issued tokens.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Audience is the "aud" claim of the issued tokens.
+	Audience []string `json:"audience,omitempty" yaml:"audience,omitempty"`
+	// MaxAge is the lifetime of the issued tokens, e.g. "15m", required.
+	MaxAge Duration `json:"max_age" yaml:"max_age"`
+}
+
+// VerifierConfig is the configuration of a `jwt.Verifier`.
+type VerifierConfig struct {
+	// Alg is the algorithm name the tokens are signed with, e.g. "RS256", required.
+	Alg string `json:"alg" yaml:"alg"`
+	// Key is the public key (or the shared secret), required if the JWKSURL is empty.
+	Key *KeyConfig `json:"key,omitempty" yaml:"key,omitempty"`
+	// JWKSURL is the URL of a JSON Web Key Set, the keys are selected by the "kid" header, see `jwt.JWKSClient`.
+	JWKSURL string `json:"jwks_url,omitempty" yaml:"jwks_url,omitempty"`
+	// Issuer is the expected "iss" claim.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Audience is the expected "aud" claim, see `jwt.Expected`.
+	Audience []string `json:"audience,omitempty" yaml:"audience,omitempty"`
+	// Leeway disallows tokens which expire in less than that duration, see `jwt.Leeway`.
+	Leeway Duration `json:"leeway,omitempty" yaml:"leeway,omitempty"`
+	// Tolerance is the clock skew tolerance of the time claims, see `jwt.Tolerance`.
+	Tolerance Duration `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	// Blocklist is the optional blocklist of invalidated tokens.
+	Blocklist *BlocklistConfig `json:"blocklist,omitempty" yaml:"blocklist,omitempty"`
+}
+
+// BlocklistConfig is the configuration of a verifier's blocklist.
+type BlocklistConfig struct {
+	// Backend is the name of the blocklist backend, see `Blocklists`. Defaults to "memory".
+	Backend string `json:"backend,omitempty" yaml:"backend,omitempty"`
+	// GCEvery is the interval of the expired tokens removal of the "memory" backend, e.g. "1h".
+	GCEvery Duration `json:"gc_every,omitempty" yaml:"gc_every,omitempty"`
+	// Options are backend-specific options, e.g. the address of a redis server.
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// Duration is a time.Duration which is decoded from its string form, e.g. "15m" or "1h30m".
+type Duration time.Duration
+
+// UnmarshalText decodes the "text" duration, e.g. "15m".
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText encodes the duration as a string, e.g. "15m0s".
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Duration returns the time.Duration value.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// Load reads and decodes the configuration file of the "path",
+// the decoder is selected by its extension (see `Decoders`).
+// Relative key files are resolved against the file's directory.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtconfig: %w", err)
+	}
+
+	cfg, err := Parse(data, filepath.Ext(path))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.dir = filepath.Dir(path)
+	return cfg, nil
+}
+
+// Parse decodes the "data" configuration of "ext" file extension, e.g. ".json".
+// Relative key files are resolved against the working directory.
+func Parse(data []byte, ext string) (*Config, error) {
+	decode, ok := Decoders[strings.ToLower(ext)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrFormat, ext)
+	}
+
+	cfg := new(Config)
+	if err := decode(data, cfg); err != nil {
+		return nil, fmt.Errorf("jwtconfig: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Issuer builds the configured Issuer of the "name".
+// Returns ErrNotFound if it's not configured and ErrInvalid on invalid configuration values.
+func (cfg *Config) Issuer(name string) (*jwt.Issuer, error) {
+	c, ok := cfg.Issuers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: issuer %q", ErrNotFound, name)
+	}
+
+	alg, parse, err := lookupAlg(c.Alg)
+	if err != nil {
+		return nil, fmt.Errorf("issuer %q: %w", name, err)
+	}
+
+	if c.MaxAge <= 0 {
+		return nil, fmt.Errorf("issuer %q: %w: max_age is required", name, ErrInvalid)
+	}
+
+	key, err := cfg.loadKey(c.Key, parse)
+	if err != nil {
+		return nil, fmt.Errorf("issuer %q: %w", name, err)
+	}
+
+	if key.Private == nil {
+		return nil, fmt.Errorf("issuer %q: %w: key is not a private key", name, ErrInvalid)
+	}
+
+	issuer := jwt.NewIssuer(alg, key.Private, c.MaxAge.Duration())
+	issuer.KeyID = key.ID
+	issuer.Issuer = c.Issuer
+	issuer.Audience = c.Audience
+	return issuer, nil
+}
+
+// Verifier builds the configured Verifier of the "name".
+// Returns ErrNotFound if it's not configured and ErrInvalid on invalid configuration values.
+func (cfg *Config) Verifier(name string) (*jwt.Verifier, error) {
+	c, ok := cfg.Verifiers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: verifier %q", ErrNotFound, name)
+	}
+
+	alg, parse, err := lookupAlg(c.Alg)
+	if err != nil {
+		return nil, fmt.Errorf("verifier %q: %w", name, err)
+	}
+
+	verifier := jwt.NewVerifier(alg, nil)
+	switch {
+	case c.JWKSURL != "":
+		verifier.KeyProvider = jwt.NewJWKSClient(c.JWKSURL)
+	case c.Key != nil:
+		key, err := cfg.loadKey(*c.Key, parse)
+		if err != nil {
+			return nil, fmt.Errorf("verifier %q: %w", name, err)
+		}
+
+		key.Private = nil // never kept by a verifier.
+		verifier.KeyProvider = key
+	default:
+		return nil, fmt.Errorf("verifier %q: %w: key or jwks_url is required", name, ErrInvalid)
+	}
+
+	if c.Issuer != "" || len(c.Audience) > 0 {
+		verifier.Validators = append(verifier.Validators, jwt.Expected{Issuer: c.Issuer, Audience: c.Audience})
+	}
+
+	verifier.Leeway = c.Leeway.Duration()
+	verifier.Tolerance = c.Tolerance.Duration()
+
+	if c.Blocklist != nil {
+		backend := c.Blocklist.Backend
+		if backend == "" {
+			backend = "memory"
+		}
+
+		factory, ok := Blocklists[backend]
+		if !ok {
+			return nil, fmt.Errorf("verifier %q: %w: unknown blocklist backend %q", name, ErrInvalid, backend)
+		}
+
+		if verifier.Blocklist, err = factory(*c.Blocklist); err != nil {
+			return nil, fmt.Errorf("verifier %q: blocklist: %w", name, err)
+		}
+	}
+
+	return verifier, nil
+}
+
+// VerifierRouter builds the configured jwt.VerifierRouter and its verifiers,
+// a verifier of many routes is built once.
+// Returns ErrNotFound if the router or one of its verifiers is not configured.
+func (cfg *Config) VerifierRouter() (*jwt.VerifierRouter, error) {
+	if cfg.Router == nil {
+		return nil, fmt.Errorf("%w: router", ErrNotFound)
+	}
+
+	verifiers := make(map[string]*jwt.Verifier)
+	verifier := func(name string) (*jwt.Verifier, error) {
+		if v, ok := verifiers[name]; ok {
+			return v, nil
+		}
+
+		v, err := cfg.Verifier(name)
+		if err != nil {
+			return nil, fmt.Errorf("router: %w", err)
+		}
+
+		verifiers[name] = v
+		return v, nil
+	}
+
+	router := jwt.NewVerifierRouter()
+	for _, c := range cfg.Router.Routes {
+		v, err := verifier(c.Verifier)
+		if err != nil {
+			return nil, err
+		}
+
+		router.Routes = append(router.Routes, jwt.VerifierRoute{Issuer: c.Issuer, Audience: c.Audience, Verifier: v})
+	}
+
+	if name := cfg.Router.Default; name != "" {
+		v, err := verifier(name)
+		if err != nil {
+			return nil, err
+		}
+
+		router.Default = v
+	}
+
+	return router, nil
+}
+
+func lookupAlg(name string) (jwt.Alg, jwt.KeyParser, error) {
+	a, ok := algs[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalid, name)
+	}
+
+	return a.alg, a.parse, nil
+}
+
+func (cfg *Config) loadKey(c KeyConfig, parse jwt.KeyParser) (*jwt.StaticKey, error) {
+	switch {
+	case c.File != "":
+		filename := c.File
+		if !filepath.IsAbs(filename) && cfg.dir != "" {
+			filename = filepath.Join(cfg.dir, filename)
+		}
+
+		return jwt.KeyFromFile(filename, c.ID, parse)
+	case c.Env != "":
+		return jwt.KeyFromEnv(c.Env, c.ID, parse)
+	default:
+		return nil, fmt.Errorf("%w: key file or env is required", ErrInvalid)
+	}
+}
@@ -0,0 +1,148 @@
+package jwtconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kataras/jwt"
+)
+
+const testConfig = `{
+  "issuers": {
+    "default": {"alg": "EdDSA", "key": {"file": "../_testfiles/ed25519_private_key.pem", "kid": "k1"}, "issuer": "myapp", "audience": ["orders"], "max_age": "15m"},
+    "hmac":    {"alg": "HS256", "key": {"env": "JWTCONFIG_TEST_SECRET"}, "max_age": "1h"}
+  },
+  "verifiers": {
+    "default": {"alg": "EdDSA", "key": {"file": "../_testfiles/ed25519_public_key.pem", "kid": "k1"}, "issuer": "myapp", "audience": ["orders"],
+                "tolerance": "30s", "blocklist": {"gc_every": "1h"}},
+    "idp":     {"alg": "RS256", "jwks_url": "https://idp.example.com/.well-known/jwks.json", "leeway": "1m"}
+  },
+  "router": {
+    "routes": [{"audience": "orders", "verifier": "default"}, {"issuer": "https://idp.example.com", "verifier": "idp"}],
+    "default": "default"
+  }
+}`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "auth.JSON")
+	if err := os.WriteFile(filename, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.dir = wd // the key files are relative to the package's directory.
+
+	issuer, err := cfg.Issuer("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := cfg.Verifier("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifier.Tolerance.Seconds() != 30 || verifier.Blocklist == nil {
+		t.Fatalf("unexpected verifier: %#+v", verifier)
+	}
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := verifier.VerifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims := verifiedToken.StandardClaims; claims.Issuer != "myapp" || claims.Age().Minutes() != 15 {
+		t.Fatalf("unexpected claims: %#+v", claims)
+	}
+
+	t.Setenv("JWTCONFIG_TEST_SECRET", "sercrethatmaycontainch@r$32chars")
+	if _, err = cfg.Issuer("hmac"); err != nil {
+		t.Fatal(err)
+	}
+
+	idp, err := cfg.Verifier("idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := idp.KeyProvider.(*jwt.JWKSClient); !ok || idp.Leeway.Minutes() != 1 {
+		t.Fatalf("unexpected verifier: %#+v", idp)
+	}
+
+	if _, err = cfg.Verifier("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected error: %v but got: %v", ErrNotFound, err)
+	}
+
+	router, err := cfg.VerifierRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(router.Routes) != 2 || router.Routes[0].Verifier != router.Default || router.Routes[1].Issuer != "https://idp.example.com" {
+		t.Fatalf("unexpected router: %#+v", router)
+	}
+
+	if _, err = router.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Router.Routes = append(cfg.Router.Routes, RouteConfig{Verifier: "unknown"})
+	if _, err = cfg.VerifierRouter(); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected error: %v but got: %v", ErrNotFound, err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte("issuers: {}"), ".yaml"); !errors.Is(err, ErrFormat) {
+		t.Fatalf("expected error: %v but got: %v", ErrFormat, err)
+	}
+
+	if _, err := Parse([]byte(`{"issuer": {}}`), ".json"); err == nil {
+		t.Fatalf("expected an error of an unknown field")
+	}
+
+	tests := []struct {
+		config string
+		issuer bool
+	}{
+		{`{"issuers": {"a": {"alg": "HS1", "key": {"env": "X"}, "max_age": "1m"}}}`, true},
+		{`{"issuers": {"a": {"alg": "HS256", "key": {"env": "X"}}}}`, true},
+		{`{"issuers": {"a": {"alg": "HS256", "key": {}, "max_age": "1m"}}}`, true},
+		{`{"issuers": {"a": {"alg": "EdDSA", "key": {"file": "../_testfiles/ed25519_public_key.pem"}, "max_age": "1m"}}}`, true},
+		{`{"verifiers": {"a": {"alg": "RS256"}}}`, false},
+		{`{"verifiers": {"a": {"alg": "RS256", "jwks_url": "https://idp", "blocklist": {"backend": "redis"}}}}`, false},
+	}
+
+	for i, tt := range tests {
+		cfg, err := Parse([]byte(tt.config), ".json")
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if tt.issuer {
+			_, err = cfg.Issuer("a")
+		} else {
+			_, err = cfg.Verifier("a")
+		}
+
+		if !errors.Is(err, ErrInvalid) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrInvalid, err)
+		}
+	}
+}
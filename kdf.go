@@ -0,0 +1,228 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ScryptCost holds the cost parameters of the scrypt (RFC 7914) key derivation function.
+type ScryptCost struct {
+	N int // CPU/memory cost, a power of two greater than 1.
+	R int // Block size.
+	P int // Parallelization.
+}
+
+// DefaultScryptCost is the scrypt cost of the `DeriveHMACKey` function.
+// It requires about 32MB of memory per derivation.
+var DefaultScryptCost = ScryptCost{N: 1 << 15, R: 8, P: 1}
+
+// DeriveHMACKey derives a full-entropy HMAC key of the "alg" (HS256, HS384 or HS512) size
+// from a human "passphrase", so services configured with "secret strings" still get proper keys.
+// The passphrase is stretched through scrypt (see `DefaultScryptCost`) with the "salt"
+// and the result is expanded through HKDF with the "context" (e.g. "myapp access tokens"),
+// so different contexts of the same passphrase get independent keys.
+//
+// The "salt" should be random, at least 16 bytes, and stored along with the configuration;
+// the same passphrase, salt and context always derive the same key.
+//
+// Usage:
+//  sharedKey, err := jwt.DeriveHMACKey(jwt.HS256, []byte(os.Getenv("JWT_SECRET")), salt, "access")
+func DeriveHMACKey(alg Alg, passphrase, salt []byte, context string) ([]byte, error) {
+	a, ok := alg.(*algHMAC)
+	if !ok {
+		return nil, fmt.Errorf("key derivation: %s is not an HMAC algorithm", alg.Name())
+	}
+
+	if len(passphrase) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if len(salt) == 0 {
+		return nil, errors.New("key derivation: missing salt")
+	}
+
+	size := a.hasher.Size()
+	master, err := Scrypt(passphrase, salt, DefaultScryptCost, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return HKDF(a.hasher, master, nil, []byte(context), size)
+}
+
+// HKDF derives a key of "size" bytes from the "secret" (input keying material)
+// through the HMAC-based key derivation function (RFC 5869) of the "hash" function.
+// The "salt" and "info" are optional. Use it for secrets of high entropy,
+// for human passphrases use the `DeriveHMACKey` function instead.
+func HKDF(hash crypto.Hash, secret, salt, info []byte, size int) ([]byte, error) {
+	if !hash.Available() {
+		return nil, fmt.Errorf("hkdf: hash function %d is not available", hash)
+	}
+
+	if size <= 0 || size > 255*hash.Size() {
+		return nil, errors.New("hkdf: invalid key size")
+	}
+
+	if len(salt) == 0 {
+		salt = make([]byte, hash.Size())
+	}
+
+	extract := hmac.New(hash.New, salt)
+	extract.Write(secret)
+	prk := extract.Sum(nil)
+
+	expand := hmac.New(hash.New, prk)
+	var (
+		key = make([]byte, 0, size+hash.Size())
+		t   []byte
+	)
+	for counter := byte(1); len(key) < size; counter++ {
+		expand.Reset()
+		expand.Write(t)
+		expand.Write(info)
+		expand.Write([]byte{counter})
+		t = expand.Sum(nil)
+		key = append(key, t...)
+	}
+
+	return key[:size], nil
+}
+
+// Scrypt derives a key of "size" bytes from the "password" and "salt"
+// through the scrypt (RFC 7914) key derivation function of the given "cost".
+func Scrypt(password, salt []byte, cost ScryptCost, size int) ([]byte, error) {
+	const maxInt = int(^uint(0) >> 1)
+
+	n, r, p := cost.N, cost.R, cost.P
+	if n <= 1 || n&(n-1) != 0 {
+		return nil, errors.New("scrypt: N must be a power of two greater than 1")
+	}
+
+	if r <= 0 || p <= 0 || uint64(r)*uint64(p) >= 1<<30 || r > maxInt/128/p || r > maxInt/256 || n > maxInt/128/r {
+		return nil, errors.New("scrypt: parameters are too large")
+	}
+
+	if size <= 0 {
+		return nil, errors.New("scrypt: invalid key size")
+	}
+
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*n*r)
+	b := pbkdf2SHA256(password, salt, p*128*r)
+	for i := 0; i < p; i++ {
+		scryptSMix(b[i*128*r:], r, n, v, xy)
+	}
+
+	return pbkdf2SHA256(password, b, size), nil
+}
+
+// pbkdf2SHA256 is the PBKDF2 (RFC 8018) function of HMAC-SHA256 and a single iteration, as scrypt uses it.
+func pbkdf2SHA256(password, salt []byte, size int) []byte {
+	return pbkdf2Key(sha256.New, password, salt, 1, size)
+}
+
+// scryptSalsaXOR applies the Salsa20/8 core to "tmp" XOR "in" and copies the result to "tmp" and "out".
+func scryptSalsaXOR(tmp *[16]uint32, in, out []uint32) {
+	var x [16]uint32
+	for i := range tmp {
+		tmp[i] ^= in[i]
+		x[i] = tmp[i]
+	}
+
+	for i := 0; i < 8; i += 2 {
+		// Columns.
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+		// Rows.
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+
+	for i := range tmp {
+		tmp[i] += x[i]
+		out[i] = tmp[i]
+	}
+}
+
+// scryptBlockMix is the scryptBlockMix function of RFC 7914 section 4.
+func scryptBlockMix(tmp *[16]uint32, in, out []uint32, r int) {
+	copy(tmp[:], in[(2*r-1)*16:])
+	for i := 0; i < 2*r; i += 2 {
+		scryptSalsaXOR(tmp, in[i*16:], out[i*8:])
+		scryptSalsaXOR(tmp, in[i*16+16:], out[i*8+r*16:])
+	}
+}
+
+// scryptSMix is the scryptROMix function of RFC 7914 section 5, it modifies the "b" block in place.
+func scryptSMix(b []byte, r, n int, v, xy []uint32) {
+	var tmp [16]uint32
+	size := 32 * r
+	x, y := xy[:size], xy[size:]
+
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+
+	for i := 0; i < n; i += 2 {
+		copy(v[i*size:], x)
+		scryptBlockMix(&tmp, x, y, r)
+		copy(v[(i+1)*size:], y)
+		scryptBlockMix(&tmp, y, x, r)
+	}
+
+	integer := func(block []uint32) int {
+		j := (2*r - 1) * 16
+		return int((uint64(block[j]) | uint64(block[j+1])<<32) & uint64(n-1))
+	}
+
+	for i := 0; i < n; i += 2 {
+		j := integer(x)
+		for k, value := range v[j*size : (j+1)*size] {
+			x[k] ^= value
+		}
+		scryptBlockMix(&tmp, x, y, r)
+
+		j = integer(y)
+		for k, value := range v[j*size : (j+1)*size] {
+			y[k] ^= value
+		}
+		scryptBlockMix(&tmp, y, x, r)
+	}
+
+	for i, value := range x {
+		binary.LittleEndian.PutUint32(b[i*4:], value)
+	}
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"testing"
+)
+
+func TestScrypt(t *testing.T) {
+	// RFC 7914 section 12.
+	tests := []struct {
+		password, salt string
+		cost           ScryptCost
+		expected       string
+	}{
+		{"", "", ScryptCost{N: 16, R: 1, P: 1}, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"password", "NaCl", ScryptCost{N: 1024, R: 8, P: 16}, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+	}
+
+	for _, tt := range tests {
+		key, err := Scrypt([]byte(tt.password), []byte(tt.salt), tt.cost, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := hex.EncodeToString(key); got != tt.expected {
+			t.Fatalf("%q: expected key: %s but got: %s", tt.password, tt.expected, got)
+		}
+	}
+
+	for _, cost := range []ScryptCost{{N: 0, R: 1, P: 1}, {N: 15, R: 1, P: 1}, {N: 16, R: 0, P: 1}, {N: 16, R: 1, P: 1 << 30}} {
+		if _, err := Scrypt([]byte("password"), []byte("salt"), cost, 32); err == nil {
+			t.Fatalf("%#+v: expected an error", cost)
+		}
+	}
+}
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869 Appendix A.1.
+	secret := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	expected := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+	key, err := HKDF(crypto.SHA256, secret, salt, info, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := hex.EncodeToString(key); got != expected {
+		t.Fatalf("expected key: %s but got: %s", expected, got)
+	}
+
+	if _, err = HKDF(crypto.SHA256, secret, salt, info, 255*32+1); err == nil {
+		t.Fatalf("expected an error on too large key size")
+	}
+}
+
+func TestDeriveHMACKey(t *testing.T) {
+	passphrase, salt := []byte("correct horse battery staple"), []byte("0123456789abcdef")
+
+	// Generated by the Node.js crypto.scryptSync and crypto.hkdfSync functions.
+	tests := []struct {
+		alg      Alg
+		expected string
+	}{
+		{HS256, "0300a79da5d43c109de6af5c962b8fb30d793f0ce5932952e8baccb51e88878f"},
+		{HS512, "9032584e7068c28bca13639554ec09f0e03ca9d7513ebe9d257f1a1d224e0302b1016ec497a0ee5b698247c6f5437eb7b10f531f2f6e4242a503df3cf91909fa"},
+	}
+
+	for _, tt := range tests {
+		key, err := DeriveHMACKey(tt.alg, passphrase, salt, "access")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := hex.EncodeToString(key); got != tt.expected {
+			t.Fatalf("%s: expected key: %s but got: %s", tt.alg.Name(), tt.expected, got)
+		}
+
+		testEncodeDecodeToken(t, tt.alg, key, key, nil)
+	}
+
+	other, err := DeriveHMACKey(HS256, passphrase, salt, "refresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(other) == tests[0].expected {
+		t.Fatalf("expected different keys for different contexts")
+	}
+
+	if _, err = DeriveHMACKey(RS256, passphrase, salt, "access"); err == nil {
+		t.Fatalf("expected an error on non-HMAC algorithm")
+	}
+
+	if _, err = DeriveHMACKey(HS256, nil, salt, "access"); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	if _, err = DeriveHMACKey(HS256, passphrase, nil, "access"); err == nil {
+		t.Fatalf("expected an error on missing salt")
+	}
+}
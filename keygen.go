@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+	"io"
+)
+
+// generatedRSAKeyBits is the size of the RSA keys of `GenerateKeyPair`.
+const generatedRSAKeyBits = 2048
+
+// GenerateKeyPair generates a new random key pair for the given builtin algorithm,
+// typed for direct use with it: a shared secret of the hash size (same value for both keys) for HMAC,
+// a 2048-bit RSA key for RSA and RSA-PSS, a key of the algorithm's curve for ECDSA
+// and an ed25519 key for EdDSA. It is helpful for tests and dev environments,
+// production keys should be generated once and loaded, e.g. through `LoadPrivateKeyRSA`.
+//
+// Usage:
+//  privateKey, publicKey, err := jwt.GenerateKeyPair(jwt.ES256)
+//  token, err := jwt.Sign(jwt.ES256, privateKey, claims)
+//  verifiedToken, err := jwt.Verify(jwt.ES256, publicKey, token)
+func GenerateKeyPair(alg Alg) (PrivateKey, PublicKey, error) {
+	switch a := alg.(type) {
+	case *algHMAC:
+		secret := make([]byte, a.hasher.Size())
+		if _, err := io.ReadFull(Random, secret); err != nil {
+			return nil, nil, err
+		}
+
+		return secret, secret, nil
+	case *algRSA, *algRSAPSS:
+		privateKey, err := rsa.GenerateKey(Random, generatedRSAKeyBits)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return privateKey, &privateKey.PublicKey, nil
+	case *algECDSA:
+		var curve elliptic.Curve
+		switch a.curveBits {
+		case 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		default:
+			curve = elliptic.P521()
+		}
+
+		privateKey, err := ecdsa.GenerateKey(curve, Random)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return privateKey, &privateKey.PublicKey, nil
+	case *algEdDSA, *algEd25519Options:
+		publicKey, privateKey, err := ed25519.GenerateKey(Random)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return privateKey, publicKey, nil
+	default:
+		return nil, nil, fmt.Errorf("generate key: unsupported algorithm: %s", alg.Name())
+	}
+}
+
+// MustGenerateKeyPair same as `GenerateKeyPair` but it panics on errors.
+func MustGenerateKeyPair(alg Alg) (PrivateKey, PublicKey) {
+	privateKey, publicKey, err := GenerateKeyPair(alg)
+	if err != nil {
+		panicHandler(err)
+	}
+
+	return privateKey, publicKey
+}
@@ -0,0 +1,25 @@
+package jwt
+
+import "testing"
+
+func TestGenerateKeyPair(t *testing.T) {
+	for _, alg := range []Alg{HS256, HS512, RS256, PS384, ES256, ES384, ES512, EdDSA} {
+		privateKey, publicKey, err := GenerateKeyPair(alg)
+		if err != nil {
+			t.Fatalf("[%s] %v", alg.Name(), err)
+		}
+
+		token, err := Sign(alg, privateKey, Claims{Subject: "kataras"})
+		if err != nil {
+			t.Fatalf("[%s] %v", alg.Name(), err)
+		}
+
+		if _, err = Verify(alg, publicKey, token); err != nil {
+			t.Fatalf("[%s] %v", alg.Name(), err)
+		}
+	}
+
+	if _, _, err := GenerateKeyPair(NONE); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
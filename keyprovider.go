@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyProvider provides the verification keys by key id (the "kid" header).
+// The `Verifier` consumes it through its KeyProvider field, so the key source
+// (a static key, an environment variable, a file, a `JWKSClient`, a KMS...)
+// is a construction-time choice only.
+type KeyProvider interface {
+	// PublicKey returns the public key (or the shared secret) of the "kid" key id.
+	// The "kid" is empty when the token has no "kid" header.
+	// It should return ErrUnknownKid if the key id does not match any key.
+	PublicKey(ctx context.Context, kid string) (PublicKey, error)
+}
+
+// SigningKeyProvider provides the current signing key and its key id.
+// The `Issuer` consumes it through its KeyProvider field.
+type SigningKeyProvider interface {
+	// PrivateKey returns the private key (or the shared secret) and its key id,
+	// the key id is set as the "kid" header of the generated tokens (if not empty).
+	PrivateKey(ctx context.Context) (kid string, key PrivateKey, err error)
+}
+
+var (
+	_ KeyProvider        = (*JWKSClient)(nil)
+	_ KeyProvider        = (*StaticKey)(nil)
+	_ SigningKeyProvider = (*StaticKey)(nil)
+)
+
+// StaticKey is a KeyProvider and a SigningKeyProvider of a single key pair
+// (or a shared secret, same value for both Private and Public fields).
+// See `KeyFromEnv` and `KeyFromFile` too.
+type StaticKey struct {
+	ID      string     // The key id, optional.
+	Private PrivateKey // The signing key, optional for verification-only use.
+	Public  PublicKey  // The verification key, optional for signing-only use.
+}
+
+// PublicKey completes the KeyProvider interface.
+// Returns ErrUnknownKid if both the key's ID and the "kid" are not empty and they don't match.
+func (k *StaticKey) PublicKey(_ context.Context, kid string) (PublicKey, error) {
+	if kid != "" && k.ID != "" && kid != k.ID {
+		return nil, ErrUnknownKid
+	}
+
+	if k.Public == nil {
+		return nil, ErrInvalidKey
+	}
+
+	return k.Public, nil
+}
+
+// PrivateKey completes the SigningKeyProvider interface.
+func (k *StaticKey) PrivateKey(_ context.Context) (string, PrivateKey, error) {
+	if k.Private == nil {
+		return "", nil, ErrInvalidKey
+	}
+
+	return k.ID, k.Private, nil
+}
+
+// KeyParser parses the raw contents of a key,
+// e.g. a PEM-encoded private or public key or a shared secret.
+// The private key is nil when the contents contain a public key.
+// See `ParseKeyHMAC`, `ParseKeyRSA`, `ParseKeyECDSA` and `ParseKeyEdDSA`.
+type KeyParser func(b []byte) (PrivateKey, PublicKey, error)
+
+// ParseKeyHMAC is a KeyParser of HMAC shared secrets.
+// The leading and trailing white space is removed.
+func ParseKeyHMAC(b []byte) (PrivateKey, PublicKey, error) {
+	secret := []byte(strings.TrimSpace(string(b)))
+	if len(secret) == 0 {
+		return nil, nil, ErrInvalidKey
+	}
+
+	return secret, secret, nil
+}
+
+// ParseKeyRSA is a KeyParser of PEM-encoded RSA private or public keys.
+func ParseKeyRSA(b []byte) (PrivateKey, PublicKey, error) {
+	if privateKey, err := ParsePrivateKeyRSA(b); err == nil {
+		return privateKey, &privateKey.PublicKey, nil
+	}
+
+	publicKey, err := ParsePublicKeyRSA(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, publicKey, nil
+}
+
+// ParseKeyECDSA is a KeyParser of PEM-encoded ECDSA private or public keys.
+func ParseKeyECDSA(b []byte) (PrivateKey, PublicKey, error) {
+	if privateKey, err := ParsePrivateKeyECDSA(b); err == nil {
+		return privateKey, &privateKey.PublicKey, nil
+	}
+
+	publicKey, err := ParsePublicKeyECDSA(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, publicKey, nil
+}
+
+// ParseKeyEdDSA is a KeyParser of PEM-encoded Ed25519 private or public keys.
+func ParseKeyEdDSA(b []byte) (PrivateKey, PublicKey, error) {
+	if privateKey, err := ParsePrivateKeyEdDSA(b); err == nil {
+		return privateKey, privateKey.Public().(ed25519.PublicKey), nil
+	}
+
+	publicKey, err := ParsePublicKeyEdDSA(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, publicKey, nil
+}
+
+// KeyFromEnv returns a StaticKey of "kid" key id,
+// parsed from the contents of the "name" environment variable.
+func KeyFromEnv(name, kid string, parse KeyParser) (*StaticKey, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil, fmt.Errorf("key: environment variable %q is empty", name)
+	}
+
+	return newStaticKey(kid, []byte(value), parse)
+}
+
+// KeyFromFile returns a StaticKey of "kid" key id,
+// parsed from the contents of the "filename" file (see the `ReadFile` package-level variable).
+func KeyFromFile(filename, kid string, parse KeyParser) (*StaticKey, error) {
+	b, err := ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStaticKey(kid, b, parse)
+}
+
+func newStaticKey(kid string, b []byte, parse KeyParser) (*StaticKey, error) {
+	privateKey, publicKey, err := parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticKey{ID: kid, Private: privateKey, Public: publicKey}, nil
+}
@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestKeyProvider(t *testing.T) {
+	signingKey, err := KeyFromFile("./_testfiles/rsa_private_key.pem", "key-1", ParseKeyRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verificationKey, err := KeyFromFile("./_testfiles/rsa_public_key.pem", "key-1", ParseKeyRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verificationKey.Private != nil {
+		t.Fatalf("expected a public-only key")
+	}
+
+	issuer := NewIssuer(RS256, nil, time.Minute)
+	issuer.KeyProvider = signingKey
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(RS256, nil)
+	verifier.KeyProvider = verificationKey
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	// The signing key provider derives the public key too.
+	verifier.KeyProvider = signingKey
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier.KeyProvider = &StaticKey{ID: "key-2", Public: verificationKey.Public}
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if _, err = verifier.VerifyToken([]byte("not.a-token")); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	issuer.KeyProvider = &StaticKey{Public: verificationKey.Public}
+	if _, err = issuer.Token("kataras", nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
+
+func TestKeyFromEnv(t *testing.T) {
+	const name = "JWT_TEST_SECRET"
+	os.Setenv(name, " sercrethatmaycontainch@r$32chars\n")
+	t.Cleanup(func() {
+		os.Unsetenv(name)
+	})
+
+	key, err := KeyFromEnv(name, "", ParseKeyHMAC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := NewIssuer(HS256, nil, time.Minute)
+	issuer.KeyProvider = key
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens without a "kid" header match any key.
+	if _, err = Verify(HS256, []byte("sercrethatmaycontainch@r$32chars"), token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = KeyFromEnv("JWT_TEST_MISSING", "", ParseKeyHMAC); err == nil {
+		t.Fatalf("expected an error on a missing environment variable")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		filename string
+		parse    KeyParser
+		private  bool
+	}{
+		{"./_testfiles/ecdsa_private_key.pem", ParseKeyECDSA, true},
+		{"./_testfiles/ecdsa_public_key.pem", ParseKeyECDSA, false},
+		{"./_testfiles/ed25519_private_key.pem", ParseKeyEdDSA, true},
+		{"./_testfiles/ed25519_public_key.pem", ParseKeyEdDSA, false},
+	}
+
+	for _, tt := range tests {
+		key, err := KeyFromFile(tt.filename, "", tt.parse)
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.filename, err)
+		}
+
+		if key.Public == nil {
+			t.Fatalf("[%s] expected a public key", tt.filename)
+		}
+
+		if private := key.Private != nil; private != tt.private {
+			t.Fatalf("[%s] expected private key: %v but got: %v", tt.filename, tt.private, private)
+		}
+	}
+
+	if _, err := KeyFromFile("./_testfiles/invalid_pem.pem", "", ParseKeyRSA); err == nil {
+		t.Fatalf("expected an error on an invalid key")
+	}
+}
@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// KeyRing is a KeyProvider and a SigningKeyProvider which supports key rotation.
+// It signs with its active key and it verifies tokens of the active key
+// and of the retired keys, the latter only for a limited acceptance window
+// after their retirement. Expired keys are removed automatically,
+// so the verification set does not grow forever.
+// The tokens must carry a "kid" header, tokens without it are rejected with ErrUnknownKid.
+//
+// A KeyRing is safe for concurrent use.
+//
+// Usage:
+//  keys := jwt.NewKeyRing(24 * time.Hour)
+//  keys.Rotate("key-1", sharedKey, sharedKey)
+//
+//  issuer := jwt.NewIssuer(jwt.HS256, nil, 15*time.Minute)
+//  issuer.KeyProvider = keys
+//  verifier := jwt.NewVerifier(jwt.HS256, nil)
+//  verifier.KeyProvider = keys
+//
+//  // Later on, tokens signed by "key-1" are still accepted for 24 hours.
+//  keys.Rotate("key-2", newSharedKey, newSharedKey)
+type KeyRing struct {
+	// RolloverWindow is the default acceptance window of the keys retired by `Rotate`.
+	RolloverWindow time.Duration
+
+	mu     sync.RWMutex
+	active string
+	keys   map[string]*ringKey
+}
+
+type ringKey struct {
+	private PrivateKey
+	public  PublicKey
+	// expiresAt is the end of the acceptance window, zero for the active key.
+	expiresAt time.Time
+}
+
+var (
+	_ KeyProvider        = (*KeyRing)(nil)
+	_ SigningKeyProvider = (*KeyRing)(nil)
+)
+
+// NewKeyRing returns a new empty KeyRing. The retired keys are accepted
+// for "rolloverWindow" duration after a `Rotate` call.
+// Call `Rotate` to set its first active key.
+func NewKeyRing(rolloverWindow time.Duration) *KeyRing {
+	return &KeyRing{
+		RolloverWindow: rolloverWindow,
+		keys:           make(map[string]*ringKey),
+	}
+}
+
+// Rotate sets the "kid" key as the active one, the "private" key signs the new tokens
+// and the "public" key verifies them (the same shared secret for HMAC).
+// The previously active key is retired, it verifies tokens for the RolloverWindow duration.
+func (k *KeyRing) Rotate(kid string, private PrivateKey, public PublicKey) {
+	now := Clock()
+
+	k.mu.Lock()
+	k.removeExpired(now)
+	if previous, ok := k.keys[k.active]; ok && k.active != kid {
+		previous.expiresAt = now.Add(k.RolloverWindow)
+	}
+
+	k.keys[kid] = &ringKey{private: private, public: public}
+	k.active = kid
+	k.mu.Unlock()
+}
+
+// Retire stops the verification of the "kid" key's tokens after "acceptFor" duration from now,
+// e.g. Retire("key-1", 24*time.Hour) to accept the tokens of "key-1" for one more day.
+// A zero or negative "acceptFor" removes the key immediately.
+// Returns ErrUnknownKid if the key does not exist and ErrInvalidKey if the key is the active one.
+func (k *KeyRing) Retire(kid string, acceptFor time.Duration) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return ErrUnknownKid
+	}
+
+	if kid == k.active {
+		return ErrInvalidKey
+	}
+
+	if acceptFor <= 0 {
+		delete(k.keys, kid)
+		return nil
+	}
+
+	key.expiresAt = Clock().Add(acceptFor)
+	return nil
+}
+
+// PublicKey completes the KeyProvider interface.
+// Returns ErrUnknownKid if the "kid" key does not exist or its acceptance window is over.
+func (k *KeyRing) PublicKey(_ context.Context, kid string) (PublicKey, error) {
+	now := Clock()
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	expired := ok && key.expired(now)
+	k.mu.RUnlock()
+
+	if expired {
+		k.mu.Lock()
+		k.removeExpired(now)
+		k.mu.Unlock()
+	}
+
+	if !ok || expired {
+		return nil, ErrUnknownKid
+	}
+
+	return key.public, nil
+}
+
+// PrivateKey completes the SigningKeyProvider interface.
+// Returns ErrInvalidKey if the KeyRing has no active key.
+func (k *KeyRing) PrivateKey(_ context.Context) (string, PrivateKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[k.active]
+	if !ok || key.private == nil {
+		return "", nil, ErrInvalidKey
+	}
+
+	return k.active, key.private, nil
+}
+
+// AcceptedUntil reports the end of the acceptance window of the "kid" key.
+// The time is zero for the active key, the boolean is false if the key is not accepted.
+func (k *KeyRing) AcceptedUntil(kid string) (time.Time, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[kid]
+	if !ok || key.expired(Clock()) {
+		return time.Time{}, false
+	}
+
+	return key.expiresAt, true
+}
+
+// removeExpired removes the keys of an expired acceptance window, the caller must hold the lock.
+func (k *KeyRing) removeExpired(now time.Time) {
+	for kid, key := range k.keys {
+		if key.expired(now) {
+			delete(k.keys, kid)
+		}
+	}
+}
+
+func (key *ringKey) expired(now time.Time) bool {
+	return !key.expiresAt.IsZero() && !now.Before(key.expiresAt)
+}
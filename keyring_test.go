@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyRing(t *testing.T) {
+	now := time.Now()
+	Clock = func() time.Time { return now }
+	t.Cleanup(func() {
+		Clock = time.Now
+	})
+
+	oldSecret := []byte("sercrethatmaycontainch@r$32chars")
+	newSecret := []byte("anothersercrethatmaycontainch@r$")
+
+	keys := NewKeyRing(24 * time.Hour)
+	if _, _, err := keys.PrivateKey(context.Background()); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	keys.Rotate("key-1", oldSecret, oldSecret)
+
+	issuer := NewIssuer(HS256, nil, 48*time.Hour)
+	issuer.KeyProvider = keys
+	verifier := NewVerifier(HS256, nil)
+	verifier.KeyProvider = keys
+
+	oldToken, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys.Rotate("key-2", newSecret, newSecret)
+
+	newToken, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kid, _ := tokenKeyID(newToken); kid != "key-2" {
+		t.Fatalf("expected the new token to be signed by key-2 but got: %s", kid)
+	}
+
+	if until, ok := keys.AcceptedUntil("key-1"); !ok || !until.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected acceptance window: %s (%v)", until, ok)
+	}
+
+	for _, token := range [][]byte{oldToken, newToken} {
+		if _, err = verifier.VerifyToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The acceptance window of the retired key is over.
+	now = now.Add(24 * time.Hour)
+	if _, err = verifier.VerifyToken(oldToken); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if _, ok := keys.AcceptedUntil("key-1"); ok {
+		t.Fatalf("expected key-1 to be removed")
+	}
+
+	if _, err = verifier.VerifyToken(newToken); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = keys.Retire("key-2", time.Hour); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	if err = keys.Retire("key-1", time.Hour); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	keys.Rotate("key-3", oldSecret, oldSecret)
+	if err = keys.Retire("key-2", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(newToken); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	// Tokens without a "kid" header are rejected.
+	token, err := Sign(HS256, oldSecret, Map{"sub": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+}
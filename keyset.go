@@ -0,0 +1,96 @@
+package jwt
+
+import "context"
+
+// Key is a key pair of a `Keys` set, with its own algorithm.
+type Key struct {
+	Alg     Alg
+	Private PrivateKey // The signing key, optional for verification-only use.
+	Public  PublicKey  // The verification key, optional for signing-only use.
+}
+
+// Keys is a set of keys by their key id (the "kid" header), each one of its own algorithm.
+// It signs tokens with the "kid" header of the chosen key and it verifies tokens
+// by the key of their "kid" header, so the tokens of the previous keys
+// keep verifying while the new ones are signed by a new key.
+// Tokens of unknown or missing "kid" are rejected with ErrUnknownKid.
+//
+// A Keys map should not be modified while it's in use,
+// see `KeyRing` for rotation at runtime.
+//
+// Usage:
+//  keys := jwt.Keys{
+//    "2023": {Alg: jwt.HS256, Private: oldSecret, Public: oldSecret},
+//    "2024": {Alg: jwt.EdDSA, Private: privateKey, Public: publicKey},
+//  }
+//
+//  token, err := keys.Sign("2024", claims, jwt.MaxAge(15*time.Minute))
+//  verifiedToken, err := keys.Verify(token)
+type Keys map[string]*Key
+
+var _ KeyProvider = (Keys)(nil)
+
+// Sign signs the "claims" by the "kid" key and sets the "kid" header.
+// Returns ErrUnknownKid if the key does not exist and ErrInvalidKey if it has no private key.
+func (keys Keys) Sign(kid string, claims interface{}, opts ...SignOption) ([]byte, error) {
+	key, ok := keys[kid]
+	if !ok || kid == "" {
+		return nil, ErrUnknownKid
+	}
+
+	if key.Alg == nil || key.Private == nil {
+		return nil, ErrInvalidKey
+	}
+
+	return signEncrypted(key.Alg, key.Private, kid, nil, nil, claims, opts...)
+}
+
+// Verify verifies the "token" by the key and the algorithm of its "kid" header.
+// The "validators" are the same as the `Verify` function accepts.
+func (keys Keys) Verify(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return keys.VerifyContext(context.Background(), token, validators...)
+}
+
+// VerifyContext same as `Verify` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+func (keys Keys) VerifyContext(ctx context.Context, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	if len(token) == 0 {
+		recordVerification(token, ErrMissing)
+		return nil, ErrMissing
+	}
+
+	kid, err := tokenKeyID(token)
+	if err != nil {
+		recordVerification(token, err)
+		return nil, err
+	}
+
+	key, ok := keys[kid]
+	if !ok || kid == "" {
+		recordVerification(token, ErrUnknownKid)
+		return nil, ErrUnknownKid
+	}
+
+	if key.Alg == nil || key.Public == nil {
+		recordVerification(token, ErrInvalidKey)
+		return nil, ErrInvalidKey
+	}
+
+	return VerifyContext(ctx, key.Alg, key.Public, token, validators...)
+}
+
+// PublicKey completes the KeyProvider interface,
+// so a `Verifier` of a single algorithm can use the Keys too.
+// Returns ErrUnknownKid if the "kid" key does not exist.
+func (keys Keys) PublicKey(_ context.Context, kid string) (PublicKey, error) {
+	key, ok := keys[kid]
+	if !ok || kid == "" {
+		return nil, ErrUnknownKid
+	}
+
+	if key.Public == nil {
+		return nil, ErrInvalidKey
+	}
+
+	return key.Public, nil
+}
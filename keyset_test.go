@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeys(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	keys := Keys{
+		"old":         {Alg: HS256, Private: testSecret, Public: testSecret},
+		"new":         {Alg: EdDSA, Private: privateKey, Public: publicKey},
+		"verify-only": {Alg: HS256, Public: testSecret},
+	}
+
+	claims := map[string]interface{}{"username": "kataras"}
+	for _, kid := range []string{"old", "new"} {
+		token, err := keys.Sign(kid, claims, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := keys.Verify(token)
+		if err != nil {
+			t.Fatalf("[%s] %v", kid, err)
+		}
+
+		if got, err := tokenKeyID(verifiedToken.Token); err != nil || got != kid {
+			t.Fatalf("expected kid: %q but got: %q (%v)", kid, got, err)
+		}
+	}
+
+	if _, err := keys.Sign("verify-only", claims); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	if _, err := keys.Sign("unknown", claims); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	// Tokens of unknown or missing kid are rejected.
+	unknown, err := Keys{"unknown": {Alg: HS256, Private: testSecret}}.Sign("unknown", claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withoutKid, err := Sign(HS256, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range [][]byte{unknown, withoutKid} {
+		if _, err = keys.Verify(token); !errors.Is(err, ErrUnknownKid) {
+			t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+		}
+	}
+
+	// The "alg" header should match the key's algorithm.
+	forged, err := SignWithHeader(HS256, testSecret, Header{{"alg", "HS256"}, {"kid", "new"}}, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keys.Verify(forged); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+}
+
+func TestKeysKeyProvider(t *testing.T) {
+	keys := Keys{"key-1": {Alg: HS256, Private: testSecret, Public: testSecret}}
+
+	verifier := NewVerifier(HS256, nil)
+	verifier.KeyProvider = keys
+
+	token, err := keys.Sign("key-1", Claims{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// KeyEnvelopeVersion is the version of the envelopes the `WrapPrivateKey` function generates.
+const KeyEnvelopeVersion = 1
+
+// ErrKeyEnvelope indicates a malformed, unsupported or tampered private key envelope
+// or one which was wrapped by an unknown key encryption key.
+var ErrKeyEnvelope = errors.New("key envelope: invalid envelope")
+
+// KEK is a key encryption key, the AES key (16, 24 or 32 bytes)
+// which wraps private signing keys for storage, see `WrapPrivateKey`.
+// The ID is stored (not encrypted) in the envelope so a KEK can be rotated:
+// envelopes of the previous KEK are still unwrapped when both are passed to `UnwrapPrivateKey`.
+type KEK struct {
+	ID  string
+	Key []byte
+}
+
+// The envelope key types.
+const (
+	envelopeTypePKCS8 = "pkcs8" // RSA, ECDSA and EdDSA keys.
+	envelopeTypeOct   = "oct"   // HMAC shared keys.
+)
+
+// keyEnvelope is the JSON form of a wrapped private key.
+type keyEnvelope struct {
+	Version    int    `json:"v"`
+	KEK        string `json:"kek,omitempty"`
+	Type       string `json:"typ"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ct"`
+}
+
+// additionalData returns the authenticated (but not encrypted) data of the envelope.
+func (e *keyEnvelope) additionalData() []byte {
+	return []byte(strconv.Itoa(e.Version) + "." + e.KEK + "." + e.Type)
+}
+
+// WrapPrivateKey encrypts a private signing key (*rsa.PrivateKey, *ecdsa.PrivateKey,
+// ed25519.PrivateKey or a []byte HMAC key) with the "kek" through AES-GCM
+// and returns a versioned JSON envelope which can be stored in configuration stores
+// instead of a plaintext key. Use `UnwrapPrivateKey` to get the key back.
+//
+// Usage:
+//  kek := jwt.KEK{ID: "2024-01", Key: kekBytes}
+//  envelope, err := jwt.WrapPrivateKey(kek, privateKey)
+//  [store envelope...]
+//  privateKey, err := jwt.UnwrapPrivateKey(envelope, kek)
+func WrapPrivateKey(kek KEK, key PrivateKey) ([]byte, error) {
+	var (
+		typ       string
+		plaintext []byte
+	)
+
+	switch k := key.(type) {
+	case []byte:
+		if len(k) == 0 {
+			return nil, ErrInvalidKey
+		}
+		typ, plaintext = envelopeTypeOct, k
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		typ, plaintext = envelopeTypePKCS8, der
+	default:
+		return nil, ErrInvalidKey
+	}
+
+	gcm, err := newKEKCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(Random, nonce); err != nil {
+		return nil, err
+	}
+
+	envelope := keyEnvelope{
+		Version: KeyEnvelopeVersion,
+		KEK:     kek.ID,
+		Type:    typ,
+		Nonce:   BytesToString(Base64Encode(nonce)),
+	}
+	ciphertext := gcm.Seal(nil, nonce, plaintext, envelope.additionalData())
+	envelope.Ciphertext = BytesToString(Base64Encode(ciphertext))
+
+	return json.Marshal(envelope)
+}
+
+// UnwrapPrivateKey decrypts an envelope generated by `WrapPrivateKey`.
+// The KEK is selected by the envelope's KEK ID, so all the KEKs
+// which are still in use (e.g. during a rotation) can be passed.
+// It returns an `ErrKeyEnvelope` error when the envelope is malformed,
+// its KEK is missing or its contents were tampered.
+func UnwrapPrivateKey(envelope []byte, keks ...KEK) (PrivateKey, error) {
+	var e keyEnvelope
+	if err := json.Unmarshal(envelope, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyEnvelope, err)
+	}
+
+	if e.Version != KeyEnvelopeVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrKeyEnvelope, e.Version)
+	}
+
+	var kek *KEK
+	for i := range keks {
+		if keks[i].ID == e.KEK {
+			kek = &keks[i]
+			break
+		}
+	}
+
+	if kek == nil {
+		return nil, fmt.Errorf("%w: unknown key encryption key %q", ErrKeyEnvelope, e.KEK)
+	}
+
+	gcm, err := newKEKCipher(*kek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := Base64Decode([]byte(e.Nonce))
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: malformed nonce", ErrKeyEnvelope)
+	}
+
+	ciphertext, err := Base64Decode([]byte(e.Ciphertext))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed ciphertext", ErrKeyEnvelope)
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, e.additionalData())
+	if err != nil {
+		return nil, fmt.Errorf("%w: authentication failed", ErrKeyEnvelope)
+	}
+
+	switch e.Type {
+	case envelopeTypeOct:
+		return plaintext, nil
+	case envelopeTypePKCS8:
+		key, err := x509.ParsePKCS8PrivateKey(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrKeyEnvelope, err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %q", ErrKeyEnvelope, e.Type)
+	}
+}
+
+func newKEKCipher(kek KEK) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(kek.Key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key encryption key: %v", ErrInvalidKey, err)
+	}
+
+	return cipher.NewGCM(block)
+}
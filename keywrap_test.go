@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWrapPrivateKey(t *testing.T) {
+	kek := KEK{ID: "kek-1", Key: MustGenerateRandom(32)}
+	rsaPrivateKey, rsaPublicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	ecdsaPrivateKey, ecdsaPublicKey := MustLoadECDSA("./_testfiles/ecdsa_private_key.pem", "./_testfiles/ecdsa_public_key.pem")
+	edPrivateKey, edPublicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+	sharedKey := MustGenerateRandom(32)
+
+	tests := []struct {
+		alg        Alg
+		privateKey PrivateKey
+		publicKey  PublicKey
+	}{
+		{HS256, sharedKey, sharedKey},
+		{RS256, rsaPrivateKey, rsaPublicKey},
+		{ES256, ecdsaPrivateKey, ecdsaPublicKey},
+		{EdDSA, edPrivateKey, edPublicKey},
+	}
+
+	for _, tt := range tests {
+		envelope, err := WrapPrivateKey(kek, tt.privateKey)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.alg.Name(), err)
+		}
+
+		key, err := UnwrapPrivateKey(envelope, kek)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.alg.Name(), err)
+		}
+
+		if b, ok := tt.privateKey.([]byte); ok && !bytes.Equal(key.([]byte), b) {
+			t.Fatalf("%s: unwrapped key does not match", tt.alg.Name())
+		}
+
+		testEncodeDecodeToken(t, tt.alg, key, tt.publicKey, nil)
+	}
+
+	if _, err := WrapPrivateKey(kek, "not a key"); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	if _, err := WrapPrivateKey(KEK{Key: []byte("short")}, sharedKey); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
+
+func TestUnwrapPrivateKeyRotation(t *testing.T) {
+	oldKEK := KEK{ID: "kek-1", Key: MustGenerateRandom(32)}
+	newKEK := KEK{ID: "kek-2", Key: MustGenerateRandom(16)}
+	sharedKey := MustGenerateRandom(32)
+
+	envelope, err := WrapPrivateKey(oldKEK, sharedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = UnwrapPrivateKey(envelope, newKEK); !errors.Is(err, ErrKeyEnvelope) {
+		t.Fatalf("expected error: %v but got: %v", ErrKeyEnvelope, err)
+	}
+
+	key, err := UnwrapPrivateKey(envelope, newKEK, oldKEK)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(key.([]byte), sharedKey) {
+		t.Fatalf("unwrapped key does not match")
+	}
+}
+
+func TestUnwrapPrivateKeyTampered(t *testing.T) {
+	kek := KEK{ID: "kek-1", Key: MustGenerateRandom(32)}
+	envelope, err := WrapPrivateKey(kek, MustGenerateRandom(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := func(fn func(e map[string]interface{})) []byte {
+		var e map[string]interface{}
+		if err := json.Unmarshal(envelope, &e); err != nil {
+			t.Fatal(err)
+		}
+		fn(e)
+		b, _ := json.Marshal(e)
+		return b
+	}
+
+	tests := [][]byte{
+		[]byte("{"),
+		tamper(func(e map[string]interface{}) { e["v"] = 2 }),
+		// The key type is authenticated.
+		tamper(func(e map[string]interface{}) { e["typ"] = "pkcs8" }),
+		tamper(func(e map[string]interface{}) { e["nonce"] = "AAAA" }),
+		tamper(func(e map[string]interface{}) { e["ct"] = e["nonce"] }),
+	}
+
+	for i, tt := range tests {
+		if _, err := UnwrapPrivateKey(tt, kek); !errors.Is(err, ErrKeyEnvelope) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrKeyEnvelope, err)
+		}
+	}
+}
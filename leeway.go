@@ -1,6 +1,10 @@
 package jwt
 
-import "time"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 // Leeway adds validation for a leeway expiration time.
 // If the token was not expired then a comparison between
@@ -18,3 +22,54 @@ func Leeway(leeway time.Duration) TokenValidatorFunc {
 		return err
 	}
 }
+
+// Tolerance adds a clock skew tolerance to the builtin validation of the time claims:
+// tokens which expired (or become valid, or were issued) less than "skew" ago
+// (or from now) pass, e.g. when the issuer's clock is a few seconds ahead.
+// Note that it's the opposite of the `Leeway`, which disallows tokens early.
+// It should be the first validator, so the next ones see the tolerated result,
+// see the `Verifier.Tolerance` field too.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.Tolerance(30*time.Second), jwt.Expected{...})
+func Tolerance(skew time.Duration) TokenValidatorFunc {
+	return func(_ []byte, standardClaims Claims, err error) error {
+		if err != ErrExpired && err != ErrNotValidYet && err != ErrIssuedInTheFuture {
+			return err
+		}
+
+		now := Clock()
+		notYet := standardClaims
+		notYet.Expiry = 0
+		if err = validateClaims(now.Add(skew), notYet); err != nil {
+			return err
+		}
+
+		return validateClaims(now.Add(-skew), Claims{Expiry: standardClaims.Expiry})
+	}
+}
+
+// ExpiryGrace is a ContextValidator which accepts the tokens which expired less than "grace" ago,
+// on the idempotent GET and HEAD requests only, so the SPAs' page loads and polls do not fail
+// while their tokens are refreshed during a rolling deploy. Writes still require a valid token.
+// The request's method is retrieved from the Context (see `ClientInfoFromRequest`
+// and `Verifier.VerifyRequest`), any other verification is not affected.
+// It should be one of the first validators, right after the `Tolerance`,
+// so the next ones see the accepted result, see the `Verifier.ExpiryGrace` field too.
+//
+// Usage:
+//  verifier.ExpiryGrace = 30 * time.Second
+func ExpiryGrace(grace time.Duration) ContextValidatorFunc {
+	return func(ctx context.Context, _ []byte, standardClaims Claims, err error) error {
+		if err != ErrExpired {
+			return err
+		}
+
+		info, ok := GetClientInfo(ctx)
+		if !ok || (info.Method != http.MethodGet && info.Method != http.MethodHead) {
+			return err
+		}
+
+		return validateClaims(Clock().Add(-grace), Claims{Expiry: standardClaims.Expiry})
+	}
+}
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -20,3 +22,85 @@ func TestLeeway(t *testing.T) {
 		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
 	}
 }
+
+func TestTolerance(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		claims Claims
+		err    error
+	}{
+		{Claims{Expiry: now.Add(-20 * time.Second).Unix()}, nil},
+		{Claims{Expiry: now.Add(-time.Minute).Unix()}, ErrExpired},
+		{Claims{NotBefore: now.Add(20 * time.Second).Unix()}, nil},
+		{Claims{NotBefore: now.Add(time.Minute).Unix()}, ErrNotValidYet},
+		{Claims{IssuedAt: now.Add(20 * time.Second).Unix(), Expiry: now.Add(time.Hour).Unix()}, nil},
+		{Claims{IssuedAt: now.Add(time.Minute).Unix()}, ErrIssuedInTheFuture},
+	}
+
+	v := NewVerifier(testAlg, testSecret)
+	v.Tolerance = 30 * time.Second
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token); err == nil {
+			t.Fatalf("[%d] expected an error without tolerance", i)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, Tolerance(30*time.Second)); err != tt.err {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+
+		if _, err = v.VerifyToken(token); err != tt.err {
+			t.Fatalf("[%d] verifier: expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+
+	// Test respect other errors.
+	if err := Tolerance(time.Minute).ValidateToken(nil, Claims{}, ErrInvalidKey); err != ErrInvalidKey {
+		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
+	}
+}
+
+func TestExpiryGrace(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		method string
+		expiry time.Time
+		err    error
+	}{
+		{http.MethodGet, now.Add(-20 * time.Second), nil},
+		{http.MethodHead, now.Add(-20 * time.Second), nil},
+		{http.MethodGet, now.Add(-time.Minute), ErrExpired},
+		{http.MethodPost, now.Add(-20 * time.Second), ErrExpired},
+		{http.MethodDelete, now.Add(-20 * time.Second), ErrExpired},
+		{http.MethodPost, now.Add(time.Minute), nil},
+	}
+
+	v := NewVerifier(testAlg, testSecret)
+	v.ExpiryGrace = 30 * time.Second
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, Claims{Expiry: tt.expiry.Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := httptest.NewRequest(tt.method, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+string(token))
+		if _, err = v.VerifyRequest(r); err != tt.err {
+			t.Fatalf("[%d] %s: expected error: %v but got: %v", i, tt.method, tt.err, err)
+		}
+	}
+
+	// Test without a request.
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: now.Add(-20 * time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = v.VerifyToken(token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+}
@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrRunning indicates that the Run (or Start) method of a background component
+// was called while its goroutine is still running.
+var ErrRunning = errors.New("already running")
+
+// runner manages the single background goroutine of a component, see the Run and Close
+// methods of the `Blocklist`, `BloomBlocklist`, `JWKSClient` and `Renewer`.
+type runner struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start calls the "loop" in a new goroutine with a context which is cancelled
+// on "ctx" cancellation or on `stop`. The loop should return when its context is done.
+func (r *runner) start(ctx context.Context, loop func(ctx context.Context)) (<-chan struct{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done != nil {
+		return nil, ErrRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	r.cancel, r.done = cancel, done
+
+	go func() {
+		defer close(done)
+		defer cancel()
+
+		loop(ctx)
+
+		r.mu.Lock()
+		r.cancel, r.done = nil, nil
+		r.mu.Unlock()
+	}()
+
+	return done, nil
+}
+
+// run same as start but it blocks until the loop returns.
+// Returns the "ctx" error or nil when it was stopped.
+func (r *runner) run(ctx context.Context, loop func(ctx context.Context)) error {
+	done, err := r.start(ctx, loop)
+	if err != nil {
+		return err
+	}
+
+	<-done
+	return ctx.Err()
+}
+
+// stop cancels the running loop, if any, and waits for it to return.
+func (r *runner) stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
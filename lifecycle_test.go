@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunner(t *testing.T) {
+	var r runner
+	started := make(chan struct{})
+	loop := func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.run(context.Background(), loop) }()
+	<-started
+
+	if _, err := r.start(context.Background(), loop); err != ErrRunning {
+		t.Fatalf("expected error: %v but got: %v", ErrRunning, err)
+	}
+
+	r.stop()
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected a nil error on stop but got: %v", err)
+	}
+
+	// Cancellation and a next run.
+	ctx, cancel := context.WithCancel(context.Background())
+	started = make(chan struct{})
+	go func() { errCh <- r.run(ctx, loop) }()
+	<-started
+	cancel()
+
+	if err := <-errCh; err != context.Canceled {
+		t.Fatalf("expected error: %v but got: %v", context.Canceled, err)
+	}
+
+	r.stop() // no-op.
+}
+
+func TestBlocklistClose(t *testing.T) {
+	b := NewBlocklist(10 * time.Millisecond)
+	if err := b.Run(context.Background()); err != ErrRunning {
+		t.Fatalf("expected error: %v but got: %v", ErrRunning, err)
+	}
+
+	b.Close()
+	b.InvalidateToken([]byte("expired"), Claims{Expiry: 1})
+	time.Sleep(50 * time.Millisecond)
+
+	if got, _ := b.Count(); got != 1 {
+		t.Fatalf("expected no GC after Close but got: %d entries", got)
+	}
+}
+
+func TestRenewerClose(t *testing.T) {
+	var calls uint32
+	renewer := NewRenewer(func(ctx context.Context) ([]byte, error) {
+		atomic.AddUint32(&calls, 1)
+		return []byte("token"), nil
+	})
+	renewer.RetryInterval = 5 * time.Millisecond // the minimum time between renewals.
+	renewer.Expiry = func(token []byte) (time.Time, error) {
+		return Clock().Add(10 * time.Millisecond), nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- renewer.Run(context.Background()) }()
+
+	time.Sleep(50 * time.Millisecond)
+	renewer.Close()
+
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	n := atomic.LoadUint32(&calls)
+	if n < 2 {
+		t.Fatalf("expected background renewals but got: %d call(s)", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := atomic.LoadUint32(&calls); got != n {
+		t.Fatalf("expected no renewals after Close but got: %d", got-n)
+	}
+}
+
+func TestJWKSClientRun(t *testing.T) {
+	set := testJWKS(t)
+	var requests uint32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&requests, 1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	c := NewJWKSClient(srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.Run(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for c.Set() == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Served by the background refresh.
+	if _, err := c.PublicKey(ctx, "rsa"); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if err := <-errCh; err != context.Canceled {
+		t.Fatalf("expected error: %v but got: %v", context.Canceled, err)
+	}
+
+	if got := atomic.LoadUint32(&requests); got != 1 {
+		t.Fatalf("expected a single request but got: %d", got)
+	}
+}
@@ -0,0 +1,113 @@
+package jwt
+
+import "sync"
+
+// ClaimParent is the name of the claim which holds the "jti" of the token a token was derived from,
+// see the `Issuer.Lineage` field.
+const ClaimParent = "prt"
+
+// defaultLineageEntries is the default maximum number of entries of a `LineageLog`.
+const defaultLineageEntries = 10000
+
+// withLineage returns a copy of the "customClaims" with a random "jti" claim
+// and the "prt" claim of the "parentID", if it's not empty.
+func withLineage(customClaims interface{}, parentID string) (interface{}, error) {
+	claims, err := claimsMap(customClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := make(Map, len(claims)+2)
+	for k, v := range claims {
+		lineage[k] = v
+	}
+
+	lineage["jti"] = string(Base64Encode(MustGenerateRandom(16)))
+	if parentID != "" {
+		lineage[ClaimParent] = parentID
+	} else {
+		delete(lineage, ClaimParent)
+	}
+
+	return lineage, nil
+}
+
+// LineageLog is a bounded in-memory index of the issued tokens by their "jti" claim,
+// which walks the lineage of a token (see the `Issuer.Lineage` field)
+// back to its origin session, e.g. for a leaked token which was found in the logs.
+// It's fed by the `Audit` hook, the "jti" and "prt" claims must not be listed in the `RedactedClaims`.
+// A LineageLog is safe for concurrent use.
+//
+// Usage:
+//  lineage := jwt.NewLineageLog(0)
+//  jwt.Audit = lineage.Record
+//
+//  for _, entry := range lineage.Trace(leakedTokenID) {
+//    log.Printf("jti=%s prt=%s sub=%s iat=%d", entry.ID, entry.Parent, entry.Subject, entry.IssuedAt)
+//  }
+type LineageLog struct {
+	// MaxEntries is the maximum number of recorded tokens,
+	// the least recently used one is evicted on a full log. Defaults to 10000.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries *lru[string, AuditEntry]
+}
+
+// NewLineageLog returns a new LineageLog of "maxEntries" maximum recorded tokens.
+// The rest of the LineageLog fields can be modified before its first use.
+func NewLineageLog(maxEntries int) *LineageLog {
+	return &LineageLog{MaxEntries: maxEntries}
+}
+
+// Record records the issued token of the "entry", it can be set as the `Audit` hook.
+// The tokens without a "jti" claim are ignored.
+func (l *LineageLog) Record(entry AuditEntry) {
+	if entry.ID == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.entries == nil {
+		maxEntries := l.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultLineageEntries
+		}
+		l.entries = newLRU[string, AuditEntry](maxEntries)
+	}
+
+	l.entries.set(entry.ID, entry)
+}
+
+// Trace returns the lineage of the token of the "id" ("jti" claim): its entry first,
+// then the entry of its parent and so on, up to its origin (a token without a "prt" claim)
+// or to the first token which is not recorded. Returns nil if the "id" is not recorded.
+func (l *LineageLog) Trace(id string) []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.entries == nil {
+		return nil
+	}
+
+	var lineage []AuditEntry
+	seen := make(map[string]struct{})
+	for id != "" {
+		if _, ok := seen[id]; ok {
+			break // a forged cycle.
+		}
+		seen[id] = struct{}{}
+
+		entry, ok := l.entries.get(id)
+		if !ok {
+			break
+		}
+
+		lineage = append(lineage, entry)
+		id = entry.Parent
+	}
+
+	return lineage
+}
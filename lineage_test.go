@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIssuerLineage(t *testing.T) {
+	lineage := NewLineageLog(0)
+	Audit = lineage.Record
+	defer func() { Audit = nil }()
+
+	ctx := context.Background()
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.RefreshMaxAge = time.Hour
+	issuer.RefreshReplays = NewReplayStore(0)
+	issuer.Lineage = true
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.Blocklist = NewBlocklist(0)
+
+	pair, err := issuer.TokenPair(ctx, "kataras", Map{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, refreshToken := pair.Tokens()
+
+	pair, err = issuer.RefreshPair(ctx, verifier, refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, refreshToken = pair.Tokens()
+
+	accessToken, err := issuer.Refresh(ctx, verifier, refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gateway := NewIssuer(testAlg, testSecret, time.Minute)
+	gateway.Lineage = true
+	outbound, err := NewTokenExchange(verifier, ForwardClaims("role"), gateway).Exchange(ctx, accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, outbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// outbound -> access -> rotated refresh -> origin refresh.
+	trace := lineage.Trace(verifiedToken.StandardClaims.ID)
+	if len(trace) != 4 {
+		t.Fatalf("expected a lineage of 4 tokens but got: %#+v", trace)
+	}
+
+	for i, entry := range trace {
+		if entry.Subject != "kataras" {
+			t.Fatalf("[%d] expected subject: kataras but got: %q", i, entry.Subject)
+		}
+
+		if last := i == len(trace)-1; last != (entry.Parent == "") {
+			t.Fatalf("[%d] unexpected parent: %q", i, entry.Parent)
+		}
+	}
+
+	if origin, accessTokens := trace[3].Expiry-trace[3].IssuedAt, trace[1].Expiry-trace[1].IssuedAt; origin != 3600 || accessTokens != 60 {
+		t.Fatalf("expected the origin to be a refresh token but got lifetimes: %d and %d", origin, accessTokens)
+	}
+
+	if trace := lineage.Trace("unknown"); trace != nil {
+		t.Fatalf("expected no lineage but got: %#+v", trace)
+	}
+}
+
+func TestIssuerLineageDisabled(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	refreshToken, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "jti": "refresh-1", ClaimTokenUse: TokenUseRefresh}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken, err := issuer.Refresh(context.Background(), NewVerifier(testAlg, testSecret), refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if view := verifiedToken.View(); view.Has(ClaimParent) || view.Has("jti") {
+		t.Fatalf("expected no lineage claims but got: %s", verifiedToken.Payload)
+	}
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import "container/list"
+
+// lru is a least recently used cache of at most "max" entries,
+// it's not safe for concurrent use.
+type lru[K comparable, V any] struct {
+	max   int
+	order *list.List // front is the most recently used.
+	items map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRU[K comparable, V any](max int) *lru[K, V] {
+	return &lru[K, V]{
+		max:   max,
+		order: list.New(),
+		items: make(map[K]*list.Element),
+	}
+}
+
+// get returns the value of the "key" and marks it as the most recently used.
+func (c *lru[K, V]) get(key K) (V, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// set adds or replaces the value of the "key",
+// it evicts the least recently used entry when the cache is full.
+func (c *lru[K, V]) set(key K, value V) {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.max > 0 && c.order.Len() >= c.max {
+		c.remove(c.order.Back().Value.(*lruEntry[K, V]).key)
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+}
+
+func (c *lru[K, V]) remove(key K) {
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+func (c *lru[K, V]) len() int {
+	return c.order.Len()
+}
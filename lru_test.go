@@ -0,0 +1,33 @@
+package jwt
+
+import "testing"
+
+func TestLRU(t *testing.T) {
+	c := newLRU[string, int](2)
+	c.set("a", 1)
+	c.set("b", 2)
+
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1 but got: %d (%v)", v, ok)
+	}
+
+	// "b" is the least recently used one.
+	c.set("c", 3)
+	if _, ok := c.get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+
+	if c.len() != 2 {
+		t.Fatalf("expected 2 entries but got: %d", c.len())
+	}
+
+	c.set("a", 10)
+	if v, _ := c.get("a"); v != 10 {
+		t.Fatalf("expected a=10 but got: %d", v)
+	}
+
+	c.remove("a")
+	if _, ok := c.get("a"); ok || c.len() != 1 {
+		t.Fatalf("expected a to be removed")
+	}
+}
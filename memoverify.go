@@ -0,0 +1,196 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"hash"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// defaultMemoEntries is the default maximum number of memoized verifications of a MemoVerifier.
+const defaultMemoEntries = 10000
+
+// MemoVerifier is an AlgVerifier which memoizes the successful signature verifications
+// of an asymmetric algorithm (e.g. RS256) by the hash of the public key, the signed header.payload and the signature,
+// so high-RPS services which receive the same token thousands of times per minute verify its signature once.
+// A memoized verification expires after the MaxAge or at the token's "exp" claim, whichever comes first.
+// The claims are still validated on every verification, the failed verifications are never memoized
+// (see `NegativeCache`) and the keys of an unknown type (e.g. HMAC secrets) are not memoized.
+// A MemoVerifier is safe for concurrent use.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.NewMemoVerifier(jwt.RS256), publicKey)
+type MemoVerifier struct {
+	// Alg is the memoized algorithm, required.
+	Alg AlgVerifier
+	// MaxAge is the maximum duration a verification is memoized for. Defaults to 1 minute.
+	MaxAge time.Duration
+	// MaxEntries is the maximum number of memoized verifications,
+	// the least recently used one is evicted on a full cache. Defaults to 10000.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries *lru[[sha256.Size]byte, time.Time] // the expiration of the verification.
+}
+
+var _ AlgVerifier = (*MemoVerifier)(nil)
+
+// NewMemoVerifier returns a new MemoVerifier of the "alg" algorithm.
+// The rest of the MemoVerifier fields can be modified before its first use.
+func NewMemoVerifier(alg AlgVerifier) *MemoVerifier {
+	return &MemoVerifier{
+		Alg:        alg,
+		MaxAge:     time.Minute,
+		MaxEntries: defaultMemoEntries,
+	}
+}
+
+// Name completes the AlgVerifier interface, it returns the name of the memoized algorithm.
+func (m *MemoVerifier) Name() string {
+	return m.Alg.Name()
+}
+
+// Verify completes the AlgVerifier interface. It verifies the signature through the memoized algorithm
+// unless the same signature of the same header.payload is already verified by the same key.
+func (m *MemoVerifier) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
+	h := sha256.New()
+	if !writeKeyFingerprint(h, key) {
+		return m.Alg.Verify(key, headerAndPayload, signature)
+	}
+	h.Write(headerAndPayload)
+	h.Write(sep) // separate the signature from the payload.
+	h.Write(signature)
+
+	var memoKey [sha256.Size]byte
+	h.Sum(memoKey[:0])
+
+	now := Clock()
+	if m.verified(memoKey, now) {
+		return nil
+	}
+
+	if err := m.Alg.Verify(key, headerAndPayload, signature); err != nil {
+		return err
+	}
+
+	maxAge := m.MaxAge
+	if maxAge <= 0 {
+		maxAge = time.Minute
+	}
+
+	expiresAt := now.Add(maxAge)
+	if exp := payloadExpiry(headerAndPayload); exp > 0 && time.Unix(exp, 0).Before(expiresAt) {
+		expiresAt = time.Unix(exp, 0)
+	}
+
+	if now.Before(expiresAt) {
+		m.store(memoKey, expiresAt)
+	}
+
+	return nil
+}
+
+func (m *MemoVerifier) verified(key [sha256.Size]byte, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		return false
+	}
+
+	expiresAt, ok := m.entries.get(key)
+	if !ok {
+		return false
+	}
+
+	if !now.Before(expiresAt) {
+		m.entries.remove(key)
+		return false
+	}
+
+	return true
+}
+
+func (m *MemoVerifier) store(key [sha256.Size]byte, expiresAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		maxEntries := m.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultMemoEntries
+		}
+		m.entries = newLRU[[sha256.Size]byte, time.Time](maxEntries)
+	}
+
+	m.entries.set(key, expiresAt)
+}
+
+// Invalidate completes the CacheInvalidator interface, it drops the memoized verifications
+// on an InvalidateKeys event. The revocations do not affect them, the claims are validated on every verification.
+func (m *MemoVerifier) Invalidate(event Invalidation) {
+	if event.Kind != InvalidateKeys {
+		return
+	}
+
+	m.mu.Lock()
+	m.entries = nil
+	m.mu.Unlock()
+}
+
+// writeKeyFingerprint writes the public key's material to the "h" hash,
+// it reports false if the key's type is not of an asymmetric algorithm.
+func writeKeyFingerprint(h hash.Hash, key PublicKey) bool {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return writeKeyFingerprint(h, &k.PublicKey)
+	case *rsa.PublicKey:
+		h.Write([]byte("rsa"))
+		h.Write(k.N.Bytes())
+		h.Write(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PrivateKey:
+		return writeKeyFingerprint(h, &k.PublicKey)
+	case *ecdsa.PublicKey:
+		h.Write([]byte(k.Curve.Params().Name))
+		h.Write(k.X.Bytes())
+		h.Write(sep)
+		h.Write(k.Y.Bytes())
+	case ed25519.PublicKey:
+		h.Write([]byte("ed25519"))
+		h.Write(k)
+	default:
+		return false
+	}
+
+	h.Write(sep)
+	return true
+}
+
+// payloadExpiry returns the "exp" claim of the signed header.payload, zero if it can't be decoded.
+func payloadExpiry(headerAndPayload []byte) int64 {
+	i := bytes.IndexByte(headerAndPayload, '.')
+	if i == -1 {
+		return 0
+	}
+
+	payload, err := Base64Decode(headerAndPayload[i+1:])
+	if err != nil {
+		return 0
+	}
+
+	var claims struct {
+		Expiry json.Number `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil {
+		return 0 // e.g. an encrypted payload.
+	}
+
+	exp, _ := claims.Expiry.Float64()
+	return int64(exp)
+}
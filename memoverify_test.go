@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoVerifier(t *testing.T) {
+	defer func() { Clock = time.Now }()
+	now := time.Now()
+	Clock = func() time.Time { return now }
+
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+
+	alg := &countingVerifier{AlgVerifier: RS256}
+	memo := NewMemoVerifier(alg)
+	memo.MaxAge = time.Hour
+	verifier := NewVerifier(memo, publicKey)
+
+	token, err := Sign(RS256, privateKey, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = verifier.VerifyToken(token); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+	}
+
+	if alg.calls != 1 {
+		t.Fatalf("expected a single signature verification but got: %d", alg.calls)
+	}
+
+	// Tampered signature, never memoized.
+	tampered := append([]byte(nil), token...)
+	if tampered[len(tampered)-2] == 'A' {
+		tampered[len(tampered)-2] = 'B'
+	} else {
+		tampered[len(tampered)-2] = 'A'
+	}
+	for i := 0; i < 2; i++ {
+		if _, err = verifier.VerifyToken(tampered); !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrTokenSignature, err)
+		}
+	}
+
+	if alg.calls != 3 {
+		t.Fatalf("expected the failed verifications to not be memoized but got: %d calls", alg.calls)
+	}
+
+	// The memoized verification is bounded by the token's expiration, not the MaxAge.
+	now = now.Add(2 * time.Minute)
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if alg.calls != 4 {
+		t.Fatalf("expected an expired memoized verification but got: %d calls", alg.calls)
+	}
+}
+
+func TestMemoVerifierUnknownKey(t *testing.T) {
+	alg := &countingVerifier{AlgVerifier: testAlg}
+	verifier := NewVerifier(NewMemoVerifier(alg), testSecret)
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = verifier.VerifyToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if alg.calls != 2 {
+		t.Fatalf("expected the HMAC verifications to not be memoized but got: %d calls", alg.calls)
+	}
+}
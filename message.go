@@ -0,0 +1,89 @@
+package jwt
+
+import "context"
+
+// MessageHeaders is the headers of a message of a queue or an event bus.
+// The NATS nats.Header (and the http.Header) implement it,
+// see `RecordHeaders` for Kafka records and `MessageHeadersFunc` for the rest of the clients.
+type MessageHeaders interface {
+	// Get returns the value of the "key" header, or an empty string.
+	Get(key string) string
+}
+
+// MessageHeadersFunc is a function which completes the MessageHeaders interface.
+type MessageHeadersFunc func(key string) string
+
+// Get completes the MessageHeaders interface.
+func (fn MessageHeadersFunc) Get(key string) string {
+	return fn(key)
+}
+
+// RecordHeader is a header of a Kafka record,
+// the record headers of the Kafka clients can be converted to it, e.g. jwt.RecordHeader(h).
+type RecordHeader struct {
+	Key   string
+	Value []byte
+}
+
+// RecordHeaders is a MessageHeaders of the (case-sensitive) Kafka record headers.
+//
+// Usage:
+//  headers := make(jwt.RecordHeaders, 0, len(msg.Headers))
+//  for _, h := range msg.Headers {
+//      headers = append(headers, jwt.RecordHeader(h))
+//  }
+type RecordHeaders []RecordHeader
+
+// Get completes the MessageHeaders interface.
+// It returns the value of the first "key" header.
+func (h RecordHeaders) Get(key string) string {
+	for _, header := range h {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// MessageExtractor is a function which extracts a token from the headers of a message.
+// It returns an empty string when the message does not carry a token.
+// See `FromMessageHeader` and the `Verifier.MessageExtractors` field.
+type MessageExtractor func(headers MessageHeaders) string
+
+// FromMessageHeader returns a MessageExtractor which reads the token
+// from the "name" message header, e.g. FromMessageHeader("Authorization").
+// An optional "Bearer" scheme prefix of the header value is removed, like `FromCustomHeader` does.
+func FromMessageHeader(name string) MessageExtractor {
+	return func(headers MessageHeaders) string {
+		return customHeaderToken(headers.Get(name))
+	}
+}
+
+// VerifyMessage extracts the token from the "headers" of a message through the Verifier's
+// MessageExtractors and verifies it, exactly like `VerifyTokenContext` does,
+// for event-driven authorization of the consumers.
+// Returns ErrMissing if the message does not carry a token.
+//
+// Usage:
+//  verifiedToken, err := verifier.VerifyMessage(ctx, msg.Header) // NATS
+func (v *Verifier) VerifyMessage(ctx context.Context, headers MessageHeaders, validators ...TokenValidator) (*VerifiedToken, error) {
+	return v.VerifyTokenContext(ctx, v.MessageToken(headers), validators...)
+}
+
+// MessageToken returns the token of the message "headers" based on the Verifier's MessageExtractors
+// or nil if the message does not carry any.
+func (v *Verifier) MessageToken(headers MessageHeaders) []byte {
+	extractors := v.MessageExtractors
+	if len(extractors) == 0 {
+		extractors = []MessageExtractor{FromMessageHeader("Authorization")}
+	}
+
+	for _, extract := range extractors {
+		if token := extract(headers); token != "" {
+			return []byte(token)
+		}
+	}
+
+	return nil
+}
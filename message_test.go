@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyMessage(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	ctx := context.Background()
+
+	var tests = []struct {
+		name    string
+		headers MessageHeaders
+	}{
+		{"kafka", RecordHeaders{{Key: "trace_id", Value: []byte("1")}, {Key: "Authorization", Value: append([]byte("Bearer "), token...)}}},
+		{"nats", http.Header{"Authorization": {string(token)}}}, // nats.Header is a map[string][]string too.
+		{"func", MessageHeadersFunc(func(key string) string { return "Bearer " + string(token) })},
+	}
+
+	for _, tt := range tests {
+		verifiedToken, err := verifier.VerifyMessage(ctx, tt.headers)
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+
+		if verifiedToken.StandardClaims.Subject != "kataras" {
+			t.Fatalf("[%s] expected subject: kataras but got: %q", tt.name, verifiedToken.StandardClaims.Subject)
+		}
+	}
+
+	if _, err = verifier.VerifyMessage(ctx, RecordHeaders{}); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+
+	// Kafka record headers are case-sensitive.
+	verifier.MessageExtractors = []MessageExtractor{FromMessageHeader("authorization")}
+	if _, err = verifier.VerifyMessage(ctx, tests[0].headers); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+}
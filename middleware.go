@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorHandler is a function which renders a verification error of the HTTP middleware,
+// see the `Verifier.Handler` method and the `DefaultErrorHandler` function.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// SuccessHandler is a post-verification hook of the HTTP middleware, see `Verifier.SuccessHandler`.
+// It can load the user's record, attach derived values to the request's Context
+// or reject the request based on the application's state (e.g. a disabled user).
+// It returns the request passed to the next handler, e.g. r.WithContext(ctx), or an error which
+// is rendered by the ErrorHandler. Wrap ErrPolicyDenied to respond with 403 by the DefaultErrorHandler.
+type SuccessHandler func(r *http.Request, verifiedToken *VerifiedToken) (*http.Request, error)
+
+type (
+	verifiedTokenContextKey struct{}
+	tokenSourceContextKey   struct{}
+)
+
+// WithVerifiedToken returns a copy of "ctx" which carries the verified token.
+// Use `GetVerifiedToken` to retrieve it.
+func WithVerifiedToken(ctx context.Context, verifiedToken *VerifiedToken) context.Context {
+	return context.WithValue(ctx, verifiedTokenContextKey{}, verifiedToken)
+}
+
+// GetVerifiedToken returns the verified token stored by the HTTP middleware
+// (see `Verifier.Handler`) or by `WithVerifiedToken`.
+// Reports false if the "ctx" does not carry any.
+func GetVerifiedToken(ctx context.Context) (*VerifiedToken, bool) {
+	verifiedToken, ok := ctx.Value(verifiedTokenContextKey{}).(*VerifiedToken)
+	return verifiedToken, ok && verifiedToken != nil
+}
+
+// GetTokenSource returns the name of the `TokenSource` which matched
+// the request's token on the HTTP middleware (see `Verifier.Sources`).
+// Returns an empty string if the Verifier has no Sources.
+func GetTokenSource(ctx context.Context) string {
+	source, _ := ctx.Value(tokenSourceContextKey{}).(string)
+	return source
+}
+
+// Handler returns an HTTP middleware which verifies the request's token
+// (see `VerifyRequest`) before the "next" handler.
+// The verified token (and the matched source name) is stored to the request's Context, the
+// handlers can retrieve it through the `GetVerifiedToken` (and `GetTokenSource`) function.
+// On success the Verifier's SuccessHandler, if any, is called before the "next" handler.
+// On verification failures the Verifier's ErrorHandler (or the `DefaultErrorHandler`)
+// is called instead of the "next" handler.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.HS256, sharedKey)
+//  http.Handle("/protected", verifier.Handler(protectedHandler))
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	return v.RouteHandler(next)
+}
+
+// RouteHandler same as `Handler` but it accepts route-specific options,
+// e.g. different audiences, scopes or optional authentication for a single route.
+//
+// Usage:
+//  mux.Handle("/invoices", verifier.RouteHandler(invoicesHandler, jwt.WithAudience("billing"), jwt.WithScopes("invoices:read")))
+//  mux.Handle("/", verifier.RouteHandler(indexHandler, jwt.OptionalAuth()))
+func (v *Verifier) RouteHandler(next http.Handler, opts ...RouteOption) http.Handler {
+	var route routeConfig
+	for _, opt := range opts {
+		opt(&route)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, source := v.RequestTokenSource(r)
+		if len(token) == 0 && route.optional {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		verifiedToken, err := v.verifyRequest(r, token, route.validators)
+		if err != nil {
+			v.handleError(w, r, err)
+			return
+		}
+
+		ctx := WithVerifiedToken(r.Context(), verifiedToken)
+		if source != "" {
+			ctx = context.WithValue(ctx, tokenSourceContextKey{}, source)
+		}
+		r = r.WithContext(ctx)
+		if v.SuccessHandler != nil {
+			enriched, err := v.SuccessHandler(r, verifiedToken)
+			if err != nil {
+				v.handleError(w, r, err)
+				return
+			}
+
+			r = enriched
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (v *Verifier) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if v.ErrorHandler != nil {
+		v.ErrorHandler(w, r, err)
+		return
+	}
+
+	DefaultErrorHandler(w, r, err)
+}
+
+// The error codes of the RFC 6750 section 3.1.
+const (
+	errorCodeInvalidRequest    = "invalid_request"
+	errorCodeInvalidToken      = "invalid_token"
+	errorCodeInsufficientScope = "insufficient_scope"
+	// RFC 9470 (step-up authentication).
+	errorCodeInsufficientAuth = "insufficient_user_authentication"
+)
+
+// DefaultErrorHandler is the default ErrorHandler of the HTTP middleware.
+// It follows the RFC 6750: it sets the "WWW-Authenticate: Bearer" response header,
+// along with the error code, and it responds with a JSON body, e.g.
+//  {"error": "invalid_token", "error_description": "token expired", "reason": "expired"}
+// The "reason" field is the machine-readable `FailureReason` of the error.
+// The status code is the `HTTPStatus` of the error.
+// Requests without a token are answered with 401 and no error code,
+// tokens denied by a policy (ErrPolicyDenied, ErrClientNotAllowed) with 403 "insufficient_scope",
+// tokens of unsupported header fields or claims version with 400 "invalid_request",
+// tokens of an insufficient authentication context (ErrInsufficientAuth) with 401 "insufficient_user_authentication",
+// unavailable keys or dependencies (ErrCircuitOpen, ErrNotReady, ErrDependencyUnavailable) with 503 and no error code
+// and any other verification failure with 401 "invalid_token".
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		statusCode = HTTPStatus(err)
+		code       = errorCodeInvalidToken
+		reason     = FailureReason(err)
+	)
+
+	switch {
+	case errors.Is(err, ErrMissing), statusCode == http.StatusServiceUnavailable:
+		code = ""
+	case statusCode == http.StatusForbidden:
+		code = errorCodeInsufficientScope
+	case statusCode == http.StatusBadRequest:
+		code = errorCodeInvalidRequest
+	case errors.Is(err, ErrInsufficientAuth):
+		code = errorCodeInsufficientAuth
+	}
+
+	// Do not expose the details of custom errors, they may contain sensitive information.
+	description := http.StatusText(statusCode)
+	if reason != "other" {
+		description = err.Error()
+	}
+
+	authenticate := "Bearer"
+	if code != "" {
+		authenticate += ` error="` + code + `", error_description="` + headerQuotedString(description) + `"`
+	}
+
+	w.Header().Set("WWW-Authenticate", authenticate)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if code == "" {
+		code = "unauthorized"
+		if statusCode == http.StatusServiceUnavailable {
+			code = "temporarily_unavailable"
+		}
+	}
+
+	json.NewEncoder(w).Encode(struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+		Reason      string `json:"reason"`
+	}{code, description, reason})
+}
+
+// headerQuotedString makes "s" a valid RFC 6750 quoted attribute value:
+// double quotes and backslashes are replaced with single quotes,
+// control and non-ASCII characters are removed.
+func headerQuotedString(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '"' || r == '\\' {
+			return '\''
+		}
+
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+
+		return r
+	}, s)
+}
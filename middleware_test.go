@@ -0,0 +1,194 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifierHandler(t *testing.T) {
+	verifier := NewVerifier(testAlg, testSecret, ClaimContains("roles", "admin"))
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifiedToken, ok := GetVerifiedToken(r.Context())
+		if !ok {
+			t.Fatalf("expected verified token in the request context")
+		}
+
+		w.Write([]byte(verifiedToken.StandardClaims.Subject))
+	}))
+
+	admin, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "roles": []string{"admin"}}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := Sign(testAlg, testSecret, Map{"sub": "makis", "roles": []string{"user"}}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := Sign(testAlg, testSecret, Claims{Subject: "kataras", Expiry: time.Now().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		token          []byte
+		statusCode     int
+		authenticate   string
+		expectedError  string
+		expectedReason string
+	}{
+		{admin, http.StatusOK, "", "", ""},
+		{nil, http.StatusUnauthorized, "Bearer", "unauthorized", "missing"},
+		{expired, http.StatusUnauthorized, `Bearer error="invalid_token", error_description="token expired"`, "invalid_token", "expired"},
+		{[]byte("invalid"), http.StatusUnauthorized, `Bearer error="invalid_token", error_description="invalid token form"`, "invalid_token", "form"},
+		{user, http.StatusForbidden, `Bearer error="insufficient_scope", error_description="token denied by policy"`, "insufficient_scope", "policy_denied"},
+	}
+
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.token != nil {
+			r.Header.Set("Authorization", "Bearer "+string(tt.token))
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tt.statusCode {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.statusCode, w.Code)
+		}
+
+		if got := w.Header().Get("WWW-Authenticate"); got != tt.authenticate {
+			t.Fatalf("[%d] expected WWW-Authenticate: %q but got: %q", i, tt.authenticate, got)
+		}
+
+		if tt.statusCode == http.StatusOK {
+			if got := w.Body.String(); got != "kataras" {
+				t.Fatalf("[%d] expected body: kataras but got: %s", i, got)
+			}
+			continue
+		}
+
+		var body struct {
+			Error  string `json:"error"`
+			Reason string `json:"reason"`
+		}
+		if err = json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		if body.Error != tt.expectedError || body.Reason != tt.expectedReason {
+			t.Fatalf("[%d] unexpected error body: %#+v", i, body)
+		}
+	}
+
+	// Test custom error handler.
+	verifier.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		http.Error(w, FailureReason(err), http.StatusTeapot)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("expected custom error handler to be called but got status code: %d", w.Code)
+	}
+}
+
+func TestVerifierSuccessHandler(t *testing.T) {
+	type userContextKey struct{}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.SuccessHandler = func(r *http.Request, verifiedToken *VerifiedToken) (*http.Request, error) {
+		if verifiedToken.StandardClaims.Subject == "disabled" {
+			return nil, fmt.Errorf("%w: user is disabled", ErrPolicyDenied)
+		}
+
+		return r.WithContext(context.WithValue(r.Context(), userContextKey{}, "user:"+verifiedToken.StandardClaims.Subject)), nil
+	}
+
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Context().Value(userContextKey{}).(string)))
+	}))
+
+	for _, tt := range []struct {
+		subject    string
+		statusCode int
+		body       string
+	}{
+		{"kataras", http.StatusOK, "user:kataras"},
+		{"disabled", http.StatusForbidden, ""},
+	} {
+		token, err := Sign(testAlg, testSecret, Claims{Subject: tt.subject})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+string(token))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tt.statusCode {
+			t.Fatalf("[%s] expected status code: %d but got: %d", tt.subject, tt.statusCode, w.Code)
+		}
+
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Fatalf("[%s] expected body: %s but got: %s", tt.subject, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestVerifierSources(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.Sources = []TokenSource{
+		{Name: "bearer", Extract: FromHeader},
+		{Name: "header", Extract: FromCustomHeader("X-Access-Token")},
+		{Name: "cookie", Extract: FromCookie("jwt")},
+	}
+
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(GetTokenSource(r.Context())))
+	}))
+
+	tests := []struct {
+		setup  func(r *http.Request)
+		source string
+	}{
+		{func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+string(token)) }, "bearer"},
+		{func(r *http.Request) { r.Header.Set("X-Access-Token", string(token)) }, "header"},
+		{func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "jwt", Value: string(token)}) }, "cookie"},
+		{func(r *http.Request) { // priority order.
+			r.AddCookie(&http.Cookie{Name: "jwt", Value: "invalid"})
+			r.Header.Set("X-Access-Token", "Bearer "+string(token))
+		}, "header"},
+	}
+
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		tt.setup(r)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK || w.Body.String() != tt.source {
+			t.Fatalf("[%d] expected source: %s but got: %d: %s", i, tt.source, w.Code, w.Body.String())
+		}
+	}
+
+	// Test extractors are ignored when sources are set.
+	verifier.Extractors = []TokenExtractor{FromQuery("token")}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?token="+string(token), nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusUnauthorized, w.Code)
+	}
+}
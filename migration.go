@@ -0,0 +1,136 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ClaimVersion is the name of the claim which holds the schema version of a token's claims,
+// see the `Issuer.Version` field and the `Migrations` type.
+const ClaimVersion = "ver"
+
+// ErrClaimsVersion indicates that a token's claims version is not supported,
+// e.g. it's newer than the current version of the `Migrations`.
+var ErrClaimsVersion = errors.New("unsupported claims version")
+
+// ClaimsUpgrader transforms the claims of a version to the next one, e.g. renames a claim.
+// It modifies the "claims" in place.
+type ClaimsUpgrader func(claims Map) error
+
+// Migrations holds the claims upgraders which are applied after verification,
+// so long-lived tokens (e.g. refresh tokens) survive the changes of the claims schema.
+// The claims version is read from the "ver" claim, tokens without it are considered version 1.
+// See the `Verifier.Migrations` field.
+//
+// Usage:
+//  migrations := jwt.NewMigrations(2)
+//  migrations.Register(1, func(claims jwt.Map) error {
+//    claims["roles"] = []interface{}{claims["role"]}
+//    delete(claims, "role")
+//    return nil
+//  })
+//
+//  issuer.Version = 2
+//  verifier.Migrations = migrations
+type Migrations struct {
+	// Version is the current claims version.
+	Version   int
+	upgraders map[int]ClaimsUpgrader
+}
+
+// NewMigrations returns a new Migrations of "version" current claims version.
+// Use its `Register` method to add the claims upgraders.
+func NewMigrations(version int) *Migrations {
+	return &Migrations{
+		Version:   version,
+		upgraders: make(map[int]ClaimsUpgrader),
+	}
+}
+
+// Register adds the claims upgrader of "from" version to the "from+1" one.
+// It should be called before the first `Migrate` call.
+func (m *Migrations) Register(from int, upgrade ClaimsUpgrader) {
+	m.upgraders[from] = upgrade
+}
+
+// Migrate upgrades the claims of the verified token to the current version.
+// It replaces the token's Payload (and StandardClaims) with the upgraded claims,
+// including the new "ver" claim, the original Token field is not modified.
+// Tokens of the current version are left untouched, an upgrade reports a SignalClaimsVersion
+// migration signal (see `OnMigrationSignal`).
+// Returns ErrClaimsVersion if the token's version is newer than the current one
+// or if an upgrader is missing.
+func (m *Migrations) Migrate(verifiedToken *VerifiedToken) error {
+	var claims Map
+	if err := defaultUnmarshal(verifiedToken.Payload, &claims); err != nil {
+		return err
+	}
+
+	version, err := claimsVersion(claims)
+	if err != nil {
+		return err
+	}
+
+	if version == m.Version {
+		return nil
+	}
+
+	if version > m.Version {
+		return fmt.Errorf("%w: %d", ErrClaimsVersion, version)
+	}
+
+	signal := MigrationSignal{
+		Kind:           SignalClaimsVersion,
+		Issuer:         verifiedToken.StandardClaims.Issuer,
+		Version:        version,
+		CurrentVersion: m.Version,
+	}
+
+	for ; version < m.Version; version++ {
+		upgrade, ok := m.upgraders[version]
+		if !ok {
+			return fmt.Errorf("%w: %d: missing upgrader", ErrClaimsVersion, version)
+		}
+
+		if err = upgrade(claims); err != nil {
+			return err
+		}
+	}
+	claims[ClaimVersion] = m.Version
+
+	payload, err := Marshal(claims)
+	if err != nil {
+		return err
+	}
+
+	var standardClaims Claims
+	if err = json.Unmarshal(payload, &standardClaims); err != nil {
+		return err
+	}
+
+	verifiedToken.Payload = payload
+	verifiedToken.StandardClaims = standardClaims
+	reportSignal(signal)
+	return nil
+}
+
+// claimsVersion returns the "ver" claim's value, 1 if it's missing.
+func claimsVersion(claims Map) (int, error) {
+	value, ok := claims[ClaimVersion]
+	if !ok {
+		return 1, nil
+	}
+
+	n, ok := value.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%w: %v", ErrClaimsVersion, value)
+	}
+
+	version, err := n.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrClaimsVersion, n)
+	}
+
+	return int(version), nil
+}
@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations := NewMigrations(3)
+	migrations.Register(1, func(claims Map) error {
+		claims["roles"] = []interface{}{claims["role"]}
+		delete(claims, "role")
+		return nil
+	})
+	migrations.Register(2, func(claims Map) error {
+		claims["tenant"] = "default"
+		return nil
+	})
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.Migrations = migrations
+
+	type claimsV3 struct {
+		Version int      `json:"ver"`
+		Roles   []string `json:"roles"`
+		Role    string   `json:"role"`
+		Tenant  string   `json:"tenant"`
+	}
+	expected := claimsV3{Version: 3, Roles: []string{"admin"}, Tenant: "default"}
+
+	// A token issued before the "ver" claim convention.
+	v1, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "role": "admin"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Version = 2
+	v2, err := issuer.Token("kataras", Map{"roles": []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer.Version = 3
+	v3, err := issuer.Token("kataras", Map{"roles": []string{"admin"}, "tenant": "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, token := range [][]byte{v1, v2, v3} {
+		verifiedToken, err := verifier.VerifyToken(token)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		var got claimsV3
+		if err = verifiedToken.Claims(&got); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("[%d] expected claims: %#+v but got: %#+v", i, expected, got)
+		}
+
+		if verifiedToken.StandardClaims.Subject != "kataras" {
+			t.Fatalf("[%d] expected the standard claims to be kept but got: %#+v", i, verifiedToken.StandardClaims)
+		}
+	}
+
+	issuer.Version = 4
+	v4, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(v4); !errors.Is(err, ErrClaimsVersion) {
+		t.Fatalf("expected error: %v but got: %v", ErrClaimsVersion, err)
+	}
+
+	migrations.Version = 5
+	if _, err = verifier.VerifyToken(v4); !errors.Is(err, ErrClaimsVersion) {
+		t.Fatalf("expected error on a missing upgrader: %v but got: %v", ErrClaimsVersion, err)
+	}
+}
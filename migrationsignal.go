@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// The kinds of the migration signals, see `MigrationSignal`.
+const (
+	// SignalRetiredKey reports a token verified with a retired key of a `KeyRing`,
+	// i.e. a key in its acceptance window after a rotation.
+	SignalRetiredKey = "retired_key"
+	// SignalAlg reports a token of an algorithm which is not one of the preferred ones, see `PreferAlgs`.
+	SignalAlg = "alg"
+	// SignalClaimsVersion reports a token of an older claims version which was upgraded by the `Migrations`.
+	SignalClaimsVersion = "claims_version"
+	// SignalSession reports a credential which was resolved by the `Verifier.SessionResolver`,
+	// i.e. a server-side session instead of a JWT.
+	SignalSession = "session"
+)
+
+// signalKinds are the kinds of the migration signals, see `Statistics.MigrationSignals`.
+var signalKinds = []string{SignalRetiredKey, SignalAlg, SignalClaimsVersion, SignalSession}
+
+// MigrationSignal holds the information of a successful verification which still depends
+// on a configuration that is being migrated away from, e.g. a retired key or a legacy algorithm.
+// It's passed to the `OnMigrationSignal` hook.
+type MigrationSignal struct {
+	Time   time.Time // The time of the verification.
+	Kind   string    // The kind of the signal, e.g. SignalRetiredKey.
+	Issuer string    // The token's "iss" claim, if any.
+
+	// KeyID is the token's "kid" header (SignalRetiredKey).
+	KeyID string
+	// Alg is the token's algorithm and PreferredAlgs are the preferred ones (SignalAlg).
+	Alg           string
+	PreferredAlgs []string
+	// Version is the token's claims version and CurrentVersion is the upgraded one (SignalClaimsVersion).
+	Version        int
+	CurrentVersion int
+}
+
+// String returns a human-readable description of the signal,
+// e.g. "token verified with retired key kid=abc".
+func (s MigrationSignal) String() string {
+	switch s.Kind {
+	case SignalRetiredKey:
+		return fmt.Sprintf("token verified with retired key kid=%s", s.KeyID)
+	case SignalAlg:
+		return fmt.Sprintf("%s token seen on a verifier configured to prefer %s", s.Alg, strings.Join(s.PreferredAlgs, ", "))
+	case SignalClaimsVersion:
+		return fmt.Sprintf("token of claims version %d upgraded to %d", s.Version, s.CurrentVersion)
+	case SignalSession:
+		return "session credential resolved instead of a token"
+	default:
+		return s.Kind
+	}
+}
+
+// OnMigrationSignal is an optional hook which is called on every migration signal,
+// so operators can track the progress of a migration (e.g. a key rotation, an RS256 to EdDSA move
+// or a sessions to JWTs one) and know when it's safe to drop the old configuration.
+// The signal counts by kind are always available through the `Stats` function.
+// It's called on the verification's goroutine, so it should be cheap (e.g. increment a metric),
+// it should be set once on initialization of the program and it must be safe for concurrent use.
+//
+// Usage:
+//  jwt.OnMigrationSignal = func(signal jwt.MigrationSignal) {
+//    log.Printf("migration: %s (iss=%s)", signal, signal.Issuer)
+//  }
+var OnMigrationSignal func(signal MigrationSignal)
+
+// reportSignal counts the "signal" and calls the OnMigrationSignal hook, if any.
+func reportSignal(signal MigrationSignal) {
+	for i, kind := range signalKinds {
+		if kind == signal.Kind {
+			atomic.AddUint64(&stats.signals[i], 1)
+			break
+		}
+	}
+
+	if OnMigrationSignal == nil {
+		return
+	}
+
+	signal.Time = Clock()
+	OnMigrationSignal(signal)
+}
+
+// PreferAlgs returns a TokenValidator which never fails a verification
+// but reports a SignalAlg migration signal for the tokens which are not signed
+// by one of the "algs" algorithms, e.g. PreferAlgs(jwt.RS256) on a verifier
+// which still accepts the HS256 tokens of the legacy clients (see `Keys` and `AlgPolicy`).
+//
+// Usage:
+//  verifier.Validators = append(verifier.Validators, jwt.PreferAlgs(jwt.RS256))
+func PreferAlgs(algs ...Alg) TokenValidator {
+	preferred := make([]string, 0, len(algs))
+	for _, alg := range algs {
+		preferred = append(preferred, alg.Name())
+	}
+
+	return TokenValidatorFunc(func(token []byte, standardClaims Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		var h tokenHeader
+		if unverifiedHeader(token, &h) != nil || containsString(preferred, h.Alg) {
+			return nil
+		}
+
+		reportSignal(MigrationSignal{
+			Kind:          SignalAlg,
+			Issuer:        standardClaims.Issuer,
+			Alg:           h.Alg,
+			PreferredAlgs: preferred,
+		})
+		return nil
+	})
+}
+
+// retiredKeys is implemented by the key providers which know the retired keys, e.g. the `KeyRing`.
+type retiredKeys interface {
+	AcceptedUntil(kid string) (time.Time, bool)
+}
+
+// signalRetiredKey reports a SignalRetiredKey migration signal
+// if the "verifiedToken" was verified with a retired key of the Verifier's KeyProvider.
+func (v *Verifier) signalRetiredKey(verifiedToken *VerifiedToken) {
+	keys, ok := v.KeyProvider.(retiredKeys)
+	if !ok {
+		return
+	}
+
+	var h tokenHeader
+	if err := json.Unmarshal(verifiedToken.Header, &h); err != nil || h.Kid == "" {
+		return
+	}
+
+	if acceptedUntil, ok := keys.AcceptedUntil(h.Kid); ok && !acceptedUntil.IsZero() {
+		reportSignal(MigrationSignal{
+			Kind:   SignalRetiredKey,
+			Issuer: verifiedToken.StandardClaims.Issuer,
+			KeyID:  h.Kid,
+		})
+	}
+}
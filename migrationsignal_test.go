@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMigrationSignals(t *testing.T) {
+	var signals []MigrationSignal
+	OnMigrationSignal = func(signal MigrationSignal) { signals = append(signals, signal) }
+	defer func() { OnMigrationSignal = nil }()
+
+	prev := Stats()
+
+	keys := NewKeyRing(time.Hour)
+	keys.Rotate("key-1", testSecret, testSecret)
+
+	issuer := NewIssuer(testAlg, nil, time.Minute)
+	issuer.KeyProvider = keys
+	issuer.Issuer = "idp"
+	oldToken, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys.Rotate("key-2", []byte("anothersercrethatmaycontainch@r$"), []byte("anothersercrethatmaycontainch@r$"))
+	newToken, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, nil, PreferAlgs(EdDSA))
+	verifier.KeyProvider = keys
+	verifier.Migrations = NewMigrations(2)
+	verifier.Migrations.Register(1, func(claims Map) error { return nil })
+	verifier.SessionResolver = func(ctx context.Context, credential []byte) (interface{}, error) {
+		return Map{"sub": "kataras", "ver": 2}, nil
+	}
+
+	for _, token := range [][]byte{oldToken, newToken, []byte("session-id")} {
+		if _, err = verifier.VerifyToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		SignalAlg + ":" + testAlg.Name(), SignalRetiredKey + ":key-1", SignalClaimsVersion + ":1",
+		SignalAlg + ":" + testAlg.Name(), SignalClaimsVersion + ":1",
+		SignalSession,
+	}
+
+	if len(signals) != len(expected) {
+		t.Fatalf("expected %d signals but got: %d: %v", len(expected), len(signals), signals)
+	}
+
+	for i, signal := range signals {
+		got := signal.Kind
+		switch signal.Kind {
+		case SignalAlg:
+			got += ":" + signal.Alg
+		case SignalRetiredKey:
+			got += ":" + signal.KeyID
+		case SignalClaimsVersion:
+			got += fmt.Sprintf(":%d", signal.Version)
+		}
+
+		if got != expected[i] {
+			t.Fatalf("[%d] expected signal: %s but got: %s", i, expected[i], got)
+		}
+
+		if signal.Time.IsZero() {
+			t.Fatalf("[%d] expected the signal's time", i)
+		}
+	}
+
+	if s := signals[1]; s.Issuer != "idp" || s.String() != "token verified with retired key kid=key-1" {
+		t.Fatalf("unexpected retired key signal: %#+v: %s", s, s)
+	}
+
+	if s := signals[0].String(); s != testAlg.Name()+" token seen on a verifier configured to prefer EdDSA" {
+		t.Fatalf("unexpected alg signal: %s", s)
+	}
+
+	got := Stats().MigrationSignals
+	for kind, n := range map[string]uint64{SignalAlg: 2, SignalRetiredKey: 1, SignalClaimsVersion: 2, SignalSession: 1} {
+		if expected := prev.MigrationSignals[kind] + n; got[kind] != expected {
+			t.Fatalf("expected %d %s signals but got: %d", expected, kind, got[kind])
+		}
+	}
+}
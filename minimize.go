@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+)
+
+// Minimize returns a copy of the "verifiedToken" claims which contains only the "allow" claims,
+// for claims minimization at trust boundaries, e.g. a gateway which forwards
+// to a downstream service only the claims it needs (see `Forward`).
+// A dot-separated name selects a nested claim, e.g. "address.country".
+// Claims which don't exist in the token are omitted.
+//
+// Usage:
+//  claims, err := jwt.Minimize(verifiedToken, "sub", "roles", "org.id")
+func Minimize(verifiedToken *VerifiedToken, allow ...string) (Map, error) {
+	if verifiedToken == nil {
+		return nil, ErrMissing
+	}
+
+	var claims Map
+	if err := defaultUnmarshal(verifiedToken.Payload, &claims); err != nil {
+		return nil, err
+	}
+
+	if claims == nil {
+		return nil, errors.New("minimize: claims are not a JSON object")
+	}
+
+	minimized := make(Map, len(allow))
+	for _, name := range allow {
+		projectClaim(claims, minimized, strings.Split(name, "."))
+	}
+
+	return minimized, nil
+}
+
+// projectClaim copies the "path" claim of "src" to "dst", if it exists.
+func projectClaim(src, dst Map, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+
+	nested, ok := value.(Map)
+	if !ok {
+		return
+	}
+
+	nestedDst, ok := dst[path[0]].(Map)
+	if !ok {
+		nestedDst = make(Map)
+	}
+
+	projectClaim(nested, nestedDst, path[1:])
+	if len(nestedDst) > 0 {
+		dst[path[0]] = nestedDst
+	}
+}
+
+// Forward signs the `Minimize` claims of the "verifiedToken" with the "alg" and "key",
+// e.g. the key of a gateway, for a downstream service.
+// The standard claims of the "opts" (e.g. `MaxAge` and a Claims value of the gateway's issuer
+// and the downstream audience) override the forwarded ones, except that
+// the forwarded token never expires after the "verifiedToken".
+//
+// Usage:
+//  downstreamToken, err := jwt.Forward(jwt.EdDSA, gatewayKey, verifiedToken,
+//    []string{"sub", "roles"}, jwt.MaxAge(time.Minute), jwt.Claims{Issuer: "gateway", Audience: []string{"orders"}})
+func Forward(alg AlgSigner, key PrivateKey, verifiedToken *VerifiedToken, allow []string, opts ...SignOption) ([]byte, error) {
+	claims, err := Minimize(verifiedToken, allow...)
+	if err != nil {
+		return nil, err
+	}
+
+	var standardClaims Claims
+	for _, opt := range opts {
+		opt.ApplyClaims(&standardClaims)
+	}
+
+	if expiry := verifiedToken.StandardClaims.Expiry; expiry > 0 && (standardClaims.Expiry == 0 || standardClaims.Expiry > expiry) {
+		standardClaims.Expiry = expiry
+	}
+
+	override, err := claimsMap(standardClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range override {
+		claims[name] = value
+	}
+
+	return Sign(alg, key, claims)
+}
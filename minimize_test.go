@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMinimize(t *testing.T) {
+	payload := Map{
+		"sub":   "kataras",
+		"email": "kataras2006@hotmail.com",
+		"roles": []string{"admin"},
+		"org":   Map{"id": 42, "name": "Iris", "billing": "secret"},
+	}
+
+	token, err := Sign(testAlg, testSecret, payload, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := Minimize(verifiedToken, "sub", "roles", "org.id", "org.missing", "missing", "sub.nested")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Map{
+		"sub":   "kataras",
+		"roles": []interface{}{"admin"},
+		"org":   Map{"id": json.Number("42")},
+	}
+	if !reflect.DeepEqual(claims, expected) {
+		t.Fatalf("expected claims: %#+v but got: %#+v", expected, claims)
+	}
+}
+
+func TestForward(t *testing.T) {
+	inbound, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "email": "kataras2006@hotmail.com"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, inbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gatewayKey := []byte("gatewaysercrethatmaycontainch@r$")
+	outbound, err := Forward(HS256, gatewayKey, verifiedToken, []string{"sub"},
+		MaxAge(time.Hour), Claims{Issuer: "gateway", Audience: []string{"orders"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forwarded, err := Verify(HS256, gatewayKey, outbound, Expected{Issuer: "gateway", Subject: "kataras", Audience: []string{"orders"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = forwarded.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := claims["email"]; ok {
+		t.Fatalf("expected the email claim to be removed")
+	}
+
+	// It never expires after the inbound token.
+	if got, expected := forwarded.StandardClaims.Expiry, verifiedToken.StandardClaims.Expiry; got != expected {
+		t.Fatalf("expected exp: %d but got: %d", expected, got)
+	}
+}
@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded indicates that an `Issuer` refused to sign a token
+// because the subject (or client) exceeded its minting quota, see `MintQuota`.
+var ErrQuotaExceeded = errors.New("token minting quota exceeded")
+
+// MintQuota is consulted by an `Issuer` before it signs a token, see the `Issuer.Quota` field.
+// It protects against token-minting abuse, e.g. from compromised credentials,
+// through a rate limiter or a quota service of the "subject" (or the client, see `GetClientInfo`).
+// It should return a type of ErrQuotaExceeded when the token is refused.
+type MintQuota func(ctx context.Context, subject string) error
+
+// MintKeyFunc returns the key of a `MintRateLimit` quota, e.g. the subject or the client's IP.
+type MintKeyFunc func(ctx context.Context, subject string) string
+
+// MintBySubject is a MintKeyFunc which limits each subject separately.
+func MintBySubject(_ context.Context, subject string) string {
+	return subject
+}
+
+// MintByClient is a MintKeyFunc which limits each client IP address separately,
+// see `WithClientInfo`. Tokens minted without client information share the same quota.
+func MintByClient(ctx context.Context, _ string) string {
+	if info, ok := GetClientInfo(ctx); ok && info.IP != nil {
+		return info.IP.String()
+	}
+
+	return ""
+}
+
+// maxMintQuotaKeys is the number of keys of a `MintRateLimit` quota
+// which makes it remove the keys of the passed windows.
+const maxMintQuotaKeys = 10000
+
+// MintRateLimit returns an in-memory MintQuota which allows at most "n" tokens
+// per "every" duration for each key of the "by" function (defaults to `MintBySubject`).
+// The tokens over the limit fail with ErrQuotaExceeded.
+//
+// Usage:
+//  issuer.Quota = jwt.MintRateLimit(10, time.Minute, jwt.MintBySubject)
+func MintRateLimit(n int, every time.Duration, by MintKeyFunc) MintQuota {
+	if by == nil {
+		by = MintBySubject
+	}
+
+	type window struct {
+		start time.Time
+		count int
+	}
+
+	var (
+		mu      sync.Mutex
+		windows = make(map[string]*window)
+	)
+
+	return func(ctx context.Context, subject string) error {
+		key := by(ctx, subject)
+		now := Clock()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(windows) >= maxMintQuotaKeys {
+			for k, w := range windows {
+				if now.Sub(w.start) >= every {
+					delete(windows, k)
+				}
+			}
+		}
+
+		w, ok := windows[key]
+		if !ok || now.Sub(w.start) >= every || now.Before(w.start) {
+			w = &window{start: now}
+			windows[key] = w
+		}
+
+		if w.count >= n {
+			return fmt.Errorf("%w: %d tokens per %s", ErrQuotaExceeded, n, every)
+		}
+
+		w.count++
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMintRateLimit(t *testing.T) {
+	now := time.Now()
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Quota = MintRateLimit(2, time.Minute, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := issuer.Token("kataras", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := issuer.Token("kataras", nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected error: %v but got: %v", ErrQuotaExceeded, err)
+	}
+
+	// Other subjects have their own quota.
+	if _, err := issuer.Token("makis", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new window.
+	now = now.Add(time.Minute)
+	if _, err := issuer.Token("kataras", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMintByClient(t *testing.T) {
+	quota := MintRateLimit(1, time.Hour, MintByClient)
+
+	ctx := WithClientInfo(context.Background(), ClientInfo{IP: net.ParseIP("10.0.0.1")})
+	if err := quota(ctx, "kataras"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := quota(ctx, "makis"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected error: %v but got: %v", ErrQuotaExceeded, err)
+	}
+
+	other := WithClientInfo(context.Background(), ClientInfo{IP: net.ParseIP("10.0.0.2")})
+	if err := quota(other, "kataras"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIssuerQuotaHook(t *testing.T) {
+	denied := errors.New("denied")
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Quota = func(ctx context.Context, subject string) error {
+		if subject == "compromised" {
+			return denied
+		}
+		return nil
+	}
+
+	if _, err := issuer.Token("compromised", nil); err != denied {
+		t.Fatalf("expected error: %v but got: %v", denied, err)
+	}
+
+	if _, err := issuer.Token("kataras", nil); err != nil {
+		t.Fatal(err)
+	}
+}
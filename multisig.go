@@ -0,0 +1,296 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrThreshold indicates that a multi-signature token has fewer valid signatures
+// of the configured signers than the required threshold, see `VerifyThreshold`.
+var ErrThreshold = errors.New("token has not enough valid signatures")
+
+type (
+	// MultiSigner is a member of a multi-signature token, see `SignMulti`.
+	// The KeyID is required, it's set as the "kid" (protected) header of its signature.
+	MultiSigner struct {
+		KeyID string
+		Alg   AlgSigner
+		Key   PrivateKey
+	}
+
+	// MultiKey is a verification key of a multi-signature token signer, see `VerifyThreshold`.
+	MultiKey struct {
+		KeyID string
+		Alg   AlgVerifier
+		Key   PublicKey
+	}
+
+	// generalJWS is the JWS JSON general serialization (RFC 7515 section 7.2.1).
+	generalJWS struct {
+		Payload    string         `json:"payload"`
+		Signatures []jwsSignature `json:"signatures"`
+	}
+
+	jwsSignature struct {
+		Protected string          `json:"protected"`
+		Header    json.RawMessage `json:"header,omitempty"` // the unprotected header, kept as it is.
+		Signature string          `json:"signature"`
+	}
+)
+
+// SignMulti generates a token of the JWS JSON general serialization
+// signed by all the "signers", for actions which require more than one approval.
+// More signatures can be appended later, see `AddSignature`.
+// The result is not a compact (header.payload.signature) token,
+// verify it through the `VerifyThreshold` function.
+//
+// Example Code:
+//
+//  token, err := jwt.SignMulti([]jwt.MultiSigner{
+//    {KeyID: "alice", Alg: jwt.EdDSA, Key: alicePrivateKey},
+//  }, jwt.Map{"action": "delete-tenant"}, jwt.MaxAge(time.Hour))
+func SignMulti(signers []MultiSigner, claims interface{}, opts ...SignOption) ([]byte, error) {
+	if len(opts) > 0 {
+		var standardClaims Claims
+		for _, opt := range opts {
+			opt.ApplyClaims(&standardClaims)
+		}
+
+		claims = Merge(claims, standardClaims)
+	}
+
+	payload, err := Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	jws := generalJWS{Payload: BytesToString(Base64Encode(payload))}
+	for _, signer := range signers {
+		if err = jws.sign(signer); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(jws)
+}
+
+// AddSignature appends the signature of the "signer" to a token generated by `SignMulti`,
+// so the approvers of an action can sign the token one after the other.
+// The existing signatures are not verified.
+func AddSignature(token []byte, signer MultiSigner) ([]byte, error) {
+	return Countersign(token, signer)
+}
+
+// Countersign appends the signature of the "signer" (of any key and algorithm)
+// to an existing token, e.g. a gateway can attest the tokens it forwards.
+// The token can be a compact one (header.payload.signature) or of the JWS JSON
+// (general or flattened) serialization, the result is always of the JWS JSON general serialization.
+// The payload and the existing signatures are kept as they are, they are not verified.
+//
+// Verify the result through `VerifyThreshold`, a `MultiKey` of an empty KeyID
+// matches the signatures without a "kid" header, e.g. the original signature of a compact token.
+//
+// Example Code:
+//
+//  attested, err := jwt.Countersign(token, jwt.MultiSigner{KeyID: "gateway", Alg: jwt.EdDSA, Key: gatewayPrivateKey})
+//  verifiedToken, err := jwt.VerifyThreshold(2, []jwt.MultiKey{
+//    {Alg: jwt.HS256, Key: issuerSharedKey},
+//    {KeyID: "gateway", Alg: jwt.EdDSA, Key: gatewayPublicKey},
+//  }, attested)
+func Countersign(token []byte, signer MultiSigner) ([]byte, error) {
+	jws, err := decodeGeneralJWS(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = jws.sign(signer); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jws)
+}
+
+func (jws *generalJWS) sign(signer MultiSigner) error {
+	if signer.KeyID == "" {
+		return fmt.Errorf("multi-signature: %w: missing key id", ErrInvalidKey)
+	}
+
+	for _, s := range jws.Signatures {
+		if kid, _ := protectedKeyID(s.Protected); kid == signer.KeyID {
+			return fmt.Errorf("multi-signature: already signed by %q", signer.KeyID)
+		}
+	}
+
+	protected := createHeaderWithKid(signer.Alg.Name(), signer.KeyID)
+	signature, err := createSignature(signer.Alg, signer.Key, joinParts(protected, []byte(jws.Payload)))
+	if err != nil {
+		return fmt.Errorf("multi-signature: %s: %w", signer.KeyID, err)
+	}
+
+	jws.Signatures = append(jws.Signatures, jwsSignature{
+		Protected: BytesToString(protected),
+		Signature: BytesToString(signature),
+	})
+	return nil
+}
+
+// decodeGeneralJWS decodes a token of the JWS JSON general or flattened serialization
+// or a compact one to the general serialization.
+func decodeGeneralJWS(token []byte) (*generalJWS, error) {
+	token = bytes.TrimSpace(token)
+	if len(token) == 0 {
+		return nil, ErrMissing
+	}
+
+	if token[0] != '{' {
+		parts := bytes.Split(token, sep)
+		if len(parts) != 3 {
+			return nil, ErrTokenForm
+		}
+
+		return &generalJWS{
+			Payload:    string(parts[1]),
+			Signatures: []jwsSignature{{Protected: string(parts[0]), Signature: string(parts[2])}},
+		}, nil
+	}
+
+	var jws struct {
+		generalJWS
+		jwsSignature // flattened.
+	}
+	if err := json.Unmarshal(token, &jws); err != nil || jws.Payload == "" {
+		return nil, ErrTokenForm
+	}
+
+	if jws.Signatures == nil {
+		if jws.Protected == "" || jws.Signature == "" {
+			return nil, ErrTokenForm
+		}
+
+		jws.Signatures = []jwsSignature{jws.jwsSignature}
+	}
+
+	return &jws.generalJWS, nil
+}
+
+// protectedKeyID returns the "kid" of an (encoded) protected header.
+func protectedKeyID(protected string) (string, error) {
+	header, err := Base64Decode([]byte(protected))
+	if err != nil {
+		return "", ErrTokenForm
+	}
+
+	var h tokenHeader
+	if err = json.Unmarshal(header, &h); err != nil {
+		return "", ErrTokenForm
+	}
+
+	return h.Kid, nil
+}
+
+// VerifyThreshold verifies a token generated by `SignMulti`. The token is valid when
+// at least "threshold" distinct signers of the "keys" have signed it with their configured algorithm,
+// signatures of unknown key ids are ignored. A key of an empty KeyID matches the signatures
+// without a "kid" header (see `Countersign`). Then the standard claims and the "validators" are validated.
+// It returns an `ErrThreshold` error when the token has not enough valid signatures.
+//
+// The Header and Signature fields of the result are the (decoded) parts of the first valid signature.
+//
+// Example Code:
+//
+//  keys := []jwt.MultiKey{
+//    {KeyID: "alice", Alg: jwt.EdDSA, Key: alicePublicKey},
+//    {KeyID: "bob", Alg: jwt.EdDSA, Key: bobPublicKey},
+//    {KeyID: "carol", Alg: jwt.ES256, Key: carolPublicKey},
+//  }
+//  verifiedToken, err := jwt.VerifyThreshold(2, keys, token) // 2-of-3.
+func VerifyThreshold(threshold int, keys []MultiKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyThresholdContext(context.Background(), threshold, keys, token, validators...)
+}
+
+// VerifyThresholdContext same as `VerifyThreshold` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+func VerifyThresholdContext(ctx context.Context, threshold int, keys []MultiKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	verifiedToken, err := verifyThreshold(ctx, threshold, keys, token, validators)
+	recordVerified(token, verifiedToken, err)
+	return verifiedToken, err
+}
+
+func verifyThreshold(ctx context.Context, threshold int, keys []MultiKey, token []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	if threshold < 1 || threshold > len(keys) {
+		return nil, fmt.Errorf("%w: threshold %d of %d keys", ErrThreshold, threshold, len(keys))
+	}
+
+	jws, err := decodeGeneralJWS(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		valid     = make(map[string]struct{}, threshold)
+		header    []byte
+		signature []byte
+	)
+
+	for _, s := range jws.Signatures {
+		kid, err := protectedKeyID(s.Protected)
+		if err != nil {
+			continue
+		}
+
+		if _, counted := valid[kid]; counted {
+			continue
+		}
+
+		key := findMultiKey(keys, kid)
+		if key == nil {
+			continue
+		}
+
+		h, _, sig, err := decodeToken(key.Alg, key.Key, joinParts([]byte(s.Protected), []byte(jws.Payload), []byte(s.Signature)))
+		if err != nil {
+			continue
+		}
+
+		if header == nil {
+			header, signature = h, sig
+		}
+		valid[kid] = struct{}{}
+	}
+
+	if len(valid) < threshold {
+		return nil, fmt.Errorf("%w: %d of %d", ErrThreshold, len(valid), threshold)
+	}
+
+	payload, err := Base64Decode([]byte(jws.Payload))
+	if err != nil {
+		return nil, ErrTokenForm
+	}
+
+	claims, err := validatePayload(ctx, token, payload, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedTok := &VerifiedToken{
+		Token:          token,
+		Header:         header,
+		Payload:        payload,
+		Signature:      signature,
+		StandardClaims: claims,
+	}
+	return verifiedTok, nil
+}
+
+func findMultiKey(keys []MultiKey, kid string) *MultiKey {
+	for i := range keys {
+		if keys[i].KeyID == kid {
+			return &keys[i]
+		}
+	}
+
+	return nil
+}
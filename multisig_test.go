@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyThreshold(t *testing.T) {
+	var (
+		signers = make([]MultiSigner, 3)
+		keys    = make([]MultiKey, 3)
+	)
+	for i, kid := range []string{"alice", "bob", "carol"} {
+		publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+		signers[i] = MultiSigner{KeyID: kid, Alg: EdDSA, Key: privateKey}
+		keys[i] = MultiKey{KeyID: kid, Alg: EdDSA, Key: publicKey}
+	}
+	// A signer of a different algorithm.
+	signers[2].Alg, signers[2].Key = HS256, testSecret
+	keys[2].Alg, keys[2].Key = HS256, testSecret
+
+	claims := Map{"action": "delete-tenant"}
+	token, err := SignMulti(signers[:1], claims, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyThreshold(2, keys, token); !errors.Is(err, ErrThreshold) {
+		t.Fatalf("expected error: %v but got: %v", ErrThreshold, err)
+	}
+
+	if _, err = AddSignature(token, signers[0]); err == nil {
+		t.Fatalf("expected an error on duplicated signer")
+	}
+
+	token, err = AddSignature(token, signers[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyThreshold(2, keys, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Map
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["action"] != claims["action"] {
+		t.Fatalf("expected action: %v but got: %v", claims["action"], got["action"])
+	}
+
+	if _, err = VerifyThreshold(3, keys, token); !errors.Is(err, ErrThreshold) {
+		t.Fatalf("expected error: %v but got: %v", ErrThreshold, err)
+	}
+
+	// A signature of a key which is not configured for its key id does not count.
+	impostor := signers[0]
+	impostor.KeyID = "bob"
+	token, err = AddSignature(token, impostor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyThreshold(3, keys, token); !errors.Is(err, ErrThreshold) {
+		t.Fatalf("expected error: %v but got: %v", ErrThreshold, err)
+	}
+
+	if _, err = VerifyThreshold(4, keys, token); !errors.Is(err, ErrThreshold) {
+		t.Fatalf("expected error on invalid threshold: %v but got: %v", ErrThreshold, err)
+	}
+
+	if _, err = VerifyThreshold(1, keys, []byte("header.payload")); err != ErrTokenForm {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+}
+
+func TestVerifyThresholdExpired(t *testing.T) {
+	signers := []MultiSigner{{KeyID: "a", Alg: HS256, Key: testSecret}, {KeyID: "b", Alg: HS384, Key: testSecret}}
+	keys := []MultiKey{{KeyID: "a", Alg: HS256, Key: testSecret}, {KeyID: "b", Alg: HS384, Key: testSecret}}
+
+	token, err := SignMulti(signers, Map{"action": "rotate"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	Clock = func() time.Time { return now.Add(time.Hour) }
+	defer func() { Clock = time.Now }()
+
+	if _, err = VerifyThreshold(2, keys, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+}
+
+func TestCountersign(t *testing.T) {
+	gatewayPublicKey, gatewayPrivateKey, _ := ed25519.GenerateKey(rand.Reader)
+	gateway := MultiSigner{KeyID: "gateway", Alg: EdDSA, Key: gatewayPrivateKey}
+	keys := []MultiKey{
+		{Alg: testAlg, Key: testSecret},
+		{KeyID: "gateway", Alg: EdDSA, Key: gatewayPublicKey},
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attested, err := Countersign(token, gateway)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyThreshold(2, keys, attested)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("expected subject: kataras but got: %s", verifiedToken.StandardClaims.Subject)
+	}
+
+	// The original token's parts are kept as they are.
+	parts := bytes.Split(token, sep)
+	if !bytes.Contains(attested, parts[1]) || !bytes.Contains(attested, parts[2]) {
+		t.Fatalf("expected the original payload and signature to be kept")
+	}
+
+	if _, err = Countersign(attested, gateway); err == nil {
+		t.Fatalf("expected an error on duplicated signer")
+	}
+
+	// Flattened serialization, with an unprotected header.
+	flattened := []byte(`{"payload":"` + string(parts[1]) + `","protected":"` + string(parts[0]) +
+		`","header":{"x-trace":"1"},"signature":"` + string(parts[2]) + `"}`)
+	attested, err = Countersign(flattened, gateway)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(attested, []byte(`"header":{"x-trace":"1"}`)) {
+		t.Fatalf("expected the unprotected header to be kept but got: %s", attested)
+	}
+
+	if _, err = VerifyThreshold(2, keys, attested); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range [][]byte{[]byte("a.b"), []byte(`{"payload":"e30"}`)} {
+		if _, err = Countersign(tt, gateway); err != ErrTokenForm {
+			t.Fatalf("%s: expected error: %v but got: %v", tt, ErrTokenForm, err)
+		}
+	}
+}
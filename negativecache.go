@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// defaultNegativeCacheEntries is the default maximum number of cached tokens of a NegativeCache.
+const defaultNegativeCacheEntries = 10000
+
+// NegativeCache remembers the tokens of an invalid signature for a short TTL, by their hash,
+// so the replays of the same forged token (common in scraping attacks) are rejected
+// with ErrTokenSignature without verifying their (e.g. RSA) signature again.
+// The TTL adapts to the attack: every replay of a cached token doubles its TTL, up to the MaxTTL.
+// A NegativeCache is safe for concurrent use, see the `Verifier.NegativeCache` field.
+//
+// Usage:
+//  verifier.NegativeCache = jwt.NewNegativeCache(10 * time.Second)
+type NegativeCache struct {
+	// TTL is the initial duration a token is cached for. Defaults to 10 seconds.
+	TTL time.Duration
+	// MaxTTL is the maximum duration a replayed token is cached for. Defaults to 5 minutes.
+	MaxTTL time.Duration
+	// MaxEntries is the maximum number of cached tokens,
+	// the least recently used token is evicted on a full cache. Defaults to 10000.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries *lru[[sha256.Size]byte, *negativeEntry]
+}
+
+type negativeEntry struct {
+	ttl       time.Duration
+	expiresAt time.Time
+}
+
+// NewNegativeCache returns a new NegativeCache of "ttl" initial TTL.
+// The rest of the NegativeCache fields can be modified before its first use.
+func NewNegativeCache(ttl time.Duration) *NegativeCache {
+	return &NegativeCache{
+		TTL:        ttl,
+		MaxTTL:     5 * time.Minute,
+		MaxEntries: defaultNegativeCacheEntries,
+	}
+}
+
+// Rejected reports whether the "token" is cached as one of an invalid signature,
+// a cached token's TTL is doubled (up to the MaxTTL).
+func (c *NegativeCache) Rejected(token []byte) bool {
+	key := sha256.Sum256(token)
+	now := Clock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		return false
+	}
+
+	entry, ok := c.entries.get(key)
+	if !ok {
+		return false
+	}
+
+	if !now.Before(entry.expiresAt) {
+		c.entries.remove(key)
+		return false
+	}
+
+	maxTTL := c.MaxTTL
+	if maxTTL <= 0 {
+		maxTTL = 5 * time.Minute
+	}
+
+	if entry.ttl *= 2; entry.ttl > maxTTL {
+		entry.ttl = maxTTL
+	}
+	entry.expiresAt = now.Add(entry.ttl)
+
+	return true
+}
+
+// Add caches the "token" as one of an invalid signature for the TTL.
+func (c *NegativeCache) Add(token []byte) {
+	key := sha256.Sum256(token)
+
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = 10 * time.Second
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		maxEntries := c.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultNegativeCacheEntries
+		}
+		c.entries = newLRU[[sha256.Size]byte, *negativeEntry](maxEntries)
+	}
+
+	c.entries.set(key, &negativeEntry{ttl: ttl, expiresAt: Clock().Add(ttl)})
+}
+
+// Invalidate completes the CacheInvalidator interface, it drops the cached tokens
+// on an InvalidateKeys event, as a new key may verify a token which was rejected before.
+func (c *NegativeCache) Invalidate(event Invalidation) {
+	if event.Kind != InvalidateKeys {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// Len returns the number of the cached tokens, including the expired ones which are not evicted yet.
+func (c *NegativeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		return 0
+	}
+
+	return c.entries.len()
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifierNegativeCache(t *testing.T) {
+	defer func() { Clock = time.Now }()
+	now := time.Now()
+	Clock = func() time.Time { return now }
+
+	alg := &countingVerifier{AlgVerifier: testAlg}
+	verifier := NewVerifier(alg, testSecret)
+	verifier.NegativeCache = NewNegativeCache(10 * time.Second)
+	verifier.NegativeCache.MaxTTL = 30 * time.Second
+
+	forged, err := Sign(testAlg, []byte("attacker's secret key of 32 bytes"), Map{"sub": "admin"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err = verifier.VerifyToken(forged); !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrTokenSignature, err)
+		}
+	}
+
+	if alg.calls != 1 || verifier.NegativeCache.Len() != 1 {
+		t.Fatalf("expected a single signature verification of the replayed token but got: %d", alg.calls)
+	}
+
+	// The two replays doubled the TTL to 30 seconds (capped).
+	now = now.Add(25 * time.Second)
+	if _, err = verifier.VerifyToken(forged); !errors.Is(err, ErrTokenSignature) || alg.calls != 1 {
+		t.Fatalf("expected a cached rejection but got: %v (%d)", err, alg.calls)
+	}
+
+	now = now.Add(31 * time.Second)
+	if _, err = verifier.VerifyToken(forged); !errors.Is(err, ErrTokenSignature) || alg.calls != 2 {
+		t.Fatalf("expected an expired cache entry but got: %v (%d)", err, alg.calls)
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if verifier.NegativeCache.Len() != 1 {
+		t.Fatalf("expected only the tokens of an invalid signature to be cached")
+	}
+}
@@ -1,6 +1,11 @@
 package jwt
 
-import "bytes"
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+)
 
 type algNONE struct{}
 
@@ -19,3 +24,52 @@ func (a *algNONE) Verify(key PublicKey, headerAndPayload []byte, signature []byt
 
 	return nil
 }
+
+// taggedNONESize is the size of the TaggedNONE tag, the truncated HMAC-SHA256.
+const taggedNONESize = 16
+
+type algTaggedNONE struct{}
+
+func (a *algTaggedNONE) Name() string {
+	return "NONE+HS256"
+}
+
+func (a *algTaggedNONE) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
+	sessionKey, ok := key.([]byte)
+	if !ok || len(sessionKey) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	h := hmac.New(sha256.New, sessionKey)
+	h.Write(headerAndPayload)
+	return h.Sum(nil)[:taggedNONESize], nil
+}
+
+func (a *algTaggedNONE) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
+	expectedTag, err := a.Sign(key, headerAndPayload)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(expectedTag, signature) {
+		return ErrTokenSignature
+	}
+
+	return nil
+}
+
+// SessionTagKey derives the `TaggedNONE` key of a session from the server's "secret"
+// and the session's identifier, so each session gets its own key and clients
+// which hold the key of their session can't tag the view data of another one.
+//
+// Usage:
+//  sessionKey, err := jwt.SessionTagKey(serverSecret, sessionID)
+//  token, err := jwt.Sign(jwt.TaggedNONE, sessionKey, viewData)
+//  // send the token and the session key to the client.
+func SessionTagKey(secret []byte, session string) ([]byte, error) {
+	if len(secret) == 0 || session == "" {
+		return nil, ErrInvalidKey
+	}
+
+	return HKDF(crypto.SHA256, secret, nil, []byte("jwt none tag: "+session), sha256.Size)
+}
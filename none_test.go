@@ -1,8 +1,55 @@
 package jwt
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+)
 
 func TestEncodeDecodeTokenNONE(t *testing.T) {
 	expectedToken := []byte("eyJhbGciOiJOT05FIiwidHlwIjoiSldUIn0.eyJ1c2VybmFtZSI6ImthdGFyYXMifQ.")
 	testEncodeDecodeToken(t, NONE, nil, nil, expectedToken)
 }
+
+func TestTaggedNONE(t *testing.T) {
+	secret := []byte("server-secret")
+	sessionKey, err := SessionTagKey(secret, "ch72gsb320000udocl363eofy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := SessionTagKey(secret, "another-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(sessionKey, otherKey) {
+		t.Fatalf("expected different keys per session")
+	}
+
+	token, err := Sign(TaggedNONE, sessionKey, Map{"name": "Pretty Name", "lastpage": "/views/settings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := bytes.Split(token, sep)
+	if tag, _ := Base64Decode(parts[2]); len(tag) != 16 {
+		t.Fatalf("expected a tag of 16 bytes but got: %d", len(tag))
+	}
+
+	if _, err = Verify(TaggedNONE, sessionKey, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(TaggedNONE, otherKey, token); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// The plain NONE does not accept a tagged token.
+	if _, err = Verify(NONE, nil, token); err != ErrTokenAlg {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+
+	if _, err = SessionTagKey(secret, ""); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxOPAResponseSize is the maximum size of an OPA decision response body.
+const maxOPAResponseSize = 1 << 20
+
+// OPA is a PayloadValidator which posts the claims of verified tokens
+// to an Open Policy Agent decision endpoint (the Data API) and converts
+// deny decisions into ErrPolicyDenied errors.
+//
+// The request body is: {"input": {"claims": {...}, "client": {"ip": "...", "user_agent": "..."}}},
+// the client information is included when the verification Context carries it (see `WithClientInfo`).
+// The decision "result" should be a boolean or an object with an "allow" boolean field,
+// an undefined decision is a deny one. It fails closed, remote errors fail the verification.
+//
+// Usage:
+//  opa := jwt.NewOPA("http://localhost:8181/v1/data/httpapi/authz")
+//  verifiedToken, err := jwt.VerifyContext(ctx, alg, key, token, opa)
+type OPA struct {
+	// URL is the decision endpoint, e.g. "http://localhost:8181/v1/data/httpapi/authz/allow".
+	URL string
+	// Client is the HTTP client, defaults to the package-level HTTPClient.
+	Client *http.Client
+	// Breaker is an optional circuit breaker which fails fast when the OPA server is down.
+	Breaker *CircuitBreaker
+}
+
+var _ PayloadValidator = (*OPA)(nil)
+
+// NewOPA returns a new OPA validator of the "url" decision endpoint.
+func NewOPA(url string) *OPA {
+	return &OPA{URL: url}
+}
+
+// ValidateToken completes the TokenValidator interface.
+// See `PayloadValidatorFunc.ValidateToken`.
+func (o *OPA) ValidateToken(token []byte, standardClaims Claims, err error) error {
+	return PayloadValidatorFunc(o.ValidatePayload).ValidateToken(token, standardClaims, err)
+}
+
+// ValidatePayload completes the PayloadValidator interface.
+// Returns ErrPolicyDenied on deny decisions.
+func (o *OPA) ValidatePayload(ctx context.Context, _, payload []byte, _ Claims, err error) error {
+	if err != nil {
+		return err
+	}
+
+	input := map[string]interface{}{"claims": json.RawMessage(payload)}
+	if info, ok := GetClientInfo(ctx); ok {
+		client := map[string]string{"user_agent": info.UserAgent}
+		if info.IP != nil {
+			client["ip"] = info.IP.String()
+		}
+		input["client"] = client
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return err
+	}
+
+	var allow bool
+	err = retry(ctx, o.Breaker, 0, 0, func() (err error) {
+		allow, err = o.decide(ctx, body)
+		return
+	})
+	if err != nil {
+		return err
+	}
+
+	if !allow {
+		return ErrPolicyDenied
+	}
+
+	return nil
+}
+
+func (o *OPA) decide(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient(o.Client).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, &remoteStatusError{name: "opa", statusCode: resp.StatusCode}
+	}
+
+	var decision struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxOPAResponseSize)).Decode(&decision); err != nil {
+		return false, fmt.Errorf("opa: %w", err)
+	}
+
+	if len(decision.Result) == 0 { // undefined decision.
+		return false, nil
+	}
+
+	var allow bool
+	if json.Unmarshal(decision.Result, &allow) == nil {
+		return allow, nil
+	}
+
+	var result struct {
+		Allow bool `json:"allow"`
+	}
+	if err = json.Unmarshal(decision.Result, &result); err != nil {
+		return false, fmt.Errorf("opa: unexpected decision result: %w", err)
+	}
+
+	return result.Allow, nil
+}
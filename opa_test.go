@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOPA(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input struct {
+				Claims Map               `json:"claims"`
+				Client map[string]string `json:"client"`
+			} `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/data/authz/allow":
+			json.NewEncoder(w).Encode(Map{"result": body.Input.Claims["role"] == "admin"})
+		case "/v1/data/authz":
+			json.NewEncoder(w).Encode(Map{"result": Map{"allow": body.Input.Client["ip"] == "10.0.0.1"}})
+		case "/v1/data/undefined":
+			w.Write([]byte(`{}`))
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	admin, err := Sign(testAlg, testSecret, Map{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := Sign(testAlg, testSecret, Map{"role": "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opa := NewOPA(srv.URL + "/v1/data/authz/allow")
+	if _, err = Verify(testAlg, testSecret, admin, opa); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, user, opa); err != ErrPolicyDenied {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+
+	// Test object decision with client information.
+	opa.URL = srv.URL + "/v1/data/authz"
+	ctx := WithClientInfo(context.Background(), ClientInfo{IP: net.ParseIP("10.0.0.1")})
+	if _, err = VerifyContext(ctx, testAlg, testSecret, user, opa); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, user, opa); err != ErrPolicyDenied {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+
+	opa.URL = srv.URL + "/v1/data/undefined"
+	if _, err = Verify(testAlg, testSecret, admin, opa); err != ErrPolicyDenied {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+
+	// Test it fails closed.
+	opa.URL = srv.URL + "/v1/data/failure"
+	var statusErr *remoteStatusError
+	if _, err = Verify(testAlg, testSecret, admin, opa); !errors.As(err, &statusErr) {
+		t.Fatalf("expected a remote status error but got: %v", err)
+	}
+}
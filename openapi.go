@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// SecurityScheme is an OpenAPI Security Scheme Object of the bearer tokens,
+// see `OpenAPI.SecuritySchemes`.
+type SecurityScheme struct {
+	Type         string `json:"type"`   // "http".
+	Scheme       string `json:"scheme"` // "bearer".
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement is an OpenAPI Security Requirement Object,
+// the security scheme names to the required scopes. An empty requirement allows anonymous requests.
+type SecurityRequirement map[string][]string
+
+// RouteSecurity is the security requirements of a route registered through `OpenAPI.RouteHandler`.
+type RouteSecurity struct {
+	Pattern  string   // The route's pattern, e.g. "/invoices" or "GET /invoices".
+	Scopes   []string // The required scopes, see `WithScopes`.
+	Optional bool     // Requests without a token are allowed, see `OptionalAuth`.
+}
+
+// OpenAPI is a registry of the routes protected by a Verifier, which exports their
+// security requirements as OpenAPI 3.1 fragments (the "securitySchemes" component
+// and the "security" of each route's operations), so the API documentation
+// is generated from the same options the middleware enforces.
+// An OpenAPI is safe for concurrent use.
+//
+// Usage:
+//  api := jwt.NewOpenAPI(verifier)
+//  mux.Handle("/invoices", api.RouteHandler("/invoices", invoicesHandler, jwt.WithScopes("invoices:read")))
+//  mux.Handle("/", api.RouteHandler("/", indexHandler, jwt.OptionalAuth()))
+//
+//  b, err := json.MarshalIndent(api, "", "  ")
+type OpenAPI struct {
+	// Verifier is the Verifier of the registered routes, required.
+	Verifier *Verifier
+	// SchemeName is the name of the security scheme. Defaults to "bearerAuth".
+	SchemeName string
+	// Scheme is the security scheme of the Verifier's tokens.
+	// Defaults to an "http" "bearer" scheme of the "JWT" format.
+	Scheme SecurityScheme
+
+	mu     sync.Mutex
+	routes map[string]RouteSecurity
+}
+
+// NewOpenAPI returns a new OpenAPI registry of the routes protected by the "verifier".
+// The rest of the OpenAPI fields can be modified before its first use.
+func NewOpenAPI(verifier *Verifier) *OpenAPI {
+	return &OpenAPI{
+		Verifier:   verifier,
+		SchemeName: "bearerAuth",
+		Scheme: SecurityScheme{
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+		},
+	}
+}
+
+// RouteHandler registers the security requirements of the "pattern" route
+// and returns the Verifier's `RouteHandler` of the "next" handler and the route options.
+// A registered pattern is replaced.
+func (o *OpenAPI) RouteHandler(pattern string, next http.Handler, opts ...RouteOption) http.Handler {
+	var route routeConfig
+	for _, opt := range opts {
+		opt(&route)
+	}
+
+	scopes := route.scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	o.mu.Lock()
+	if o.routes == nil {
+		o.routes = make(map[string]RouteSecurity)
+	}
+	o.routes[pattern] = RouteSecurity{Pattern: pattern, Scopes: scopes, Optional: route.optional}
+	o.mu.Unlock()
+
+	return o.Verifier.RouteHandler(next, opts...)
+}
+
+// Routes returns the registered routes, sorted by their pattern.
+func (o *OpenAPI) Routes() []RouteSecurity {
+	o.mu.Lock()
+	routes := make([]RouteSecurity, 0, len(o.routes))
+	for _, route := range o.routes {
+		routes = append(routes, route)
+	}
+	o.mu.Unlock()
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern < routes[j].Pattern
+	})
+
+	return routes
+}
+
+// SecuritySchemes returns the "components.securitySchemes" fragment.
+func (o *OpenAPI) SecuritySchemes() map[string]SecurityScheme {
+	return map[string]SecurityScheme{o.schemeName(): o.Scheme}
+}
+
+// Security returns the "security" fragment of the operations of the "pattern" route,
+// reports false if the route is not registered.
+// The required scopes are listed (as OpenAPI 3.1 allows for any scheme)
+// and an optional authentication adds an empty requirement too.
+func (o *OpenAPI) Security(pattern string) ([]SecurityRequirement, bool) {
+	o.mu.Lock()
+	route, ok := o.routes[pattern]
+	o.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return o.security(route), true
+}
+
+func (o *OpenAPI) security(route RouteSecurity) []SecurityRequirement {
+	security := []SecurityRequirement{{o.schemeName(): route.Scopes}}
+	if route.Optional {
+		security = append(security, SecurityRequirement{})
+	}
+
+	return security
+}
+
+func (o *OpenAPI) schemeName() string {
+	if o.SchemeName == "" {
+		return "bearerAuth"
+	}
+
+	return o.SchemeName
+}
+
+// MarshalJSON completes the json.Marshaler interface.
+// It returns the security schemes and the security of each registered route, e.g.
+//  {"components": {"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}}},
+//   "security": {"/invoices": [{"bearerAuth": ["invoices:read"]}]}}
+func (o *OpenAPI) MarshalJSON() ([]byte, error) {
+	routes := o.Routes()
+	security := make(map[string][]SecurityRequirement, len(routes))
+	for _, route := range routes {
+		security[route.Pattern] = o.security(route)
+	}
+
+	type components struct {
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+	}
+
+	return json.Marshal(struct {
+		Components components                       `json:"components"`
+		Security   map[string][]SecurityRequirement `json:"security"`
+	}{components{o.SecuritySchemes()}, security})
+}
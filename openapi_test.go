@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	api := NewOpenAPI(NewVerifier(testAlg, testSecret))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	invoices := api.RouteHandler("GET /invoices", next, WithAudience("billing"), WithScopes("invoices:read"), WithScopes("profile"))
+	api.RouteHandler("/", next, OptionalAuth())
+	api.RouteHandler("/me", next)
+
+	// The registered handler enforces the same scopes.
+	token, err := Sign(testAlg, testSecret, Map{"aud": []string{"billing"}, "scope": "invoices:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/invoices", nil)
+	r.Header.Set("Authorization", "Bearer "+string(token))
+	w := httptest.NewRecorder()
+	invoices.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusForbidden, w.Code)
+	}
+
+	if _, ok := api.Security("/unknown"); ok {
+		t.Fatalf("expected an unknown route")
+	}
+
+	b, err := json.Marshal(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"components":{"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer","bearerFormat":"JWT"}}},` +
+		`"security":{"/":[{"bearerAuth":[]},{}],"/me":[{"bearerAuth":[]}],"GET /invoices":[{"bearerAuth":["invoices:read","profile"]}]}}`
+	if got := string(b); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	if routes := api.Routes(); len(routes) != 3 || routes[0].Pattern != "/" || !routes[0].Optional {
+		t.Fatalf("unexpected routes: %#+v", routes)
+	}
+}
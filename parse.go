@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+)
+
+// KeyResolver resolves the verification algorithm and key of a token
+// by its decoded (but not verified yet) header, e.g. by a custom header field.
+// A nil algorithm fails the verification with a type of ErrInvalidKey. See `Parse`.
+type KeyResolver[H any] func(ctx context.Context, header H) (AlgVerifier, PublicKey, error)
+
+// StaticKeyResolver returns a KeyResolver which always resolves the given "alg" and "key".
+func StaticKeyResolver[H any](alg AlgVerifier, key PublicKey) KeyResolver[H] {
+	return func(context.Context, H) (AlgVerifier, PublicKey, error) {
+		return alg, key, nil
+	}
+}
+
+// Parse verifies the "token" and decodes both its header into a value of "H"
+// and its claims into a value of "C", for custom header parameters and typed claims
+// without a second decode pass of the `VerifiedToken`.
+// The algorithm and the key are resolved by the "resolve" function,
+// the "validators" are the same as the `Verify` function accepts.
+// The claims are decoded through the `Unmarshal` package-level function.
+//
+// Usage:
+//  type header struct {
+//    Kid    string `json:"kid"`
+//    Tenant string `json:"tenant"`
+//  }
+//
+//  h, claims, err := jwt.Parse[header, jwt.StandardUserClaims](token,
+//    func(ctx context.Context, h header) (jwt.AlgVerifier, jwt.PublicKey, error) {
+//      return jwt.RS256, tenantKeys[h.Tenant], nil
+//    })
+func Parse[H, C any](token []byte, resolve KeyResolver[H], validators ...TokenValidator) (H, C, error) {
+	return ParseContext[H, C](context.Background(), token, resolve, validators...)
+}
+
+// ParseContext same as `Parse` but it accepts a standard Go Context
+// which is passed to the "resolve" function and any `ContextValidator` of the "validators".
+func ParseContext[H, C any](ctx context.Context, token []byte, resolve KeyResolver[H], validators ...TokenValidator) (H, C, error) {
+	var (
+		header H
+		claims C
+	)
+
+	if len(token) == 0 {
+		recordVerification(token, ErrMissing)
+		return header, claims, ErrMissing
+	}
+
+	unverifiedToken, err := Decode(token)
+	if err == nil {
+		err = unverifiedToken.DecodeHeader(&header)
+	}
+	if err != nil {
+		recordVerification(token, ErrTokenForm)
+		return header, claims, ErrTokenForm
+	}
+
+	alg, key, err := resolve(ctx, header)
+	if err == nil && alg == nil {
+		err = fmt.Errorf("%w: resolver returned no algorithm", ErrInvalidKey)
+	}
+	if err != nil {
+		recordVerification(token, err)
+		return header, claims, err
+	}
+
+	verifiedToken, err := unverifiedToken.VerifyContext(ctx, alg, key, validators...)
+	if err != nil {
+		return header, claims, err
+	}
+
+	// The "header" is verified now, it was decoded from the same signed bytes.
+	err = verifiedToken.Claims(&claims)
+	return header, claims, err
+}
@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	type header struct {
+		Alg    string `json:"alg"`
+		Kid    string `json:"kid"`
+		Tenant string `json:"tenant"`
+	}
+
+	type userClaims struct {
+		Claims
+		Username string `json:"username"`
+	}
+
+	tenantKeys := map[string][]byte{"acme": []byte("acme-secret"), "initech": []byte("initech-secret")}
+	resolve := func(_ context.Context, h header) (AlgVerifier, PublicKey, error) {
+		key, ok := tenantKeys[h.Tenant]
+		if !ok {
+			return nil, nil, ErrUnknownKid
+		}
+
+		return HS256, key, nil
+	}
+
+	token, err := SignWithHeader(HS256, tenantKeys["acme"], Header{{"alg", "HS256"}, {"kid", "k1"}, {"tenant", "acme"}},
+		Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, claims, err := Parse[header, userClaims](token, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (header{Alg: "HS256", Kid: "k1", Tenant: "acme"}); h != expected {
+		t.Fatalf("expected header: %#+v but got: %#+v", expected, h)
+	}
+
+	if claims.Username != "kataras" || claims.Expiry == 0 {
+		t.Fatalf("unexpected claims: %#+v", claims)
+	}
+
+	// The resolved key of another tenant does not verify the token.
+	forged, err := SignWithHeader(HS256, tenantKeys["acme"], Header{{"alg", "HS256"}, {"tenant", "initech"}}, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = Parse[header, userClaims](forged, resolve); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	unknown, err := SignWithHeader(HS256, tenantKeys["acme"], Header{{"alg", "HS256"}}, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = Parse[header, userClaims](unknown, resolve); err != ErrUnknownKid {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if _, _, err = Parse[header, userClaims](nil, resolve); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+
+	if _, _, err = Parse[header, userClaims]([]byte("header.payload"), resolve); err != ErrTokenForm {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	noAlg := func(context.Context, header) (AlgVerifier, PublicKey, error) { return nil, tenantKeys["acme"], nil }
+	if _, _, err = Parse[header, userClaims](token, noAlg); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	// Validators run as on Verify.
+	_, _, err = Parse[header, Map](token, resolve, Expected{Subject: "kataras"})
+	if !errors.Is(err, ErrExpected) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpected, err)
+	}
+}
+
+func TestStaticKeyResolver(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, claims, err := Parse[Map, Map](token, StaticKeyResolver[Map](testAlg, testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h["alg"] != testAlg.Name() || claims["username"] != "kataras" {
+		t.Fatalf("unexpected header: %v or claims: %v", h, claims)
+	}
+}
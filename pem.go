@@ -0,0 +1,226 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrEncryptedKey indicates that a private key is encrypted, see `DecryptPEM`.
+var ErrEncryptedKey = errors.New("private key: encrypted, see DecryptPEM")
+
+// decodeKey returns the DER contents of a PEM-encoded key
+// or the key itself if it looks DER-encoded already (an ASN.1 sequence).
+// It returns nil if the key is neither of them and ErrEncryptedKey for encrypted PEM blocks.
+func decodeKey(key []byte) ([]byte, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		if len(key) > 0 && key[0] == 0x30 {
+			return key, nil
+		}
+
+		return nil, nil
+	}
+
+	if block.Type == "ENCRYPTED PRIVATE KEY" || x509.IsEncryptedPEMBlock(block) {
+		return nil, ErrEncryptedKey
+	}
+
+	return block.Bytes, nil
+}
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type (
+	// encryptedPrivateKeyInfo is the PKCS#8 (RFC 5958) encrypted private key.
+	encryptedPrivateKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		Data      []byte
+	}
+
+	// pbes2Params are the PBES2 (RFC 8018) parameters.
+	pbes2Params struct {
+		KeyDerivationFunc pkix.AlgorithmIdentifier
+		EncryptionScheme  pkix.AlgorithmIdentifier
+	}
+
+	pbkdf2Params struct {
+		Salt       []byte
+		Iterations int
+		KeyLength  int                      `asn1:"optional"`
+		PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+	}
+)
+
+// maxPBKDF2Iterations limits the cost of decrypting a (malicious) key.
+const maxPBKDF2Iterations = 10_000_000
+
+// DecryptPEM decrypts a passphrase-protected PEM-encoded private key
+// and returns its unencrypted PEM encoding, ready for the ParsePrivateKeyXXX functions.
+// It supports the PKCS#8 "ENCRYPTED PRIVATE KEY" blocks of PBES2
+// (PBKDF2 of HMAC-SHA1 or HMAC-SHA256 and AES-CBC, the openssl defaults)
+// and the legacy (RFC 1423) encrypted PEM blocks. Unencrypted keys are returned as they are.
+// Returns x509.IncorrectPasswordError on a wrong "passphrase".
+//
+// Usage:
+//  b, err := jwt.ReadFile("private_key.pem")
+//  b, err = jwt.DecryptPEM(b, []byte(os.Getenv("KEY_PASSPHRASE")))
+//  privateKey, err := jwt.ParsePrivateKeyRSA(b)
+func DecryptPEM(key, passphrase []byte) ([]byte, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("private key: malformed or missing PEM format")
+	}
+
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		der, err := decryptPKCS8(block.Bytes, passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	}
+
+	// The legacy encryption is insecure by design, it's supported for existing keys only.
+	if x509.IsEncryptedPEMBlock(block) {
+		der, err := x509.DecryptPEMBlock(block, passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+	}
+
+	return key, nil
+}
+
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("private key: unsupported encryption: %s", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("private key: unsupported key derivation: %s", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, err
+	}
+
+	if kdf.Iterations <= 0 || kdf.Iterations > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("private key: bad iteration count: %d", kdf.Iterations)
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("private key: unsupported pseudorandom function: %s", kdf.PRF.Algorithm)
+	}
+
+	var keySize int
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keySize = 16
+	case scheme.Equal(oidAES192CBC):
+		keySize = 24
+	case scheme.Equal(oidAES256CBC):
+		keySize = 32
+	default:
+		return nil, fmt.Errorf("private key: unsupported cipher: %s", scheme)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aes.BlockSize || len(info.Data) == 0 || len(info.Data)%aes.BlockSize != 0 {
+		return nil, errors.New("private key: malformed encrypted data")
+	}
+
+	block, err := aes.NewCipher(pbkdf2Key(prf, passphrase, kdf.Salt, kdf.Iterations, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(info.Data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.Data)
+
+	// Remove the PKCS#7 padding, a bad one (or content) means a wrong passphrase.
+	n := int(plaintext[len(plaintext)-1])
+	if n == 0 || n > aes.BlockSize {
+		return nil, x509.IncorrectPasswordError
+	}
+
+	for _, b := range plaintext[len(plaintext)-n:] {
+		if int(b) != n {
+			return nil, x509.IncorrectPasswordError
+		}
+	}
+
+	plaintext = plaintext[:len(plaintext)-n]
+	if rest, err := asn1.Unmarshal(plaintext, new(asn1.RawValue)); err != nil || len(rest) > 0 {
+		return nil, x509.IncorrectPasswordError
+	}
+
+	return plaintext, nil
+}
+
+// pbkdf2Key is the PBKDF2 (RFC 8018) function of the HMAC of the "h" hash.
+func pbkdf2Key(h func() hash.Hash, password, salt []byte, iterations, size int) []byte {
+	prf := hmac.New(h, password)
+	key := make([]byte, 0, size+prf.Size())
+	var counter [4]byte
+	for block := uint32(1); len(key) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], block)
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:size]
+}
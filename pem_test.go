@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+func TestDecryptPEM(t *testing.T) {
+	passphrase := []byte("kataras")
+
+	tests := []struct {
+		encrypted string
+		plain     string
+		parse     func([]byte) (interface{}, error)
+	}{
+		{ // PKCS#8, PBKDF2 of HMAC-SHA256 and AES-256-CBC.
+			"./_testfiles/rsa_private_key_encrypted.pem",
+			"./_testfiles/rsa_private_key.pem",
+			func(b []byte) (interface{}, error) { return ParsePrivateKeyRSA(b) },
+		},
+		{ // Legacy, AES-128-CBC.
+			"./_testfiles/ecdsa_private_key_encrypted.pem",
+			"./_testfiles/ecdsa_private_key.pem",
+			func(b []byte) (interface{}, error) { return ParsePrivateKeyECDSA(b) },
+		},
+		{ // PKCS#8, PBKDF2 of HMAC-SHA1 (default) and AES-128-CBC.
+			"./_testfiles/ed25519_private_key_encrypted.pem",
+			"./_testfiles/ed25519_private_key.pem",
+			func(b []byte) (interface{}, error) { return ParsePrivateKeyEdDSA(b) },
+		},
+	}
+
+	for _, tt := range tests {
+		encrypted, err := ReadFile(tt.encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = tt.parse(encrypted); !errors.Is(err, ErrEncryptedKey) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.encrypted, ErrEncryptedKey, err)
+		}
+
+		if _, err = DecryptPEM(encrypted, []byte("wrong")); err != x509.IncorrectPasswordError {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.encrypted, x509.IncorrectPasswordError, err)
+		}
+
+		decrypted, err := DecryptPEM(encrypted, passphrase)
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.encrypted, err)
+		}
+
+		got, err := tt.parse(decrypted)
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.encrypted, err)
+		}
+
+		plain, err := ReadFile(tt.plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, err := tt.parse(plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !got.(interface{ Equal(crypto.PrivateKey) bool }).Equal(expected) {
+			t.Fatalf("[%s] decrypted key does not match the plain one", tt.encrypted)
+		}
+
+		// Unencrypted keys are returned as they are.
+		if b, err := DecryptPEM(plain, passphrase); err != nil || string(b) != string(plain) {
+			t.Fatalf("[%s] expected the plain key as it is (%v)", tt.plain, err)
+		}
+	}
+}
+
+func TestParseKeyDER(t *testing.T) {
+	derOf := func(filename string) []byte {
+		b, err := ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		block, _ := pem.Decode(b)
+		return block.Bytes
+	}
+
+	if _, err := ParsePrivateKeyRSA(derOf("./_testfiles/rsa_private_key.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePublicKeyRSA(derOf("./_testfiles/rsa_public_key.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePrivateKeyECDSA(derOf("./_testfiles/ecdsa_private_key.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePrivateKeyEdDSA(derOf("./_testfiles/ed25519_private_key.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePublicKeyEdDSA(derOf("./_testfiles/ed25519_public_key.pem")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParsePrivateKeyECDSAPKCS8(t *testing.T) {
+	privateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParsePrivateKeyECDSA(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Equal(privateKey) {
+		t.Fatalf("expected the same private key")
+	}
+
+	// PKCS#8 keys of other types are rejected.
+	rsaKey, err := LoadPrivateKeyRSA("./_testfiles/rsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if der, err = x509.MarshalPKCS8PrivateKey(rsaKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParsePrivateKeyECDSA(der); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
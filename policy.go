@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// ErrPolicyDenied indicates that a verified token's claims
+// are not authorized by a `Policy`.
+var ErrPolicyDenied = errors.New("token denied by policy")
+
+// Policy is a claims-based authorization rule.
+// It reports whether the decoded claims of a verified token are authorized.
+// Policies are composed through the builder functions, e.g.
+//  policy := jwt.All(
+//    jwt.ClaimContains("aud", "billing"),
+//    jwt.ClaimContains("roles", "admin"),
+//  )
+//
+// A Policy implements the `PayloadValidator` interface,
+// so it can be passed to the `Verify` functions (and `Verifier.Validators`) as it's,
+// or it can be evaluated standalone through its `Authorize` method.
+type Policy func(claims Map) bool
+
+var _ PayloadValidator = Policy(nil)
+
+// Authorize evaluates the policy against the claims of a verified token.
+// Returns ErrPolicyDenied if the claims are not authorized.
+func (p Policy) Authorize(t *VerifiedToken) error {
+	return p.ValidatePayload(context.Background(), t.Token, t.Payload, t.StandardClaims, nil)
+}
+
+// ValidatePayload completes the PayloadValidator interface.
+// Returns ErrPolicyDenied if the claims are not authorized.
+func (p Policy) ValidatePayload(ctx context.Context, token, payload []byte, standardClaims Claims, err error) error {
+	if err != nil {
+		return err
+	}
+
+	var claims Map
+	if err = defaultUnmarshal(payload, &claims); err != nil {
+		return err
+	}
+
+	if !p(claims) {
+		return ErrPolicyDenied
+	}
+
+	return nil
+}
+
+// ValidateToken completes the TokenValidator interface.
+// See `PayloadValidatorFunc.ValidateToken`.
+func (p Policy) ValidateToken(token []byte, standardClaims Claims, err error) error {
+	return PayloadValidatorFunc(p.ValidatePayload).ValidateToken(token, standardClaims, err)
+}
+
+// All returns a Policy which authorizes the claims
+// if all of the "policies" authorize them.
+func All(policies ...Policy) Policy {
+	return func(claims Map) bool {
+		for _, p := range policies {
+			if !p(claims) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns a Policy which authorizes the claims
+// if at least one of the "policies" authorizes them.
+func Any(policies ...Policy) Policy {
+	return func(claims Map) bool {
+		for _, p := range policies {
+			if p(claims) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a Policy which authorizes the claims
+// if the "policy" does not authorize them.
+func Not(policy Policy) Policy {
+	return func(claims Map) bool {
+		return !policy(claims)
+	}
+}
+
+// ClaimExists returns a Policy which authorizes the claims
+// if the "name" claim exists and it's not null.
+func ClaimExists(name string) Policy {
+	return func(claims Map) bool {
+		return claims[name] != nil
+	}
+}
+
+// ClaimEquals returns a Policy which authorizes the claims
+// if the "name" claim deep-equals to the "value", e.g.
+//  ClaimEquals("tenant", "acme")
+func ClaimEquals(name string, value interface{}) Policy {
+	expected, err := normalizeClaimValue(value)
+	return func(claims Map) bool {
+		if err != nil {
+			return false
+		}
+
+		got, ok := claims[name]
+		return ok && reflect.DeepEqual(expected, got)
+	}
+}
+
+// ClaimContains returns a Policy which authorizes the claims
+// if the "name" claim is an array which contains the "value"
+// or if it's a single value equal to the "value"
+// (e.g. the "aud" claim can be a string or an array of strings), e.g.
+//  ClaimContains("roles", "admin")
+func ClaimContains(name string, value interface{}) Policy {
+	expected, err := normalizeClaimValue(value)
+	return func(claims Map) bool {
+		if err != nil {
+			return false
+		}
+
+		switch got := claims[name].(type) {
+		case nil:
+			return false
+		case []interface{}:
+			for _, elem := range got {
+				if reflect.DeepEqual(expected, elem) {
+					return true
+				}
+			}
+
+			return false
+		default:
+			return reflect.DeepEqual(expected, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{
+		"aud":    []string{"billing"},
+		"roles":  []string{"user", "admin"},
+		"tenant": "acme",
+		"level":  3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		policy  Policy
+		allowed bool
+	}{
+		{All(ClaimContains("aud", "billing"), ClaimContains("roles", "admin")), true},
+		{All(ClaimContains("aud", "billing"), ClaimContains("roles", "owner")), false},
+		{Any(ClaimContains("roles", "owner"), ClaimEquals("tenant", "acme")), true},
+		{Any(ClaimContains("roles", "owner"), ClaimEquals("tenant", "other")), false},
+		{ClaimEquals("level", 3), true},
+		{ClaimEquals("roles", []string{"user", "admin"}), true},
+		{ClaimEquals("roles", []string{"admin"}), false},
+		{Not(ClaimExists("disabled")), true},
+		{ClaimExists("tenant"), true},
+		{ClaimContains("missing", "value"), false},
+	}
+
+	for i, tt := range tests {
+		_, err = Verify(testAlg, testSecret, token, tt.policy)
+		if tt.allowed && err != nil {
+			t.Fatalf("[%d] expected to be allowed but got: %v", i, err)
+		}
+
+		if !tt.allowed && err != ErrPolicyDenied {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrPolicyDenied, err)
+		}
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ClaimEquals("tenant", "other").Authorize(verifiedToken); err != ErrPolicyDenied {
+		t.Fatalf("expected error: %v but got: %v", ErrPolicyDenied, err)
+	}
+
+	// Test the TokenValidator fallback.
+	if err = ClaimEquals("tenant", "acme").ValidateToken(token, Claims{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
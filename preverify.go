@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPreVerify indicates a token which was rejected by a `PreVerifyFunc`
+// before its signature was verified, e.g. of an unknown issuer.
+var ErrPreVerify = errors.New("token rejected before verification")
+
+// PreVerifyFunc is a check of the unverified header and claims of a token,
+// see the `Verifier.PreVerify` field. It runs before the key lookup and the signature verification,
+// so gateways can reject obviously bad tokens (e.g. of an unknown issuer or a banned key id)
+// without spending CPU on signature checks during attacks.
+// The token's values can't be trusted: a check may only reject tokens, passing it proves nothing.
+type PreVerifyFunc func(ctx context.Context, t *UnverifiedToken) error
+
+// PreVerifyAll returns a PreVerifyFunc which runs the "checks" in order
+// and returns the first error.
+func PreVerifyAll(checks ...PreVerifyFunc) PreVerifyFunc {
+	return func(ctx context.Context, t *UnverifiedToken) error {
+		for _, check := range checks {
+			if err := check(ctx, t); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// AllowIssuers returns a PreVerifyFunc which rejects the tokens of an "iss" claim
+// which is not one of the "issuers" (see `NormalizeIssuer`) with a type of ErrPreVerify.
+// Note that the claims of an encrypted payload can't be read, so all of its tokens are rejected.
+//
+// Usage:
+//  verifier.PreVerify = jwt.AllowIssuers("https://idp.example.com")
+func AllowIssuers(issuers ...string) PreVerifyFunc {
+	return func(_ context.Context, t *UnverifiedToken) error {
+		for _, issuer := range issuers {
+			if sameIssuer(issuer, t.UnverifiedClaims.Issuer) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: unknown issuer", ErrPreVerify)
+	}
+}
+
+// DenyKeyIDs returns a PreVerifyFunc which rejects the tokens of a "kid" header
+// which is one of the "kids" (e.g. of a compromised key) with a type of ErrPreVerify.
+//
+// Usage:
+//  verifier.PreVerify = jwt.PreVerifyAll(jwt.AllowIssuers("myapp"), jwt.DenyKeyIDs("2023-leaked"))
+func DenyKeyIDs(kids ...string) PreVerifyFunc {
+	return func(_ context.Context, t *UnverifiedToken) error {
+		if containsString(kids, t.KeyID()) {
+			return fmt.Errorf("%w: banned kid", ErrPreVerify)
+		}
+
+		return nil
+	}
+}
+
+// preVerify decodes the token and runs the Verifier's PreVerify hook.
+func (v *Verifier) preVerify(ctx context.Context, token []byte) error {
+	report := verifyReport(ctx)
+	start := report.now()
+
+	t, err := Decode(token)
+	if err == nil {
+		preVerify := v.PreVerify
+		if v.RecoverPanics {
+			preVerify = recoverPreVerify(preVerify)
+		}
+
+		err = preVerify(ctx, t)
+	}
+
+	report.add("preverify", start, err)
+	if err != nil && report != nil {
+		report.Err = err
+	}
+
+	return err
+}
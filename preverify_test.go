@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type countingVerifier struct {
+	AlgVerifier
+	calls int
+}
+
+func (a *countingVerifier) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
+	a.calls++
+	return a.AlgVerifier.Verify(key, headerAndPayload, signature)
+}
+
+func TestVerifierPreVerify(t *testing.T) {
+	alg := &countingVerifier{AlgVerifier: testAlg}
+	verifier := NewVerifier(alg, testSecret)
+	verifier.PreVerify = PreVerifyAll(AllowIssuers("myapp"), DenyKeyIDs("leaked"))
+
+	sign := func(kid string, claims Claims) []byte {
+		t.Helper()
+
+		header := Header{{Name: "alg", Value: testAlg.Name()}, {Name: "typ", Value: "JWT"}}
+		if kid != "" {
+			header = append(header, HeaderField{Name: "kid", Value: kid})
+		}
+
+		token, err := SignWithHeader(testAlg, testSecret, header, claims, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return token
+	}
+
+	if _, err := verifier.VerifyToken(sign("current", Claims{Issuer: "myapp"})); err != nil {
+		t.Fatal(err)
+	}
+
+	if alg.calls != 1 {
+		t.Fatalf("expected a signature verification but got: %d", alg.calls)
+	}
+
+	for _, token := range [][]byte{
+		sign("current", Claims{Issuer: "attacker"}),
+		sign("leaked", Claims{Issuer: "myapp"}),
+	} {
+		if _, err := verifier.VerifyToken(token); !errors.Is(err, ErrPreVerify) {
+			t.Fatalf("expected error: %v but got: %v", ErrPreVerify, err)
+		}
+	}
+
+	if alg.calls != 1 {
+		t.Fatalf("expected no signature verification of the rejected tokens but got: %d", alg.calls-1)
+	}
+
+	ctx, report := WithVerifyReport(context.Background())
+	if _, err := verifier.VerifyTokenContext(ctx, []byte("not.a.token")); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	if len(report.Checks) != 1 || report.Checks[0].Name != "preverify" || !errors.Is(report.Err, ErrTokenForm) {
+		t.Fatalf("unexpected report: %s", report)
+	}
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// ErrPanic indicates a validator (or a hook) which panicked,
+// the panic is converted to a verification error by `Recover` and the `Verifier.RecoverPanics` field.
+var ErrPanic = errors.New("recovered panic")
+
+// PanicEntry holds the information of a recovered panic,
+// it's passed to the `OnPanic` hook.
+type PanicEntry struct {
+	Time  time.Time   // The time of the panic.
+	Value interface{} // The value passed to panic.
+	Err   error       // The error the panic was converted to, a type of ErrPanic.
+	Stack []byte      // The stack trace of the panicking goroutine.
+}
+
+// OnPanic is an optional hook which is called on every recovered panic of a validator or a hook,
+// see `Recover` and the `Verifier.RecoverPanics` field. The verification fails with a type of ErrPanic
+// but the stack trace is reported to this hook only, it can be used to log it.
+// It should be set once on initialization of the program and it must be safe for concurrent use.
+//
+// Usage:
+//  jwt.OnPanic = func(entry jwt.PanicEntry) {
+//    log.Printf("verify: %v\n%s", entry.Err, entry.Stack)
+//  }
+var OnPanic func(entry PanicEntry)
+
+// recoverPanic converts a panic to an ErrPanic error, stored in the "err",
+// it must be called through defer.
+func recoverPanic(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	*err = fmt.Errorf("%w: %v", ErrPanic, r)
+	if OnPanic != nil {
+		OnPanic(PanicEntry{
+			Time:  Clock(),
+			Value: r,
+			Err:   *err,
+			Stack: debug.Stack(),
+		})
+	}
+}
+
+// Recover wraps the "validator" so a panic of it fails the verification
+// with a type of ErrPanic (reported to the `OnPanic` hook) instead of crashing the program.
+// The wrapped validator keeps its ContextValidator and PayloadValidator implementations.
+// Look the `Verifier.RecoverPanics` field to wrap all the validators and hooks of a Verifier.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(alg, key, token, jwt.Recover(thirdPartyValidator))
+func Recover(validator TokenValidator) TokenValidator {
+	if _, ok := validator.(recovered); ok {
+		return validator
+	}
+
+	return recovered{validator: validator}
+}
+
+type recovered struct {
+	validator TokenValidator
+}
+
+// ValidateToken completes the TokenValidator interface.
+func (r recovered) ValidateToken(token []byte, standardClaims Claims, err error) (validationErr error) {
+	defer recoverPanic(&validationErr)
+	return r.validator.ValidateToken(token, standardClaims, err)
+}
+
+// ValidatePayload completes the PayloadValidator interface,
+// it calls the wrapped validator the same way it would be called without the wrapper.
+func (r recovered) ValidatePayload(ctx context.Context, token, payload []byte, standardClaims Claims, err error) (validationErr error) {
+	defer recoverPanic(&validationErr)
+	return validateToken(ctx, r.validator, token, payload, standardClaims, err)
+}
+
+// recoverValidators returns the "validators" wrapped with `Recover`.
+func recoverValidators(validators []TokenValidator) []TokenValidator {
+	for i, validator := range validators {
+		validators[i] = Recover(validator)
+	}
+
+	return validators
+}
+
+// recoverPreVerify returns the "preVerify" wrapped so a panic of it returns a type of ErrPanic.
+func recoverPreVerify(preVerify PreVerifyFunc) PreVerifyFunc {
+	return func(ctx context.Context, t *UnverifiedToken) (err error) {
+		defer recoverPanic(&err)
+		return preVerify(ctx, t)
+	}
+}
+
+// recoverSessionResolver returns the "resolver" wrapped so a panic of it returns a type of ErrPanic.
+func recoverSessionResolver(resolver SessionResolver) SessionResolver {
+	return func(ctx context.Context, credential []byte) (claims interface{}, err error) {
+		defer recoverPanic(&err)
+		return resolver(ctx, credential)
+	}
+}
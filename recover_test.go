@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecover(t *testing.T) {
+	var entries []PanicEntry
+	OnPanic = func(entry PanicEntry) { entries = append(entries, entry) }
+	defer func() { OnPanic = nil }()
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buggy := TokenValidatorFunc(func(token []byte, standardClaims Claims, err error) error {
+		var claims map[string]string
+		claims["sub"] = standardClaims.Subject // nil map.
+		return err
+	})
+
+	_, err = Verify(testAlg, testSecret, token, Recover(buggy))
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected error: %v but got: %v", ErrPanic, err)
+	}
+
+	if len(entries) != 1 || !errors.Is(entries[0].Err, ErrPanic) || !strings.Contains(string(entries[0].Stack), "TestRecover") {
+		t.Fatalf("unexpected panic entries: %#+v", entries)
+	}
+
+	if reason := FailureReason(err); reason != "panic" {
+		t.Fatalf("expected reason: panic but got: %s", reason)
+	}
+
+	// The wrapped validator is still called with the context and the payload.
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	var gotPayload bool
+	payloadValidator := PayloadValidatorFunc(func(ctx context.Context, token, payload []byte, standardClaims Claims, err error) error {
+		if ctx.Value(ctxKey{}) != "value" {
+			panic("missing context value")
+		}
+
+		gotPayload = strings.Contains(string(payload), "kataras")
+		return err
+	})
+
+	if _, err = VerifyContext(ctx, testAlg, testSecret, token, Recover(payloadValidator)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !gotPayload {
+		t.Fatalf("expected the payload validator to receive the payload")
+	}
+}
+
+func TestVerifierRecoverPanics(t *testing.T) {
+	defer func() { OnPanic = nil }()
+	var panics int
+	OnPanic = func(entry PanicEntry) { panics++ }
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.RecoverPanics = true
+	verifier.PreVerify = func(ctx context.Context, t *UnverifiedToken) error {
+		panic("pre verify")
+	}
+
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected error: %v but got: %v", ErrPanic, err)
+	}
+
+	verifier.PreVerify = nil
+	verifier.Validators = []TokenValidator{TokenValidatorFunc(func(token []byte, standardClaims Claims, err error) error {
+		panic("validator")
+	})}
+
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected error: %v but got: %v", ErrPanic, err)
+	}
+
+	verifier.Validators = nil
+	verifier.SessionResolver = func(ctx context.Context, credential []byte) (interface{}, error) {
+		panic("session")
+	}
+
+	if _, err = verifier.VerifyToken([]byte("session-id")); !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected error: %v but got: %v", ErrPanic, err)
+	}
+
+	if panics != 3 {
+		t.Fatalf("expected 3 recovered panics but got: %d", panics)
+	}
+}
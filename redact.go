@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// RedactedValue is the value which replaces any redacted data.
+const RedactedValue = "[REDACTED]"
+
+// RedactedClaims is a list of claim names which values are considered sensitive.
+// Their values are replaced with the RedactedValue on debug dumps (see `RedactPayload`)
+// and on the information passed to the logger hooks (e.g. `Audit`).
+// The matching is case-insensitive and it applies to nested claims too.
+// Modify it once on initialization to append custom secrets, e.g.
+//  jwt.RedactedClaims = append(jwt.RedactedClaims, "ssn", "sub")
+//
+// Note that the token themselves are always redacted, see `RedactToken`.
+var RedactedClaims = []string{"email", "phone_number", "address", "password", "secret"}
+
+func isRedactedClaim(name string) bool {
+	for _, claim := range RedactedClaims {
+		if strings.EqualFold(claim, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redactString(name, value string) string {
+	if value != "" && isRedactedClaim(name) {
+		return RedactedValue
+	}
+
+	return value
+}
+
+// RedactPayload returns a JSON copy of the given (decoded) payload
+// with the values of the `RedactedClaims` replaced by the RedactedValue.
+// If the payload is not a JSON object then the RedactedValue is returned instead.
+func RedactPayload(payload []byte) []byte {
+	var claims Map
+	if err := defaultUnmarshal(payload, &claims); err != nil || claims == nil {
+		return []byte(RedactedValue)
+	}
+
+	redactMap(claims)
+
+	b, err := Marshal(claims)
+	if err != nil {
+		return []byte(RedactedValue)
+	}
+
+	return b
+}
+
+func redactMap(m Map) {
+	for name, value := range m {
+		if isRedactedClaim(name) {
+			m[name] = RedactedValue
+			continue
+		}
+
+		redactValue(value)
+	}
+}
+
+func redactValue(value interface{}) {
+	switch v := value.(type) {
+	case Map:
+		redactMap(v)
+	case []interface{}:
+		for _, elem := range v {
+			redactValue(elem)
+		}
+	}
+}
+
+// RedactToken returns a log-safe representation of a compact token:
+// its header part followed by the RedactedValue (e.g. "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.[REDACTED]").
+// The header is kept so the algorithm (and key id) can still be inspected.
+func RedactToken(token []byte) string {
+	if idx := bytes.IndexByte(token, '.'); idx > 0 {
+		return string(token[:idx]) + "." + RedactedValue
+	}
+
+	if len(token) == 0 {
+		return ""
+	}
+
+	return RedactedValue
+}
+
+// String completes the fmt.Stringer interface.
+// It returns a redacted representation of the verified token,
+// the raw token and its signature are never included
+// and the payload is redacted through `RedactPayload`.
+func (t *VerifiedToken) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("{Token:%s Header:%s Payload:%s}", RedactToken(t.Token), t.Header, RedactPayload(t.Payload))
+}
+
+// GoString completes the fmt.GoStringer interface,
+// so the %#v verb prints the redacted representation too, see `String`.
+func (t *VerifiedToken) GoString() string {
+	return "&jwt.VerifiedToken" + t.String()
+}
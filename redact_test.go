@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactPayload(t *testing.T) {
+	tests := []struct {
+		payload  string
+		expected string
+	}{
+		{`{"username":"kataras","email":"kataras2006@hotmail.com"}`, `{"email":"[REDACTED]","username":"kataras"}`},
+		{`{"user":{"Email":"kataras2006@hotmail.com","roles":[{"secret":"s"}]}}`, `{"user":{"Email":"[REDACTED]","roles":[{"secret":"[REDACTED]"}]}}`},
+		{`{"exp":1616767366}`, `{"exp":1616767366}`},
+		{`not a json`, RedactedValue},
+	}
+
+	for i, tt := range tests {
+		if got := string(RedactPayload([]byte(tt.payload))); got != tt.expected {
+			t.Fatalf("[%d] expected redacted payload:\n%s\nbut got:\n%s", i, tt.expected, got)
+		}
+	}
+}
+
+func TestRedactToken(t *testing.T) {
+	token := []byte("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJmb28iOiJiYXIifQ.signature")
+	if expected, got := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9."+RedactedValue, RedactToken(token); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	if got := RedactToken([]byte("invalid")); got != RedactedValue {
+		t.Fatalf("expected: %s but got: %s", RedactedValue, got)
+	}
+}
+
+func TestVerifiedTokenString(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"email": "kataras2006@hotmail.com", "username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature := string(verifiedToken.Signature)
+	for _, format := range []string{"%v", "%+v", "%s", "%#v", "%#+v"} {
+		got := fmt.Sprintf(format, verifiedToken)
+		if strings.Contains(got, "kataras2006") || strings.Contains(got, string(token)) || strings.Contains(got, signature) {
+			t.Fatalf("[%s] expected sensitive data to be redacted but got: %s", format, got)
+		}
+
+		if !strings.Contains(got, "kataras") {
+			t.Fatalf("[%s] expected non-sensitive claims to be kept but got: %s", format, got)
+		}
+	}
+}
+
+func TestAuditRedacted(t *testing.T) {
+	var entry AuditEntry
+	Audit = func(e AuditEntry) {
+		entry = e
+	}
+	RedactedClaims = append(RedactedClaims, "sub")
+	t.Cleanup(func() {
+		Audit = nil
+		RedactedClaims = RedactedClaims[:len(RedactedClaims)-1]
+	})
+
+	if _, err := Sign(testAlg, testSecret, Claims{ID: "jti:1", Subject: "kataras"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.Subject != RedactedValue || entry.ID != "jti:1" {
+		t.Fatalf("expected subject to be redacted but got: %#+v", entry)
+	}
+}
@@ -0,0 +1,233 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	// ErrRefreshMaxAge indicates that a token pair was requested
+	// from an `Issuer` without a RefreshMaxAge.
+	ErrRefreshMaxAge = errors.New("issuer: refresh max age is required")
+	// ErrRefreshReplays indicates that a refresh token was rotated
+	// by an `Issuer` without a RefreshReplays store.
+	ErrRefreshReplays = errors.New("issuer: refresh replays store is required")
+	// ErrTokenUse indicates a refresh token which is used as an access token
+	// or an access token which is used as a refresh token, see `ClaimTokenUse`.
+	ErrTokenUse = errors.New("invalid token use")
+)
+
+// ClaimTokenUse is the name of the claim which marks the refresh tokens of an `Issuer`,
+// its value is TokenUseRefresh. A `Verifier` rejects the marked tokens with ErrTokenUse,
+// except on the refresh token verification of the `Issuer.Refresh` and `Issuer.RefreshPair` methods,
+// which require it. So a refresh token is never accepted as an access token, even of the same key.
+const ClaimTokenUse = "token_use"
+
+// TokenUseRefresh is the value of the `ClaimTokenUse` claim of the refresh tokens.
+const TokenUseRefresh = "refresh"
+
+// refreshContextKey marks the Context of a refresh token verification.
+type refreshContextKey struct{}
+
+// validateTokenUse is a PayloadValidatorFunc which requires the `ClaimTokenUse` claim
+// on a refresh token verification (see refreshContextKey) and rejects it on any other one.
+func validateTokenUse(ctx context.Context, token, payload []byte, _ Claims, err error) error {
+	if err != nil {
+		return err
+	}
+
+	isRefresh := false
+	if bytes.Contains(payload, []byte(`"`+ClaimTokenUse+`"`)) {
+		var claims struct {
+			TokenUse string `json:"token_use"`
+		}
+		if err = json.Unmarshal(payload, &claims); err != nil {
+			return err
+		}
+
+		isRefresh = claims.TokenUse == TokenUseRefresh
+	}
+
+	if wantRefresh := ctx.Value(refreshContextKey{}) != nil; isRefresh != wantRefresh {
+		return ErrTokenUse
+	}
+
+	return nil
+}
+
+// standardClaimNames are the JSON names of the `Claims` fields.
+var standardClaimNames = []string{"nbf", "iat", "exp", "jti", "iss", "sub", "aud"}
+
+// Refresh verifies the "refreshToken" through the "verifier" and generates
+// a new access token for its subject ("sub" claim).
+// The refresh token must carry the `ClaimTokenUse` claim, e.g. one of a `TokenPair`,
+// otherwise ErrTokenUse is returned.
+// The custom claims of the refresh token are carried over to the access token,
+// the standard ones are set by the Issuer's configuration.
+//
+// The verifier's Migrations (if any) upgrade the refresh token's claims first,
+// so the new access tokens get the current claims layout even when the refresh token predates it.
+// Set the Issuer's Version to the current version and the "ver" claim is replaced as well.
+//
+// Usage:
+//  refreshVerifier := jwt.NewVerifier(jwt.HS256, refreshKey)
+//  refreshVerifier.Migrations = migrations
+//
+//  accessToken, err := issuer.Refresh(ctx, refreshVerifier, refreshToken)
+func (i *Issuer) Refresh(ctx context.Context, verifier *Verifier, refreshToken []byte) ([]byte, error) {
+	verifiedToken, customClaims, err := i.verifyRefresh(ctx, verifier, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.Lineage {
+		if customClaims, err = withLineage(customClaims, verifiedToken.StandardClaims.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return i.TokenContext(ctx, verifiedToken.StandardClaims.Subject, customClaims)
+}
+
+// TokenPair generates a new access token, of the Issuer's lifetime, and a refresh token,
+// of the RefreshMaxAge lifetime, a random "jti" and the `ClaimTokenUse` claim,
+// for the given "subject" and the same "customClaims".
+// The Quota (if any) is consulted once for both of them.
+// Returns ErrRefreshMaxAge if the Issuer's RefreshMaxAge field is not set.
+func (i *Issuer) TokenPair(ctx context.Context, subject string, customClaims interface{}) (TokenPair, error) {
+	return i.tokenPair(ctx, subject, customClaims, "")
+}
+
+// tokenPair same as TokenPair but it accepts the "jti" of the rotated refresh token
+// of a RefreshPair, the parent of the new refresh token (see the Lineage field).
+func (i *Issuer) tokenPair(ctx context.Context, subject string, customClaims interface{}, parentID string) (TokenPair, error) {
+	if i.RefreshMaxAge <= 0 {
+		return TokenPair{}, ErrRefreshMaxAge
+	}
+
+	if i.Quota != nil {
+		if err := i.Quota(ctx, subject); err != nil {
+			return TokenPair{}, err
+		}
+	}
+
+	// A unique "jti" makes each refresh token distinct, so a rotated one
+	// can be invalidated without the new one, even if signed in the same second.
+	claims, err := claimsMap(customClaims)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refreshID := string(Base64Encode(MustGenerateRandom(16)))
+	refreshClaims := make(Map, len(claims)+3)
+	for k, v := range claims {
+		refreshClaims[k] = v
+	}
+	refreshClaims["jti"] = refreshID
+	refreshClaims[ClaimTokenUse] = TokenUseRefresh
+
+	if i.Lineage {
+		delete(refreshClaims, ClaimParent)
+		if parentID != "" {
+			refreshClaims[ClaimParent] = parentID
+		}
+
+		if customClaims, err = withLineage(claims, refreshID); err != nil {
+			return TokenPair{}, err
+		}
+	}
+
+	accessToken, err := i.sign(ctx, "", nil, subject, customClaims)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refresh := &SigningProfile{MaxAge: i.RefreshMaxAge, Audience: i.Audience}
+	refreshToken, err := i.sign(ctx, "", refresh, subject, refreshClaims)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return NewTokenPair(accessToken, refreshToken), nil
+}
+
+// RefreshPair same as `Refresh` but it rotates the refresh token too: it returns
+// a new pair of an access and a refresh token (see `TokenPair`).
+// The "refreshToken" is recorded by the Issuer's RefreshReplays store on its verification,
+// before the new pair is signed, so each refresh token can be exchanged once
+// and concurrent exchanges of the same one fail with ErrReplayed.
+// It's invalidated through the verifier's Blocklist too, if it's a `TokenInvalidator`,
+// e.g. so the `Refresh` method (or another instance of a shared blocklist) rejects it.
+// Returns ErrRefreshReplays if the Issuer's RefreshReplays field is nil.
+//
+// Usage:
+//  refreshVerifier := jwt.NewVerifier(jwt.HS256, refreshKey)
+//  refreshVerifier.Blocklist = jwt.NewBlocklist(time.Hour)
+//
+//  issuer.RefreshMaxAge = 24 * time.Hour
+//  issuer.RefreshReplays = jwt.NewReplayStore(issuer.RefreshMaxAge)
+//  tokenPair, err := issuer.RefreshPair(ctx, refreshVerifier, refreshToken)
+func (i *Issuer) RefreshPair(ctx context.Context, verifier *Verifier, refreshToken []byte) (TokenPair, error) {
+	if i.RefreshMaxAge <= 0 {
+		return TokenPair{}, ErrRefreshMaxAge
+	}
+
+	if i.RefreshReplays == nil {
+		return TokenPair{}, ErrRefreshReplays
+	}
+
+	verifiedToken, customClaims, err := i.verifyRefresh(ctx, verifier, refreshToken, i.RefreshReplays)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	tokenPair, err := i.tokenPair(ctx, verifiedToken.StandardClaims.Subject, customClaims, verifiedToken.StandardClaims.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if invalidator, ok := verifier.Blocklist.(TokenInvalidator); ok {
+		if err = invalidator.InvalidateToken(verifiedToken.Token, verifiedToken.StandardClaims); err != nil {
+			return TokenPair{}, err
+		}
+	}
+
+	return tokenPair, nil
+}
+
+// verifyRefresh verifies the "refreshToken", which must carry the `ClaimTokenUse` claim,
+// and returns its custom claims (nil if none), without the standard ones, the token use
+// and the "ver" claim of a versioned Issuer. The "validators" run after the verifier's ones.
+func (i *Issuer) verifyRefresh(ctx context.Context, verifier *Verifier, refreshToken []byte, validators ...TokenValidator) (*VerifiedToken, interface{}, error) {
+	ctx = context.WithValue(ctx, refreshContextKey{}, struct{}{})
+	verifiedToken, err := verifier.VerifyTokenContext(ctx, refreshToken, validators...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var claims Map
+	if err = defaultUnmarshal(verifiedToken.Payload, &claims); err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range standardClaimNames {
+		delete(claims, name)
+	}
+	delete(claims, ClaimTokenUse)
+
+	if i.Version > 0 {
+		delete(claims, ClaimVersion)
+	}
+
+	if i.Lineage {
+		delete(claims, ClaimParent)
+	}
+
+	if len(claims) == 0 {
+		return verifiedToken, nil, nil
+	}
+
+	return verifiedToken, claims, nil
+}
@@ -0,0 +1,211 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIssuerRefresh(t *testing.T) {
+	refreshSecret := []byte("anothersercrethatmaycontainch@r$")
+
+	// The refresh token predates the "ver" claim convention (version 1).
+	refreshToken, err := Sign(testAlg, refreshSecret, Map{"sub": "kataras", "role": "admin", "jti": "refresh-1", ClaimTokenUse: TokenUseRefresh}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations := NewMigrations(3)
+	migrations.Register(1, func(claims Map) error {
+		claims["roles"] = []interface{}{claims["role"]}
+		delete(claims, "role")
+		return nil
+	})
+	migrations.Register(2, func(claims Map) error {
+		claims["tenant"] = "default"
+		return nil
+	})
+
+	refreshVerifier := NewVerifier(testAlg, refreshSecret)
+	refreshVerifier.Migrations = migrations
+
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Issuer = "myapp"
+	issuer.Version = 3
+
+	accessToken, err := issuer.Refresh(context.Background(), refreshVerifier, refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The access token is verified without migrations, it has the current layout.
+	verifiedToken, err := Verify(testAlg, testSecret, accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type claimsV3 struct {
+		Issuer  string   `json:"iss"`
+		Subject string   `json:"sub"`
+		ID      string   `json:"jti"`
+		Version int      `json:"ver"`
+		Role    string   `json:"role"`
+		Roles   []string `json:"roles"`
+		Tenant  string   `json:"tenant"`
+	}
+
+	var claims claimsV3
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := claimsV3{Issuer: "myapp", Subject: "kataras", Version: 3, Roles: []string{"admin"}, Tenant: "default"}
+	if !reflect.DeepEqual(claims, expected) {
+		t.Fatalf("expected claims: %#+v but got: %#+v", expected, claims)
+	}
+
+	if bytes.Count(verifiedToken.Payload, []byte(`"ver"`)) != 1 || bytes.Contains(verifiedToken.Payload, []byte(ClaimTokenUse)) {
+		t.Fatalf("expected a single version claim but got: %s", verifiedToken.Payload)
+	}
+
+	if verifiedToken.StandardClaims.Expiry-verifiedToken.StandardClaims.IssuedAt != 60 {
+		t.Fatalf("expected the access token lifetime of the issuer but got: %#+v", verifiedToken.StandardClaims)
+	}
+
+	// Invalid refresh tokens are rejected.
+	if _, err = issuer.Refresh(context.Background(), refreshVerifier, accessToken); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// Tokens without the refresh marker are not refresh tokens.
+	unmarked, err := Sign(testAlg, refreshSecret, Map{"sub": "kataras"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = issuer.Refresh(context.Background(), refreshVerifier, unmarked); err != ErrTokenUse {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenUse, err)
+	}
+
+	// And the refresh tokens are not access tokens.
+	if _, err = refreshVerifier.VerifyToken(refreshToken); err != ErrTokenUse {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenUse, err)
+	}
+}
+
+func TestIssuerRefreshPair(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	if _, err := issuer.TokenPair(context.Background(), "kataras", nil); err != ErrRefreshMaxAge {
+		t.Fatalf("expected error: %v but got: %v", ErrRefreshMaxAge, err)
+	}
+
+	issuer.RefreshMaxAge = time.Hour
+	tokenPair, err := issuer.TokenPair(context.Background(), "kataras", Map{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken, refreshToken := tokenPair.Tokens()
+	for token, lifetime := range map[string]int64{string(accessToken): 60, string(refreshToken): 3600} {
+		verifiedToken, err := Verify(testAlg, testSecret, []byte(token))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := verifiedToken.StandardClaims.Expiry - verifiedToken.StandardClaims.IssuedAt; got != lifetime {
+			t.Fatalf("expected lifetime: %d but got: %d", lifetime, got)
+		}
+
+		if !bytes.Contains(verifiedToken.Payload, []byte(`"role":"admin"`)) {
+			t.Fatalf("expected the custom claims but got: %s", verifiedToken.Payload)
+		}
+	}
+
+	// The access token verifier of the same key rejects the refresh token.
+	verifier := NewVerifier(testAlg, testSecret)
+	if _, err = verifier.VerifyToken(accessToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.VerifyToken(refreshToken); err != ErrTokenUse {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenUse, err)
+	}
+
+	refreshVerifier := NewVerifier(testAlg, testSecret)
+	refreshVerifier.Blocklist = NewBlocklist(0)
+
+	if _, err = issuer.RefreshPair(context.Background(), refreshVerifier, refreshToken); err != ErrRefreshReplays {
+		t.Fatalf("expected error: %v but got: %v", ErrRefreshReplays, err)
+	}
+
+	if _, err = issuer.RefreshPair(context.Background(), refreshVerifier, accessToken); err != ErrRefreshReplays {
+		t.Fatalf("expected error: %v but got: %v", ErrRefreshReplays, err)
+	}
+
+	issuer.RefreshReplays = NewReplayStore(0)
+	if _, err = issuer.RefreshPair(context.Background(), refreshVerifier, accessToken); err != ErrTokenUse {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenUse, err)
+	}
+
+	rotated, err := issuer.RefreshPair(context.Background(), refreshVerifier, refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, rotatedRefreshToken := rotated.Tokens()
+	if bytes.Equal(rotatedRefreshToken, refreshToken) {
+		t.Fatalf("expected a new refresh token")
+	}
+
+	// The refresh token is exchanged once.
+	if _, err = issuer.RefreshPair(context.Background(), refreshVerifier, refreshToken); err != ErrBlocked {
+		t.Fatalf("expected error: %v but got: %v", ErrBlocked, err)
+	}
+}
+
+func TestIssuerRefreshPairConcurrent(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.RefreshMaxAge = time.Hour
+	issuer.RefreshReplays = NewReplayStore(0)
+
+	tokenPair, err := issuer.TokenPair(context.Background(), "kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, refreshToken := tokenPair.Tokens()
+
+	// No Blocklist, the RefreshReplays store alone guards the exchange.
+	refreshVerifier := NewVerifier(testAlg, testSecret)
+
+	const n = 32
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		rotated  int
+		replayed int
+	)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			_, err := issuer.RefreshPair(context.Background(), refreshVerifier, refreshToken)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				rotated++
+			case ErrReplayed:
+				replayed++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rotated != 1 || replayed != n-1 {
+		t.Fatalf("expected a single exchange but got: %d exchanged and %d replayed", rotated, replayed)
+	}
+}
@@ -0,0 +1,340 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTokenInactive indicates that the remote verification service reported the token
+// as invalid (inactive), see `RemoteVerifier`.
+var ErrTokenInactive = errors.New("token is not active")
+
+type (
+	// RemoteVerification is the transport of a `RemoteVerifier`, e.g. an HTTP (see `Introspection`)
+	// or a gRPC client of an external authorization service.
+	RemoteVerification interface {
+		VerifyRemote(ctx context.Context, token []byte) (*RemoteResult, error)
+	}
+
+	// RemoteVerificationFunc is the interface-as-function shortcut for a RemoteVerification.
+	RemoteVerificationFunc func(ctx context.Context, token []byte) (*RemoteResult, error)
+
+	// RemoteResult is the decision of a remote verification service.
+	RemoteResult struct {
+		// Active reports whether the token is valid.
+		Active bool
+		// Payload is the JSON claims of the token as resolved by the service, optional.
+		// Defaults to the payload part of the token.
+		Payload []byte
+		// MaxAge is an optional caching duration of the result,
+		// it overrides the RemoteVerifier's CacheMaxAge when it's greater than zero.
+		MaxAge time.Duration
+	}
+)
+
+// VerifyRemote completes the RemoteVerification interface.
+// It calls itself.
+func (fn RemoteVerificationFunc) VerifyRemote(ctx context.Context, token []byte) (*RemoteResult, error) {
+	return fn(ctx, token)
+}
+
+// defaultRemoteCacheEntries is the default maximum number of cached results of a RemoteVerifier.
+const defaultRemoteCacheEntries = 10000
+
+// RemoteVerifier delegates the token verification to an external authorization service,
+// for organizations which centralize the token policy outside each service.
+// The tokens can be pre-checked locally (e.g. their signature) so invalid ones
+// never reach the service and the remote results are cached for the CacheMaxAge or their own MaxAge
+// (never beyond the token's expiration, the "exp" of the result payload for opaque tokens).
+// The standard claims of the result payload are validated as usual.
+// A RemoteVerifier is safe for concurrent use, its fields should not be modified after its first use.
+//
+// Usage:
+//  remote := jwt.NewRemoteVerifier(jwt.NewIntrospection("https://auth.example.com/oauth/introspect"))
+//  remote.Local = jwt.NewVerifier(jwt.RS256, publicKey)
+//  remote.CacheMaxAge = 30 * time.Second
+//
+//  verifiedToken, err := remote.Verify(ctx, token)
+type RemoteVerifier struct {
+	// Remote is the transport of the remote verification service, required.
+	Remote RemoteVerification
+	// Local is an optional Verifier which pre-checks the tokens before the remote verification.
+	Local *Verifier
+	// CacheMaxAge is the duration the remote results are cached for. Defaults to zero,
+	// only the results of a MaxAge (e.g. set by the remote service) are cached.
+	CacheMaxAge time.Duration
+	// CacheMaxEntries is the maximum number of cached results,
+	// the least recently used result is evicted on a full cache. Defaults to 10000.
+	CacheMaxEntries int
+	// CacheMaxStale is the grace period, after a cached result's expiration, which the result
+	// is still served for when the remote service fails (e.g. a network or a 5xx error),
+	// never beyond the token's expiration. Defaults to zero, the failure is returned.
+	CacheMaxStale time.Duration
+	// Breaker is an optional circuit breaker which fails fast when the service is down.
+	Breaker *CircuitBreaker
+	// FailurePolicy is the behavior when the remote service fails (and no stale result is cached).
+	// The default FailClosed fails the verifications with a *DependencyError,
+	// FailOpen accepts the tokens verified by the Local Verifier, it requires one.
+	FailurePolicy FailurePolicy
+
+	mu    sync.Mutex
+	cache *lru[[sha256.Size]byte, *remoteCacheEntry]
+}
+
+type remoteCacheEntry struct {
+	result     *RemoteResult
+	expiresAt  time.Time
+	staleUntil time.Time // expiresAt plus CacheMaxStale, capped to the token's expiration.
+}
+
+// NewRemoteVerifier returns a new RemoteVerifier of the "remote" transport.
+// The rest of the RemoteVerifier fields can be modified before its first use.
+func NewRemoteVerifier(remote RemoteVerification) *RemoteVerifier {
+	return &RemoteVerifier{Remote: remote}
+}
+
+// Verify pre-checks the "token" through the Local Verifier (if any) and verifies it
+// through the remote service (or its cached result). Then the standard claims
+// and the "validators" are validated against the result payload.
+// Returns ErrTokenInactive when the service reported the token as invalid.
+func (v *RemoteVerifier) Verify(ctx context.Context, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	var local *VerifiedToken
+	if v.Local != nil {
+		verifiedToken, err := v.Local.VerifyTokenContext(ctx, token) // it records its own verifications.
+		if err != nil {
+			return nil, err
+		}
+		local = verifiedToken
+	}
+
+	verifiedToken, err := v.verifyRemote(ctx, token, local, validators)
+	if local == nil || err != nil {
+		recordVerified(token, verifiedToken, err)
+	}
+
+	return verifiedToken, err
+}
+
+func (v *RemoteVerifier) verifyRemote(ctx context.Context, token []byte, local *VerifiedToken, validators []TokenValidator) (*VerifiedToken, error) {
+	if len(token) == 0 {
+		return nil, ErrMissing
+	}
+
+	result, err := v.result(ctx, token)
+	if err != nil {
+		if local == nil || v.FailurePolicy != FailOpen || !errors.Is(err, ErrDependencyUnavailable) {
+			return nil, err
+		}
+
+		recordDegraded(DependencyIntrospection)
+		result = &RemoteResult{Active: true} // the local payload.
+	}
+
+	if !result.Active {
+		return nil, ErrTokenInactive
+	}
+
+	verifiedToken := &VerifiedToken{Token: token, Payload: result.Payload}
+	if local != nil {
+		verifiedToken.Header, verifiedToken.Signature = local.Header, local.Signature
+		if verifiedToken.Payload == nil {
+			verifiedToken.Payload = local.Payload
+		}
+	} else if parts := bytes.Split(token, sep); len(parts) == 3 {
+		verifiedToken.Header, _ = Base64Decode(parts[0])
+		verifiedToken.Signature, _ = Base64Decode(parts[2])
+		if verifiedToken.Payload == nil {
+			verifiedToken.Payload, _ = Base64Decode(parts[1])
+		}
+	}
+
+	if len(verifiedToken.Payload) == 0 {
+		return nil, ErrTokenForm
+	}
+
+	claims, err := validatePayload(ctx, token, verifiedToken.Payload, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedToken.StandardClaims = claims
+	return verifiedToken, nil
+}
+
+// result returns the cached or the remote result of the "token".
+func (v *RemoteVerifier) result(ctx context.Context, token []byte) (*RemoteResult, error) {
+	key := sha256.Sum256(token)
+	now := Clock()
+
+	var (
+		stale *remoteCacheEntry
+		entry *remoteCacheEntry
+		ok    bool
+	)
+	v.mu.Lock()
+	if v.cache != nil { // created on the first cached result.
+		if entry, ok = v.cache.get(key); ok && !now.Before(entry.expiresAt) {
+			if now.Before(entry.staleUntil) {
+				stale = entry
+			} else {
+				v.cache.remove(key)
+			}
+			ok = false
+		}
+	}
+	v.mu.Unlock()
+
+	if ok {
+		return entry.result, nil
+	}
+
+	var result *RemoteResult
+	err := retry(ctx, v.Breaker, 0, 0, func() (err error) {
+		result, err = v.Remote.VerifyRemote(ctx, token)
+		return
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		if stale != nil {
+			return stale.result, nil
+		}
+
+		return nil, dependencyError(DependencyIntrospection, err)
+	}
+
+	if result == nil {
+		return nil, ErrTokenInactive
+	}
+
+	maxAge := v.CacheMaxAge
+	if result.MaxAge > 0 {
+		maxAge = result.MaxAge
+	}
+
+	if maxAge > 0 {
+		expiresAt, staleUntil := now.Add(maxAge), now.Add(maxAge+v.CacheMaxStale)
+		if exp, ok := remoteExpiry(token, result); ok {
+			if exp.Before(expiresAt) {
+				expiresAt = exp
+			}
+
+			if exp.Before(staleUntil) {
+				staleUntil = exp
+			}
+		}
+
+		v.mu.Lock()
+		if v.cache == nil {
+			maxEntries := v.CacheMaxEntries
+			if maxEntries <= 0 {
+				maxEntries = defaultRemoteCacheEntries
+			}
+			v.cache = newLRU[[sha256.Size]byte, *remoteCacheEntry](maxEntries)
+		}
+		v.cache.set(key, &remoteCacheEntry{result: result, expiresAt: expiresAt, staleUntil: staleUntil})
+		v.mu.Unlock()
+	}
+
+	return result, nil
+}
+
+// Invalidate completes the CacheInvalidator interface, it drops the cached results
+// on any event, e.g. a cached result may be of a token which is revoked now.
+func (v *RemoteVerifier) Invalidate(event Invalidation) {
+	v.mu.Lock()
+	v.cache = nil
+	v.mu.Unlock()
+}
+
+// remoteExpiry returns the expiration of the "token", its "exp" claim
+// or the "exp" of the result payload (e.g. of an opaque token), whichever comes first.
+func remoteExpiry(token []byte, result *RemoteResult) (time.Time, bool) {
+	exp, err := unverifiedExpiry(token)
+	ok := err == nil
+
+	if len(result.Payload) > 0 {
+		var claims Claims
+		if err = json.Unmarshal(result.Payload, &claims); err == nil && claims.Expiry > 0 {
+			if resultExp := claims.ExpiresAt(); !ok || resultExp.Before(exp) {
+				exp, ok = resultExp, true
+			}
+		}
+	}
+
+	return exp, ok
+}
+
+// maxIntrospectionResponseSize is the maximum size of an introspection response body.
+const maxIntrospectionResponseSize = 1 << 20
+
+// Introspection is a `RemoteVerification` of an OAuth 2.0 token introspection endpoint (RFC 7662).
+// The introspection response (its "active" member and the token's claims) is the result payload.
+type Introspection struct {
+	// URL is the introspection endpoint.
+	URL string
+	// ClientID and ClientSecret are the client credentials,
+	// sent through HTTP Basic authentication, if ClientID is not empty.
+	ClientID     string
+	ClientSecret string
+	// Client is the HTTP client, defaults to the package-level HTTPClient.
+	Client *http.Client
+}
+
+var _ RemoteVerification = (*Introspection)(nil)
+
+// NewIntrospection returns a new introspection client for the "url" endpoint.
+func NewIntrospection(url string) *Introspection {
+	return &Introspection{URL: url}
+}
+
+// VerifyRemote completes the RemoteVerification interface.
+func (c *Introspection) VerifyRemote(ctx context.Context, token []byte) (*RemoteResult, error) {
+	form := url.Values{"token": {string(token)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	resp, err := httpClient(c.Client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("introspection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &remoteStatusError{name: "introspection", statusCode: resp.StatusCode}
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxIntrospectionResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("introspection: %w", err)
+	}
+
+	var response struct {
+		Active bool `json:"active"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("introspection: decode: %w", err)
+	}
+
+	return &RemoteResult{Active: response.Active, Payload: body}, nil
+}
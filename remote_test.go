@@ -0,0 +1,243 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoteVerifier(t *testing.T) {
+	var calls uint32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&calls, 1)
+
+		if id, secret, ok := r.BasicAuth(); !ok || id != "my-client" || secret != "my-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		token := []byte(r.PostFormValue("token"))
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			w.Write([]byte(`{"active":false}`))
+			return
+		}
+
+		var claims Map
+		verifiedToken.Claims(&claims)
+		if claims["sub"] == "revoked" {
+			w.Write([]byte(`{"active":false}`))
+			return
+		}
+
+		claims["active"] = true
+		claims["scope"] = "remote"
+		json.NewEncoder(w).Encode(claims)
+	}))
+	defer srv.Close()
+
+	introspection := NewIntrospection(srv.URL)
+	introspection.ClientID, introspection.ClientSecret = "my-client", "my-secret"
+
+	remote := NewRemoteVerifier(introspection)
+	remote.Local = NewVerifier(testAlg, testSecret)
+	remote.CacheMaxAge = time.Minute
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		verifiedToken, err := remote.Verify(ctx, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var claims struct {
+			Subject string `json:"sub"`
+			Scope   string `json:"scope"`
+		}
+		if err = verifiedToken.Claims(&claims); err != nil {
+			t.Fatal(err)
+		}
+
+		if claims.Subject != "kataras" || claims.Scope != "remote" {
+			t.Fatalf("expected the remote claims but got: %#+v", claims)
+		}
+	}
+
+	if expected, got := uint32(1), atomic.LoadUint32(&calls); got != expected {
+		t.Fatalf("expected %d remote call(s) (cached) but got: %d", expected, got)
+	}
+
+	// The local pre-check rejects invalid signatures without a remote call.
+	forged, err := Sign(testAlg, []byte("other"), Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = remote.Verify(ctx, forged); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	if expected, got := uint32(1), atomic.LoadUint32(&calls); got != expected {
+		t.Fatalf("expected %d remote call(s) but got: %d", expected, got)
+	}
+
+	revoked, err := Sign(testAlg, testSecret, Map{"sub": "revoked"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = remote.Verify(ctx, revoked); err != ErrTokenInactive {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenInactive, err)
+	}
+
+	// Remote errors fail the verification.
+	introspection.ClientSecret = "invalid"
+	other, err := Sign(testAlg, testSecret, Map{"sub": "other"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = remote.Verify(ctx, other); err == nil {
+		t.Fatalf("expected a remote error")
+	}
+}
+
+func TestRemoteVerifierFunc(t *testing.T) {
+	remote := NewRemoteVerifier(RemoteVerificationFunc(func(ctx context.Context, token []byte) (*RemoteResult, error) {
+		return &RemoteResult{Active: true}, nil
+	}))
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := remote.Verify(context.Background(), token, Expected{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" || len(verifiedToken.Header) == 0 {
+		t.Fatalf("expected the token's parts but got: %#+v", verifiedToken)
+	}
+
+	if _, err = remote.Verify(context.Background(), nil); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+
+	// The standard claims of the payload are validated.
+	expired, err := Sign(testAlg, testSecret, Claims{Subject: "kataras", Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = remote.Verify(context.Background(), expired); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+}
+
+func TestRemoteVerifierResultMaxAge(t *testing.T) {
+	var calls uint32
+	remote := NewRemoteVerifier(RemoteVerificationFunc(func(ctx context.Context, token []byte) (*RemoteResult, error) {
+		atomic.AddUint32(&calls, 1)
+		return &RemoteResult{Active: true, MaxAge: time.Minute}, nil
+	}))
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No CacheMaxAge, the result's MaxAge is honored.
+	for i := 0; i < 2; i++ {
+		if _, err = remote.Verify(context.Background(), token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadUint32(&calls); got != 1 {
+		t.Fatalf("expected the result to be cached but got: %d remote calls", got)
+	}
+}
+
+func TestRemoteVerifierCacheOpaqueStale(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	var (
+		calls   int
+		errDown = errors.New("service unavailable")
+		down    bool
+	)
+	expiry := now.Add(10 * time.Minute).Unix()
+	remote := NewRemoteVerifier(RemoteVerificationFunc(func(ctx context.Context, token []byte) (*RemoteResult, error) {
+		calls++
+		if down {
+			return nil, errDown
+		}
+
+		payload := fmt.Sprintf(`{"sub":"kataras","exp":%d}`, expiry)
+		return &RemoteResult{Active: true, Payload: []byte(payload)}, nil
+	}))
+	remote.CacheMaxAge = time.Minute
+	remote.CacheMaxStale = 5 * time.Minute
+
+	opaque := []byte("opaque-token")
+	ctx := context.Background()
+	verify := func() error {
+		_, err := remote.Verify(ctx, opaque)
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected a single remote call (cached) but got: %d", calls)
+	}
+
+	// Expired cache entry, the stale result is served on failures.
+	down = true
+	now = now.Add(2 * time.Minute)
+	if err := verify(); err != nil {
+		t.Fatalf("expected the stale result but got: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected a remote call for the expired entry but got: %d", calls)
+	}
+
+	// Beyond the stale bound.
+	now = now.Add(5 * time.Minute)
+	if err := verify(); !errors.Is(err, errDown) || !errors.Is(err, ErrDependencyUnavailable) {
+		t.Fatalf("expected error: %v but got: %v", errDown, err)
+	}
+
+	// Never beyond the (opaque) token's expiration of the result payload.
+	down = false
+	expiry = now.Add(30 * time.Second).Unix()
+	if err := verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	down = true
+	now = now.Add(time.Minute)
+	if err := verify(); !errors.Is(err, errDown) || !errors.Is(err, ErrDependencyUnavailable) {
+		t.Fatalf("expected error: %v but got: %v", errDown, err)
+	}
+}
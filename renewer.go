@@ -0,0 +1,235 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrNoExpiry indicates that a token has no (readable) "exp" claim, see `Renewer`.
+var ErrNoExpiry = errors.New("token has no expiration")
+
+// RenewFunc generates (e.g. through an `Issuer`) or fetches (e.g. from an authorization server)
+// a fresh token, see `Renewer`.
+type RenewFunc func(ctx context.Context) ([]byte, error)
+
+// Renewer is a client-side holder of a token which is renewed
+// in a background goroutine before its expiration, for daemons which authenticate
+// outbound requests continuously. The subscribers are notified on every renewal
+// through the OnRenew callback and the `Subscribe` channels.
+// Its fields should not be modified after `Start` (or `Run`).
+//
+// Usage:
+//  renewer := jwt.NewRenewer(func(ctx context.Context) ([]byte, error) {
+//    return issuer.TokenContext(ctx, "billing-worker", nil)
+//  })
+//  if err := renewer.Start(ctx); err != nil { [handle error...] }
+//  req.Header.Set("Authorization", "Bearer "+string(renewer.Token()))
+type Renewer struct {
+	// Renew generates or fetches a fresh token, required.
+	Renew RenewFunc
+	// Before is the time before the token's expiration to renew it.
+	// Defaults to the 1/5 of the token's remaining lifetime.
+	Before time.Duration
+	// Jitter is the maximum random time which is subtracted from each renewal time,
+	// so a fleet of clients does not renew at the same moment. Optional.
+	Jitter time.Duration
+	// RetryInterval is the time to wait before a failed renewal is retried,
+	// it's the minimum time between two renewals too, e.g. when the Before duration
+	// is longer than the lifetime of the renewed tokens. Defaults to 5 seconds.
+	RetryInterval time.Duration
+	// Expiry returns the expiration time of a (renewed) token.
+	// Defaults to the (unverified) "exp" claim of the token's payload,
+	// set it when the payload is encrypted.
+	Expiry func(token []byte) (time.Time, error)
+
+	// OnRenew is called after each successful renewal, optional.
+	OnRenew func(token []byte)
+	// OnError is called on renewal failures, before their retry, optional.
+	OnError func(err error)
+
+	mu          sync.RWMutex
+	token       []byte
+	subscribers []chan []byte
+
+	runner runner
+}
+
+// NewRenewer returns a new token Renewer which renews its token through the "renew" function.
+// The rest of the Renewer fields can be modified before `Start`.
+func NewRenewer(renew RenewFunc) *Renewer {
+	return &Renewer{Renew: renew}
+}
+
+// Start renews the token once, synchronously, and returns its error (if any).
+// On success it keeps renewing the token in a background goroutine
+// until the "ctx" is cancelled or `Close` is called.
+// Returns ErrRunning if the Renewer is already running.
+func (r *Renewer) Start(ctx context.Context) error {
+	expiresAt, err := r.renew(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.runner.start(ctx, func(ctx context.Context) {
+		r.loop(ctx, expiresAt)
+	})
+	return err
+}
+
+// Run same as `Start` but it blocks until the "ctx" is cancelled or `Close` is called.
+// Returns the first renewal's error, the "ctx" error or nil on `Close`.
+func (r *Renewer) Run(ctx context.Context) error {
+	expiresAt, err := r.renew(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.runner.run(ctx, func(ctx context.Context) {
+		r.loop(ctx, expiresAt)
+	})
+}
+
+// Close stops the renewals, if running, and waits for the background goroutine to return.
+// The last token is still available through the `Token` method.
+func (r *Renewer) Close() error {
+	r.runner.stop()
+	return nil
+}
+
+// Token returns the current token. It's safe for concurrent use.
+func (r *Renewer) Token() []byte {
+	r.mu.RLock()
+	token := r.token
+	r.mu.RUnlock()
+	return token
+}
+
+// Subscribe returns a channel which receives every renewed token.
+// The channel holds only the latest token, a slow subscriber never blocks the Renewer.
+func (r *Renewer) Subscribe() <-chan []byte {
+	ch := make(chan []byte, 1)
+	r.mu.Lock()
+	r.subscribers = append(r.subscribers, ch)
+	r.mu.Unlock()
+	return ch
+}
+
+func (r *Renewer) loop(ctx context.Context, expiresAt time.Time) {
+	timer := time.NewTimer(r.nextRenewal(expiresAt))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		next, err := r.renew(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if r.OnError != nil {
+				r.OnError(err)
+			}
+
+			timer.Reset(r.retryInterval())
+			continue
+		}
+
+		timer.Reset(r.nextRenewal(next))
+	}
+}
+
+func (r *Renewer) renew(ctx context.Context) (time.Time, error) {
+	token, err := r.Renew(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	expiry := r.Expiry
+	if expiry == nil {
+		expiry = unverifiedExpiry
+	}
+
+	expiresAt, err := expiry(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	r.mu.Lock()
+	r.token = token
+	for _, ch := range r.subscribers {
+		select { // drop the previous (not received) token, if any.
+		case <-ch:
+		default:
+		}
+		ch <- token
+	}
+	r.mu.Unlock()
+
+	if r.OnRenew != nil {
+		r.OnRenew(token)
+	}
+
+	return expiresAt, nil
+}
+
+// retryInterval returns the RetryInterval or its default value.
+func (r *Renewer) retryInterval() time.Duration {
+	if r.RetryInterval <= 0 {
+		return 5 * time.Second
+	}
+
+	return r.RetryInterval
+}
+
+// nextRenewal returns the time to wait before the next renewal of a token which expires at "expiresAt",
+// at least the retry interval, so a token which is renewed (too) close to its expiration
+// does not renew back-to-back.
+func (r *Renewer) nextRenewal(expiresAt time.Time) time.Duration {
+	remaining := expiresAt.Sub(Clock())
+
+	before := r.Before
+	if before <= 0 {
+		before = remaining / 5
+	}
+
+	wait := remaining - before
+	if r.Jitter > 0 {
+		wait -= time.Duration(rand.Int63n(int64(r.Jitter)))
+	}
+
+	if interval := r.retryInterval(); wait < interval {
+		return interval
+	}
+
+	return wait
+}
+
+// unverifiedExpiry returns the "exp" claim of the compact "token" without verifying it.
+func unverifiedExpiry(token []byte) (time.Time, error) {
+	parts := bytes.Split(token, sep)
+	if len(parts) != 3 {
+		return time.Time{}, ErrTokenForm
+	}
+
+	payload, err := Base64Decode(parts[1])
+	if err != nil {
+		return time.Time{}, ErrTokenForm
+	}
+
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Expiry <= 0 {
+		return time.Time{}, ErrNoExpiry
+	}
+
+	return claims.ExpiresAt(), nil
+}
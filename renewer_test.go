@@ -0,0 +1,144 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRenewer(t *testing.T) {
+	var (
+		calls    uint32
+		failures uint32
+		errRenew = errors.New("renew error")
+	)
+
+	renewer := NewRenewer(func(ctx context.Context) ([]byte, error) {
+		n := atomic.AddUint32(&calls, 1)
+		if n == 2 { // the first background renewal fails.
+			return nil, errRenew
+		}
+		return []byte("token" + strconv.Itoa(int(n))), nil
+	})
+	renewer.Before = 50 * time.Millisecond
+	renewer.Jitter = 10 * time.Millisecond
+	renewer.RetryInterval = 10 * time.Millisecond
+	renewer.Expiry = func(token []byte) (time.Time, error) {
+		return Clock().Add(100 * time.Millisecond), nil
+	}
+	renewer.OnError = func(err error) {
+		if err != errRenew {
+			t.Errorf("expected error: %v but got: %v", errRenew, err)
+		}
+		atomic.AddUint32(&failures, 1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := renewer.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer renewer.Close()
+
+	if expected, got := "token1", string(renewer.Token()); got != expected {
+		t.Fatalf("expected token: %s but got: %s", expected, got)
+	}
+
+	renewed := renewer.Subscribe()
+	select {
+	case token := <-renewed:
+		if expected := "token3"; string(token) != expected {
+			t.Fatalf("expected renewed token: %s but got: %s", expected, token)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected a renewed token")
+	}
+
+	if atomic.LoadUint32(&failures) != 1 {
+		t.Fatalf("expected one failure but got: %d", failures)
+	}
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	n := atomic.LoadUint32(&calls)
+	time.Sleep(150 * time.Millisecond)
+	if got := atomic.LoadUint32(&calls); got != n {
+		t.Fatalf("expected no renewals after cancel but got: %d", got-n)
+	}
+}
+
+func TestRenewerStartError(t *testing.T) {
+	renewer := NewRenewer(func(ctx context.Context) ([]byte, error) {
+		return []byte("not.a.jwt"), nil
+	})
+
+	if err := renewer.Start(context.Background()); err != ErrTokenForm {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	renewer.Renew = func(ctx context.Context) ([]byte, error) {
+		return Sign(testAlg, testSecret, Map{"foo": "bar"})
+	}
+
+	if err := renewer.Start(context.Background()); err != ErrNoExpiry {
+		t.Fatalf("expected error: %v but got: %v", ErrNoExpiry, err)
+	}
+}
+
+func TestRenewerExpiry(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, MaxAge(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renewer := NewRenewer(func(ctx context.Context) ([]byte, error) { return token, nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err = renewer.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer renewer.Close() // waits for the loop, it reads the Clock.
+
+	expiresAt, err := unverifiedExpiry(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The default renewal time is at the 4/5 of the remaining lifetime.
+	if wait := renewer.nextRenewal(expiresAt); wait < 47*time.Minute || wait > 48*time.Minute {
+		t.Fatalf("unexpected renewal wait time: %s", wait)
+	}
+}
+
+func TestRenewerMinInterval(t *testing.T) {
+	var calls uint32
+	renewer := NewRenewer(func(ctx context.Context) ([]byte, error) {
+		atomic.AddUint32(&calls, 1)
+		return []byte("token"), nil
+	})
+	renewer.Before = time.Hour // longer than the tokens' lifetime.
+	renewer.RetryInterval = 50 * time.Millisecond
+	renewer.Expiry = func(token []byte) (time.Time, error) {
+		return Clock().Add(100 * time.Millisecond), nil
+	}
+
+	if wait := renewer.nextRenewal(Clock().Add(100 * time.Millisecond)); wait != renewer.RetryInterval {
+		t.Fatalf("expected a renewal wait time of the retry interval but got: %s", wait)
+	}
+
+	if err := renewer.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(220 * time.Millisecond)
+	renewer.Close()
+
+	// The first renewal plus one every 50ms.
+	if got := atomic.LoadUint32(&calls); got < 2 || got > 6 {
+		t.Fatalf("expected a renewal per retry interval but got: %d renewals", got)
+	}
+}
@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrReplayed indicates a token (or a nonce) which was already used,
+// see the `ReplayStore`.
+var ErrReplayed = errors.New("token replayed")
+
+const (
+	// replayShards is the number of the ReplayStore's shards, a power of two.
+	replayShards = 64
+	// replayWheelSlots is the number of the one-second slots of a shard's time wheel.
+	// Entries which expire later than a wheel's rotation are re-checked on the next one.
+	replayWheelSlots = 256
+)
+
+// ReplayStore is a high-concurrency in-memory store of the used token ids ("jti") and nonces,
+// which remembers each id until its expiration, so one-time tokens (and proofs)
+// are accepted once. The ids are sharded across independently locked maps
+// and the expired ids are removed by a time wheel, amortized on the writes,
+// so there is no GC goroutine and the cost of an operation does not grow with the store's size.
+// A ReplayStore is safe for concurrent use and it implements the `TokenValidator` interface.
+//
+// Usage:
+//  replays := jwt.NewReplayStore(5 * time.Minute)
+//  verifiedToken, err := verifier.VerifyToken(token, replays)
+//  // err == jwt.ErrReplayed on the second use of the token.
+type ReplayStore struct {
+	// TTL is the duration an id without an expiration is remembered for,
+	// e.g. a token without an "exp" claim. Defaults to 5 minutes.
+	TTL time.Duration
+
+	shards [replayShards]replayShard
+}
+
+type replayShard struct {
+	mu      sync.Mutex
+	entries map[string]int64 // key = id | value = expiration unix seconds.
+	wheel   [replayWheelSlots][]string
+	tick    int64 // the last processed second of the wheel.
+}
+
+var _ TokenValidator = (*ReplayStore)(nil)
+
+// NewReplayStore returns a new ReplayStore which remembers the ids without an expiration for "ttl".
+// The rest of the ReplayStore fields can be modified before its first use.
+func NewReplayStore(ttl time.Duration) *ReplayStore {
+	return &ReplayStore{TTL: ttl}
+}
+
+// Seen reports whether the "id" was already recorded and it has not expired yet,
+// otherwise it records the "id" until "expiresAt" (or for the TTL if it's zero) and reports false.
+// The check and the record are atomic: of concurrent calls for the same id only one reports false.
+func (s *ReplayStore) Seen(id string, expiresAt time.Time) bool {
+	now := Clock().Unix()
+
+	exp := expiresAt.Unix()
+	if expiresAt.IsZero() {
+		ttl := s.TTL
+		if ttl <= 0 {
+			ttl = 5 * time.Minute
+		}
+		exp = now + int64(ttl/time.Second)
+	}
+
+	shard := &s.shards[replayShardIndex(id)]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.advance(now)
+
+	if prev, ok := shard.entries[id]; ok && prev > now {
+		return true
+	}
+
+	if exp > now {
+		if shard.entries == nil {
+			shard.entries = make(map[string]int64)
+		}
+
+		shard.entries[id] = exp
+		slot := exp % replayWheelSlots
+		shard.wheel[slot] = append(shard.wheel[slot], id)
+	}
+
+	return false
+}
+
+// Len returns the number of the recorded ids, including the expired ones which are not removed yet.
+func (s *ReplayStore) Len() int {
+	n := 0
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+
+	return n
+}
+
+// ValidateToken completes the `TokenValidator` interface.
+// It records the token's "jti" (or the token itself if it has no "jti") until its "exp"
+// and returns ErrReplayed if it was already recorded.
+func (s *ReplayStore) ValidateToken(token []byte, c Claims, err error) error {
+	if err != nil {
+		return err // respect the previous error.
+	}
+
+	var expiresAt time.Time
+	if c.Expiry > 0 {
+		expiresAt = time.Unix(c.Expiry, 0)
+	}
+
+	if s.Seen(defaultGetKey(token, c), expiresAt) {
+		return ErrReplayed
+	}
+
+	return nil
+}
+
+// advance removes the ids of the wheel's slots up to the "now" second.
+func (shard *replayShard) advance(now int64) {
+	if shard.tick == 0 || now-shard.tick > replayWheelSlots {
+		shard.tick = now - replayWheelSlots
+	}
+
+	for ; shard.tick < now; shard.tick++ {
+		second := shard.tick + 1
+		slot := second % replayWheelSlots
+		ids := shard.wheel[slot]
+		if len(ids) == 0 {
+			continue
+		}
+
+		kept := ids[:0]
+		for _, id := range ids {
+			exp, ok := shard.entries[id]
+			if !ok || exp%replayWheelSlots != slot {
+				continue // removed or recorded again, on another slot.
+			}
+
+			if exp <= now {
+				delete(shard.entries, id)
+				continue
+			}
+
+			kept = append(kept, id) // expires on a later rotation.
+		}
+
+		for i := len(kept); i < len(ids); i++ {
+			ids[i] = "" // release the removed ids.
+		}
+		shard.wheel[slot] = kept
+	}
+}
+
+// replayShardIndex returns the shard of the "id", by its FNV-1a hash.
+func replayShardIndex(id string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(id); i++ {
+		h ^= uint32(id[i])
+		h *= 16777619
+	}
+
+	return h & (replayShards - 1)
+}
@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReplayStore(t *testing.T) {
+	defer func() { Clock = time.Now }()
+	now := time.Now()
+	Clock = func() time.Time { return now }
+
+	store := NewReplayStore(time.Minute)
+	if store.Seen("a", now.Add(10*time.Second)) {
+		t.Fatalf("expected a first use")
+	}
+
+	if !store.Seen("a", now.Add(10*time.Second)) {
+		t.Fatalf("expected a replay")
+	}
+
+	// Without an expiration, the TTL applies.
+	store.Seen("b", time.Time{})
+	// Longer than a rotation of the wheel.
+	store.Seen("c", now.Add(10*time.Minute))
+
+	now = now.Add(11 * time.Second)
+	if store.Seen("a", now.Add(10*time.Second)) {
+		t.Fatalf("expected an expired id to be accepted again")
+	}
+
+	now = now.Add(time.Minute)
+	if !store.Seen("c", time.Time{}) {
+		t.Fatalf("expected a replay of an id which expires later than a wheel's rotation")
+	}
+
+	if store.Seen("b", time.Time{}) {
+		t.Fatalf("expected an expired id (TTL) to be accepted again")
+	}
+
+	now = now.Add(10 * time.Minute)
+	store.Seen("d", now.Add(time.Second)) // advances the wheels of the shards it touches only.
+	for i := 0; i < 1000; i++ {
+		store.Seen(strconv.Itoa(i), now.Add(time.Second))
+	}
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 1000; i++ {
+		store.Seen(strconv.Itoa(i), now.Add(time.Second))
+	}
+
+	if n := store.Len(); n != 1000 {
+		t.Fatalf("expected the expired ids to be removed but got: %d entries", n)
+	}
+}
+
+func TestReplayStoreValidator(t *testing.T) {
+	store := NewReplayStore(time.Minute)
+	verifier := NewVerifier(testAlg, testSecret, store)
+
+	token, err := Sign(testAlg, testSecret, Claims{ID: "one-time"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected error: %v but got: %v", ErrReplayed, err)
+	}
+
+	if reason := FailureReason(err); reason != "replayed" {
+		t.Fatalf("expected reason: replayed but got: %s", reason)
+	}
+
+	// Concurrent uses of the same id, only one is accepted.
+	var (
+		wg       sync.WaitGroup
+		accepted uint32
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !store.Seen("nonce", time.Time{}) {
+				atomic.AddUint32(&accepted, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if accepted != 1 {
+		t.Fatalf("expected a single accepted use but got: %d", accepted)
+	}
+}
+
+func BenchmarkReplayStore(b *testing.B) {
+	store := NewReplayStore(time.Minute)
+	expiresAt := time.Now().Add(time.Minute)
+
+	var n uint64
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			store.Seen(strconv.FormatUint(atomic.AddUint64(&n, 1), 36), expiresAt)
+		}
+	})
+}
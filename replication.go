@@ -0,0 +1,285 @@
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// JWKSStore is the storage of a JSON Web Key Set document mirror,
+// e.g. an object storage bucket or a redis key of a region.
+// See `JWKSReplicator` and `JWKSMirror`.
+type JWKSStore interface {
+	// LoadJWKS returns the stored JSON Web Key Set document.
+	LoadJWKS(ctx context.Context) ([]byte, error)
+	// StoreJWKS replaces the stored JSON Web Key Set document with the "data" one.
+	StoreJWKS(ctx context.Context, data []byte) error
+}
+
+// JWKSReplicator mirrors the key set of a primary `JWKSClient` into the stores
+// of the secondary regions, so their verifiers (see `JWKSMirror`) read the keys locally
+// and they keep verifying tokens while the primary region is unavailable.
+// A store is written only when its key set document changed, failed writes are retried
+// on the next replication.
+//
+// Usage:
+//  replicator := jwt.NewJWKSReplicator(jwt.NewJWKSClient("https://idp.example.com/.well-known/jwks.json"), map[string]jwt.JWKSStore{
+//   "eu-west-1": euStore,
+//   "us-east-1": usStore,
+//  })
+//  go replicator.Run(ctx)
+type JWKSReplicator struct {
+	// Source is the primary key set, required.
+	Source *JWKSClient
+	// Mirrors are the stores of the secondary regions, by region name.
+	Mirrors map[string]JWKSStore
+	// Every is the interval of the periodic replication, see `Run`.
+	// Defaults to 5 minutes.
+	Every time.Duration
+	// OnError is an optional hook which is called on periodic replication failures.
+	OnError func(err error)
+
+	mu      sync.Mutex
+	written map[string][sha256.Size]byte // the last written document's checksum, by region.
+
+	runner runner
+}
+
+// NewJWKSReplicator returns a new JWKSReplicator of the "source" key set and the "mirrors" stores.
+// The rest of the JWKSReplicator fields can be modified before its first use.
+func NewJWKSReplicator(source *JWKSClient, mirrors map[string]JWKSStore) *JWKSReplicator {
+	return &JWKSReplicator{
+		Source:  source,
+		Mirrors: mirrors,
+		Every:   5 * time.Minute,
+	}
+}
+
+// Replicate refreshes the source key set and writes it to the mirrors which don't have it yet.
+// All the mirrors are written, even if one of them fails, the first failure is returned.
+func (r *JWKSReplicator) Replicate(ctx context.Context) error {
+	if err := r.Source.Refresh(ctx); err != nil {
+		return err
+	}
+
+	set := r.Source.Set()
+	if set == nil {
+		return fmt.Errorf("jwks: replicate: %w", ErrNotReady)
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		return fmt.Errorf("jwks: replicate: %w", err)
+	}
+	checksum := sha256.Sum256(data)
+
+	regions := make([]string, 0, len(r.Mirrors))
+	for region := range r.Mirrors {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.written == nil {
+		r.written = make(map[string][sha256.Size]byte, len(regions))
+	}
+
+	var firstErr error
+	for _, region := range regions {
+		if written, ok := r.written[region]; ok && written == checksum {
+			continue
+		}
+
+		if err = r.Mirrors[region].StoreJWKS(ctx, data); err != nil {
+			delete(r.written, region)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("jwks: replicate %q: %w", region, err)
+			}
+			continue
+		}
+
+		r.written[region] = checksum
+	}
+
+	return firstErr
+}
+
+// Run replicates the key set and then every `Every` duration, until the "ctx" is cancelled
+// or `Close` is called. The failures are reported to the OnError hook.
+// Returns ErrRunning if it's already running.
+func (r *JWKSReplicator) Run(ctx context.Context) error {
+	return r.runner.run(ctx, r.runReplicate)
+}
+
+// Close stops the periodic replication and waits for it to return.
+func (r *JWKSReplicator) Close() error {
+	r.runner.stop()
+	return nil
+}
+
+func (r *JWKSReplicator) runReplicate(ctx context.Context) {
+	every := r.Every
+	if every <= 0 {
+		every = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Replicate(ctx); err != nil && ctx.Err() == nil && r.OnError != nil {
+			r.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// JWKSMirror is a `KeyProvider` which prefers the key set of a local store (see `JWKSReplicator`)
+// and falls back to the primary key provider (e.g. the primary region's `JWKSClient`)
+// for the key ids which the mirror does not contain, e.g. a key which is not replicated yet.
+// A failed reload keeps the previously loaded keys. A JWKSMirror is safe for concurrent use.
+type JWKSMirror struct {
+	// Store is the local mirror, required.
+	Store JWKSStore
+	// Primary is the fallback key provider, optional.
+	Primary KeyProvider
+	// MaxAge is the duration the loaded keys are cached before the store is read again.
+	// Defaults to 1 minute.
+	MaxAge time.Duration
+	// OnError is an optional hook which is called on store reload failures.
+	OnError func(err error)
+
+	mu       sync.RWMutex
+	keys     *StaticJWKS
+	loadedAt time.Time
+
+	loadMu sync.Mutex // allows a single reload at a time.
+}
+
+var _ KeyProvider = (*JWKSMirror)(nil)
+
+// NewJWKSMirror returns a new JWKSMirror of the "local" store and the optional "primary" key provider.
+func NewJWKSMirror(local JWKSStore, primary KeyProvider) *JWKSMirror {
+	return &JWKSMirror{
+		Store:   local,
+		Primary: primary,
+		MaxAge:  time.Minute,
+	}
+}
+
+// NewMirrorVerifier returns a new token Verifier of the "alg" algorithm
+// and the keys of the "local" mirror, which falls back to the "primary" key provider,
+// see `JWKSMirror`.
+//
+// Usage:
+//  verifier := jwt.NewMirrorVerifier(jwt.RS256, regionStore, jwt.NewJWKSClient(primaryURL), jwt.Expected{Issuer: "idp"})
+func NewMirrorVerifier(alg AlgVerifier, local JWKSStore, primary KeyProvider, validators ...TokenValidator) *Verifier {
+	verifier := NewVerifier(alg, nil, validators...)
+	verifier.KeyProvider = NewJWKSMirror(local, primary)
+	return verifier
+}
+
+// PublicKey returns the public key of the given key id, through the local mirror
+// or the primary key provider, if the mirror does not contain it (or it can't be loaded).
+// Returns ErrUnknownKid when none of them contain the "kid".
+func (m *JWKSMirror) PublicKey(ctx context.Context, kid string) (PublicKey, error) {
+	keys, err := m.load(ctx)
+	if keys != nil {
+		key, keyErr := keys.PublicKey(ctx, kid)
+		if keyErr == nil {
+			return key, nil
+		}
+
+		err = keyErr
+	}
+
+	if m.Primary != nil {
+		return m.Primary.PublicKey(ctx, kid)
+	}
+
+	if err == nil {
+		err = ErrUnknownKid
+	}
+
+	return nil, err
+}
+
+// load returns the loaded keys, it reads the store again if they are expired.
+// It returns the previously loaded keys (if any) along with a reload error.
+func (m *JWKSMirror) load(ctx context.Context) (*StaticJWKS, error) {
+	if keys, ok := m.cached(); ok {
+		return keys, nil
+	}
+
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+
+	if keys, ok := m.cached(); ok { // loaded by another call.
+		return keys, nil
+	}
+
+	keys, err := m.reload(ctx)
+	if err != nil && ctx.Err() != nil {
+		return m.keysLoaded(), err // the caller gave up, read the store on the next call.
+	}
+
+	m.mu.Lock()
+	if err == nil {
+		m.keys = keys
+	} else {
+		keys = m.keys
+	}
+	m.loadedAt = Clock() // on errors too, the store is not read again before MaxAge.
+	m.mu.Unlock()
+
+	if err != nil && m.OnError != nil {
+		m.OnError(err)
+	}
+
+	return keys, err
+}
+
+func (m *JWKSMirror) keysLoaded() *StaticJWKS {
+	m.mu.RLock()
+	keys := m.keys
+	m.mu.RUnlock()
+
+	return keys
+}
+
+func (m *JWKSMirror) cached() (*StaticJWKS, bool) {
+	m.mu.RLock()
+	keys, loadedAt := m.keys, m.loadedAt
+	m.mu.RUnlock()
+
+	maxAge := m.MaxAge
+	if maxAge <= 0 {
+		maxAge = time.Minute
+	}
+
+	if loadedAt.IsZero() || !Clock().Before(loadedAt.Add(maxAge)) {
+		return keys, false
+	}
+
+	return keys, true
+}
+
+func (m *JWKSMirror) reload(ctx context.Context) (*StaticJWKS, error) {
+	data, err := m.Store.LoadJWKS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: mirror: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
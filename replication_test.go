@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type testJWKSStore struct {
+	mu     sync.Mutex
+	data   []byte
+	writes int
+	err    error
+}
+
+func (s *testJWKSStore) LoadJWKS(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.data, nil
+}
+
+func (s *testJWKSStore) StoreJWKS(ctx context.Context, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.data = data
+	s.writes++
+	return nil
+}
+
+func TestJWKSReplicator(t *testing.T) {
+	set := testJWKS(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	eu, us := new(testJWKSStore), &testJWKSStore{err: errors.New("unavailable")}
+	replicator := NewJWKSReplicator(NewJWKSClient(srv.URL), map[string]JWKSStore{"eu": eu, "us": us})
+
+	ctx := context.Background()
+	if err := replicator.Replicate(ctx); err == nil {
+		t.Fatalf("expected the us mirror error")
+	}
+
+	if eu.writes != 1 {
+		t.Fatalf("expected the eu mirror to be written once but got: %d", eu.writes)
+	}
+
+	us.err = nil
+	if err := replicator.Replicate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The eu mirror is up to date, the failed us mirror is written again.
+	if eu.writes != 1 || us.writes != 1 {
+		t.Fatalf("expected a single write of each mirror but got: eu=%d, us=%d", eu.writes, us.writes)
+	}
+
+	keys, err := ParseJWKS(us.data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keys.PublicKey(ctx, "eddsa"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWKSMirror(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	jwk, err := NewJWK("mirrored", EdDSA, publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(JWKS{Keys: []*JWK{jwk}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := &testJWKSStore{data: data}
+	primary := &StaticKey{ID: "new", Public: publicKey}
+	verifier := NewMirrorVerifier(EdDSA, local, primary)
+
+	for _, kid := range []string{"mirrored", "new"} {
+		token, err := SignWithHeader(EdDSA, privateKey, Header{{Name: "alg", Value: "EdDSA"}, {Name: "kid", Value: kid}}, Map{"sub": "kataras"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = verifier.VerifyToken(token); err != nil {
+			t.Fatalf("[%s] %v", kid, err)
+		}
+	}
+
+	mirror := verifier.KeyProvider.(*JWKSMirror)
+	mirror.Primary = nil
+	if _, err = mirror.PublicKey(context.Background(), "new"); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	// A store outage keeps the loaded keys.
+	local.err = errors.New("unavailable")
+	mirror.loadedAt = mirror.loadedAt.Add(-2 * mirror.MaxAge)
+	if _, err = mirror.PublicKey(context.Background(), "mirrored"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRevocationClient(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{ID: "jti:1"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "my-client" || pass != "my-secret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		if r.Method != http.MethodPost || r.PostFormValue("token") != string(token) || r.PostFormValue("token_type_hint") != TokenTypeHintAccess {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	blocklist := NewBlocklist(0)
+	c := NewRevocationClient(srv.URL)
+	c.Blocklist = blocklist
+
+	claims := Claims{ID: "jti:1", Expiry: time.Now().Add(time.Minute).Unix()}
+
+	// Test the remote failure still invalidates the token locally.
+	err = c.Revoke(context.Background(), token, claims, TokenTypeHintAccess)
+	if !errors.Is(err, ErrRevocation) || !strings.Contains(err.Error(), "invalid_client") {
+		t.Fatalf("expected an invalid_client ErrRevocation error but got: %v", err)
+	}
+
+	if blocked, _ := blocklist.Has("jti:1"); !blocked {
+		t.Fatalf("expected token to be blocked locally")
+	}
+
+	c.ClientID, c.ClientSecret = "my-client", "my-secret"
+	if err = c.Revoke(context.Background(), token, claims, TokenTypeHintAccess); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.Revoke(context.Background(), nil, claims, ""); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteOption is a route-specific configuration of the HTTP middleware,
+// see the `Verifier.RouteHandler` method.
+type RouteOption func(*routeConfig)
+
+type routeConfig struct {
+	validators []TokenValidator
+	scopes     []string // the required scopes, see `OpenAPI`.
+	optional   bool
+}
+
+// WithValidators is a RouteOption which adds validators
+// which run after the Verifier's ones only on that route.
+func WithValidators(validators ...TokenValidator) RouteOption {
+	return func(route *routeConfig) {
+		route.validators = append(route.validators, validators...)
+	}
+}
+
+// WithAudience is a RouteOption which requires the token's "aud" claim
+// to contain at least one of the given "audience" values.
+// It returns a type of ErrExpected on validation failures.
+func WithAudience(audience ...string) RouteOption {
+	return WithValidators(anyAudience(audience))
+}
+
+// anyAudience returns a TokenValidator which requires the token's "aud" claim
+// to contain at least one of the "audience" values, see `WithAudience`.
+func anyAudience(audience []string) TokenValidatorFunc {
+	return func(_ []byte, standardClaims Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		for _, expected := range audience {
+			for _, aud := range standardClaims.Audience {
+				if aud == expected {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("%w: aud", ErrExpected)
+	}
+}
+
+// WithScopes is a RouteOption which requires the token to grant all of the given "scopes".
+// The scopes are read from the "scope" claim, a space-delimited string (RFC 8693),
+// or from the "scp" claim, an array of strings.
+// It returns ErrPolicyDenied on validation failures.
+func WithScopes(scopes ...string) RouteOption {
+	withPolicy := WithValidators(Policy(func(claims Map) bool {
+		granted := grantedScopes(claims)
+		for _, scope := range scopes {
+			if !containsString(granted, scope) {
+				return false
+			}
+		}
+
+		return true
+	}))
+
+	return func(route *routeConfig) {
+		route.scopes = append(route.scopes, scopes...)
+		withPolicy(route)
+	}
+}
+
+// grantedScopes returns the scopes of the "scope" claim, a space-delimited string,
+// and of the "scp" claim, an array of strings, in order and without duplicates.
+func grantedScopes(claims Map) []string {
+	var granted []string
+	add := func(scope string) {
+		if !containsString(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		for _, s := range strings.Fields(scope) {
+			add(s)
+		}
+	}
+
+	if scp, ok := claims["scp"].([]interface{}); ok {
+		for _, v := range scp {
+			if s, ok := v.(string); ok {
+				add(s)
+			}
+		}
+	}
+
+	return granted
+}
+
+// OptionalAuth is a RouteOption which lets requests without a token
+// pass through to the next handler, without a verified token in their Context.
+// Requests with an invalid token are still rejected.
+func OptionalAuth() RouteOption {
+	return func(route *routeConfig) {
+		route.optional = true
+	}
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifierRouteHandler(t *testing.T) {
+	verifier := NewVerifier(testAlg, testSecret)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if verifiedToken, ok := GetVerifiedToken(r.Context()); ok {
+			w.Write([]byte(verifiedToken.StandardClaims.Subject))
+			return
+		}
+
+		w.Write([]byte("anonymous"))
+	})
+
+	billing := verifier.RouteHandler(next, WithAudience("billing", "admin"), WithScopes("invoices:read"))
+	index := verifier.RouteHandler(next, OptionalAuth())
+
+	reader, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "aud": []string{"billing"}, "scope": "profile invoices:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scp, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "aud": []string{"admin"}, "scp": []string{"invoices:read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherAudience, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "aud": []string{"shop"}, "scope": "invoices:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noScope, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "aud": []string{"billing"}, "scope": "profile"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		handler    http.Handler
+		token      []byte
+		statusCode int
+		body       string
+	}{
+		{billing, reader, http.StatusOK, "kataras"},
+		{billing, scp, http.StatusOK, "kataras"},
+		{billing, otherAudience, http.StatusUnauthorized, ""},
+		{billing, noScope, http.StatusForbidden, ""},
+		{billing, nil, http.StatusUnauthorized, ""},
+		{index, nil, http.StatusOK, "anonymous"},
+		{index, noScope, http.StatusOK, "kataras"},
+		{index, []byte("invalid"), http.StatusUnauthorized, ""},
+	}
+
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.token != nil {
+			r.Header.Set("Authorization", "Bearer "+string(tt.token))
+		}
+
+		w := httptest.NewRecorder()
+		tt.handler.ServeHTTP(w, r)
+
+		if w.Code != tt.statusCode {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.statusCode, w.Code)
+		}
+
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Fatalf("[%d] expected body: %s but got: %s", i, tt.body, w.Body.String())
+		}
+	}
+}
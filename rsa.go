@@ -2,10 +2,8 @@ package jwt
 
 import (
 	"crypto"
-	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 )
 
@@ -32,7 +30,7 @@ func (a *algRSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	}
 
 	hashed := h.Sum(nil)
-	return rsa.SignPKCS1v15(rand.Reader, privateKey, a.hasher, hashed)
+	return rsa.SignPKCS1v15(Random, privateKey, a.hasher, hashed)
 }
 
 func (a *algRSA) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
@@ -117,17 +115,21 @@ func LoadPublicKeyRSA(filename string) (*rsa.PublicKey, error) {
 }
 
 // ParsePrivateKeyRSA decodes and parses the
-// PEM-encoded RSA private key's raw contents.
+// PEM-encoded (or DER) RSA private key's raw contents, of PKCS#1 or PKCS#8 form.
 // Pass the result to the `Token` (signing) function.
 func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
+	der, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if der == nil {
 		return nil, fmt.Errorf("private key: malformed or missing PEM format (RSA)")
 	}
 
-	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	privateKey, err := x509.ParsePKCS1PrivateKey(der)
 	if err != nil {
-		if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
 			pKey, ok := key.(*rsa.PrivateKey)
 			if !ok {
 				return nil, fmt.Errorf("private key: expected a type of *rsa.PrivateKey")
@@ -143,17 +145,21 @@ func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
 }
 
 // ParsePublicKeyRSA decodes and parses the
-// PEM-encoded RSA public key's raw contents.
+// PEM-encoded (or DER) RSA public key's raw contents.
 // Pass the result to the `Verify` function.
 func ParsePublicKeyRSA(key []byte) (*rsa.PublicKey, error) {
-	block, _ := pem.Decode(key)
-	if block == nil {
+	der, err := decodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if der == nil {
 		return nil, fmt.Errorf("public key: malformed or missing PEM format (RSA)")
 	}
 
-	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	parsedKey, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		if cert, err := x509.ParseCertificate(der); err == nil {
 			parsedKey = cert.PublicKey
 		} else {
 			return nil, err
@@ -1,7 +1,6 @@
 package jwt
 
 import (
-	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
 )
@@ -29,7 +28,10 @@ func (a *algRSAPSS) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error
 	}
 
 	hashed := h.Sum(nil)
-	return rsa.SignPSS(rand.Reader, privateKey, a.opts.Hash, hashed, a.opts)
+	// RFC 7518 section 3.5: the salt size is the same as the hash function output,
+	// strict verifiers reject any other size. Verification stays lenient (see opts).
+	opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: a.opts.Hash}
+	return rsa.SignPSS(Random, privateKey, a.opts.Hash, hashed, opts)
 }
 
 func (a *algRSAPSS) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
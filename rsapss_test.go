@@ -1,6 +1,9 @@
 package jwt
 
-import "testing"
+import (
+	"crypto/rsa"
+	"testing"
+)
 
 func TestEncodeDecodeTokenRSAPSS(t *testing.T) {
 	privateKey, err := LoadPrivateKeyRSA("./_testfiles/rsapss_private_key.pem")
@@ -17,3 +20,26 @@ func TestEncodeDecodeTokenRSAPSS(t *testing.T) {
 	// test the automatic extract of public key from private key.
 	testEncodeDecodeToken(t, PS256, privateKey, privateKey, nil)
 }
+
+func TestRSAPSSSaltLength(t *testing.T) {
+	privateKey, err := LoadPrivateKeyRSA("./_testfiles/rsapss_private_key.pem")
+	if err != nil {
+		t.Fatalf("rsa-pss: private key: %v", err)
+	}
+
+	headerAndPayload := []byte("header.payload")
+	for _, alg := range []*algRSAPSS{PS256.(*algRSAPSS), PS384.(*algRSAPSS), PS512.(*algRSAPSS)} {
+		signature, err := alg.Sign(privateKey, headerAndPayload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Strict verifiers require the salt size to be the same as the hash output.
+		h := alg.opts.Hash.New()
+		h.Write(headerAndPayload)
+		strict := &rsa.PSSOptions{SaltLength: alg.opts.Hash.Size(), Hash: alg.opts.Hash}
+		if err = rsa.VerifyPSS(&privateKey.PublicKey, alg.opts.Hash, h.Sum(nil), signature, strict); err != nil {
+			t.Fatalf("%s: %v", alg.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+)
+
+// SegmentCodec encodes and decodes the segments (the header, the payload and the signature)
+// of the non-standard tokens of `SignRaw` and `VerifyRaw`, e.g. the `Base32Segments`
+// for tokens embedded in DNS names or the `HexSegments` for debugging.
+// The encoded segments must not contain the '.' separator.
+// The compact JWS tokens of `Sign` and `Verify` are always base64url encoded (RFC 7515).
+type SegmentCodec interface {
+	// Encode returns the encoded "src".
+	Encode(src []byte) []byte
+	// Decode returns the decoded "src".
+	Decode(src []byte) ([]byte, error)
+}
+
+var (
+	// Base64Segments is the base64url (without padding) codec of the compact JWS,
+	// see the `Base64Encode` and `Base64Decode` functions.
+	Base64Segments SegmentCodec = base64Segments{}
+	// Base32Segments is the lowercase base32 (RFC 4648, without padding) codec,
+	// for case-insensitive transports, e.g. DNS labels. Its decoder accepts uppercase letters too.
+	Base32Segments SegmentCodec = base32Segments{}
+	// HexSegments is the lowercase hex codec, for debugging.
+	HexSegments SegmentCodec = hexSegments{}
+)
+
+type base64Segments struct{}
+
+func (base64Segments) Encode(src []byte) []byte          { return Base64Encode(src) }
+func (base64Segments) Decode(src []byte) ([]byte, error) { return Base64Decode(src) }
+
+var base32Encoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+type base32Segments struct{}
+
+func (base32Segments) Encode(src []byte) []byte {
+	buf := make([]byte, base32Encoding.EncodedLen(len(src)))
+	base32Encoding.Encode(buf, src)
+	return buf
+}
+
+func (base32Segments) Decode(src []byte) ([]byte, error) {
+	src = bytes.ToLower(src)
+	buf := make([]byte, base32Encoding.DecodedLen(len(src)))
+	n, err := base32Encoding.Decode(buf, src)
+	return buf[:n], err
+}
+
+type hexSegments struct{}
+
+func (hexSegments) Encode(src []byte) []byte {
+	buf := make([]byte, hex.EncodedLen(len(src)))
+	hex.Encode(buf, src)
+	return buf
+}
+
+func (hexSegments) Decode(src []byte) ([]byte, error) {
+	buf := make([]byte, hex.DecodedLen(len(src)))
+	n, err := hex.Decode(buf, src)
+	return buf[:n], err
+}
+
+// SignRaw same as `Sign` but it encodes the token's segments through the given "codec",
+// the signature is computed over the encoded header and payload, as in a compact JWS.
+// The result is not a standard JWT (unless the codec is the `Base64Segments`),
+// it can be verified through the `VerifyRaw` function only.
+//
+// Example Code:
+//  token, err := jwt.SignRaw(jwt.HS256, sharedKey, jwt.Base32Segments, claims, jwt.MaxAge(time.Minute))
+func SignRaw(alg AlgSigner, key PrivateKey, codec SegmentCodec, claims interface{}, opts ...SignOption) ([]byte, error) {
+	if codec == nil {
+		codec = Base64Segments
+	}
+
+	payload, maxSize, err := marshalSignClaims(claims, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	headerAndPayload := joinParts(codec.Encode(createHeaderRaw(alg.Name())), codec.Encode(payload))
+	signature, err := alg.Sign(key, headerAndPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	token := joinParts(headerAndPayload, codec.Encode(signature))
+	if maxSize > 0 && len(token) > maxSize {
+		return nil, newTokenSizeError(len(token), maxSize, payload)
+	}
+
+	audit(alg, "", payload)
+	return token, nil
+}
+
+// VerifyRaw same as `Verify` but it decodes the token's segments through the given "codec",
+// see `SignRaw`. The signature is verified over the re-encoded header and payload.
+func VerifyRaw(alg AlgVerifier, key PublicKey, codec SegmentCodec, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	verifiedToken, err := verifyRaw(alg, key, codec, token, validators)
+	recordVerified(token, verifiedToken, err)
+	return verifiedToken, err
+}
+
+func verifyRaw(alg AlgVerifier, key PublicKey, codec SegmentCodec, token []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	if codec == nil {
+		codec = Base64Segments
+	}
+
+	if len(token) == 0 {
+		return nil, ErrMissing
+	}
+
+	header, payload, signature, ok := splitToken(token)
+	if !ok {
+		return nil, ErrTokenForm
+	}
+
+	headerDecoded, err := codec.Decode(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = compareHeader(alg.Name(), headerDecoded); err != nil {
+		return nil, err
+	}
+
+	payloadDecoded, err := codec.Decode(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureDecoded, err := codec.Decode(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature is verified over the canonical encoding of the segments,
+	// so a transport which changes their letter case (e.g. DNS) does not invalidate it.
+	headerAndPayload := joinParts(codec.Encode(headerDecoded), codec.Encode(payloadDecoded))
+	if err = alg.Verify(key, headerAndPayload, signatureDecoded); err != nil {
+		return nil, err
+	}
+
+	claims, err := validatePayload(context.Background(), token, payloadDecoded, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifiedToken{
+		Token:          token,
+		Header:         headerDecoded,
+		Payload:        payloadDecoded,
+		Signature:      signatureDecoded,
+		StandardClaims: claims,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSignRaw(t *testing.T) {
+	tests := []struct {
+		codec    SegmentCodec
+		alphabet *regexp.Regexp
+	}{
+		{Base64Segments, regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)},
+		{Base32Segments, regexp.MustCompile(`^[a-z2-7.]+$`)},
+		{HexSegments, regexp.MustCompile(`^[a-f0-9.]+$`)},
+	}
+
+	for i, tt := range tests {
+		token, err := SignRaw(testAlg, testSecret, tt.codec, Map{"sub": "kataras"}, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !tt.alphabet.Match(token) {
+			t.Fatalf("[%d] unexpected token alphabet: %s", i, token)
+		}
+
+		verifiedToken, err := VerifyRaw(testAlg, testSecret, tt.codec, token)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if verifiedToken.StandardClaims.Subject != "kataras" {
+			t.Fatalf("[%d] expected subject: kataras but got: %q", i, verifiedToken.StandardClaims.Subject)
+		}
+
+		if _, err = VerifyRaw(testAlg, []byte("other secret"), tt.codec, token); !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrTokenSignature, err)
+		}
+	}
+}
+
+func TestSignRawBase64(t *testing.T) {
+	claims := Map{"sub": "kataras", "exp": Clock().Add(time.Minute).Unix()}
+
+	raw, err := SignRaw(testAlg, testSecret, nil, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(raw, token) {
+		t.Fatalf("expected the compact token:\n%s\nbut got:\n%s", token, raw)
+	}
+}
+
+func TestVerifyRawBase32Case(t *testing.T) {
+	token, err := SignRaw(testAlg, testSecret, Base32Segments, Map{"sub": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DNS may change the letter case of the names.
+	if _, err = VerifyRaw(testAlg, testSecret, Base32Segments, bytes.ToUpper(token)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Compact JWS is strictly base64url.
+	if _, err = Verify(testAlg, testSecret, token); err == nil {
+		t.Fatalf("expected a base32 token to fail the compact verification")
+	}
+}
@@ -0,0 +1,51 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+)
+
+// ErrUnknownSession indicates that an opaque credential does not match any session,
+// a `SessionResolver` should return it when the session does not exist.
+var ErrUnknownSession = errors.New("unknown session")
+
+// SessionResolver resolves an opaque (not a JWT) credential, e.g. a server-side session id,
+// to the claims of its session. The claims can be a struct or a map value, like the `Sign` ones,
+// and they pass through the same validation as the verified tokens' claims (e.g. the "exp" claim and the validators).
+// It should return ErrUnknownSession if the credential does not match any session.
+//
+// See the `Verifier.SessionResolver` field.
+type SessionResolver func(ctx context.Context, credential []byte) (claims interface{}, err error)
+
+// isCompactToken reports whether the "token" has the form of a compact JWT (three dot-separated parts).
+func isCompactToken(token []byte) bool {
+	return bytes.Count(token, sep) == 2
+}
+
+// resolveSession resolves the opaque "credential" through the "resolver"
+// and validates the session's claims as a token's ones.
+// The result VerifiedToken's Header and Signature fields are nil.
+func resolveSession(ctx context.Context, resolver SessionResolver, credential []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	sessionClaims, err := resolver(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := Marshal(sessionClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := validatePayload(ctx, credential, payload, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedTok := &VerifiedToken{
+		Token:          credential,
+		Payload:        payload,
+		StandardClaims: claims,
+	}
+	return verifiedTok, nil
+}
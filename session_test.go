@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifierSessionResolver(t *testing.T) {
+	sessions := map[string]Map{
+		"session-1": {"sub": "kataras", "exp": time.Now().Add(time.Minute).Unix(), "role": "admin"},
+		"session-2": {"sub": "makis", "exp": time.Now().Add(-time.Minute).Unix()},
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.SessionResolver = func(_ context.Context, credential []byte) (interface{}, error) {
+		claims, ok := sessions[string(credential)]
+		if !ok {
+			return nil, ErrUnknownSession
+		}
+
+		return claims, nil
+	}
+
+	verifiedToken, err := verifier.VerifyToken([]byte("session-1"), ClaimEquals("role", "admin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" || verifiedToken.Header != nil {
+		t.Fatalf("unexpected resolved session: %#+v", verifiedToken)
+	}
+
+	if _, err = verifier.VerifyToken([]byte("session-2")); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if _, err = verifier.VerifyToken([]byte("session-3")); err != ErrUnknownSession {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownSession, err)
+	}
+
+	// Test JWTs are still verified and never passed to the resolver.
+	token, err := Sign(testAlg, testSecret, Claims{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifiedToken, _ := GetVerifiedToken(r.Context())
+		w.Write([]byte(verifiedToken.StandardClaims.Subject))
+	}))
+
+	for _, credential := range []string{string(token), "session-1"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+credential)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK || w.Body.String() != "kataras" {
+			t.Fatalf("[%s] unexpected response: %d: %s", RedactToken([]byte(credential)), w.Code, w.Body.String())
+		}
+	}
+
+	verifier.Key = []byte("other")
+	if _, err = verifier.VerifyToken(token); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
@@ -31,28 +31,32 @@ package jwt
 //
 //  type User struct { Username string `json:"username"` }
 //  token, err := jwt.Sign(jwt.HS256, []byte("secret"), User{Username: "kataras"}, jwt.MaxAge(15 * time.Minute))
-func Sign(alg Alg, key PrivateKey, claims interface{}, opts ...SignOption) ([]byte, error) {
+func Sign(alg AlgSigner, key PrivateKey, claims interface{}, opts ...SignOption) ([]byte, error) {
 	return SignEncrypted(alg, key, nil, claims, opts...)
 }
 
 // SignEncrypted same as `Sign` but it encrypts the payload part with the given "encrypt" function.
 // The "encrypt" function is called AFTER Marshal.
 // Look the `GCM` function for details.
-func SignEncrypted(alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{}, opts ...SignOption) ([]byte, error) {
-	if len(opts) > 0 {
-		var standardClaims Claims
-		for _, opt := range opts {
-			opt.ApplyClaims(&standardClaims)
-		}
+func SignEncrypted(alg AlgSigner, key PrivateKey, encrypt InjectFunc, claims interface{}, opts ...SignOption) ([]byte, error) {
+	return signEncrypted(alg, key, "", nil, encrypt, claims, opts...)
+}
 
-		claims = Merge(claims, standardClaims)
-	}
+// signEncrypted same as SignEncrypted but it accepts a key id to be set as the "kid" header
+// and the signer attestation to be set as the "att" header, both are optional.
+func signEncrypted(alg AlgSigner, key PrivateKey, kid string, att *Attestation, encrypt InjectFunc, claims interface{}, opts ...SignOption) ([]byte, error) {
+	return signWithHeader(alg, key, kid, createHeaderWithAttestation(alg.Name(), kid, att), encrypt, claims, opts...)
+}
 
-	payload, err := Marshal(claims)
+// signWithHeader same as signEncrypted but it accepts the (encoded) header,
+// the "kid" is the header's key id, used for auditing.
+func signWithHeader(alg AlgSigner, key PrivateKey, kid string, header []byte, encrypt InjectFunc, claims interface{}, opts ...SignOption) ([]byte, error) {
+	payload, maxSize, err := marshalSignClaims(claims, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	plainPayload := payload
 	if encrypt != nil {
 		payload, err = encrypt(payload)
 		if err != nil {
@@ -60,13 +64,45 @@ func SignEncrypted(alg Alg, key PrivateKey, encrypt InjectFunc, claims interface
 		}
 	}
 
-	return encodeToken(alg, key, payload)
+	token, err := encodeTokenWithHeader(alg, key, header, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxSize > 0 && len(token) > maxSize {
+		return nil, newTokenSizeError(len(token), maxSize, plainPayload)
+	}
+
+	audit(alg, kid, plainPayload)
+	return token, nil
+}
+
+// marshalSignClaims merges the standard claims of the "opts" into the "claims" and marshals them,
+// it returns the size budget of the `MaxTokenSize` option too (zero if none).
+func marshalSignClaims(claims interface{}, opts []SignOption) ([]byte, int, error) {
+	maxSize, opts := tokenSizeOption(opts)
+	if len(opts) > 0 {
+		var standardClaims Claims
+		for _, opt := range opts {
+			opt.ApplyClaims(&standardClaims)
+		}
+
+		claims = Merge(claims, standardClaims)
+	}
+
+	payload, err := Marshal(claims)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return payload, maxSize, nil
 }
 
 // SignOption is just a helper which sets the standard claims at the `Sign` function.
 //
 // Available SignOptions:
 // - MaxAge(time.Duration)
+// - MaxTokenSize(int)
 // - Claims{}
 type SignOption interface {
 	// ApplyClaims should apply standard claims.
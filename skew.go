@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ClockSkew estimates the difference between the local clock and a server's one.
+// It's designed for client-side use (e.g. SDKs running on devices with bad clocks),
+// where tokens issued by the server may look "not valid yet" or "issued in the future"
+// to the local clock. Feed it with server time samples, through the `ObserveDate`,
+// `ObserveIssuedAt` or `Transport` methods, and set its `Now` method as the package's `Clock`,
+// so the validation uses the estimated server time automatically:
+//  skew := jwt.NewClockSkew()
+//  jwt.Clock = skew.Now
+//  client := &http.Client{Transport: skew.Transport(nil)}
+//
+// A ClockSkew is safe for concurrent use.
+type ClockSkew struct {
+	offset int64 // nanoseconds, first field for 64-bit alignment of atomic operations.
+
+	// Clock is the local clock. Defaults to the standard time.Now,
+	// do not set it to the package-level Clock when that one is set to the `Now` method.
+	Clock func() time.Time
+}
+
+// NewClockSkew returns a new clock skew estimator,
+// its offset is zero until the first observation.
+func NewClockSkew() *ClockSkew {
+	return &ClockSkew{Clock: time.Now}
+}
+
+// Offset returns the current estimated offset of the server's clock,
+// positive when the server's clock is ahead of the local one.
+func (s *ClockSkew) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.offset))
+}
+
+// Now returns the local time adjusted by the estimated offset.
+// Set it as the package-level `Clock` to use it on validation.
+func (s *ClockSkew) Now() time.Time {
+	return s.Clock().Add(s.Offset())
+}
+
+// Observe records a server time sample, the offset becomes
+// the difference between the "serverTime" and the local time.
+func (s *ClockSkew) Observe(serverTime time.Time) {
+	if serverTime.IsZero() {
+		return
+	}
+
+	atomic.StoreInt64(&s.offset, int64(serverTime.Sub(s.Clock())))
+}
+
+// ObserveDate records the server time of an HTTP response's "Date" header.
+// Returns an error if the header is missing or malformed.
+func (s *ClockSkew) ObserveDate(header http.Header) error {
+	serverTime, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return err
+	}
+
+	s.Observe(serverTime)
+	return nil
+}
+
+// ObserveIssuedAt records the "iat" claim of a just issued token as a server time sample.
+func (s *ClockSkew) ObserveIssuedAt(issuedAt int64) {
+	if issuedAt > 0 {
+		s.Observe(time.Unix(issuedAt, 0))
+	}
+}
+
+// Transport returns an http.RoundTripper which observes the "Date" header
+// of every response of the "next" (or the http.DefaultTransport, if nil) RoundTripper.
+func (s *ClockSkew) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return skewTransport{skew: s, next: next}
+}
+
+type skewTransport struct {
+	skew *ClockSkew
+	next http.RoundTripper
+}
+
+func (t skewTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(r)
+	if err == nil {
+		t.skew.ObserveDate(resp.Header) // ignore responses without a Date header.
+	}
+
+	return resp, err
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	local := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	skew := NewClockSkew()
+	skew.Clock = func() time.Time { return local }
+
+	if skew.Offset() != 0 || !skew.Now().Equal(local) {
+		t.Fatalf("expected zero offset before the first observation")
+	}
+
+	server := local.Add(5 * time.Minute)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", server.Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: skew.Transport(nil)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if expected, got := 5*time.Minute, skew.Offset(); expected != got {
+		t.Fatalf("expected offset: %s but got: %s", expected, got)
+	}
+
+	// Test the token issued by the server is valid using the estimated clock.
+	token, err := Sign(testAlg, testSecret, Claims{IssuedAt: server.Unix(), Expiry: server.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		Clock = time.Now
+	})
+
+	Clock = skew.Clock
+	if _, err = Verify(testAlg, testSecret, token); err != ErrIssuedInTheFuture {
+		t.Fatalf("expected error: %v but got: %v", ErrIssuedInTheFuture, err)
+	}
+
+	Clock = skew.Now
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	skew.ObserveIssuedAt(local.Add(-time.Minute).Unix())
+	if expected, got := -time.Minute, skew.Offset(); expected != got {
+		t.Fatalf("expected offset: %s but got: %s", expected, got)
+	}
+
+	if err = skew.ObserveDate(make(http.Header)); err == nil {
+		t.Fatalf("expected error on missing Date header")
+	}
+}
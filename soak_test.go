@@ -0,0 +1,340 @@
+//go:build soak
+// +build soak
+
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The soak test is not part of the default test run, run it for hours with:
+//  go test -tags soak -run TestSoak -timeout 0 -soak.duration 4h .
+var (
+	soakDuration = flag.Duration("soak.duration", time.Minute, "the duration of the soak test")
+	soakWorkers  = flag.Int("soak.workers", 2*runtime.GOMAXPROCS(0), "the number of the concurrent verifiers")
+	soakRotate   = flag.Duration("soak.rotate", 100*time.Millisecond, "the key rotation interval of the soak test")
+)
+
+const (
+	soakKeepKeys   = 4               // the published keys, the active one and the retired ones.
+	soakMaxEntries = 1000            // the maximum entries of the caches under test.
+	soakTokenAge   = 2 * time.Second // the maximum lifetime of the soak tokens.
+	soakGCEvery    = 100 * time.Millisecond
+)
+
+// soakIdentityProvider is an identity provider which rotates its signing key
+// and publishes its latest public keys as a JWKS.
+type soakIdentityProvider struct {
+	issuer *Issuer
+
+	mu   sync.RWMutex
+	n    int
+	jwks JWKS
+}
+
+func (idp *soakIdentityProvider) rotate() error {
+	privateKey, publicKey, err := GenerateKeyPair(EdDSA)
+	if err != nil {
+		return err
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	idp.n++
+	kid := fmt.Sprintf("key-%d", idp.n)
+	jwk, err := NewJWK(kid, EdDSA, publicKey)
+	if err != nil {
+		return err
+	}
+
+	idp.jwks.Keys = append(idp.jwks.Keys, jwk)
+	if n := len(idp.jwks.Keys); n > soakKeepKeys {
+		idp.jwks.Keys = append([]*JWK(nil), idp.jwks.Keys[n-soakKeepKeys:]...)
+	}
+
+	idp.issuer.SetKey(kid, privateKey)
+	return nil
+}
+
+func (idp *soakIdentityProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	idp.mu.RLock()
+	defer idp.mu.RUnlock()
+
+	w.Header().Set("Cache-Control", "max-age=1")
+	json.NewEncoder(w).Encode(idp.jwks)
+}
+
+type soakSample struct {
+	at         time.Duration
+	goroutines int
+	heapAlloc  uint64
+}
+
+func takeSoakSample(start time.Time) soakSample {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return soakSample{
+		at:         time.Since(start).Round(time.Second),
+		goroutines: runtime.NumGoroutine(),
+		heapAlloc:  m.HeapAlloc,
+	}
+}
+
+// TestSoak rotates keys, revokes tokens and verifies tokens under load for the soak.duration.
+// It fails on any unexpected verification result, on caches which exceed their bounds
+// and on goroutines or memory which leak from the JWKS refresher, the blocklist GC and the LRU caches.
+func TestSoak(t *testing.T) {
+	baseGoroutines := runtime.NumGoroutine()
+	start := time.Now()
+
+	idp := &soakIdentityProvider{issuer: NewIssuer(EdDSA, nil, soakTokenAge)}
+	if err := idp.rotate(); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(idp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	jwks := NewJWKSClient(srv.URL)
+	jwks.UnknownKidRefreshInterval = soakGCEvery / 10
+	jwks.OnError = func(err error) {
+		t.Errorf("jwks: %v", err)
+	}
+	jwksDone := make(chan error, 1)
+	go func() { jwksDone <- jwks.Run(ctx) }()
+
+	blocklist := NewBlocklistContext(ctx, soakGCEvery)
+
+	memo := NewMemoVerifier(EdDSA)
+	memo.MaxEntries = soakMaxEntries
+
+	negative := NewNegativeCache(time.Second)
+	negative.MaxEntries = soakMaxEntries
+
+	replays := NewReplayStore(soakTokenAge)
+
+	verifier := NewVerifier(memo, nil)
+	verifier.KeyProvider = jwks
+	verifier.Blocklist = blocklist
+	verifier.NegativeCache = negative
+
+	var (
+		stop       int32
+		verified   uint64
+		blocked    uint64
+		tampered   uint64
+		unknownKid uint64
+		wg         sync.WaitGroup
+	)
+	fail := func(format string, args ...interface{}) {
+		if atomic.CompareAndSwapInt32(&stop, 0, 1) {
+			t.Errorf(format, args...)
+		}
+	}
+
+	// verify retries the tokens of a just rotated key, which the client may not have refreshed yet.
+	verify := func(token []byte, validators ...TokenValidator) (err error) {
+		for i := 0; i < 10; i++ {
+			if _, err = verifier.VerifyToken(token, validators...); !errors.Is(err, ErrUnknownKid) {
+				return
+			}
+
+			atomic.AddUint64(&unknownKid, 1)
+			time.Sleep(soakGCEvery / 10)
+		}
+
+		return
+	}
+
+	wg.Add(1)
+	go func() { // the key rotation.
+		defer wg.Done()
+
+		ticker := time.NewTicker(*soakRotate)
+		defer ticker.Stop()
+
+		for atomic.LoadInt32(&stop) == 0 {
+			<-ticker.C
+			if err := idp.rotate(); err != nil {
+				fail("rotate: %v", err)
+			}
+		}
+	}()
+
+	for w := 0; w < *soakWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(int64(w)))
+			for i := 0; atomic.LoadInt32(&stop) == 0; i++ {
+				maxAge := time.Duration(1+rng.Intn(int(soakTokenAge/time.Second))) * time.Second
+				token, err := idp.issuer.Token(fmt.Sprintf("user-%d", rng.Intn(100000)), Map{
+					"jti": fmt.Sprintf("%d-%d", w, i),
+					"exp": Clock().Add(maxAge).Unix(),
+				})
+				if err != nil {
+					fail("sign: %v", err)
+					return
+				}
+
+				switch {
+				case i%5 == 0: // revoked.
+					if err = blocklist.InvalidateToken(token, Claims{ID: fmt.Sprintf("%d-%d", w, i), Expiry: Clock().Add(maxAge).Unix()}); err != nil {
+						fail("revoke: %v", err)
+						return
+					}
+
+					if err = verify(token); !errors.Is(err, ErrBlocked) {
+						fail("revoked token: expected error: %v but got: %v", ErrBlocked, err)
+						return
+					}
+					atomic.AddUint64(&blocked, 1)
+				case i%7 == 0: // tampered signature, twice for the negative cache.
+					if token[len(token)-2] == 'A' {
+						token[len(token)-2] = 'B'
+					} else {
+						token[len(token)-2] = 'A'
+					}
+
+					for j := 0; j < 2; j++ {
+						if err = verify(token); !errors.Is(err, ErrTokenSignature) {
+							fail("tampered token: expected error: %v but got: %v", ErrTokenSignature, err)
+							return
+						}
+					}
+					atomic.AddUint64(&tampered, 1)
+				default: // valid, twice for the memoized verification, once through the replay store.
+					for j := 0; j < 2; j++ {
+						if err = verify(token); err != nil {
+							fail("valid token: %v", err)
+							return
+						}
+					}
+
+					if err = verify(token, replays); err != nil {
+						fail("first use: %v", err)
+						return
+					}
+
+					if err = verify(token, replays); !errors.Is(err, ErrReplayed) {
+						fail("replayed token: expected error: %v but got: %v", ErrReplayed, err)
+						return
+					}
+					atomic.AddUint64(&verified, 1)
+				}
+			}
+		}(w)
+	}
+
+	checkBounds := func() {
+		memo.mu.Lock()
+		memoEntries := 0
+		if memo.entries != nil {
+			memoEntries = memo.entries.len()
+		}
+		memo.mu.Unlock()
+
+		if memoEntries > soakMaxEntries {
+			fail("memo verifier: %d entries exceed the maximum of %d", memoEntries, soakMaxEntries)
+		}
+
+		if n := negative.Len(); n > soakMaxEntries {
+			fail("negative cache: %d entries exceed the maximum of %d", n, soakMaxEntries)
+		}
+
+		if n := len(jwks.Set().Keys); n > soakKeepKeys {
+			fail("jwks: %d keys exceed the published %d", n, soakKeepKeys)
+		}
+	}
+
+	// Sample the goroutines and the heap, the first sample after the warm-up.
+	interval := *soakDuration / 20
+	if interval < time.Second {
+		interval = time.Second
+	}
+	deadline := time.After(*soakDuration)
+	ticker := time.NewTicker(interval)
+	var samples []soakSample
+sampling:
+	for atomic.LoadInt32(&stop) == 0 {
+		select {
+		case <-deadline:
+			break sampling
+		case <-ticker.C:
+		}
+
+		checkBounds()
+		sample := takeSoakSample(start)
+		samples = append(samples, sample)
+		t.Logf("%s: goroutines: %d, heap: %d KB, verified: %d, blocked: %d, tampered: %d, blocklist: %d, replays: %d",
+			sample.at, sample.goroutines, sample.heapAlloc/1024, atomic.LoadUint64(&verified), atomic.LoadUint64(&blocked),
+			atomic.LoadUint64(&tampered), soakCount(blocklist), replays.Len())
+	}
+	ticker.Stop()
+
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+	if t.Failed() {
+		cancel()
+		srv.Close()
+		return
+	}
+
+	checkBounds()
+	t.Logf("verified: %d, blocked: %d, tampered: %d, unknown kid retries: %d",
+		verified, blocked, tampered, unknownKid)
+
+	// The revoked tokens are removed by the blocklist GC after their expiration (in seconds).
+	time.Sleep(soakTokenAge + time.Second + 2*soakGCEvery)
+	if n := soakCount(blocklist); n != 0 {
+		t.Errorf("blocklist: expected the GC to remove all the expired tokens but %d are left", n)
+	}
+
+	// The heap of the last sample should not have grown unbounded since the first one.
+	final := takeSoakSample(start)
+	if len(samples) > 1 {
+		if first := samples[0]; final.heapAlloc > 2*first.heapAlloc+16<<20 {
+			t.Errorf("heap: grew from %d KB to %d KB", first.heapAlloc/1024, final.heapAlloc/1024)
+		}
+	}
+
+	// All the background goroutines return on cancellation.
+	cancel()
+	if err := <-jwksDone; err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("jwks: %v", err)
+	}
+	blocklist.Close()
+	srv.Close()
+	HTTPClient.CloseIdleConnections()
+
+	leakDeadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > baseGoroutines && time.Now().Before(leakDeadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if n := runtime.NumGoroutine(); n > baseGoroutines {
+		buf := make([]byte, 1<<20)
+		t.Errorf("goroutines: %d leaked:\n%s", n-baseGoroutines, buf[:runtime.Stack(buf, true)])
+	}
+}
+
+func soakCount(b *Blocklist) int64 {
+	n, _ := b.Count()
+	return n
+}
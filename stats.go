@@ -0,0 +1,282 @@
+package jwt
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Statistics holds a snapshot of the package's counters.
+// See the `Stats` package-level function.
+type Statistics struct {
+	// Verifications is the total number of verified (or failed to verify) tokens.
+	Verifications uint64 `json:"verifications"`
+	// Failures is the number of failed verifications by reason,
+	// e.g. "expired", "signature", "blocked".
+	Failures map[string]uint64 `json:"failures"`
+	// KeyCacheHits is the number of remote keys served by cache.
+	KeyCacheHits uint64 `json:"key_cache_hits"`
+	// KeyCacheMisses is the number of remote keys lookups which required a refresh
+	// or did not match any key.
+	KeyCacheMisses uint64 `json:"key_cache_misses"`
+	// BlocklistSize is the total number of entries of all in-memory blocklists.
+	BlocklistSize int64 `json:"blocklist_size"`
+	// SuppressedFailures is the number of failures which were not reported
+	// to the `OnFailure` hook because of its rate limit (see `RateLimitFailures`).
+	SuppressedFailures uint64 `json:"suppressed_failures"`
+	// Degraded is the number of verifications which failed open (see `FailOpen`)
+	// by dependency, e.g. "jwks", "blocklist", "introspection".
+	Degraded map[string]uint64 `json:"degraded"`
+	// TokenAge is the histogram of the verified tokens' age (now - "iat"),
+	// e.g. to spot clients which cache their tokens for too long.
+	// Tokens without an "iat" claim are not observed.
+	TokenAge DurationHistogram `json:"token_age"`
+	// TokenTimeLeft is the histogram of the verified tokens' remaining lifetime ("exp" - now),
+	// e.g. to tune the tokens' max age. Tokens without an "exp" claim are not observed.
+	TokenTimeLeft DurationHistogram `json:"token_time_left"`
+	// MigrationSignals is the number of migration signals by kind,
+	// e.g. "retired_key", "alg", see `MigrationSignal`.
+	MigrationSignals map[string]uint64 `json:"migration_signals"`
+}
+
+// DurationHistogram holds a snapshot of a histogram of durations,
+// see the `Statistics.TokenAge` and `Statistics.TokenTimeLeft` fields.
+type DurationHistogram struct {
+	// Count is the total number of observations.
+	Count uint64 `json:"count"`
+	// Sum is the sum of all observed durations.
+	Sum time.Duration `json:"sum"`
+	// Bounds are the inclusive upper bounds of the buckets, in ascending order.
+	Bounds []time.Duration `json:"bounds"`
+	// Buckets is the number of observations per bucket: Buckets[i] counts the durations
+	// greater than Bounds[i-1] and less than or equal to Bounds[i],
+	// the last one (Buckets[len(Bounds)]) counts the durations greater than all Bounds.
+	Buckets []uint64 `json:"buckets"`
+}
+
+// tokenLifetimeBounds are the bucket bounds of the token age and time left histograms.
+var tokenLifetimeBounds = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+type durationHistogram struct {
+	// 64-bit fields first, atomic operations require 64-bit alignment.
+	count   uint64
+	sum     int64
+	buckets []uint64
+}
+
+func newDurationHistogram(bounds []time.Duration) *durationHistogram {
+	return &durationHistogram{buckets: make([]uint64, len(bounds)+1)}
+}
+
+func (h *durationHistogram) observe(bounds []time.Duration, d time.Duration) {
+	if d < 0 {
+		d = 0 // e.g. a tolerated clock skew.
+	}
+
+	i := 0
+	for i < len(bounds) && d > bounds[i] {
+		i++
+	}
+
+	atomic.AddUint64(&h.buckets[i], 1)
+	atomic.AddUint64(&h.count, 1)
+	atomic.AddInt64(&h.sum, int64(d))
+}
+
+func (h *durationHistogram) snapshot(bounds []time.Duration) DurationHistogram {
+	s := DurationHistogram{
+		Count:   atomic.LoadUint64(&h.count),
+		Sum:     time.Duration(atomic.LoadInt64(&h.sum)),
+		Bounds:  append([]time.Duration(nil), bounds...),
+		Buckets: make([]uint64, len(h.buckets)),
+	}
+
+	for i := range h.buckets {
+		s.Buckets[i] = atomic.LoadUint64(&h.buckets[i])
+	}
+
+	return s
+}
+
+// failureReasons is a list of the known errors and their reason name.
+// The order matters, wrapped builtin errors are checked first.
+var failureReasons = []struct {
+	err    error
+	reason string
+}{
+	{ErrDependencyUnavailable, "dependency_unavailable"},
+	{ErrMissing, "missing"},
+	{ErrTokenForm, "form"},
+	{ErrTokenAlg, "alg"},
+	{ErrHeader, "header"},
+	{ErrTokenSignature, "signature"},
+	{ErrInvalidKey, "invalid_key"},
+	{ErrUnknownKid, "unknown_kid"},
+	{ErrDecrypt, "decrypt"},
+	{ErrExpired, "expired"},
+	{ErrNotValidYet, "not_valid_yet"},
+	{ErrIssuedInTheFuture, "issued_in_the_future"},
+	{ErrExpected, "expected"},
+	{ErrBlocked, "blocked"},
+	{ErrClientNotAllowed, "client_not_allowed"},
+	{ErrUnknownSession, "unknown_session"},
+	{ErrPolicyDenied, "policy_denied"},
+	{ErrSubjectForm, "subject_form"},
+	{ErrSubjectNotAllowed, "subject_not_allowed"},
+	{ErrInsufficientAuth, "insufficient_auth"},
+	{ErrStrict, "strict"},
+	{ErrAnonymous, "anonymous"},
+	{ErrAPIKey, "api_key"},
+	{ErrPreVerify, "pre_verify"},
+	{ErrReplayed, "replayed"},
+	{ErrTokenUse, "token_use"},
+	{ErrNoVerifier, "no_verifier"},
+	{ErrPanic, "panic"},
+	{nil, "other"}, // any other error, it should be the last one.
+}
+
+// FailureReason returns a short name of the verification error "err", the same names
+// the `Statistics.Failures` field uses, e.g. "expired". Useful for logging and metrics labels.
+// Returns an empty string if "err" is nil and "other" if it's not a builtin error.
+func FailureReason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return failureReasons[failureReasonIndex(err)].reason
+}
+
+func failureReasonIndex(err error) int {
+	last := len(failureReasons) - 1
+	for i, r := range failureReasons[:last] {
+		if errors.Is(err, r.err) {
+			return i
+		}
+	}
+
+	return last
+}
+
+var stats = struct {
+	// 64-bit fields first, atomic operations require 64-bit alignment.
+	verifications      uint64
+	keyCacheHits       uint64
+	keyCacheMisses     uint64
+	blocklistSize      int64
+	suppressedFailures uint64
+	failures           []uint64 // by failureReasons index.
+	degraded           []uint64 // by dependencies index.
+	signals            []uint64 // by signalKinds index.
+	tokenAge           *durationHistogram
+	tokenTimeLeft      *durationHistogram
+}{
+	failures:      make([]uint64, len(failureReasons)),
+	degraded:      make([]uint64, len(dependencies)),
+	signals:       make([]uint64, len(signalKinds)),
+	tokenAge:      newDurationHistogram(tokenLifetimeBounds),
+	tokenTimeLeft: newDurationHistogram(tokenLifetimeBounds),
+}
+
+func recordVerification(token []byte, err error) {
+	atomic.AddUint64(&stats.verifications, 1)
+	if err != nil {
+		atomic.AddUint64(&stats.failures[failureReasonIndex(err)], 1)
+		reportFailure(token, err)
+	}
+}
+
+// recordVerified same as recordVerification but it observes the lifetime
+// of the "verifiedToken" on success too.
+func recordVerified(token []byte, verifiedToken *VerifiedToken, err error) {
+	recordVerification(token, err)
+	if err != nil || verifiedToken == nil {
+		return
+	}
+
+	now := Clock()
+	claims := verifiedToken.StandardClaims
+	if claims.IssuedAt > 0 {
+		stats.tokenAge.observe(tokenLifetimeBounds, now.Sub(time.Unix(claims.IssuedAt, 0)))
+	}
+
+	if claims.Expiry > 0 {
+		stats.tokenTimeLeft.observe(tokenLifetimeBounds, time.Unix(claims.Expiry, 0).Sub(now))
+	}
+}
+
+// dependencies are the names of the remote dependencies, see `FailurePolicy`.
+var dependencies = []string{DependencyJWKS, DependencyBlocklist, DependencyIntrospection}
+
+// recordDegraded counts a verification which failed open because of the "dependency".
+func recordDegraded(dependency string) {
+	for i, name := range dependencies {
+		if name == dependency {
+			atomic.AddUint64(&stats.degraded[i], 1)
+			return
+		}
+	}
+}
+
+func recordKeyCache(hit bool) {
+	if hit {
+		atomic.AddUint64(&stats.keyCacheHits, 1)
+	} else {
+		atomic.AddUint64(&stats.keyCacheMisses, 1)
+	}
+}
+
+func recordBlocklistSize(delta int) {
+	if delta != 0 {
+		atomic.AddInt64(&stats.blocklistSize, int64(delta))
+	}
+}
+
+// Stats returns a snapshot of the package's counters,
+// so operators can inspect the authentication behavior.
+//
+// The package does not import the "expvar" package itself,
+// to publish the statistics through expvar:
+//  expvar.Publish("jwt", expvar.Func(func() interface{} { return jwt.Stats() }))
+func Stats() Statistics {
+	s := Statistics{
+		Verifications:      atomic.LoadUint64(&stats.verifications),
+		Failures:           make(map[string]uint64),
+		KeyCacheHits:       atomic.LoadUint64(&stats.keyCacheHits),
+		KeyCacheMisses:     atomic.LoadUint64(&stats.keyCacheMisses),
+		BlocklistSize:      atomic.LoadInt64(&stats.blocklistSize),
+		SuppressedFailures: atomic.LoadUint64(&stats.suppressedFailures),
+		TokenAge:           stats.tokenAge.snapshot(tokenLifetimeBounds),
+		TokenTimeLeft:      stats.tokenTimeLeft.snapshot(tokenLifetimeBounds),
+	}
+
+	for i, r := range failureReasons {
+		if n := atomic.LoadUint64(&stats.failures[i]); n > 0 {
+			s.Failures[r.reason] = n
+		}
+	}
+
+	s.Degraded = make(map[string]uint64)
+	for i, name := range dependencies {
+		if n := atomic.LoadUint64(&stats.degraded[i]); n > 0 {
+			s.Degraded[name] = n
+		}
+	}
+
+	s.MigrationSignals = make(map[string]uint64)
+	for i, kind := range signalKinds {
+		if n := atomic.LoadUint64(&stats.signals[i]); n > 0 {
+			s.MigrationSignals[kind] = n
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	prev := Stats()
+
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, []byte("othersecret"), token); err == nil {
+		t.Fatalf("expected signature error")
+	}
+
+	b := NewBlocklist(0)
+	b.InvalidateToken(token, Claims{ID: "jti:stats", Expiry: Clock().Add(time.Minute).Unix()})
+	b.InvalidateToken(token, Claims{ID: "jti:stats", Expiry: Clock().Add(time.Minute).Unix()}) // upsert.
+
+	got := Stats()
+	if expected := prev.Verifications + 2; got.Verifications != expected {
+		t.Fatalf("expected %d verifications but got: %d", expected, got.Verifications)
+	}
+
+	if expected := prev.Failures["signature"] + 1; got.Failures["signature"] != expected {
+		t.Fatalf("expected %d signature failures but got: %d", expected, got.Failures["signature"])
+	}
+
+	if expected := prev.BlocklistSize + 1; got.BlocklistSize != expected {
+		t.Fatalf("expected blocklist size: %d but got: %d", expected, got.BlocklistSize)
+	}
+
+	b.Del("jti:stats")
+	if got = Stats(); got.BlocklistSize != prev.BlocklistSize {
+		t.Fatalf("expected blocklist size: %d but got: %d", prev.BlocklistSize, got.BlocklistSize)
+	}
+}
+
+func TestStatsTokenLifetime(t *testing.T) {
+	prev := Stats()
+
+	now := Clock()
+	token, err := Sign(testAlg, testSecret, Claims{
+		IssuedAt: now.Add(-10 * time.Minute).Unix(),
+		Expiry:   now.Add(2 * time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	// No "iat" and a failed verification, not observed.
+	if token, err = Sign(testAlg, testSecret, Map{"foo": "bar"}, MaxAge(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Verify(testAlg, []byte("othersecret"), token); err == nil {
+		t.Fatalf("expected signature error")
+	}
+
+	got := Stats()
+	if n := got.TokenAge.Count - prev.TokenAge.Count; n != 1 {
+		t.Fatalf("expected a single token age observation but got: %d", n)
+	}
+
+	// 10 minutes: (5m, 15m].
+	if n := got.TokenAge.Buckets[2] - prev.TokenAge.Buckets[2]; n != 1 || got.TokenAge.Bounds[2] != 15*time.Minute {
+		t.Fatalf("expected the token age in the 15m bucket but got: %v", got.TokenAge.Buckets)
+	}
+
+	// 2 hours: (1h, 6h].
+	if n := got.TokenTimeLeft.Buckets[5] - prev.TokenTimeLeft.Buckets[5]; n != 1 {
+		t.Fatalf("expected the token time left in the 6h bucket but got: %v", got.TokenTimeLeft.Buckets)
+	}
+
+	if sum := got.TokenAge.Sum - prev.TokenAge.Sum; sum < 9*time.Minute || sum > 11*time.Minute {
+		t.Fatalf("expected a token age sum of ~10m but got: %s", sum)
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{ErrExpired, "expired"},
+		{fmt.Errorf("%w: iss", ErrExpected), "expected"},
+		{fmt.Errorf("%w: crypto/rsa: verification error", ErrTokenSignature), "signature"},
+		{fmt.Errorf("custom"), "other"},
+	}
+
+	for i, tt := range tests {
+		if got := FailureReason(tt.err); got != tt.expected {
+			t.Fatalf("[%d] expected reason: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+)
+
+// GRPCCode is a gRPC status code, its values are the same as
+// the google.golang.org/grpc/codes ones, e.g. codes.Code(jwt.GRPCStatus(err)).
+type GRPCCode uint32
+
+// The gRPC status codes returned by `GRPCStatus`.
+const (
+	GRPCOK               GRPCCode = 0
+	GRPCInvalidArgument  GRPCCode = 3
+	GRPCPermissionDenied GRPCCode = 7
+	GRPCUnavailable      GRPCCode = 14
+	GRPCUnauthenticated  GRPCCode = 16
+)
+
+// statusCodes is a list of the known errors and their HTTP and gRPC status codes,
+// the rest of the errors are mapped to 401 Unauthorized (Unauthenticated).
+var statusCodes = []struct {
+	err  error
+	http int
+	grpc GRPCCode
+}{
+	{ErrPolicyDenied, http.StatusForbidden, GRPCPermissionDenied},
+	{ErrClientNotAllowed, http.StatusForbidden, GRPCPermissionDenied},
+	{ErrSubjectNotAllowed, http.StatusForbidden, GRPCPermissionDenied},
+	{ErrHeader, http.StatusBadRequest, GRPCInvalidArgument},
+	{ErrClaimsVersion, http.StatusBadRequest, GRPCInvalidArgument},
+	{ErrCircuitOpen, http.StatusServiceUnavailable, GRPCUnavailable},
+	{ErrNotReady, http.StatusServiceUnavailable, GRPCUnavailable},
+	{ErrDependencyUnavailable, http.StatusServiceUnavailable, GRPCUnavailable},
+}
+
+// HTTPStatus returns the HTTP status code of the verification error "err":
+// 403 for tokens denied by a policy (ErrPolicyDenied, ErrClientNotAllowed, ErrSubjectNotAllowed),
+// 400 for tokens of unsupported header fields or claims version (ErrHeader, ErrClaimsVersion),
+// 503 when the keys or a remote dependency are not available (ErrCircuitOpen, ErrNotReady, ErrDependencyUnavailable)
+// and 401 for any other error, e.g. a missing, expired or blocked token.
+// Returns 200 if "err" is nil. The `DefaultErrorHandler` uses the same status codes.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	for _, s := range statusCodes {
+		if errors.Is(err, s.err) {
+			return s.http
+		}
+	}
+
+	return http.StatusUnauthorized
+}
+
+// GRPCStatus same as `HTTPStatus` but it returns the gRPC status code of the error "err",
+// e.g. Unauthenticated for 401 and PermissionDenied for 403.
+//
+// Usage:
+//  return nil, status.Error(codes.Code(jwt.GRPCStatus(err)), err.Error())
+func GRPCStatus(err error) GRPCCode {
+	if err == nil {
+		return GRPCOK
+	}
+
+	for _, s := range statusCodes {
+		if errors.Is(err, s.err) {
+			return s.grpc
+		}
+	}
+
+	return GRPCUnauthenticated
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	var tests = []struct {
+		err        error
+		statusCode int
+		code       GRPCCode
+	}{
+		{nil, http.StatusOK, GRPCOK},
+		{ErrMissing, http.StatusUnauthorized, GRPCUnauthenticated},
+		{ErrTokenForm, http.StatusUnauthorized, GRPCUnauthenticated},
+		{ErrExpired, http.StatusUnauthorized, GRPCUnauthenticated},
+		{ErrIssuerMismatch, http.StatusUnauthorized, GRPCUnauthenticated},
+		{fmt.Errorf("%w: acr", ErrInsufficientAuth), http.StatusUnauthorized, GRPCUnauthenticated},
+		{ErrPolicyDenied, http.StatusForbidden, GRPCPermissionDenied},
+		{ErrClientNotAllowed, http.StatusForbidden, GRPCPermissionDenied},
+		{ErrHeaderType, http.StatusBadRequest, GRPCInvalidArgument},
+		{ErrClaimsVersion, http.StatusBadRequest, GRPCInvalidArgument},
+		{fmt.Errorf("jwks: %w", ErrCircuitOpen), http.StatusServiceUnavailable, GRPCUnavailable},
+		{ErrNotReady, http.StatusServiceUnavailable, GRPCUnavailable},
+		{fmt.Errorf("custom"), http.StatusUnauthorized, GRPCUnauthenticated},
+	}
+
+	for i, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.statusCode {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.statusCode, got)
+		}
+
+		if got := GRPCStatus(tt.err); got != tt.code {
+			t.Fatalf("[%d] expected grpc code: %d but got: %d", i, tt.code, got)
+		}
+	}
+}
+
+func TestDefaultErrorHandlerStatus(t *testing.T) {
+	var tests = []struct {
+		err          error
+		statusCode   int
+		authenticate string
+	}{
+		{ErrHeaderType, http.StatusBadRequest, `Bearer error="invalid_request", error_description="invalid token header: typ"`},
+		{ErrCircuitOpen, http.StatusServiceUnavailable, "Bearer"},
+	}
+
+	for i, tt := range tests {
+		w := httptest.NewRecorder()
+		DefaultErrorHandler(w, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+
+		if w.Code != tt.statusCode {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.statusCode, w.Code)
+		}
+
+		if got := w.Header().Get("WWW-Authenticate"); got != tt.authenticate {
+			t.Fatalf("[%d] expected WWW-Authenticate: %q but got: %q", i, tt.authenticate, got)
+		}
+	}
+}
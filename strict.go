@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrStrict indicates that a token does not meet the requirements of the `Strict` preset.
+var ErrStrict = errors.New("token rejected by strict mode")
+
+// Strict returns a TokenValidator preset of the hardest verification settings,
+// for security-critical verifiers which opt into all of them in one line:
+//  - the "algs" allowlist is mandatory, tokens of any other "alg" header
+//    (and all tokens, if no algorithm is given) are rejected,
+//    e.g. when the algorithm is picked by a `KeyResolver`;
+//  - the "exp" and "iat" claims are required;
+//  - the time claims are compared against the exact `Clock` time, without the
+//    builtin rounding to seconds and without any clock skew tolerance,
+//    a token is expired at its "exp" second;
+//  - the token's parts should be canonical unpadded base64url, without whitespace;
+//  - the header and the claims should be JSON objects without duplicate member names,
+//    which different parsers would read differently;
+//  - the standard claims of unexpected JSON types (e.g. a string "exp") are rejected,
+//    as they are by default.
+//
+// It returns a type of ErrStrict, or the ErrExpired, ErrNotValidYet
+// and ErrIssuedInTheFuture errors on validation failures.
+// Pass it last, so no other validator can skip its checks.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, token, jwt.Strict(jwt.RS256))
+func Strict(algs ...Alg) TokenValidator {
+	names := make([]string, 0, len(algs))
+	for _, alg := range algs {
+		names = append(names, alg.Name())
+	}
+
+	return PayloadValidatorFunc(func(_ context.Context, token, payload []byte, claims Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return fmt.Errorf("%w: no allowed algorithms", ErrStrict)
+		}
+
+		parts := bytes.Split(token, sep)
+		if len(parts) != 3 {
+			return fmt.Errorf("%w: not a compact token", ErrStrict)
+		}
+
+		for _, part := range parts {
+			if !isStrictBase64(part) {
+				return fmt.Errorf("%w: non-canonical base64url", ErrStrict)
+			}
+		}
+
+		header, _ := Base64Decode(parts[0]) // it's valid, see above.
+		if err = checkJSONObject(header); err != nil {
+			return fmt.Errorf("%w: header: %v", ErrStrict, err)
+		}
+
+		var h tokenHeader
+		if err = json.Unmarshal(header, &h); err != nil || !containsString(names, h.Alg) {
+			return fmt.Errorf("%w: algorithm is not allowed", ErrStrict)
+		}
+
+		if err = checkJSONObject(payload); err != nil {
+			return fmt.Errorf("%w: claims: %v", ErrStrict, err)
+		}
+
+		if claims.Expiry <= 0 {
+			return fmt.Errorf("%w: missing exp", ErrStrict)
+		}
+
+		if claims.IssuedAt <= 0 {
+			return fmt.Errorf("%w: missing iat", ErrStrict)
+		}
+
+		now := Clock().Unix() // truncated, not rounded.
+		switch {
+		case now >= claims.Expiry:
+			return ErrExpired
+		case now < claims.NotBefore:
+			return ErrNotValidYet
+		case now < claims.IssuedAt:
+			return ErrIssuedInTheFuture
+		}
+
+		return nil
+	})
+}
+
+// isStrictBase64 reports whether "part" is canonical, unpadded, base64url.
+func isStrictBase64(part []byte) bool {
+	if bytes.ContainsAny(part, "\r\n") { // the decoder ignores new lines.
+		return false
+	}
+
+	_, err := base64.RawURLEncoding.Strict().DecodeString(BytesToString(part))
+	return err == nil
+}
+
+// checkJSONObject reports an error if "data" is not a single JSON object
+// or if any of its (nested) objects contains duplicate member names.
+func checkJSONObject(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if delim, err := dec.Token(); err != nil || delim != json.Delim('{') {
+		return errors.New("not a JSON object")
+	}
+
+	if err := checkJSONMembers(dec); err != nil {
+		return err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("trailing data")
+	}
+
+	return nil
+}
+
+// checkJSONMembers reads the members of an object, after its opening delimiter.
+func checkJSONMembers(dec *json.Decoder) error {
+	names := make(map[string]struct{})
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		name, _ := t.(string)
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("duplicate member %q", name)
+		}
+		names[name] = struct{}{}
+
+		if err = checkJSONValue(dec); err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token() // '}'
+	return err
+}
+
+func checkJSONValue(dec *json.Decoder) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		return checkJSONMembers(dec)
+	case json.Delim('['):
+		for dec.More() {
+			if err = checkJSONValue(dec); err != nil {
+				return err
+			}
+		}
+
+		_, err = dec.Token() // ']'
+		return err
+	}
+
+	return nil
+}
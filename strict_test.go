@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStrict(t *testing.T) {
+	now := time.Unix(1600000000, 0).Add(300 * time.Millisecond)
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	iat := strconv.FormatInt(now.Unix()-60, 10)
+	signRaw := func(header Header, payload string) []byte {
+		token, err := SignWithHeader(testAlg, testSecret, header, []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	hs256 := Header{{"alg", "HS256"}, {"typ", "JWT"}}
+	valid := signRaw(hs256, `{"iat":`+iat+`,"exp":`+strconv.FormatInt(now.Unix()+60, 10)+`}`)
+
+	tests := []struct {
+		token  []byte
+		strict TokenValidator
+		err    error
+	}{
+		{valid, Strict(HS256), nil},
+		{valid, Strict(HS384, HS256), nil},
+		{valid, Strict(), ErrStrict},
+		{valid, Strict(HS512), ErrStrict},
+		// Missing exp or iat.
+		{signRaw(hs256, `{"iat":`+iat+`}`), Strict(HS256), ErrStrict},
+		{signRaw(hs256, `{"exp":`+strconv.FormatInt(now.Unix()+60, 10)+`}`), Strict(HS256), ErrStrict},
+		// Expired at its "exp" second, the builtin validation accepts it.
+		{signRaw(hs256, `{"iat":`+iat+`,"exp":`+strconv.FormatInt(now.Unix(), 10)+`}`), Strict(HS256), ErrExpired},
+		{signRaw(hs256, `{"iat":`+iat+`,"nbf":`+strconv.FormatInt(now.Unix()+1, 10)+`,"exp":`+strconv.FormatInt(now.Unix()+60, 10)+`}`), Strict(HS256), ErrNotValidYet},
+		// Duplicate members.
+		{signRaw(hs256, `{"iat":`+iat+`,"exp":1,"exp":`+strconv.FormatInt(now.Unix()+60, 10)+`}`), Strict(HS256), ErrStrict},
+		{signRaw(hs256, `{"iat":`+iat+`,"exp":`+strconv.FormatInt(now.Unix()+60, 10)+`,"data":[{"a":1,"a":2}]}`), Strict(HS256), ErrStrict},
+	}
+
+	for i, tt := range tests {
+		_, err := Verify(testAlg, testSecret, tt.token, tt.strict)
+		if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+
+	// A header of duplicate members can't be signed by SignWithHeader, build it manually.
+	header := Base64Encode([]byte(`{"alg":"HS256","alg":"HS256"}`))
+	payload := Base64Encode([]byte(`{"iat":` + iat + `,"exp":` + strconv.FormatInt(now.Unix()+60, 10) + `}`))
+	signature, err := createSignature(testAlg, testSecret, joinParts(header, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, joinParts(header, payload, signature), Strict(HS256)); !errors.Is(err, ErrStrict) {
+		t.Fatalf("expected error: %v but got: %v", ErrStrict, err)
+	}
+
+	// Padded base64url parts are accepted by default but not by the strict mode.
+	padded := append(append([]byte{}, valid...), '=')
+	if _, err = Verify(testAlg, testSecret, padded, Strict(HS256)); !errors.Is(err, ErrStrict) {
+		t.Fatalf("expected error: %v but got: %v", ErrStrict, err)
+	}
+}
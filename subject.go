@@ -0,0 +1,200 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSubjectForm indicates a "sub" claim which is not a well-formed `Subject`.
+	ErrSubjectForm = errors.New("malformed subject")
+	// ErrSubjectNotAllowed indicates a "sub" claim which is not allowed,
+	// see `AllowSubjectTypes` and `AllowSubjects`.
+	ErrSubjectNotAllowed = errors.New("subject not allowed")
+)
+
+// The common types of a `Subject`.
+const (
+	// SubjectUser is the type of the end-user subjects, e.g. "user:123".
+	SubjectUser = "user"
+	// SubjectService is the type of the service subjects, e.g. "svc:billing".
+	SubjectService = "svc"
+	// SubjectSPIFFE is the type of the SPIFFE ID subjects, e.g. "spiffe://example.org/ns/prod/sa/billing".
+	SubjectSPIFFE = "spiffe"
+)
+
+const spiffeScheme = SubjectSPIFFE + "://"
+
+// Subject is a structured "sub" claim value: a typed identifier of the "type:id" form
+// (e.g. "user:123" and "svc:billing"), a SPIFFE ID (e.g. "spiffe://example.org/ns/prod/sa/billing")
+// or an untyped identifier (e.g. "123"). See `ParseSubject`.
+//
+// Usage:
+//  token, err := issuer.Token(jwt.Subject{Type: jwt.SubjectService, ID: "billing"}.String(), nil)
+//  [...]
+//  subject, err := jwt.ParseSubject(verifiedToken.StandardClaims.Subject)
+//  if subject.Type == jwt.SubjectUser { ... }
+type Subject struct {
+	// Type is the type of the subject, e.g. SubjectUser, empty for an untyped one.
+	Type string
+	// TrustDomain is the trust domain of a SPIFFE ID, e.g. "example.org".
+	TrustDomain string
+	// ID is the identifier of the subject, e.g. "123",
+	// the path of a SPIFFE ID, e.g. "/ns/prod/sa/billing".
+	ID string
+}
+
+// ParseSubject parses and validates the "sub" claim value "s".
+// A value which starts with "spiffe://" must be a workload SPIFFE ID:
+// a lowercase trust domain of letters, digits, '.', '-' and '_' and a non-empty path
+// of segments of letters, digits, '.', '-' and '_', other than "." and "..".
+// The type of a "type:id" value must start with a lowercase letter and contain
+// lowercase letters, digits, '-' and '_' only. Any other non-empty value is an untyped subject.
+// Returns a type of ErrSubjectForm on invalid values, e.g. an empty one,
+// one of white space or control characters or an empty "id".
+func ParseSubject(s string) (Subject, error) {
+	if s == "" {
+		return Subject{}, fmt.Errorf("%w: empty", ErrSubjectForm)
+	}
+
+	for _, r := range s {
+		if r <= ' ' || r == 0x7f {
+			return Subject{}, fmt.Errorf("%w: invalid character", ErrSubjectForm)
+		}
+	}
+
+	if strings.HasPrefix(s, spiffeScheme) {
+		return parseSPIFFE(s[len(spiffeScheme):])
+	}
+
+	i := strings.IndexByte(s, ':')
+	if i == -1 || !validSubjectType(s[:i]) {
+		return Subject{ID: s}, nil
+	}
+
+	if i == len(s)-1 {
+		return Subject{}, fmt.Errorf("%w: empty id", ErrSubjectForm)
+	}
+
+	return Subject{Type: s[:i], ID: s[i+1:]}, nil
+}
+
+func validSubjectType(typ string) bool {
+	if typ == "" || typ[0] < 'a' || typ[0] > 'z' {
+		return false
+	}
+
+	for _, r := range typ {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseSPIFFE(s string) (Subject, error) {
+	i := strings.IndexByte(s, '/')
+	if i <= 0 {
+		return Subject{}, fmt.Errorf("%w: spiffe id without a trust domain or a path", ErrSubjectForm)
+	}
+
+	trustDomain, path := s[:i], s[i:]
+	for _, r := range trustDomain {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return Subject{}, fmt.Errorf("%w: invalid spiffe trust domain", ErrSubjectForm)
+		}
+	}
+
+	for _, segment := range strings.Split(path[1:], "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return Subject{}, fmt.Errorf("%w: invalid spiffe path", ErrSubjectForm)
+		}
+
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+				return Subject{}, fmt.Errorf("%w: invalid spiffe path", ErrSubjectForm)
+			}
+		}
+	}
+
+	return Subject{Type: SubjectSPIFFE, TrustDomain: trustDomain, ID: path}, nil
+}
+
+// String returns the "sub" claim value of the subject.
+func (s Subject) String() string {
+	switch s.Type {
+	case "":
+		return s.ID
+	case SubjectSPIFFE:
+		return spiffeScheme + s.TrustDomain + s.ID
+	default:
+		return s.Type + ":" + s.ID
+	}
+}
+
+// Match reports whether the subject matches the "pattern": the same subject (e.g. "svc:billing")
+// or, for a pattern which ends with '*', a subject which starts with the rest of it,
+// e.g. "user:*" and "spiffe://example.org/ns/prod/*".
+func (s Subject) Match(pattern string) bool {
+	value := s.String()
+	if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+		return strings.HasPrefix(value, prefix)
+	}
+
+	return value == pattern
+}
+
+// AllowSubjectTypes returns a TokenValidator which accepts the tokens of a "sub" claim
+// of one of the "types" only, e.g. SubjectService and SubjectSPIFFE for service-to-service APIs.
+// An empty type allows the untyped subjects.
+// It returns a type of ErrSubjectForm for a malformed subject and ErrSubjectNotAllowed for any other type.
+//
+// Usage:
+//  verifier.Validators = append(verifier.Validators, jwt.AllowSubjectTypes(jwt.SubjectService, jwt.SubjectSPIFFE))
+func AllowSubjectTypes(types ...string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		subject, err := ParseSubject(c.Subject)
+		if err != nil {
+			return err
+		}
+
+		if !containsString(types, subject.Type) {
+			return ErrSubjectNotAllowed
+		}
+
+		return nil
+	}
+}
+
+// AllowSubjects returns a TokenValidator which accepts the tokens of a "sub" claim
+// which matches one of the "patterns" only, see `Subject.Match`.
+// It returns a type of ErrSubjectForm for a malformed subject and ErrSubjectNotAllowed for any other subject.
+//
+// Usage:
+//  verifier.Validators = append(verifier.Validators, jwt.AllowSubjects("svc:billing", "spiffe://example.org/ns/prod/*"))
+func AllowSubjects(patterns ...string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		subject, err := ParseSubject(c.Subject)
+		if err != nil {
+			return err
+		}
+
+		for _, pattern := range patterns {
+			if subject.Match(pattern) {
+				return nil
+			}
+		}
+
+		return ErrSubjectNotAllowed
+	}
+}
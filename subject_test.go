@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseSubject(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Subject
+		err      bool
+	}{
+		{"user:123", Subject{Type: SubjectUser, ID: "123"}, false},
+		{"svc:billing", Subject{Type: SubjectService, ID: "billing"}, false},
+		{"tenant-1:john:doe", Subject{Type: "tenant-1", ID: "john:doe"}, false},
+		{"spiffe://example.org/ns/prod/sa/billing", Subject{Type: SubjectSPIFFE, TrustDomain: "example.org", ID: "/ns/prod/sa/billing"}, false},
+		{"123", Subject{ID: "123"}, false},
+		{"User:123", Subject{ID: "User:123"}, false},
+		{"", Subject{}, true},
+		{"user:", Subject{}, true},
+		{"user:john doe", Subject{}, true},
+		{"spiffe://example.org", Subject{}, true},
+		{"spiffe://example.org/", Subject{}, true},
+		{"spiffe://Example.org/billing", Subject{}, true},
+		{"spiffe://example.org/ns//billing", Subject{}, true},
+		{"spiffe://example.org/ns/../billing", Subject{}, true},
+		{"spiffe:///billing", Subject{}, true},
+	}
+
+	for i, tt := range tests {
+		subject, err := ParseSubject(tt.value)
+		if tt.err {
+			if !errors.Is(err, ErrSubjectForm) {
+				t.Fatalf("[%d] expected error: %v but got: %v", i, ErrSubjectForm, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if subject != tt.expected {
+			t.Fatalf("[%d] expected subject: %#+v but got: %#+v", i, tt.expected, subject)
+		}
+
+		if got := subject.String(); got != tt.value {
+			t.Fatalf("[%d] expected value: %q but got: %q", i, tt.value, got)
+		}
+	}
+}
+
+func TestSubjectMatch(t *testing.T) {
+	subject := Subject{Type: SubjectSPIFFE, TrustDomain: "example.org", ID: "/ns/prod/sa/billing"}
+
+	for _, pattern := range []string{"spiffe://example.org/ns/prod/sa/billing", "spiffe://example.org/ns/prod/*", "*"} {
+		if !subject.Match(pattern) {
+			t.Fatalf("expected subject to match: %q", pattern)
+		}
+	}
+
+	for _, pattern := range []string{"spiffe://example.org/ns/prod", "spiffe://example.org/ns/dev/*", "svc:*"} {
+		if subject.Match(pattern) {
+			t.Fatalf("expected subject to not match: %q", pattern)
+		}
+	}
+}
+
+func TestAllowSubjects(t *testing.T) {
+	verifier := NewVerifier(testAlg, testSecret)
+	verifier.Validators = append(verifier.Validators,
+		AllowSubjectTypes(SubjectService, SubjectSPIFFE),
+		AllowSubjects("svc:billing", "spiffe://example.org/ns/prod/*"),
+	)
+
+	tests := []struct {
+		subject string
+		err     error
+	}{
+		{"svc:billing", nil},
+		{"spiffe://example.org/ns/prod/sa/orders", nil},
+		{"svc:orders", ErrSubjectNotAllowed},
+		{"user:123", ErrSubjectNotAllowed},
+		{"spiffe://example.org/ns/prod/", ErrSubjectForm},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, Claims{Subject: tt.subject}, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = verifier.VerifyToken(token)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+
+	if got := HTTPStatus(ErrSubjectNotAllowed); got != http.StatusForbidden {
+		t.Fatalf("expected status: %d but got: %d", http.StatusForbidden, got)
+	}
+}
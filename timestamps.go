@@ -0,0 +1,84 @@
+package jwt
+
+import "time"
+
+// millisecondsThreshold is the smallest "exp", "nbf" or "iat" value
+// which the `Timestamps.Auto` detection reads as milliseconds.
+// As seconds it's the year 5138, as milliseconds the year 1973.
+const millisecondsThreshold = 1e11
+
+// Timestamps is a TokenValidator option of the `Verify` functions (and `Verifier.Validators`)
+// which decodes the "exp", "nbf" and "iat" claims of legacy issuers,
+// e.g. the ones which emit milliseconds instead of the RFC 7519 seconds since epoch.
+// The claims are converted to seconds since the POSIX epoch before the builtin
+// validation, so the `VerifiedToken.StandardClaims` hold the converted values.
+// It does not validate anything itself.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.Timestamps{Unit: time.Millisecond})
+//  // OR detect the milliseconds ones:
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.Timestamps{Auto: true})
+type Timestamps struct {
+	// Unit is the unit of the timestamps, e.g. time.Millisecond.
+	// Defaults to time.Second.
+	Unit time.Duration
+	// Epoch is the moment the timestamps count from.
+	// Defaults to the POSIX epoch (1970-01-01 UTC).
+	Epoch time.Time
+	// Auto, if true, reads the timestamps which are too large to be seconds
+	// (after the year 5138) as milliseconds, and the rest as of the Unit.
+	// Useful when a verifier accepts tokens of both legacy and standard issuers.
+	Auto bool
+}
+
+var _ TokenValidator = Timestamps{}
+
+// ValidateToken completes the TokenValidator interface.
+// It returns the "err" as it's.
+func (ts Timestamps) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// normalizeClaims converts the timestamps of the "claims" to seconds since the POSIX epoch.
+func (ts Timestamps) normalizeClaims(claims *Claims) {
+	claims.NotBefore = ts.seconds(claims.NotBefore)
+	claims.IssuedAt = ts.seconds(claims.IssuedAt)
+	claims.Expiry = ts.seconds(claims.Expiry)
+}
+
+func (ts Timestamps) seconds(value int64) int64 {
+	if value <= 0 { // keep the "not set" meaning.
+		return value
+	}
+
+	unit := ts.Unit
+	if unit <= 0 {
+		unit = time.Second
+	}
+
+	if ts.Auto && value >= millisecondsThreshold {
+		unit = time.Millisecond
+	}
+
+	if unit >= time.Second {
+		value *= int64(unit / time.Second)
+	} else {
+		value /= int64(time.Second / unit)
+	}
+
+	if !ts.Epoch.IsZero() {
+		value += ts.Epoch.Unix()
+	}
+
+	return value
+}
+
+// normalizeTimestamps applies the last `Timestamps` option of the "validators", if any.
+func normalizeTimestamps(claims *Claims, validators []TokenValidator) {
+	for i := len(validators) - 1; i >= 0; i-- {
+		if ts, ok := validators[i].(Timestamps); ok {
+			ts.normalizeClaims(claims)
+			return
+		}
+	}
+}
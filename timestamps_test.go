@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestamps(t *testing.T) {
+	now := time.Now()
+	defer func() { Clock = time.Now }()
+	Clock = func() time.Time { return now }
+
+	millis := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		claims    Map
+		option    Timestamps
+		expiresAt int64
+		err       error
+	}{
+		{Map{"iat": millis(now), "exp": millis(now.Add(time.Minute))}, Timestamps{Unit: time.Millisecond}, now.Add(time.Minute).Unix(), nil},
+		{Map{"iat": millis(now.Add(-time.Hour)), "exp": millis(now.Add(-time.Minute))}, Timestamps{Unit: time.Millisecond}, now.Add(-time.Minute).Unix(), ErrExpired},
+		{Map{"exp": millis(now.Add(time.Minute))}, Timestamps{Auto: true}, now.Add(time.Minute).Unix(), nil},
+		{Map{"exp": now.Add(time.Minute).Unix()}, Timestamps{Auto: true}, now.Add(time.Minute).Unix(), nil},
+		{Map{"nbf": millis(now.Add(time.Minute))}, Timestamps{Auto: true}, 0, ErrNotValidYet},
+		{Map{"exp": now.Add(time.Minute).Unix() - epoch.Unix()}, Timestamps{Epoch: epoch}, now.Add(time.Minute).Unix(), nil},
+		{Map{"exp": now.Add(-time.Minute).Unix()/60 - epoch.Unix()/60}, Timestamps{Unit: time.Minute, Epoch: epoch}, now.Add(-time.Minute).Unix() / 60 * 60, ErrExpired},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token, tt.option)
+		if err != tt.err {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+
+		if err == nil && verifiedToken.StandardClaims.Expiry != tt.expiresAt {
+			t.Fatalf("[%d] expected exp: %d but got: %d", i, tt.expiresAt, verifiedToken.StandardClaims.Expiry)
+		}
+	}
+
+	// Without the option the milliseconds "iat" is in the future.
+	token, err := Sign(testAlg, testSecret, Map{"iat": millis(now)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); err != ErrIssuedInTheFuture {
+		t.Fatalf("expected error: %v but got: %v", ErrIssuedInTheFuture, err)
+	}
+}
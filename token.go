@@ -2,7 +2,10 @@ package jwt
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -23,66 +26,178 @@ type (
 	PublicKey interface{}
 )
 
-func encodeToken(alg Alg, key PrivateKey, payload []byte) ([]byte, error) {
-	header := createHeader(alg.Name())
-	payload = Base64Encode(payload)
+func encodeToken(alg AlgSigner, key PrivateKey, payload []byte) ([]byte, error) {
+	return encodeTokenWithKid(alg, key, "", payload)
+}
+
+// encodeTokenWithKid same as encodeToken but it sets the "kid" header, if not empty.
+func encodeTokenWithKid(alg AlgSigner, key PrivateKey, kid string, payload []byte) ([]byte, error) {
+	return encodeTokenWithHeader(alg, key, createHeaderWithKid(alg.Name(), kid), payload)
+}
 
-	headerPayload := joinParts(header, payload)
+// encodeTokenWithHeader same as encodeToken but it accepts the (encoded) header.
+// The header.payload.signature token is assembled in a single allocation,
+// pre-sized by the signature size of the builtin algorithms.
+func encodeTokenWithHeader(alg AlgSigner, key PrivateKey, header, payload []byte) ([]byte, error) {
+	payloadSize := base64.RawURLEncoding.EncodedLen(len(payload))
+	signatureSize := base64.RawURLEncoding.EncodedLen(signatureSizeHint(alg, key))
 
-	signature, err := createSignature(alg, key, headerPayload)
+	token := make([]byte, 0, len(header)+1+payloadSize+1+signatureSize)
+	token = append(token, header...)
+	token = append(token, sep...)
+	token = appendBase64(token, payload)
+
+	// The signer can't append to the header.payload part of the token.
+	headerPayload := token[:len(token):len(token)]
+	signature, err := alg.Sign(key, headerPayload)
 	if err != nil {
 		return nil, fmt.Errorf("encodeToken: signature: %w", err)
 	}
 
 	// header.payload.signature
-	token := joinParts(headerPayload, signature)
+	token = append(token, sep...)
+	token = appendBase64(token, signature)
 
 	return token, nil
 }
 
+// signatureSizeHint returns the (raw) signature size of the builtin algorithms,
+// or a common size for the rest of them.
+func signatureSizeHint(alg AlgSigner, key PrivateKey) int {
+	switch a := alg.(type) {
+	case *algHMAC:
+		return a.hasher.Size()
+	case *algRSA, *algRSAPSS:
+		if privateKey, ok := key.(*rsa.PrivateKey); ok {
+			return privateKey.Size()
+		}
+	case *algECDSA:
+		return 2 * ((a.curveBits + 7) / 8)
+	case *algEdDSA, *algEd25519Options:
+		return ed25519.SignatureSize
+	}
+
+	return 256
+}
+
 // We could omit the "alg" because the token contains it
 // BUT, for security reason the algorithm MUST explicitly match
 // (even if we perform hash comparison later on).
 //
 // Decodes and verifies the given compact "token".
 // It returns the header, payoad and signature parts (decoded).
-func decodeToken(alg Alg, key PublicKey, token []byte) ([]byte, []byte, []byte, error) {
-	parts := bytes.Split(token, sep)
-	if len(parts) != 3 {
+func decodeToken(alg AlgVerifier, key PublicKey, token []byte) ([]byte, []byte, []byte, error) {
+	return decodeTokenReport(alg, key, token, nil)
+}
+
+// decodeTokenReport same as decodeToken but it records its checks to the "report", if not nil.
+func decodeTokenReport(alg AlgVerifier, key PublicKey, token []byte, report *VerifyReport) ([]byte, []byte, []byte, error) {
+	start := report.now()
+	header, payload, signature, ok := splitToken(token)
+	if !ok {
+		report.add("form", start, ErrTokenForm)
 		return nil, nil, nil, ErrTokenForm
 	}
+	report.add("form", start, nil)
 
-	header := parts[0]
-	payload := parts[1]
-	signature := parts[2]
-
+	start = report.now()
 	headerDecoded, err := Base64Decode(header)
+	if err == nil {
+		// validate header equality.
+		err = compareHeader(alg.Name(), headerDecoded)
+	}
+	report.add("header", start, err)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	// validate header equality.
-	expectedHeader := createHeaderRaw(alg.Name())
-	if !bytes.Equal(expectedHeader, headerDecoded) {
-		return nil, nil, nil, ErrTokenAlg
-	}
 
+	start = report.now()
 	signatureDecoded, err := Base64Decode(signature)
-	if err != nil {
-		return nil, nil, nil, err
+	if err == nil {
+		// validate signature, the header.payload part of the token as it's.
+		n := len(header) + len(sep) + len(payload)
+		headerPayload := token[:n:n]
+		err = alg.Verify(key, headerPayload, signatureDecoded)
 	}
-	// validate signature.
-	headerPayload := joinParts(header, payload)
-	if err := alg.Verify(key, headerPayload, signatureDecoded); err != nil {
+	report.add("signature", start, err)
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	start = report.now()
 	payload, err = Base64Decode(payload)
+	report.add("payload", start, err)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	return headerDecoded, payload, signatureDecoded, nil
 }
 
+// splitToken returns the three parts of the compact "token", without allocations.
+// It reports false if the token has not exactly three parts.
+func splitToken(token []byte) (header, payload, signature []byte, ok bool) {
+	i := bytes.IndexByte(token, sep[0])
+	if i == -1 {
+		return
+	}
+
+	j := bytes.IndexByte(token[i+1:], sep[0])
+	if j == -1 {
+		return
+	}
+	j += i + 1
+
+	if bytes.IndexByte(token[j+1:], sep[0]) != -1 {
+		return
+	}
+
+	return token[:i:i], token[i+1 : j : j], token[j+1:], true
+}
+
+// tokenKeyID returns the "kid" header of the compact "token", without verifying it.
+func tokenKeyID(token []byte) (string, error) {
+	var h tokenHeader
+	if err := unverifiedHeader(token, &h); err != nil {
+		return "", err
+	}
+
+	return h.Kid, nil
+}
+
+// unverifiedHeader decodes the header of the compact "token" into "dest", without verifying it.
+func unverifiedHeader(token []byte, dest interface{}) error {
+	parts := bytes.Split(token, sep)
+	if len(parts) != 3 {
+		return ErrTokenForm
+	}
+
+	headerDecoded, err := Base64Decode(parts[0])
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(headerDecoded, dest); err != nil {
+		return ErrTokenForm
+	}
+
+	return nil
+}
+
+// unverifiedPayload decodes the (not encrypted) payload of the compact "token" into "dest", without verifying it.
+func unverifiedPayload(token []byte, dest interface{}) error {
+	parts := bytes.Split(token, sep)
+	if len(parts) != 3 {
+		return ErrTokenForm
+	}
+
+	payload, err := Base64Decode(parts[1])
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, dest)
+}
+
 var (
 	sep    = []byte(".")
 	pad    = []byte("=")
@@ -140,7 +255,86 @@ func createHeaderRaw(alg string) []byte {
 	return []byte(`{"alg":"` + alg + `","typ":"JWT"}`)
 }
 
-func createSignature(alg Alg, key PrivateKey, headerAndPayload []byte) ([]byte, error) {
+// createHeaderWithKid same as createHeader but it includes the "kid" field, if not empty.
+func createHeaderWithKid(alg, kid string) []byte {
+	if kid == "" {
+		return createHeader(alg)
+	}
+
+	kidValue, _ := json.Marshal(kid) // escape the kid, a string never fails to marshal.
+	return Base64Encode([]byte(`{"alg":"` + alg + `","kid":` + string(kidValue) + `,"typ":"JWT"}`))
+}
+
+// createHeaderWithAttestation same as createHeaderWithKid but it includes the "att" field, if not nil.
+func createHeaderWithAttestation(alg, kid string, att *Attestation) []byte {
+	if att == nil {
+		return createHeaderWithKid(alg, kid)
+	}
+
+	header, _ := json.Marshal(tokenHeader{Alg: alg, Kid: kid, Typ: "JWT", Att: att}) // it never fails, all fields are strings.
+	return Base64Encode(header)
+}
+
+// tokenHeader is the decoded header of a token, its fields we care about.
+type tokenHeader struct {
+	Alg string       `json:"alg"`
+	Kid string       `json:"kid,omitempty"`
+	Typ string       `json:"typ,omitempty"`
+	Att *Attestation `json:"att,omitempty"`
+	// RFC 7797, see `SignDetached`.
+	B64  *bool    `json:"b64,omitempty"`
+	Crit []string `json:"crit,omitempty"`
+}
+
+// compareHeader reports whether the decoded header matches the expected algorithm.
+// The fixed headers are checked first, so tokens generated by this package (without a "kid")
+// are compared without JSON decoding. Any other header (e.g. with a "kid" or different fields order)
+// is decoded and its "alg" must match, its "typ" (if any) must be "JWT".
+func compareHeader(alg string, headerDecoded []byte) error {
+	if bytes.Equal(createHeaderRaw(alg), headerDecoded) {
+		return nil
+	}
+
+	var h tokenHeader
+	if err := json.Unmarshal(headerDecoded, &h); err != nil {
+		return ErrTokenAlg
+	}
+
+	if h.Alg != alg || (h.Typ != "" && h.Typ != "JWT") {
+		return ErrTokenAlg
+	}
+
+	return h.checkCritical(false)
+}
+
+// checkCritical reports whether the "crit" header lists understood extensions only,
+// see `CriticalHeaders`. An unencoded payload ("b64": false) is accepted if "allowUnencoded" is true,
+// its "b64" header must be listed in "crit" as the RFC 7797 requires.
+func (h tokenHeader) checkCritical(allowUnencoded bool) error {
+	if h.Crit != nil && len(h.Crit) == 0 {
+		return fmt.Errorf("%w: empty", ErrHeaderCritical)
+	}
+
+	for _, name := range h.Crit {
+		if name != "b64" && !containsString(CriticalHeaders, name) {
+			return fmt.Errorf("%w: unsupported %q", ErrHeaderCritical, name)
+		}
+	}
+
+	if h.B64 != nil {
+		if !containsString(h.Crit, "b64") {
+			return fmt.Errorf("%w: b64 is not listed", ErrHeaderCritical)
+		}
+
+		if !*h.B64 && !allowUnencoded {
+			return fmt.Errorf("%w: unencoded payload, see VerifyDetached", ErrHeaderCritical)
+		}
+	}
+
+	return nil
+}
+
+func createSignature(alg AlgSigner, key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	signature, err := alg.Sign(key, headerAndPayload)
 	if err != nil {
 		return nil, err
@@ -148,18 +342,38 @@ func createSignature(alg Alg, key PrivateKey, headerAndPayload []byte) ([]byte,
 	return Base64Encode(signature), nil
 }
 
-// Base64Encode encodes "src" to jwt base64 url format.
-// We could use the base64.RawURLEncoding but the below is a bit faster.
+// Base64Encode encodes "src" to jwt base64 url format (no trailing '=').
 func Base64Encode(src []byte) []byte {
-	buf := make([]byte, base64.URLEncoding.EncodedLen(len(src)))
-	base64.URLEncoding.Encode(buf, src)
+	buf := make([]byte, base64.RawURLEncoding.EncodedLen(len(src)))
+	base64.RawURLEncoding.Encode(buf, src)
+
+	return buf
+}
+
+// appendBase64 appends the jwt base64 url encoded "src" to "dst".
+func appendBase64(dst, src []byte) []byte {
+	n := len(dst)
+	size := base64.RawURLEncoding.EncodedLen(len(src))
+	if cap(dst)-n < size {
+		grown := make([]byte, n, n+size)
+		copy(grown, dst)
+		dst = grown
+	}
 
-	return bytes.TrimRight(buf, padStr) // JWT: no trailing '='.
+	dst = dst[:n+size]
+	base64.RawURLEncoding.Encode(dst[n:], src)
+	return dst
 }
 
 // Base64Decode decodes "src" to jwt base64 url format.
-// We could use the base64.RawURLEncoding but the below is a bit faster.
+// The trailing '=' are optional.
 func Base64Decode(src []byte) ([]byte, error) {
+	if bytes.IndexByte(src, '=') == -1 { // JWT: no trailing '='.
+		buf := make([]byte, base64.RawURLEncoding.DecodedLen(len(src)))
+		n, err := base64.RawURLEncoding.Decode(buf, src)
+		return buf[:n], err
+	}
+
 	if n := len(src) % 4; n > 0 {
 		// JWT: Because of no trailing '=' let's suffix it
 		// with the correct number of those '=' before decoding.
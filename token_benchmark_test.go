@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func benchmarkSign(b *testing.B, alg Alg, key PrivateKey) {
+	claims := testStruct{Username: "kataras", Age: 27}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Sign(alg, key, claims, MaxAge(15*time.Minute)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkVerify(b *testing.B, alg Alg, privateKey PrivateKey, publicKey PublicKey) {
+	token, err := Sign(alg, privateKey, testStruct{Username: "kataras", Age: 27}, MaxAge(15*time.Minute))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verifiedToken, err := Verify(alg, publicKey, token)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var claims testStruct
+		if err = verifiedToken.Claims(&claims); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSignHS256(b *testing.B) {
+	benchmarkSign(b, HS256, testSecret)
+}
+
+func BenchmarkVerifyHS256(b *testing.B) {
+	benchmarkVerify(b, HS256, testSecret, testSecret)
+}
+
+func BenchmarkSignRS256(b *testing.B) {
+	privateKey, _ := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	benchmarkSign(b, RS256, privateKey)
+}
+
+func BenchmarkVerifyRS256(b *testing.B) {
+	privateKey, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	benchmarkVerify(b, RS256, privateKey, publicKey)
+}
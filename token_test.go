@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -115,3 +116,37 @@ func compareMap(m1, m2 map[string]interface{}) bool {
 
 	return true
 }
+
+func TestSplitToken(t *testing.T) {
+	tests := []struct {
+		token string
+		parts []string
+	}{
+		{"a.b.c", []string{"a", "b", "c"}},
+		{"a..c", []string{"a", "", "c"}},
+		{"..", []string{"", "", ""}},
+		{"a.b", nil},
+		{"a.b.c.d", nil},
+		{"abc", nil},
+	}
+
+	for i, tt := range tests {
+		header, payload, signature, ok := splitToken([]byte(tt.token))
+		if ok != (tt.parts != nil) {
+			t.Fatalf("[%d] expected ok: %v but got: %v", i, tt.parts != nil, ok)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if got := []string{string(header), string(payload), string(signature)}; !reflect.DeepEqual(got, tt.parts) {
+			t.Fatalf("[%d] expected parts: %q but got: %q", i, tt.parts, got)
+		}
+
+		// The parts can't be appended to in place.
+		if cap(header) != len(header) || cap(payload) != len(payload) {
+			t.Fatalf("[%d] expected capped parts", i)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package jwt
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // TokenPair holds the access token and refresh token response.
 type TokenPair struct {
@@ -18,6 +21,41 @@ func NewTokenPair(accessToken, refreshToken []byte) TokenPair {
 	}
 }
 
+// SignPair signs an access token and a refresh token of the same "claims"
+// with independent lifetimes, "accessMaxAge" and "refreshMaxAge" respectively.
+// The "opts" are applied to both tokens, their MaxAge is replaced by the given lifetimes.
+// Verify the refresh tokens with a different Verifier (e.g. of a different key or audience)
+// so a refresh token is never accepted as an access token, see `Issuer.TokenPair` too.
+//
+// Usage:
+//  tokenPair, err := jwt.SignPair(jwt.HS256, secret, userClaims, 15*time.Minute, 24*time.Hour)
+//  json.NewEncoder(w).Encode(tokenPair)
+func SignPair(alg AlgSigner, key PrivateKey, claims interface{}, accessMaxAge, refreshMaxAge time.Duration, opts ...SignOption) (TokenPair, error) {
+	sign := func(maxAge time.Duration) ([]byte, error) {
+		tokenOpts := make([]SignOption, 0, len(opts)+1)
+		tokenOpts = append(tokenOpts, opts...)
+		return Sign(alg, key, claims, append(tokenOpts, MaxAge(maxAge))...)
+	}
+
+	accessToken, err := sign(accessMaxAge)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refreshToken, err := sign(refreshMaxAge)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return NewTokenPair(accessToken, refreshToken), nil
+}
+
+// Tokens returns the raw (unquoted) access and refresh tokens of the pair,
+// e.g. of a pair which was read from a client request.
+func (tp TokenPair) Tokens() (accessToken, refreshToken []byte) {
+	return BytesUnquote(tp.AccessToken), BytesUnquote(tp.RefreshToken)
+}
+
 // BytesQuote returns a double-quoted []byte slice representing "b".
 func BytesQuote(b []byte) []byte {
 	dst := make([]byte, len(b)+2)
@@ -26,3 +64,12 @@ func BytesQuote(b []byte) []byte {
 	dst[len(dst)-1] = '"'
 	return dst
 }
+
+// BytesUnquote returns "b" without its surrounding double quotes, if any.
+func BytesUnquote(b []byte) []byte {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		return b[1 : len(b)-1]
+	}
+
+	return b
+}
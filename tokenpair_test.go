@@ -42,3 +42,37 @@ func TestTokenPair(t *testing.T) {
 		t.Fatalf("expected token pairs to be matched, expected:\n%#+v\n\nbut got:\n%#+v", tokenPair, tokPair)
 	}
 }
+
+func TestSignPair(t *testing.T) {
+	tokenPair, err := SignPair(testAlg, testSecret, Map{"foo": "bar"}, 10*time.Minute, time.Hour, Claims{Subject: "kataras", Expiry: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken, refreshToken := tokenPair.Tokens()
+	for token, lifetime := range map[string]int64{string(accessToken): 600, string(refreshToken): 3600} {
+		verifiedToken, err := Verify(testAlg, testSecret, []byte(token))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if verifiedToken.StandardClaims.Subject != "kataras" {
+			t.Fatalf("expected the subject of the sign options but got: %#+v", verifiedToken.StandardClaims)
+		}
+
+		if got := verifiedToken.StandardClaims.Expiry - verifiedToken.StandardClaims.IssuedAt; got != lifetime {
+			t.Fatalf("expected lifetime: %d but got: %d", lifetime, got)
+		}
+	}
+}
+
+func TestBytesUnquote(t *testing.T) {
+	b := []byte("eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9")
+	if got := BytesUnquote(BytesQuote(b)); string(got) != string(b) {
+		t.Fatalf("expected %s but got %s", b, got)
+	}
+
+	if got := BytesUnquote(b); string(got) != string(b) {
+		t.Fatalf("expected an unquoted value as it's but got %s", got)
+	}
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Common token size budgets, see `MaxTokenSize`.
+const (
+	// CookieSizeBudget is the size limit of a cookie of most browsers, including its name and attributes.
+	CookieSizeBudget = 4096
+	// HeaderSizeBudget is the default size limit of a request header of most proxies and servers.
+	HeaderSizeBudget = 8192
+)
+
+// ErrTokenSize indicates a token which exceeds the size budget of the `MaxTokenSize` sign option,
+// the returned error is a type of *TokenSizeError.
+var ErrTokenSize = errors.New("token exceeds the size budget")
+
+// ClaimSize is the (base64-encoded) size of a top-level claim of a token's payload.
+type ClaimSize struct {
+	Name string
+	Size int
+}
+
+// TokenSizeError is the error of a token which exceeds the size budget, see `MaxTokenSize`.
+// Use errors.As to inspect the claims which contribute most to the token's size.
+type TokenSizeError struct {
+	Size   int // The token's size in bytes.
+	Budget int // The size budget in bytes.
+	// Claims are the sizes of the payload's top-level claims, the largest first.
+	// It's empty if the payload was encrypted or it's not a JSON object.
+	Claims []ClaimSize
+}
+
+// Error completes the error interface, it lists the three largest claims.
+func (e *TokenSizeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %d > %d bytes", ErrTokenSize, e.Size, e.Budget)
+
+	for i, claim := range e.Claims {
+		if i == 3 {
+			break
+		}
+
+		if i == 0 {
+			b.WriteString(" (largest claims: ")
+		} else {
+			b.WriteString(", ")
+		}
+
+		fmt.Fprintf(&b, "%s %d", claim.Name, claim.Size)
+	}
+
+	if len(e.Claims) > 0 {
+		b.WriteByte(')')
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the ErrTokenSize, so errors.Is(err, ErrTokenSize) reports true.
+func (e *TokenSizeError) Unwrap() error {
+	return ErrTokenSize
+}
+
+// tokenSizeBudget is the SignOption of MaxTokenSize.
+type tokenSizeBudget int
+
+// ApplyClaims completes the SignOption interface, it does not set any claim.
+func (tokenSizeBudget) ApplyClaims(*Claims) {}
+
+// MaxTokenSize is a SignOption which rejects the signing of a token larger than "size" bytes,
+// e.g. the `CookieSizeBudget` or the `HeaderSizeBudget`, so an oversized token fails
+// at the issuer instead of being silently dropped (or truncated) by a browser or a proxy.
+// The returned error is a type of *TokenSizeError, which reports the claims that contribute most.
+// See the `Issuer.MaxTokenSize` field too.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.HS256, sharedKey, claims, jwt.MaxAge(15*time.Minute), jwt.MaxTokenSize(jwt.CookieSizeBudget))
+//  var sizeErr *jwt.TokenSizeError
+//  if errors.As(err, &sizeErr) {
+//    log.Printf("token of %d bytes, largest claim: %s", sizeErr.Size, sizeErr.Claims[0].Name)
+//  }
+func MaxTokenSize(size int) SignOption {
+	return tokenSizeBudget(size)
+}
+
+// tokenSizeOption returns the size budget of the sign options (zero if none) and the rest of the options.
+func tokenSizeOption(opts []SignOption) (int, []SignOption) {
+	var (
+		maxSize int
+		rest    []SignOption
+	)
+
+	for i, opt := range opts {
+		budget, ok := opt.(tokenSizeBudget)
+		if !ok {
+			if rest != nil {
+				rest = append(rest, opt)
+			}
+			continue
+		}
+
+		if rest == nil {
+			rest = append(make([]SignOption, 0, len(opts)-1), opts[:i]...)
+		}
+		maxSize = int(budget)
+	}
+
+	if rest == nil {
+		return 0, opts
+	}
+
+	return maxSize, rest
+}
+
+func newTokenSizeError(size, budget int, payload []byte) *TokenSizeError {
+	return &TokenSizeError{Size: size, Budget: budget, Claims: claimSizes(payload)}
+}
+
+// claimSizes returns the sizes of the top-level claims of the "payload", the largest first,
+// or nil if it's not a JSON object.
+func claimSizes(payload []byte) []ClaimSize {
+	var claims map[string]json.RawMessage
+	if json.Unmarshal(payload, &claims) != nil {
+		return nil
+	}
+
+	sizes := make([]ClaimSize, 0, len(claims))
+	for name, value := range claims {
+		encoded, _ := json.Marshal(name)
+		// "name":value, (base64-encoded).
+		n := base64.RawURLEncoding.EncodedLen(len(encoded) + len(value) + 2)
+		sizes = append(sizes, ClaimSize{Name: name, Size: n})
+	}
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Size == sizes[j].Size {
+			return sizes[i].Name < sizes[j].Name
+		}
+		return sizes[i].Size > sizes[j].Size
+	})
+
+	return sizes
+}
@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxTokenSize(t *testing.T) {
+	claims := Map{"sub": "kataras", "roles": strings.Repeat("r", 300), "perms": strings.Repeat("p", 200)}
+
+	token, err := Sign(testAlg, testSecret, claims, MaxAge(time.Minute), MaxTokenSize(HeaderSizeBudget))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 {
+		t.Fatalf("expected the standard claims of the rest of the sign options")
+	}
+
+	_, err = Sign(testAlg, testSecret, claims, MaxTokenSize(len(token)-1), MaxAge(time.Minute))
+	if !errors.Is(err, ErrTokenSize) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSize, err)
+	}
+
+	var sizeErr *TokenSizeError
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("expected a TokenSizeError but got: %T", err)
+	}
+
+	if sizeErr.Size != len(token) || sizeErr.Budget != len(token)-1 || len(sizeErr.Claims) != 5 {
+		t.Fatalf("unexpected error: %#+v", sizeErr)
+	}
+
+	if sizeErr.Claims[0].Name != "roles" || sizeErr.Claims[1].Name != "perms" {
+		t.Fatalf("expected the largest claims first but got: %v", sizeErr.Claims)
+	}
+
+	if expected := "(largest claims: roles 415, perms 282, exp 23)"; !strings.HasSuffix(err.Error(), expected) {
+		t.Fatalf("expected error message to end with: %s but got: %s", expected, err.Error())
+	}
+}
+
+func TestIssuerMaxTokenSize(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.MaxTokenSize = 200
+
+	if _, err := issuer.Token("kataras", Map{"roles": strings.Repeat("r", 100)}); !errors.Is(err, ErrTokenSize) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSize, err)
+	}
+
+	if _, err := issuer.Token("kataras", nil); err != nil {
+		t.Fatal(err)
+	}
+}
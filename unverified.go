@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// UnverifiedToken holds the decoded parts of a token which is NOT verified,
+// see `Decode`. Its header and claims can't be trusted, they may only be used
+// to select how the token is verified, e.g. its "kid" header or a tenant claim.
+type UnverifiedToken struct {
+	Token     []byte // The original token.
+	Header    []byte // The header (decoded) part.
+	Payload   []byte // The payload (decoded) part.
+	Signature []byte // The signature (decoded) part.
+	// UnverifiedClaims are the standard claims of the payload, not validated.
+	// They're zero if the payload is not a JSON object, e.g. an encrypted payload.
+	UnverifiedClaims Claims
+
+	header tokenHeader
+}
+
+// Decode base64-decodes the header and the payload of the compact "token"
+// and parses the header and the standard claims, WITHOUT verifying the signature.
+// Returns ErrMissing on an empty token and ErrTokenForm on malformed ones.
+// Its `Verify` method verifies the token.
+//
+// Usage:
+//  unverifiedToken, err := jwt.Decode(token)
+//  [handle error...]
+//  key := keys[unverifiedToken.KeyID()]
+//  verifiedToken, err := unverifiedToken.Verify(jwt.EdDSA, key)
+func Decode(token []byte) (*UnverifiedToken, error) {
+	if len(token) == 0 {
+		return nil, ErrMissing
+	}
+
+	header, payload, signature, ok := splitToken(token)
+	if !ok {
+		return nil, ErrTokenForm
+	}
+
+	t := &UnverifiedToken{Token: token}
+
+	var err error
+	if t.Header, err = Base64Decode(header); err != nil {
+		return nil, ErrTokenForm
+	}
+
+	if err = json.Unmarshal(t.Header, &t.header); err != nil {
+		return nil, ErrTokenForm
+	}
+
+	if t.Payload, err = Base64Decode(payload); err != nil {
+		return nil, ErrTokenForm
+	}
+
+	if t.Signature, err = Base64Decode(signature); err != nil {
+		return nil, ErrTokenForm
+	}
+
+	_ = json.Unmarshal(t.Payload, &t.UnverifiedClaims) // e.g. encrypted.
+	return t, nil
+}
+
+// Alg returns the (unverified) "alg" header.
+func (t *UnverifiedToken) Alg() string {
+	return t.header.Alg
+}
+
+// KeyID returns the (unverified) "kid" header.
+func (t *UnverifiedToken) KeyID() string {
+	return t.header.Kid
+}
+
+// DecodeHeader decodes the (unverified) header to the "dest", e.g. for custom header fields.
+func (t *UnverifiedToken) DecodeHeader(dest interface{}) error {
+	return json.Unmarshal(t.Header, dest)
+}
+
+// DecodeClaims decodes the (unverified) payload to the "dest"
+// through the `Unmarshal` package-level function, e.g. to read a tenant claim.
+func (t *UnverifiedToken) DecodeClaims(dest interface{}) error {
+	return Unmarshal(t.Payload, dest)
+}
+
+// Verify verifies the token of "alg" algorithm and "key" public key, exactly like `Verify` does.
+// The Token is decoded again and a Header, Payload or Signature field
+// which was modified after `Decode` fails the verification with ErrTokenSignature.
+func (t *UnverifiedToken) Verify(alg AlgVerifier, key PublicKey, validators ...TokenValidator) (*VerifiedToken, error) {
+	return t.VerifyContext(context.Background(), alg, key, validators...)
+}
+
+// VerifyContext same as `Verify` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+func (t *UnverifiedToken) VerifyContext(ctx context.Context, alg AlgVerifier, key PublicKey, validators ...TokenValidator) (*VerifiedToken, error) {
+	report := verifyReport(ctx)
+	start := report.now()
+
+	verifiedToken, err := t.verify(ctx, alg, key, validators)
+	recordVerified(t.Token, verifiedToken, err)
+
+	if report != nil {
+		report.Token, report.Err, report.Duration = verifiedToken, err, time.Since(start)
+	}
+
+	return verifiedToken, err
+}
+
+func (t *UnverifiedToken) verify(ctx context.Context, alg AlgVerifier, key PublicKey, validators []TokenValidator) (*VerifiedToken, error) {
+	header, payload, signature, err := decodeTokenReport(alg, key, t.Token, verifyReport(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// The decoded parts are exported, they must still be the signed ones.
+	if !bytes.Equal(header, t.Header) || !bytes.Equal(payload, t.Payload) || !bytes.Equal(signature, t.Signature) {
+		return nil, ErrTokenSignature
+	}
+
+	claims, err := validatePayload(ctx, t.Token, payload, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifiedToken{
+		Token:          t.Token,
+		Header:         header,
+		Payload:        payload,
+		Signature:      signature,
+		StandardClaims: claims,
+	}, nil
+}
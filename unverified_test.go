@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	token, err := SignWithHeader(testAlg, testSecret, Header{{"alg", "HS256"}, {"typ", "JWT"}, {"kid", "key-1"}, {"tenant", "acme"}},
+		Map{"sub": "kataras", "tenant": "acme"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unverifiedToken, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if alg, kid := unverifiedToken.Alg(), unverifiedToken.KeyID(); alg != "HS256" || kid != "key-1" {
+		t.Fatalf("expected alg: HS256 and kid: key-1 but got: %s and %s", alg, kid)
+	}
+
+	if unverifiedToken.UnverifiedClaims.Subject != "kataras" {
+		t.Fatalf("expected the unverified subject but got: %#+v", unverifiedToken.UnverifiedClaims)
+	}
+
+	var (
+		header struct {
+			Tenant string `json:"tenant"`
+		}
+		claims struct {
+			Tenant string `json:"tenant"`
+		}
+	)
+	if err = unverifiedToken.DecodeHeader(&header); err != nil || header.Tenant != "acme" {
+		t.Fatalf("expected the tenant header but got: %#+v (%v)", header, err)
+	}
+
+	if err = unverifiedToken.DecodeClaims(&claims); err != nil || claims.Tenant != "acme" {
+		t.Fatalf("expected the tenant claim but got: %#+v (%v)", claims, err)
+	}
+
+	verifiedToken, err := unverifiedToken.Verify(testAlg, testSecret, Expected{Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(verifiedToken.Payload) != string(unverifiedToken.Payload) {
+		t.Fatalf("expected the same payload")
+	}
+
+	if _, err = unverifiedToken.VerifyContext(context.Background(), testAlg, []byte("other")); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	if _, err = unverifiedToken.Verify(HS512, testSecret); err != ErrTokenAlg {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+}
+
+func TestDecodeModified(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "admin": false}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unverifiedToken, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unverifiedToken.Payload = bytes.Replace(unverifiedToken.Payload, []byte(`"admin":false`), []byte(`"admin":true`), 1)
+	if _, err = unverifiedToken.Verify(testAlg, testSecret); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	if unverifiedToken, err = Decode(token); err != nil {
+		t.Fatal(err)
+	}
+
+	unverifiedToken.Token = unverifiedToken.Token[:len(unverifiedToken.Token)/2]
+	if _, err = unverifiedToken.Verify(testAlg, testSecret); err != ErrTokenForm {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	if _, err := Decode(nil); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+
+	for _, token := range []string{"a.b", "a.b.c.d", "!.e30.c", "e30.!.c", "bm90IGpzb24.e30.c"} {
+		if _, err := Decode([]byte(token)); err != ErrTokenForm {
+			t.Fatalf("[%s] expected error: %v but got: %v", token, ErrTokenForm, err)
+		}
+	}
+}
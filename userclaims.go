@@ -0,0 +1,85 @@
+package jwt
+
+import "time"
+
+// StandardUserClaims holds the OpenID Connect standard claims about the end-user
+// (OpenID Connect Core 1.0 section 5.1). It can be embedded alongside the `Claims`
+// to decode ID tokens and userinfo responses or to sign tokens with user information.
+//
+// Usage:
+//  type IDTokenClaims struct {
+//    jwt.Claims
+//    jwt.StandardUserClaims
+//  }
+//
+//  var claims IDTokenClaims
+//  err := verifiedToken.Claims(&claims)
+type StandardUserClaims struct {
+	// Name is the end-user's full name in displayable form, including all name parts.
+	Name string `json:"name,omitempty"`
+	// GivenName is the given name(s) or first name(s) of the end-user.
+	GivenName string `json:"given_name,omitempty"`
+	// FamilyName is the surname(s) or last name(s) of the end-user.
+	FamilyName string `json:"family_name,omitempty"`
+	// MiddleName is the middle name(s) of the end-user.
+	MiddleName string `json:"middle_name,omitempty"`
+	// Nickname is the casual name of the end-user.
+	Nickname string `json:"nickname,omitempty"`
+	// PreferredUsername is the shorthand name by which the end-user wishes to be referred to.
+	// It's not guaranteed to be unique, do not use it as an identifier (use the "sub" claim instead).
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	// Profile is the URL of the end-user's profile page.
+	Profile string `json:"profile,omitempty"`
+	// Picture is the URL of the end-user's profile picture.
+	Picture string `json:"picture,omitempty"`
+	// Website is the URL of the end-user's web page or blog.
+	Website string `json:"website,omitempty"`
+	// Email is the end-user's preferred e-mail address.
+	Email string `json:"email,omitempty"`
+	// EmailVerified reports whether the end-user's e-mail address has been verified.
+	EmailVerified bool `json:"email_verified,omitempty"`
+	// Gender is the end-user's gender, e.g. "female" or "male".
+	Gender string `json:"gender,omitempty"`
+	// Birthdate is the end-user's birthday, in the ISO 8601 YYYY-MM-DD (or YYYY) format.
+	Birthdate string `json:"birthdate,omitempty"`
+	// Zoneinfo is the end-user's time zone, e.g. "Europe/Athens".
+	Zoneinfo string `json:"zoneinfo,omitempty"`
+	// Locale is the end-user's locale, a BCP47 language tag, e.g. "en-US".
+	Locale string `json:"locale,omitempty"`
+	// PhoneNumber is the end-user's preferred telephone number, e.g. "+1 (425) 555-1212".
+	PhoneNumber string `json:"phone_number,omitempty"`
+	// PhoneNumberVerified reports whether the end-user's phone number has been verified.
+	PhoneNumberVerified bool `json:"phone_number_verified,omitempty"`
+	// Address is the end-user's preferred postal address.
+	Address *AddressClaim `json:"address,omitempty"`
+	// UpdatedAt is the time the end-user's information was last updated,
+	// in seconds since epoch. See the `UpdatedTime` method.
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+}
+
+// UpdatedTime returns the "updated_at" claim as time
+// or the zero time if it's missing.
+func (c StandardUserClaims) UpdatedTime() time.Time {
+	if c.UpdatedAt <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(c.UpdatedAt, 0)
+}
+
+// AddressClaim is the OpenID Connect "address" claim (OpenID Connect Core 1.0 section 5.1.1).
+type AddressClaim struct {
+	// Formatted is the full mailing address, formatted for display.
+	Formatted string `json:"formatted,omitempty"`
+	// StreetAddress is the full street address component,
+	// which may include the house number, the street name and multiple lines.
+	StreetAddress string `json:"street_address,omitempty"`
+	// Locality is the city or locality component.
+	Locality string `json:"locality,omitempty"`
+	// Region is the state, province, prefecture or region component.
+	Region string `json:"region,omitempty"`
+	// PostalCode is the zip code or postal code component.
+	PostalCode string `json:"postal_code,omitempty"`
+	// Country is the country name component.
+	Country string `json:"country,omitempty"`
+}
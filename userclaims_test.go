@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStandardUserClaims(t *testing.T) {
+	type idTokenClaims struct {
+		Claims
+		StandardUserClaims
+	}
+
+	expected := idTokenClaims{
+		Claims: Claims{Issuer: "https://idp.example.com", Subject: "248289761001"},
+		StandardUserClaims: StandardUserClaims{
+			Name:          "Jane Doe",
+			GivenName:     "Jane",
+			FamilyName:    "Doe",
+			Email:         "janedoe@example.com",
+			EmailVerified: true,
+			PhoneNumber:   "+1 (425) 555-1212",
+			Address:       &AddressClaim{Locality: "Anytown", Country: "US"},
+			Locale:        "en-US",
+			UpdatedAt:     1311280970,
+		},
+	}
+
+	token, err := Sign(testAlg, testSecret, expected, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, Expected{Subject: "248289761001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got idTokenClaims
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected.Claims = verifiedToken.StandardClaims
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected claims:\n%#+v\nbut got:\n%#+v", expected, got)
+	}
+
+	if expected := time.Unix(1311280970, 0); !got.UpdatedTime().Equal(expected) {
+		t.Fatalf("expected updated time: %s but got: %s", expected, got.UpdatedTime())
+	}
+
+	if !(StandardUserClaims{}).UpdatedTime().IsZero() {
+		t.Fatalf("expected zero updated time")
+	}
+}
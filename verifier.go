@@ -0,0 +1,276 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Verifier holds the configuration to verify tokens for a specific service.
+// It's constructed once, on the initialization of the program,
+// so call sites do not have to pass the algorithm, the key and the validators
+// on every `Verify` call. A Verifier is safe for concurrent use,
+// its fields should not be modified after its first use.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.HS256, sharedKey, jwt.Expected{Issuer: "myapp"})
+//  verifier.Blocklist = jwt.NewBlocklist(15 * time.Minute)
+//
+//  verifiedToken, err := verifier.VerifyRequest(r)
+type Verifier struct {
+	// Alg is the algorithm the tokens were signed with, required.
+	Alg AlgVerifier
+	// Key is the public key (or the shared secret) of the algorithm, required.
+	Key PublicKey
+	// KeyProvider is an optional source of the public keys, selected by the token's "kid" header,
+	// when it's set the Key field is ignored, see `StaticKey` and `JWKSClient`.
+	KeyProvider KeyProvider
+	// Decrypt is an optional function to decrypt the payload, see `VerifyEncrypted`.
+	Decrypt InjectFunc
+
+	// Validators is a list of token validators which run on every verification.
+	Validators []TokenValidator
+	// Leeway, if greater than zero, disallows tokens which are going to be expired
+	// in less than that duration from now, see the `Leeway` package-level function.
+	Leeway time.Duration
+	// Tolerance, if greater than zero, is the clock skew tolerance
+	// of the time claims validation, see the `Tolerance` package-level function.
+	Tolerance time.Duration
+	// ExpiryGrace, if greater than zero, accepts the tokens which expired less than that duration ago
+	// on the GET and HEAD requests only, see the `ExpiryGrace` package-level function.
+	ExpiryGrace time.Duration
+	// Blocklist is an optional validator of invalidated tokens, e.g. the in-memory `Blocklist`.
+	Blocklist TokenValidator
+	// PreVerify is an optional check of the unverified header and claims of the tokens,
+	// which runs before the key lookup and the signature verification,
+	// e.g. `AllowIssuers` and `DenyKeyIDs`, see `PreVerifyFunc`.
+	PreVerify PreVerifyFunc
+	// NegativeCache is an optional cache of the tokens of an invalid signature,
+	// so the replays of a forged token are rejected without verifying its signature again.
+	NegativeCache *NegativeCache
+
+	// Extractors is a list of functions which extract the token from an HTTP request,
+	// the first non-empty result is used. Defaults to the `FromHeader` one.
+	Extractors []TokenExtractor
+	// Sources is a list of named token locations (or schemes), tried in priority order.
+	// The name of the matched one is reported through the `GetTokenSource` function.
+	// When it's not empty, the Extractors field is ignored.
+	Sources []TokenSource
+	// MessageExtractors is a list of functions which extract the token from the headers of a message,
+	// e.g. a Kafka record, see `VerifyMessage`. Defaults to the FromMessageHeader("Authorization") one.
+	MessageExtractors []MessageExtractor
+	// SessionResolver is an optional fallback for credentials which are not well-formed JWTs,
+	// e.g. server-side session ids, useful on incremental migrations from sessions to JWTs.
+	// The resolved VerifiedToken's Header and Signature fields are nil.
+	SessionResolver SessionResolver
+	// Migrations is an optional set of claims upgraders which run after a successful verification,
+	// see the `Migrations` type.
+	Migrations *Migrations
+	// Abbreviations is an optional table of the short names of the custom claims,
+	// which are restored before any validation, see `ClaimAbbreviations` and the `Issuer.Abbreviations` field.
+	Abbreviations ClaimAbbreviations
+	// ErrorHandler renders the verification errors of the HTTP middleware (see `Handler`).
+	// Defaults to the `DefaultErrorHandler`.
+	ErrorHandler ErrorHandler
+	// SuccessHandler is an optional hook of the HTTP middleware which runs
+	// after a successful verification and before the next handler.
+	SuccessHandler SuccessHandler
+	// RecoverPanics, if true, converts a panic of the validators, the PreVerify, the SessionResolver
+	// and the Migrations' upgraders to a verification error of ErrPanic,
+	// so a buggy custom validator can't crash the program, see `Recover` and `OnPanic`.
+	RecoverPanics bool
+}
+
+// NewVerifier returns a new token Verifier of "alg" algorithm and "key" public key.
+// The "validators" run on every verification.
+// The rest of the Verifier fields can be modified before its first use.
+func NewVerifier(alg AlgVerifier, key PublicKey, validators ...TokenValidator) *Verifier {
+	return &Verifier{
+		Alg:        alg,
+		Key:        key,
+		Validators: validators,
+		Extractors: []TokenExtractor{FromHeader},
+	}
+}
+
+// VerifyToken verifies the given "token" based on the Verifier's configuration.
+// The optional "validators" run after the Verifier's ones.
+func (v *Verifier) VerifyToken(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return v.VerifyTokenContext(context.Background(), token, validators...)
+}
+
+// VerifyTokenContext same as `VerifyToken` but it accepts a standard Go Context
+// which is passed to any `ContextValidator`.
+// If the "token" is not a well-formed JWT and the SessionResolver is set, it resolves the session instead.
+// The claims of the verified token are upgraded through the Verifier's Migrations, if any.
+func (v *Verifier) VerifyTokenContext(ctx context.Context, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	verifiedToken, err := v.verifyTokenContext(ctx, token, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Migrations != nil {
+		if err = v.migrate(verifiedToken); err != nil {
+			return nil, err
+		}
+	}
+
+	return verifiedToken, nil
+}
+
+func (v *Verifier) verifyTokenContext(ctx context.Context, token []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	if v.SessionResolver != nil && len(token) > 0 && !isCompactToken(token) {
+		resolver := v.SessionResolver
+		if v.RecoverPanics {
+			resolver = recoverSessionResolver(resolver)
+		}
+
+		verifiedToken, err := resolveSession(ctx, resolver, token, v.validators(validators))
+		recordVerified(token, verifiedToken, err)
+		if err == nil {
+			reportSignal(MigrationSignal{Kind: SignalSession, Issuer: verifiedToken.StandardClaims.Issuer})
+		}
+
+		return verifiedToken, err
+	}
+
+	if v.NegativeCache != nil && v.NegativeCache.Rejected(token) {
+		report := verifyReport(ctx)
+		report.add("negative_cache", report.now(), ErrTokenSignature)
+		if report != nil {
+			report.Err = ErrTokenSignature
+		}
+
+		recordVerification(token, ErrTokenSignature)
+		return nil, ErrTokenSignature
+	}
+
+	if v.PreVerify != nil {
+		if err := v.preVerify(ctx, token); err != nil {
+			recordVerification(token, err)
+			return nil, err
+		}
+	}
+
+	key, err := v.publicKey(ctx, token)
+	if err != nil {
+		recordVerification(token, err)
+		return nil, err
+	}
+
+	decrypt := v.Decrypt
+	if len(v.Abbreviations) > 0 {
+		decrypt = v.Abbreviations.expandPayload(decrypt)
+	}
+
+	validators = append(v.validators(validators), PayloadValidatorFunc(validateTokenUse))
+	verifiedToken, err := VerifyEncryptedContext(ctx, v.Alg, key, decrypt, token, validators...)
+	if v.NegativeCache != nil && errors.Is(err, ErrTokenSignature) {
+		v.NegativeCache.Add(token)
+	}
+
+	if err == nil && v.KeyProvider != nil {
+		v.signalRetiredKey(verifiedToken)
+	}
+
+	return verifiedToken, err
+}
+
+// VerifyRequest extracts the token from the HTTP request through the Verifier's Extractors
+// and verifies it. The request's Context, carrying the client information (see `ClientInfoFromRequest`),
+// is passed to the validators. Returns ErrMissing if the request does not carry a token.
+func (v *Verifier) VerifyRequest(r *http.Request, validators ...TokenValidator) (*VerifiedToken, error) {
+	token, _ := v.RequestTokenSource(r)
+	return v.verifyRequest(r, token, validators)
+}
+
+func (v *Verifier) verifyRequest(r *http.Request, token []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	ctx := WithClientInfo(r.Context(), ClientInfoFromRequest(r))
+	return v.VerifyTokenContext(ctx, token, validators...)
+}
+
+// RequestToken returns the token of the HTTP request based on the Verifier's Sources or Extractors
+// or nil if the request does not carry any.
+func (v *Verifier) RequestToken(r *http.Request) []byte {
+	token, _ := v.RequestTokenSource(r)
+	return token
+}
+
+// RequestTokenSource same as `RequestToken` but it reports the name of the matched source too,
+// the name is empty when the Sources field is empty.
+func (v *Verifier) RequestTokenSource(r *http.Request) ([]byte, string) {
+	if len(v.Sources) > 0 {
+		for _, source := range v.Sources {
+			if token := source.Extract(r); token != "" {
+				return []byte(token), source.Name
+			}
+		}
+
+		return nil, ""
+	}
+
+	extractors := v.Extractors
+	if len(extractors) == 0 {
+		extractors = []TokenExtractor{FromHeader}
+	}
+
+	for _, extract := range extractors {
+		if token := extract(r); token != "" {
+			return []byte(token), ""
+		}
+	}
+
+	return nil, ""
+}
+
+// publicKey returns the verification key of the "token",
+// through the KeyProvider if it's set, otherwise the Key field.
+func (v *Verifier) publicKey(ctx context.Context, token []byte) (PublicKey, error) {
+	if v.KeyProvider == nil || len(token) == 0 {
+		return v.Key, nil
+	}
+
+	kid, err := tokenKeyID(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.KeyProvider.PublicKey(ctx, kid)
+}
+
+func (v *Verifier) validators(extra []TokenValidator) []TokenValidator {
+	validators := make([]TokenValidator, 0, len(v.Validators)+len(extra)+4)
+	if v.Tolerance > 0 {
+		validators = append(validators, Tolerance(v.Tolerance))
+	}
+
+	if v.ExpiryGrace > 0 {
+		validators = append(validators, ExpiryGrace(v.ExpiryGrace))
+	}
+
+	if v.Blocklist != nil {
+		validators = append(validators, v.Blocklist)
+	}
+
+	if v.Leeway > 0 {
+		validators = append(validators, Leeway(v.Leeway))
+	}
+
+	validators = append(validators, v.Validators...)
+	validators = append(validators, extra...)
+	if v.RecoverPanics {
+		return recoverValidators(validators)
+	}
+
+	return validators
+}
+
+// migrate upgrades the claims of the "verifiedToken" through the Verifier's Migrations.
+func (v *Verifier) migrate(verifiedToken *VerifiedToken) (err error) {
+	if v.RecoverPanics {
+		defer recoverPanic(&err)
+	}
+
+	return v.Migrations.Migrate(verifiedToken)
+}
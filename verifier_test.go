@@ -0,0 +1,139 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifier(t *testing.T) {
+	issuer := NewIssuer(testAlg, testSecret, time.Minute)
+	issuer.Issuer = "myapp"
+
+	token, err := issuer.Token("kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocklist := NewBlocklist(0)
+	verifier := NewVerifier(testAlg, testSecret, Expected{Issuer: "myapp"})
+	verifier.Blocklist = blocklist
+
+	verifiedToken, err := verifier.VerifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.StandardClaims.Subject != "kataras" {
+		t.Fatalf("unexpected standard claims: %#+v", verifiedToken.StandardClaims)
+	}
+
+	// Test extra validators.
+	if _, err = verifier.VerifyToken(token, Expected{Subject: "other"}); !errors.Is(err, ErrExpected) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpected, err)
+	}
+
+	// Test leeway.
+	verifier.Leeway = 2 * time.Minute
+	if _, err = verifier.VerifyToken(token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+	verifier.Leeway = 0
+
+	// Test blocklist.
+	if err = blocklist.InvalidateToken(token, verifiedToken.StandardClaims); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.VerifyToken(token); err != ErrBlocked {
+		t.Fatalf("expected error: %v but got: %v", ErrBlocked, err)
+	}
+}
+
+func TestVerifierRequest(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(testAlg, testSecret)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err = verifier.VerifyRequest(r); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+
+	r.Header.Set("Authorization", "Bearer "+string(token))
+	if _, err = verifier.VerifyRequest(r); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier.Extractors = []TokenExtractor{FromQuery("token"), FromCookie("jwt")}
+
+	r = httptest.NewRequest(http.MethodGet, "/?token="+string(token), nil)
+	if _, err = verifier.VerifyRequest(r); err != nil {
+		t.Fatal(err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "jwt", Value: string(token)})
+	if _, err = verifier.VerifyRequest(r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	tests := []struct {
+		authorization string
+		expected      string
+	}{
+		{"", ""},
+		{"Bearer token", "token"},
+		{"bearer  token ", "token"},
+		{"Bearer ", ""},
+		{"Basic dXNlcjpwYXNz", ""},
+	}
+
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", tt.authorization)
+
+		if got := FromHeader(r); got != tt.expected {
+			t.Fatalf("[%d] expected token: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestFromCustomHeader(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"token", "token"},
+		{"Bearer token", "token"},
+		{"Basic dXNlcjpwYXNz", ""},
+	}
+
+	extract := FromCustomHeader("X-Access-Token")
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Access-Token", tt.value)
+
+		if got := extract(r); got != tt.expected {
+			t.Fatalf("[%d] expected token: %q but got: %q", i, tt.expected, got)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "JWT token")
+	if got := FromAuthorization("jwt")(r); got != "token" {
+		t.Fatalf("expected token: %q but got: %q", "token", got)
+	}
+
+	if got := FromHeader(r); got != "" {
+		t.Fatalf("expected empty token of a different scheme but got: %q", got)
+	}
+}
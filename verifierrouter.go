@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrNoVerifier indicates a token of an issuer and audience which no route
+// of a `VerifierRouter` matches, when the router has no Default Verifier.
+var ErrNoVerifier = errors.New("no verifier for the token's issuer and audience")
+
+// VerifierRoute selects the Verifier of the tokens of an issuer and (or) an audience,
+// see the `VerifierRouter`.
+type VerifierRoute struct {
+	// Issuer matches the tokens of that "iss" claim, empty matches any issuer.
+	// The issuers are compared by their canonical forms, see `NormalizeIssuer`.
+	Issuer string
+	// Audience matches the tokens of an "aud" claim which contains it, empty matches any audience.
+	Audience string
+	// Verifier verifies the matched tokens, with its own keys and policies, required.
+	Verifier *Verifier
+}
+
+// VerifierRouter selects the Verifier of a token by its "iss" and "aud" claims,
+// so tokens of different issuers or audiences (e.g. "internal-api" and "public-api")
+// are verified by different key sets and policies.
+// The routes are matched in order, the first match verifies the token.
+// The claims are read before the verification: a route only selects the Verifier,
+// a forged claim selects a Verifier which rejects the token's signature.
+//
+// Usage:
+//  router := jwt.NewVerifierRouter(
+//    jwt.VerifierRoute{Audience: "internal-api", Verifier: internalVerifier},
+//    jwt.VerifierRoute{Issuer: "https://idp.example.com", Audience: "public-api", Verifier: publicVerifier},
+//  )
+//  http.Handle("/", router.Handler(apiHandler))
+type VerifierRouter struct {
+	// Routes are the routes of the router, in order.
+	Routes []VerifierRoute
+	// Default is the optional Verifier of the tokens which no route matches
+	// and of the requests without a token.
+	Default *Verifier
+	// Extractors are the token extractors of the router's `Handler`,
+	// the selected Verifier extracts the token again through its own ones.
+	// Defaults to the `FromHeader`.
+	Extractors []TokenExtractor
+	// ErrorHandler renders the routing errors of the `Handler`,
+	// e.g. ErrNoVerifier. Defaults to the `DefaultErrorHandler`.
+	ErrorHandler ErrorHandler
+}
+
+// NewVerifierRouter returns a new VerifierRouter of the "routes".
+// The rest of the VerifierRouter fields can be modified before its first use.
+func NewVerifierRouter(routes ...VerifierRoute) *VerifierRouter {
+	return &VerifierRouter{
+		Routes:     routes,
+		Extractors: []TokenExtractor{FromHeader},
+	}
+}
+
+// Route returns the Verifier of the "token", the Verifier of the first matched route or the Default one.
+// Returns ErrNoVerifier if none matches and ErrTokenForm if the token can't be decoded.
+func (vr *VerifierRouter) Route(token []byte) (*Verifier, error) {
+	if len(token) == 0 {
+		if vr.Default != nil {
+			return vr.Default, nil
+		}
+
+		return nil, ErrMissing
+	}
+
+	t, err := Decode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := t.UnverifiedClaims
+	for _, route := range vr.Routes {
+		if route.Issuer != "" && !sameIssuer(route.Issuer, claims.Issuer) {
+			continue
+		}
+
+		if route.Audience != "" && !containsString(claims.Audience, route.Audience) {
+			continue
+		}
+
+		return route.Verifier, nil
+	}
+
+	if vr.Default != nil {
+		return vr.Default, nil
+	}
+
+	return nil, ErrNoVerifier
+}
+
+// VerifyToken verifies the "token" through its Verifier, see `Route`.
+func (vr *VerifierRouter) VerifyToken(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return vr.VerifyTokenContext(context.Background(), token, validators...)
+}
+
+// VerifyTokenContext same as `VerifyToken` but it accepts a standard Go Context
+// which is passed to the selected Verifier.
+func (vr *VerifierRouter) VerifyTokenContext(ctx context.Context, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	verifier, err := vr.Route(token)
+	if err != nil {
+		recordVerification(token, err)
+		return nil, err
+	}
+
+	return verifier.VerifyTokenContext(ctx, token, validators...)
+}
+
+// Handler returns an HTTP middleware which verifies the request's token through its Verifier's
+// `Handler`, so each Verifier's SuccessHandler and ErrorHandler apply to its own tokens.
+func (vr *VerifierRouter) Handler(next http.Handler) http.Handler {
+	handlers := make(map[*Verifier]http.Handler, len(vr.Routes)+1)
+	for _, route := range vr.Routes {
+		if _, ok := handlers[route.Verifier]; !ok {
+			handlers[route.Verifier] = route.Verifier.Handler(next)
+		}
+	}
+
+	if vr.Default != nil {
+		if _, ok := handlers[vr.Default]; !ok {
+			handlers[vr.Default] = vr.Default.Handler(next)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := vr.requestToken(r)
+		verifier, err := vr.Route(token)
+		if err != nil {
+			recordVerification(token, err)
+			if vr.ErrorHandler != nil {
+				vr.ErrorHandler(w, r, err)
+			} else {
+				DefaultErrorHandler(w, r, err)
+			}
+			return
+		}
+
+		handlers[verifier].ServeHTTP(w, r)
+	})
+}
+
+func (vr *VerifierRouter) requestToken(r *http.Request) []byte {
+	extractors := vr.Extractors
+	if len(extractors) == 0 {
+		extractors = []TokenExtractor{FromHeader}
+	}
+
+	for _, extract := range extractors {
+		if token := extract(r); token != "" {
+			return []byte(token)
+		}
+	}
+
+	return nil
+}
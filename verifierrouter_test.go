@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifierRouter(t *testing.T) {
+	internalSecret := []byte("internal secret key of 32 bytes.")
+	internal := NewVerifier(testAlg, internalSecret)
+	public := NewVerifier(testAlg, testSecret, Expected{Issuer: "idp"})
+
+	router := NewVerifierRouter(
+		VerifierRoute{Audience: "internal-api", Verifier: internal},
+		VerifierRoute{Issuer: "idp", Audience: "public-api", Verifier: public},
+	)
+
+	sign := func(key []byte, claims Map) []byte {
+		token, err := Sign(testAlg, key, claims, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		token []byte
+		err   error
+	}{
+		{sign(internalSecret, Map{"aud": []string{"internal-api", "other"}}), nil},
+		{sign(testSecret, Map{"iss": "idp", "aud": "public-api"}), nil},
+		// Forged audience: the internal verifier rejects the signature.
+		{sign(testSecret, Map{"iss": "idp", "aud": "internal-api"}), ErrTokenSignature},
+		{sign(testSecret, Map{"iss": "other", "aud": "public-api"}), ErrNoVerifier},
+		{sign(testSecret, Map{"iss": "idp"}), ErrNoVerifier},
+		{[]byte("not.a.token"), ErrTokenForm},
+		{nil, ErrMissing},
+	}
+
+	for i, tt := range tests {
+		if _, err := router.VerifyToken(tt.token); !errors.Is(err, tt.err) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.err, err)
+		}
+	}
+
+	router.Default = public
+	if verifier, err := router.Route(sign(testSecret, Map{"iss": "idp"})); err != nil || verifier != public {
+		t.Fatalf("expected the default verifier but got: %v", err)
+	}
+}
+
+func TestVerifierRouterHandler(t *testing.T) {
+	internalSecret := []byte("internal secret key of 32 bytes.")
+	internal := NewVerifier(testAlg, internalSecret)
+	internal.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	router := NewVerifierRouter(VerifierRoute{Audience: "internal-api", Verifier: internal})
+	handler := router.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetVerifiedToken(r.Context()); !ok {
+			t.Fatalf("expected a verified token")
+		}
+	}))
+
+	tests := []struct {
+		key        []byte
+		audience   string
+		statusCode int
+	}{
+		{internalSecret, "internal-api", http.StatusOK},
+		{testSecret, "internal-api", http.StatusTeapot}, // the route's ErrorHandler.
+		{internalSecret, "public-api", http.StatusUnauthorized},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, tt.key, Map{"aud": tt.audience}, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+string(token))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.statusCode {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.statusCode, w.Code)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package jwt
 
-import "encoding/json"
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
 
 // Verify decodes, verifies and validates the standard JWT claims
 // of the given "token" using the algorithm and
@@ -21,45 +26,63 @@ import "encoding/json"
 //  [handle error...]
 //  var claims map[string]interface{}
 //  verifiedToken.Claims(&claims)
-func Verify(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+func Verify(alg AlgVerifier, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
 	return VerifyEncrypted(alg, key, nil, token, validators...)
 }
 
+// VerifyContext same as `Verify` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+// The context may carry request-scoped information,
+// e.g. the client's IP address (see `WithClientInfo`).
+func VerifyContext(ctx context.Context, alg AlgVerifier, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyEncryptedContext(ctx, alg, key, nil, token, validators...)
+}
+
 // VerifyEncrypted same as `Verify` but it decrypts the payload part with the given "decrypt" function.
 // The "decrypt" function is called AFTER base64-decode and BEFORE Unmarshal.
 // Look the `GCM` function for details.
-func VerifyEncrypted(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+func VerifyEncrypted(alg AlgVerifier, key PublicKey, decrypt InjectFunc, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyEncryptedContext(context.Background(), alg, key, decrypt, token, validators...)
+}
+
+// VerifyEncryptedContext same as `VerifyEncrypted` but it accepts a standard Go Context
+// which is passed to any `ContextValidator` of the "validators".
+func VerifyEncryptedContext(ctx context.Context, alg AlgVerifier, key PublicKey, decrypt InjectFunc, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	report := verifyReport(ctx)
+	start := report.now()
+
+	verifiedToken, err := verifyToken(ctx, alg, key, decrypt, token, validators)
+	recordVerified(token, verifiedToken, err)
+
+	if report != nil {
+		report.Token, report.Err, report.Duration = verifiedToken, err, time.Since(start)
+	}
+
+	return verifiedToken, err
+}
+
+func verifyToken(ctx context.Context, alg AlgVerifier, key PublicKey, decrypt InjectFunc, token []byte, validators []TokenValidator) (*VerifiedToken, error) {
+	report := verifyReport(ctx)
 	if len(token) == 0 {
+		report.add("form", report.now(), ErrMissing)
 		return nil, ErrMissing
 	}
 
-	header, payload, signature, err := decodeToken(alg, key, token)
+	header, payload, signature, err := decodeTokenReport(alg, key, token, report)
 	if err != nil {
 		return nil, err
 	}
 
 	if decrypt != nil {
+		start := report.now()
 		payload, err = decrypt(payload)
+		report.add("decrypt", start, err)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	var claims Claims
-	err = json.Unmarshal(payload, &claims) // use the standard one instead of the custom, no need to support "required" feature here.
-	if err != nil {
-		return nil, err
-	}
-
-	err = validateClaims(Clock(), claims)
-	for _, validator := range validators {
-		// A token validator can skip the builtin validation and return a nil error,
-		// in that case the previous error is skipped.
-		if err = validator.ValidateToken(token, claims, err); err != nil {
-			break
-		}
-	}
-
+	claims, err := validatePayload(ctx, token, payload, validators)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +97,46 @@ func VerifyEncrypted(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, v
 	return verifiedTok, nil
 }
 
+// validatePayload decodes the standard claims of the (decoded) "payload",
+// validates them and runs the "validators".
+func validatePayload(ctx context.Context, token, payload []byte, validators []TokenValidator) (Claims, error) {
+	report := verifyReport(ctx)
+	start := report.now()
+
+	var claims Claims
+	err := unmarshalClaims(payload, &claims)
+	report.add("claims", start, err)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	normalizeTimestamps(&claims, validators)
+	if report != nil {
+		now := Clock().Round(time.Second).Unix()
+		for _, check := range timeClaimChecks {
+			start = time.Now()
+			report.add(check.name, start, check.validate(now, claims))
+		}
+	}
+
+	err = validateClaims(Clock(), claims)
+	for _, validator := range validators {
+		start = report.now()
+		// A token validator can skip the builtin validation and return a nil error,
+		// in that case the previous error is skipped.
+		err = validateToken(ctx, validator, token, payload, claims, err)
+		if report != nil {
+			report.add(fmt.Sprintf("%T", validator), start, err)
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	return claims, err
+}
+
 type (
 	// TokenValidator provides further token and claims validation.
 	TokenValidator interface {
@@ -93,6 +156,33 @@ type (
 
 	// TokenValidatorFunc is the interface-as-function shortcut for a TokenValidator.
 	TokenValidatorFunc func(token []byte, standardClaims Claims, err error) error
+
+	// ContextValidator is an optional TokenValidator extension.
+	// When a validator implements it, the `ValidateTokenContext` method
+	// is called instead of the `ValidateToken` one and it accepts
+	// the Context given on `VerifyContext` (or context.Background()).
+	// Useful for policies which depend on the request's state,
+	// e.g. bind a token to a range of client IP addresses (see `IPRange`).
+	ContextValidator interface {
+		TokenValidator
+		ValidateTokenContext(ctx context.Context, token []byte, standardClaims Claims, err error) error
+	}
+
+	// ContextValidatorFunc is the interface-as-function shortcut for a ContextValidator.
+	ContextValidatorFunc func(ctx context.Context, token []byte, standardClaims Claims, err error) error
+
+	// PayloadValidator is an optional TokenValidator extension.
+	// When a validator implements it, the `ValidatePayload` method
+	// is called instead of the `ValidateToken` (and `ValidateTokenContext`) one
+	// and it accepts the decoded (and decrypted, if necessary) payload too.
+	// Useful for validations of custom claims, see `Expect`.
+	PayloadValidator interface {
+		TokenValidator
+		ValidatePayload(ctx context.Context, token, payload []byte, standardClaims Claims, err error) error
+	}
+
+	// PayloadValidatorFunc is the interface-as-function shortcut for a PayloadValidator.
+	PayloadValidatorFunc func(ctx context.Context, token, payload []byte, standardClaims Claims, err error) error
 )
 
 // ValidateToken completes the ValidateToken interface.
@@ -101,6 +191,48 @@ func (fn TokenValidatorFunc) ValidateToken(token []byte, standardClaims Claims,
 	return fn(token, standardClaims, err)
 }
 
+// ValidateToken completes the TokenValidator interface.
+// It calls itself with a background context.
+func (fn ContextValidatorFunc) ValidateToken(token []byte, standardClaims Claims, err error) error {
+	return fn(context.Background(), token, standardClaims, err)
+}
+
+// ValidateTokenContext completes the ContextValidator interface.
+// It calls itself.
+func (fn ContextValidatorFunc) ValidateTokenContext(ctx context.Context, token []byte, standardClaims Claims, err error) error {
+	return fn(ctx, token, standardClaims, err)
+}
+
+// ValidateToken completes the TokenValidator interface.
+// It calls itself with a background context and the payload part
+// of the "token" (base64-decoded), note that it can't decrypt an encrypted payload.
+func (fn PayloadValidatorFunc) ValidateToken(token []byte, standardClaims Claims, err error) error {
+	var payload []byte
+	if parts := bytes.Split(token, sep); len(parts) == 3 {
+		payload, _ = Base64Decode(parts[1])
+	}
+
+	return fn(context.Background(), token, payload, standardClaims, err)
+}
+
+// ValidatePayload completes the PayloadValidator interface.
+// It calls itself.
+func (fn PayloadValidatorFunc) ValidatePayload(ctx context.Context, token, payload []byte, standardClaims Claims, err error) error {
+	return fn(ctx, token, payload, standardClaims, err)
+}
+
+func validateToken(ctx context.Context, validator TokenValidator, token, payload []byte, standardClaims Claims, err error) error {
+	if v, ok := validator.(PayloadValidator); ok {
+		return v.ValidatePayload(ctx, token, payload, standardClaims, err)
+	}
+
+	if v, ok := validator.(ContextValidator); ok {
+		return v.ValidateTokenContext(ctx, token, standardClaims, err)
+	}
+
+	return validator.ValidateToken(token, standardClaims, err)
+}
+
 // VerifiedToken holds the information about a verified token.
 // Look `Verify` for more.
 type VerifiedToken struct {
@@ -125,3 +257,45 @@ type VerifiedToken struct {
 func (t *VerifiedToken) Claims(dest interface{}) error {
 	return Unmarshal(t.Payload, dest)
 }
+
+// ExpiresAt returns the time the token expires (round in second)
+// or the zero time if the token has no "exp" claim.
+func (t *VerifiedToken) ExpiresAt() time.Time {
+	if t.StandardClaims.Expiry <= 0 {
+		return time.Time{}
+	}
+
+	return t.StandardClaims.ExpiresAt()
+}
+
+// TimeLeft returns the remaining time until the token expires (round in second),
+// based on the package-level `Clock` function. It returns zero when
+// the token has already expired or it has no "exp" claim.
+func (t *VerifiedToken) TimeLeft() time.Duration {
+	if t.StandardClaims.Expiry <= 0 {
+		return 0
+	}
+
+	if left := t.StandardClaims.Timeleft(); left > 0 {
+		return left
+	}
+
+	return 0
+}
+
+// NearExpiry reports whether the token expires within the given "threshold",
+// so clients and middlewares can refresh it proactively.
+// A token without an "exp" claim is never near its expiry.
+//
+// Example Code:
+//
+//  if verifiedToken.NearExpiry(time.Minute) {
+//    [refresh the token...]
+//  }
+func (t *VerifiedToken) NearExpiry(threshold time.Duration) bool {
+	if t.StandardClaims.Expiry <= 0 {
+		return false
+	}
+
+	return t.TimeLeft() <= threshold
+}
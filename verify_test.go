@@ -3,6 +3,7 @@ package jwt
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 // The actual implementation tests live inside token_test.go and each algorithm's test file.
@@ -34,3 +35,61 @@ func TestVerify(t *testing.T) {
 		t.Fatalf("expected verify error: %v but got: %v", ErrTokenSignature, err)
 	}
 }
+
+type algSignerTest struct{ alg Alg }
+
+func (a algSignerTest) Name() string { return a.alg.Name() }
+func (a algSignerTest) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
+	return a.alg.Sign(key, headerAndPayload)
+}
+
+type algVerifierTest struct{ alg Alg }
+
+func (a algVerifierTest) Name() string { return a.alg.Name() }
+func (a algVerifierTest) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
+	return a.alg.Verify(key, headerAndPayload, signature)
+}
+
+func TestSignerVerifierCapabilities(t *testing.T) {
+	token, err := Sign(algSignerTest{testAlg}, testSecret, Map{"foo": "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(algVerifierTest{testAlg}, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifiedTokenExpiry(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	Clock = func() time.Time { return now }
+	defer func() { Clock = time.Now }()
+
+	verifiedToken := &VerifiedToken{StandardClaims: Claims{IssuedAt: now.Unix(), Expiry: now.Add(10 * time.Minute).Unix()}}
+	if expected, got := now.Add(10*time.Minute), verifiedToken.ExpiresAt(); !got.Equal(expected) {
+		t.Fatalf("expected expires at: %s but got: %s", expected, got)
+	}
+
+	if expected, got := 10*time.Minute, verifiedToken.TimeLeft(); got != expected {
+		t.Fatalf("expected time left: %s but got: %s", expected, got)
+	}
+
+	if verifiedToken.NearExpiry(time.Minute) {
+		t.Fatalf("expected not near expiry")
+	}
+
+	if !verifiedToken.NearExpiry(10 * time.Minute) {
+		t.Fatalf("expected near expiry")
+	}
+
+	now = now.Add(time.Hour)
+	if got := verifiedToken.TimeLeft(); got != 0 {
+		t.Fatalf("expected zero time left on expired token but got: %s", got)
+	}
+
+	noExpiry := &VerifiedToken{StandardClaims: Claims{IssuedAt: now.Unix()}}
+	if !noExpiry.ExpiresAt().IsZero() || noExpiry.TimeLeft() != 0 || noExpiry.NearExpiry(time.Hour) {
+		t.Fatalf("expected no expiration information")
+	}
+}